@@ -0,0 +1,112 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/service"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
+	"github.com/urfave/cli/v2"
+)
+
+func Migrate() *cli.Command {
+	return &cli.Command{
+		Name:     "migrate",
+		Usage:    "rewrites legacy user records to company and user scoped identities",
+		Category: "maintenance",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config_path",
+				Usage:   "sets custom configuration path",
+				Aliases: []string{"config", "conf", "c"},
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "sets migration timeout",
+				Value: 10 * time.Minute,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var (
+				CONFIG_PATH = c.String("config_path")
+			)
+
+			storage, err := config.BuildNewStorageConfig(CONFIG_PATH)()
+			if err != nil {
+				return err
+			}
+
+			if storage.Storage.URL == "" {
+				return errors.New("migration requires a persistent storage url")
+			}
+
+			cryptoConfig, err := config.BuildNewCryptoConfig(CONFIG_PATH)()
+			if err != nil {
+				return err
+			}
+
+			loggerConfig, err := config.BuildNewLoggerConfig(CONFIG_PATH)()
+			if err != nil {
+				return err
+			}
+
+			credentials, err := shared.BuildNewIntegrationCredentialsConfig(CONFIG_PATH)()
+			if err != nil {
+				return err
+			}
+
+			keyringConfig, err := keyring.BuildNewKeyringConfig(CONFIG_PATH)()
+			if err != nil {
+				return err
+			}
+
+			ring, err := keyring.NewKeyring(keyringConfig, crypto.NewEncryptor(cryptoConfig), credentials)
+			if err != nil {
+				return err
+			}
+
+			logger := log.NewDefaultLogger(loggerConfig)
+			migration := service.NewUserMigrationService(
+				adapter.NewMongoUserMigrationAdapter(storage.Storage.URL),
+				ring, client.NewPipedriveApiClient(),
+				client.NewPipedriveAuthClient(credentials), logger,
+			)
+
+			ctx, cancel := context.WithTimeout(c.Context, c.Duration("timeout"))
+			defer cancel()
+
+			migrated, err := migration.MigrateUsers(ctx)
+			if err != nil {
+				return err
+			}
+
+			logger.Infof("successfully migrated %d user records", migrated)
+			return nil
+		},
+	}
+}
@@ -27,6 +27,9 @@ import (
 func GetCommands() cli.Commands {
 	return []*cli.Command{
 		Server(),
+		Migrate(),
+		Rotate(),
+		WrapKey(),
 	}
 }
 
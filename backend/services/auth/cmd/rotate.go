@@ -0,0 +1,39 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cmd
+
+import (
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/service"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
+	"github.com/urfave/cli/v2"
+)
+
+func Rotate() *cli.Command {
+	return keyring.NewRotateCommand(
+		"re-encrypts stored user tokens with the active keyring key", "user records",
+		func(storageURL string, ring keyring.Keyring, logger log.Logger) keyring.Rotation {
+			return service.NewUserKeyRotationService(
+				adapter.NewMongoUserKeyRotationAdapter(storageURL),
+				ring, logger,
+			).RotateUsers
+		},
+	)
+}
@@ -25,8 +25,10 @@ import (
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/adapter"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/service"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/handler"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/worker"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
 	"github.com/urfave/cli/v2"
 )
 
@@ -49,11 +51,19 @@ func Server() *cli.Command {
 
 			app := pkg.NewBootstrapper(CONFIG_PATH, pkg.WithModules(
 				shared.BuildNewIntegrationCredentialsConfig(CONFIG_PATH),
+				keyring.BuildNewKeyringConfig(CONFIG_PATH), keyring.NewKeyring,
 				rpc.NewService, web.NewAuthRPCServer,
 				adapter.BuildNewUserAdapter, service.NewUserService,
 				handler.NewUserSelectHandler, handler.NewUserInsertHandler,
 				handler.NewUserDeleteHandler,
+				adapter.BuildNewAuditEventAdapter, service.NewAuditService,
 				client.NewPipedriveAuthClient,
+				shared.BuildNewTokenRefresherConfig(CONFIG_PATH),
+				adapter.BuildNewUserLeaseAdapter,
+				service.NewUserRefreshService,
+				worker.NewTokenRefresher,
+			), pkg.WithInvokables(
+				worker.RunTokenRefresher,
 			)).Bootstrap()
 
 			if err := app.Err(); err != nil {
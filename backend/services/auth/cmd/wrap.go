@@ -0,0 +1,92 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
+	"github.com/urfave/cli/v2"
+)
+
+func WrapKey() *cli.Command {
+	return &cli.Command{
+		Name:     "wrap-key",
+		Usage:    "wraps a data key read from stdin with the keyring kms master key",
+		Category: "maintenance",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config_path",
+				Usage:   "sets custom configuration path",
+				Aliases: []string{"config", "conf", "c"},
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var (
+				CONFIG_PATH = c.String("config_path")
+			)
+
+			cryptoConfig, err := config.BuildNewCryptoConfig(CONFIG_PATH)()
+			if err != nil {
+				return err
+			}
+
+			keyringConfig, err := keyring.BuildNewKeyringConfig(CONFIG_PATH)()
+			if err != nil {
+				return err
+			}
+
+			kms, err := keyring.NewLocalKMSProvider(
+				crypto.NewEncryptor(cryptoConfig),
+				keyringConfig.Keyring.KMS.MasterKeyFile,
+			)
+			if err != nil {
+				return err
+			}
+
+			buf, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+
+			key := strings.TrimSpace(string(buf))
+			if key == "" {
+				return errors.New("expected a data key on stdin")
+			}
+
+			if len(key) != keyring.KeySize {
+				return fmt.Errorf("expected a %d bytes long data key on stdin", keyring.KeySize)
+			}
+
+			wrapped, err := kms.Wrap([]byte(key))
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(c.App.Writer, wrapped)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,130 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/port"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func BuildNewAuditEventAdapter(config *config.StorageConfig) port.AuditEventAdapter {
+	adapter := NewMemoryAuditEventAdapter()
+	if config.Storage.URL != "" {
+		adapter = NewMongoAuditEventAdapter(config.Storage.URL)
+	}
+
+	return adapter
+}
+
+type memoryAuditEventAdapter struct {
+	mu  sync.Mutex
+	kvs map[string][]byte
+}
+
+func NewMemoryAuditEventAdapter() port.AuditEventAdapter {
+	return &memoryAuditEventAdapter{
+		kvs: make(map[string][]byte),
+	}
+}
+
+func (m *memoryAuditEventAdapter) InsertEvent(ctx context.Context, event domain.AuditEvent) error {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.kvs[event.ID]; ok {
+		return nil
+	}
+
+	buffer, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	m.kvs[event.ID] = buffer
+	return nil
+}
+
+type auditEventCollection struct {
+	mgm.DefaultModel `bson:",inline"`
+	EventID          string `json:"event_id" bson:"event_id"`
+	CompanyID        int    `json:"company_id" bson:"company_id"`
+	UserID           int    `json:"user_id" bson:"user_id"`
+	Action           string `json:"action" bson:"action"`
+	Scope            string `json:"scope" bson:"scope"`
+	RemovedUsers     int    `json:"removed_users" bson:"removed_users"`
+}
+
+type mongoAuditEventAdapter struct {
+}
+
+func NewMongoAuditEventAdapter(url string) port.AuditEventAdapter {
+	if err := mgm.SetDefaultConfig(
+		&mgm.Config{CtxTimeout: 3 * time.Second}, "pipedrive",
+		options.Client().ApplyURI(url),
+	); err != nil {
+		log.Fatalf("mongo initialization error: %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := mgm.Coll(&auditEventCollection{}).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "event_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	}); err != nil {
+		log.Fatalf("mongo index initialization error: %s", err.Error())
+	}
+
+	return &mongoAuditEventAdapter{}
+}
+
+// InsertEvent ignores events that have already been recorded so that retried actions stay idempotent.
+func (m *mongoAuditEventAdapter) InsertEvent(ctx context.Context, event domain.AuditEvent) error {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+
+	if err := mgm.Coll(&auditEventCollection{}).CreateWithCtx(ctx, &auditEventCollection{
+		EventID:      event.ID,
+		CompanyID:    event.CompanyID,
+		UserID:       event.UserID,
+		Action:       event.Action,
+		Scope:        event.Scope,
+		RemovedUsers: event.RemovedUsers,
+	}); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	return nil
+}
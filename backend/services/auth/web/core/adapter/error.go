@@ -23,4 +23,6 @@ import "errors"
 var (
 	ErrInvalidUserId     error = errors.New("invalid uid format")
 	ErrUserAlreadyExists error = errors.New("user already exists")
+	ErrUserModified      error = errors.New("user has been modified concurrently")
+	ErrInvalidLease      error = errors.New("invalid lease key or owner")
 )
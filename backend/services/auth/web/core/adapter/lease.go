@@ -0,0 +1,165 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/port"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func BuildNewUserLeaseAdapter(config *config.StorageConfig) port.UserLeaseAdapter {
+	adapter := NewMemoryUserLeaseAdapter()
+	if config.Storage.URL != "" {
+		adapter = NewMongoUserLeaseAdapter(config.Storage.URL)
+	}
+
+	return adapter
+}
+
+type userLease struct {
+	owner     string
+	expiresAt time.Time
+}
+
+// memoryUserLeaseAdapter is guarded by a mutex since leases are acquired by concurrent refreshes.
+type memoryUserLeaseAdapter struct {
+	mu     sync.Mutex
+	leases map[string]userLease
+}
+
+func NewMemoryUserLeaseAdapter() port.UserLeaseAdapter {
+	return &memoryUserLeaseAdapter{
+		leases: make(map[string]userLease),
+	}
+}
+
+func (m *memoryUserLeaseAdapter) AcquireLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	if strings.TrimSpace(key) == "" || strings.TrimSpace(owner) == "" {
+		return false, ErrInvalidLease
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if lease, ok := m.leases[key]; ok && lease.owner != owner && lease.expiresAt.After(now) {
+		return false, nil
+	}
+
+	m.leases[key] = userLease{
+		owner:     owner,
+		expiresAt: now.Add(ttl),
+	}
+
+	return true, nil
+}
+
+func (m *memoryUserLeaseAdapter) ReleaseLease(ctx context.Context, key, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if lease, ok := m.leases[key]; ok && lease.owner == owner {
+		delete(m.leases, key)
+	}
+
+	return nil
+}
+
+type userLeaseCollection struct {
+	mgm.DefaultModel `bson:",inline"`
+	Key              string    `json:"key" bson:"key"`
+	Owner            string    `json:"owner" bson:"owner"`
+	ExpiresAt        time.Time `json:"expires_at" bson:"expires_at"`
+}
+
+type mongoUserLeaseAdapter struct {
+}
+
+func NewMongoUserLeaseAdapter(url string) port.UserLeaseAdapter {
+	if err := mgm.SetDefaultConfig(
+		&mgm.Config{CtxTimeout: 3 * time.Second}, "pipedrive",
+		options.Client().ApplyURI(url),
+	); err != nil {
+		log.Fatalf("mongo initialization error: %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := mgm.Coll(&userLeaseCollection{}).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		log.Fatalf("mongo index initialization error: %s", err.Error())
+	}
+
+	return &mongoUserLeaseAdapter{}
+}
+
+// AcquireLease takes over a missing, expired or already owned lease. A lease held by another owner
+// makes the upsert collide with the unique key index, which is reported as a failed acquisition.
+func (m *mongoUserLeaseAdapter) AcquireLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	if strings.TrimSpace(key) == "" || strings.TrimSpace(owner) == "" {
+		return false, ErrInvalidLease
+	}
+
+	now := time.Now()
+	if _, err := mgm.Coll(&userLeaseCollection{}).UpdateOne(ctx, bson.M{
+		"key": key,
+		"$or": bson.A{
+			bson.M{"owner": owner},
+			bson.M{"expires_at": bson.M{"$lte": now}},
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"owner":      owner,
+			"expires_at": now.Add(ttl),
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": now,
+		},
+	}, options.Update().SetUpsert(true)); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (m *mongoUserLeaseAdapter) ReleaseLease(ctx context.Context, key, owner string) error {
+	_, err := mgm.Coll(&userLeaseCollection{}).DeleteOne(ctx, bson.M{
+		"key":   key,
+		"owner": owner,
+	})
+	return err
+}
@@ -22,6 +22,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sort"
 
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/port"
@@ -44,7 +45,7 @@ func (m *memoryUserAdapter) save(user domain.UserAccess) error {
 		return err
 	}
 
-	m.kvs[user.ID] = buffer
+	m.kvs[user.ID.Key()] = buffer
 
 	return nil
 }
@@ -53,8 +54,8 @@ func (m *memoryUserAdapter) InsertUser(ctx context.Context, user domain.UserAcce
 	return m.save(user)
 }
 
-func (m *memoryUserAdapter) SelectUser(ctx context.Context, uid string) (domain.UserAccess, error) {
-	buffer, ok := m.kvs[uid]
+func (m *memoryUserAdapter) SelectUser(ctx context.Context, id domain.UserIdentity) (domain.UserAccess, error) {
+	buffer, ok := m.kvs[id.Key()]
 	var user domain.UserAccess
 
 	if !ok {
@@ -76,12 +77,58 @@ func (m *memoryUserAdapter) UpsertUser(ctx context.Context, user domain.UserAcce
 	return user, nil
 }
 
-func (m *memoryUserAdapter) DeleteUser(ctx context.Context, uid string) error {
-	if _, ok := m.kvs[uid]; !ok {
-		return errors.New("user with this id doesn't exist")
+func (m *memoryUserAdapter) DeleteUser(ctx context.Context, id domain.UserIdentity) error {
+	delete(m.kvs, id.Key())
+
+	return nil
+}
+
+func (m *memoryUserAdapter) SelectCompanyUsers(ctx context.Context, cid int) ([]domain.UserAccess, error) {
+	users := make([]domain.UserAccess, 0)
+	for _, buffer := range m.kvs {
+		var user domain.UserAccess
+		if err := json.Unmarshal(buffer, &user); err != nil {
+			return nil, err
+		}
+
+		if user.ID.CompanyID == cid {
+			users = append(users, user)
+		}
 	}
 
-	delete(m.kvs, uid)
+	return users, nil
+}
 
-	return nil
+func (m *memoryUserAdapter) SelectExpiringUsers(ctx context.Context, before int64, limit int) ([]domain.UserAccess, error) {
+	users := make([]domain.UserAccess, 0)
+	for _, buffer := range m.kvs {
+		var user domain.UserAccess
+		if err := json.Unmarshal(buffer, &user); err != nil {
+			return nil, err
+		}
+
+		if !user.Revoked && user.ExpiresAt <= before {
+			users = append(users, user)
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ExpiresAt < users[j].ExpiresAt
+	})
+
+	if limit > 0 && len(users) > limit {
+		users = users[:limit]
+	}
+
+	return users, nil
+}
+
+func (m *memoryUserAdapter) RevokeUser(ctx context.Context, id domain.UserIdentity) error {
+	user, err := m.SelectUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	user.Revoked = true
+	return m.save(user)
 }
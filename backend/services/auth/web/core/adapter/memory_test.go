@@ -21,6 +21,7 @@ package adapter
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
 	"github.com/stretchr/testify/assert"
@@ -38,26 +39,111 @@ func TestMemoryAdapter(t *testing.T) {
 	})
 
 	t.Run("get user by id", func(t *testing.T) {
-		u, err := adapter.SelectUser(context.Background(), "mock")
+		u, err := adapter.SelectUser(context.Background(), id)
 		assert.NoError(t, err)
 		assert.Equal(t, user, u)
 	})
 
 	t.Run("update user by id", func(t *testing.T) {
 		u, err := adapter.UpsertUser(context.Background(), domain.UserAccess{
-			ID:          "mock",
+			ID:          id,
 			AccessToken: "BRuh",
 		})
 		assert.NoError(t, err)
 		assert.NotNil(t, u)
 	})
 
+	t.Run("users with the same id sum do not collide", func(t *testing.T) {
+		other := user
+		other.ID = domain.UserIdentity{CompanyID: id.UserID, UserID: id.CompanyID}
+		assert.NoError(t, adapter.InsertUser(context.Background(), other))
+		assert.NoError(t, adapter.DeleteUser(context.Background(), other.ID))
+		_, err := adapter.SelectUser(context.Background(), id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("select expiring users", func(t *testing.T) {
+		assert.NoError(t, adapter.InsertUser(context.Background(), user))
+		users, err := adapter.SelectExpiringUsers(context.Background(), user.ExpiresAt, 10)
+		assert.NoError(t, err)
+		assert.Len(t, users, 1)
+
+		users, err = adapter.SelectExpiringUsers(context.Background(), user.ExpiresAt-1, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("revoked users are not selected for refresh", func(t *testing.T) {
+		assert.NoError(t, adapter.RevokeUser(context.Background(), id))
+		u, err := adapter.SelectUser(context.Background(), id)
+		assert.NoError(t, err)
+		assert.True(t, u.Revoked)
+
+		users, err := adapter.SelectExpiringUsers(context.Background(), user.ExpiresAt, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
 	t.Run("delete user by id", func(t *testing.T) {
-		assert.NoError(t, adapter.DeleteUser(context.Background(), "mock"))
+		assert.NoError(t, adapter.DeleteUser(context.Background(), id))
 	})
 
 	t.Run("get invalid user", func(t *testing.T) {
-		_, err := adapter.SelectUser(context.Background(), "mock")
+		_, err := adapter.SelectUser(context.Background(), id)
 		assert.Error(t, err)
 	})
+
+	t.Run("delete missing user", func(t *testing.T) {
+		assert.NoError(t, adapter.DeleteUser(context.Background(), id))
+	})
+
+	t.Run("get company users", func(t *testing.T) {
+		other := user
+		other.ID = domain.UserIdentity{CompanyID: id.CompanyID + 1, UserID: id.UserID}
+		assert.NoError(t, adapter.InsertUser(context.Background(), user))
+		assert.NoError(t, adapter.InsertUser(context.Background(), other))
+
+		users, err := adapter.SelectCompanyUsers(context.Background(), id.CompanyID)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.UserAccess{user}, users)
+	})
+}
+
+func TestMemoryLeaseAdapter(t *testing.T) {
+	adapter := NewMemoryUserLeaseAdapter()
+
+	t.Run("acquire lease", func(t *testing.T) {
+		acquired, err := adapter.AcquireLease(context.Background(), "refresh:1:2", "first", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("acquire a lease held by another owner", func(t *testing.T) {
+		acquired, err := adapter.AcquireLease(context.Background(), "refresh:1:2", "second", time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, acquired)
+	})
+
+	t.Run("extend an owned lease", func(t *testing.T) {
+		acquired, err := adapter.AcquireLease(context.Background(), "refresh:1:2", "first", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("release lease", func(t *testing.T) {
+		assert.NoError(t, adapter.ReleaseLease(context.Background(), "refresh:1:2", "first"))
+		acquired, err := adapter.AcquireLease(context.Background(), "refresh:1:2", "second", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("acquire an expired lease", func(t *testing.T) {
+		acquired, err := adapter.AcquireLease(context.Background(), "refresh:2:2", "first", -time.Second)
+		assert.NoError(t, err)
+		assert.True(t, acquired)
+
+		acquired, err = adapter.AcquireLease(context.Background(), "refresh:2:2", "second", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, acquired)
+	})
 }
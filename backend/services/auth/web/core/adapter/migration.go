@@ -0,0 +1,116 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/port"
+	"github.com/kamva/mgm/v3"
+	"github.com/kamva/mgm/v3/operator"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Legacy records were keyed by the sum of user and company ids and have no company_id field.
+var legacyFilter = bson.M{
+	"uid":        bson.M{operator.Exists: true, operator.Ne: ""},
+	"company_id": bson.M{operator.In: bson.A{nil, 0}},
+}
+
+type mongoUserMigrationAdapter struct {
+}
+
+func NewMongoUserMigrationAdapter(url string) port.UserMigrationAdapter {
+	if err := mgm.SetDefaultConfig(
+		&mgm.Config{CtxTimeout: 3 * time.Second}, "pipedrive",
+		options.Client().ApplyURI(url),
+	); err != nil {
+		log.Fatalf("mongo initialization error: %s", err.Error())
+	}
+
+	return &mongoUserMigrationAdapter{}
+}
+
+func (m *mongoUserMigrationAdapter) SelectLegacyUsers(ctx context.Context) (map[string]domain.UserAccess, error) {
+	var users []userAccessCollection
+	if err := mgm.Coll(&userAccessCollection{}).SimpleFindWithCtx(ctx, &users, legacyFilter); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]domain.UserAccess, len(users))
+	for _, user := range users {
+		result[user.UID] = domain.UserAccess{
+			AccessToken:  user.AccessToken,
+			RefreshToken: user.RefreshToken,
+			TokenType:    user.TokenType,
+			Scope:        user.Scope,
+			ExpiresAt:    user.ExpiresAt,
+			ApiDomain:    user.ApiDomain,
+		}
+	}
+
+	return result, nil
+}
+
+func (m *mongoUserMigrationAdapter) MigrateUser(ctx context.Context, legacyID string, user domain.UserAccess) error {
+	legacyID = strings.TrimSpace(legacyID)
+	if legacyID == "" {
+		return ErrInvalidUserId
+	}
+
+	if err := user.Validate(); err != nil {
+		return err
+	}
+
+	// Users who signed in after the upgrade already have a newer record, which is kept.
+	// The legacy record is only removed once the new one exists, so that an interrupted
+	// migration is picked up by the next run without a transaction.
+	collection := mgm.Coll(&userAccessCollection{})
+	if err := collection.FirstWithCtx(ctx, identityFilter(user.ID), &userAccessCollection{}); err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+
+		if err := collection.CreateWithCtx(ctx, &userAccessCollection{
+			CompanyID:    user.ID.CompanyID,
+			UserID:       user.ID.UserID,
+			AccessToken:  user.AccessToken,
+			RefreshToken: user.RefreshToken,
+			TokenType:    user.TokenType,
+			Scope:        user.Scope,
+			ExpiresAt:    user.ExpiresAt,
+			ApiDomain:    user.ApiDomain,
+		}); err != nil {
+			return err
+		}
+	}
+
+	_, err := collection.DeleteMany(ctx, bson.M{
+		"uid":        legacyID,
+		"company_id": legacyFilter["company_id"],
+	})
+	return err
+}
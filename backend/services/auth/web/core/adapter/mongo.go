@@ -21,7 +21,6 @@ package adapter
 import (
 	"context"
 	"log"
-	"strings"
 	"time"
 
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
@@ -35,13 +34,16 @@ import (
 
 type userAccessCollection struct {
 	mgm.DefaultModel `bson:",inline"`
-	UID              string `json:"uid" bson:"uid"`
+	UID              string `json:"uid,omitempty" bson:"uid,omitempty"`
+	CompanyID        int    `json:"company_id" bson:"company_id"`
+	UserID           int    `json:"user_id" bson:"user_id"`
 	AccessToken      string `json:"access_token"`
 	RefreshToken     string `json:"refresh_token"`
 	TokenType        string `json:"token_type"`
 	Scope            string `json:"scope"`
 	ExpiresAt        int64  `json:"expires_at"`
 	ApiDomain        string `json:"api_domain"`
+	Revoked          bool   `json:"revoked"`
 }
 
 type mongoUserAdapter struct {
@@ -63,15 +65,17 @@ func (m *mongoUserAdapter) save(ctx context.Context, user domain.UserAccess) err
 		u := &userAccessCollection{}
 		collection := mgm.Coll(&userAccessCollection{})
 
-		if err := collection.FirstWithCtx(ctx, bson.M{"uid": user.ID}, u); err != nil {
+		if err := collection.FirstWithCtx(ctx, identityFilter(user.ID), u); err != nil {
 			if cerr := collection.CreateWithCtx(ctx, &userAccessCollection{
-				UID:          user.ID,
+				CompanyID:    user.ID.CompanyID,
+				UserID:       user.ID.UserID,
 				AccessToken:  user.AccessToken,
 				RefreshToken: user.RefreshToken,
 				TokenType:    user.TokenType,
 				Scope:        user.Scope,
 				ExpiresAt:    user.ExpiresAt,
 				ApiDomain:    user.ApiDomain,
+				Revoked:      user.Revoked,
 			}); cerr != nil {
 				return cerr
 			}
@@ -86,6 +90,7 @@ func (m *mongoUserAdapter) save(ctx context.Context, user domain.UserAccess) err
 		u.ExpiresAt = user.ExpiresAt
 		u.UpdatedAt = time.Now()
 		u.ApiDomain = user.ApiDomain
+		u.Revoked = user.Revoked
 
 		if err := collection.UpdateWithCtx(ctx, u); err != nil {
 			return err
@@ -103,24 +108,30 @@ func (m *mongoUserAdapter) InsertUser(ctx context.Context, user domain.UserAcces
 	return m.save(ctx, user)
 }
 
-func (m *mongoUserAdapter) SelectUser(ctx context.Context, uid string) (domain.UserAccess, error) {
-	uid = strings.TrimSpace(uid)
-
-	if uid == "" {
+func (m *mongoUserAdapter) SelectUser(ctx context.Context, id domain.UserIdentity) (domain.UserAccess, error) {
+	if err := id.Validate(); err != nil {
 		return domain.UserAccess{}, ErrInvalidUserId
 	}
 
 	user := &userAccessCollection{}
 	collection := mgm.Coll(user)
+	if err := collection.FirstWithCtx(ctx, identityFilter(id), user); err != nil {
+		return domain.UserAccess{}, err
+	}
+
 	return domain.UserAccess{
-		ID:           user.UID,
+		ID: domain.UserIdentity{
+			CompanyID: user.CompanyID,
+			UserID:    user.UserID,
+		},
 		AccessToken:  user.AccessToken,
 		RefreshToken: user.RefreshToken,
 		TokenType:    user.TokenType,
 		Scope:        user.Scope,
 		ExpiresAt:    user.ExpiresAt,
 		ApiDomain:    user.ApiDomain,
-	}, collection.FirstWithCtx(ctx, bson.M{"uid": uid}, user)
+		Revoked:      user.Revoked,
+	}, nil
 }
 
 func (m *mongoUserAdapter) UpsertUser(ctx context.Context, user domain.UserAccess) (domain.UserAccess, error) {
@@ -131,13 +142,83 @@ func (m *mongoUserAdapter) UpsertUser(ctx context.Context, user domain.UserAcces
 	return user, m.save(ctx, user)
 }
 
-func (m *mongoUserAdapter) DeleteUser(ctx context.Context, uid string) error {
-	uid = strings.TrimSpace(uid)
+func (m *mongoUserAdapter) DeleteUser(ctx context.Context, id domain.UserIdentity) error {
+	if err := id.Validate(); err != nil {
+		return ErrInvalidUserId
+	}
+
+	_, err := mgm.Coll(&userAccessCollection{}).DeleteMany(ctx, bson.M{
+		"company_id": bson.M{operator.Eq: id.CompanyID},
+		"user_id":    bson.M{operator.Eq: id.UserID},
+	})
+	return err
+}
+
+func (m *mongoUserAdapter) SelectCompanyUsers(ctx context.Context, cid int) ([]domain.UserAccess, error) {
+	if cid <= 0 {
+		return nil, ErrInvalidUserId
+	}
+
+	var users []userAccessCollection
+	if err := mgm.Coll(&userAccessCollection{}).SimpleFindWithCtx(ctx, &users, bson.M{
+		"company_id": cid,
+	}); err != nil {
+		return nil, err
+	}
+
+	return toUserAccess(users), nil
+}
+
+func (m *mongoUserAdapter) SelectExpiringUsers(ctx context.Context, before int64, limit int) ([]domain.UserAccess, error) {
+	opts := options.Find().SetSort(bson.M{"expiresat": 1})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	var users []userAccessCollection
+	if err := mgm.Coll(&userAccessCollection{}).SimpleFindWithCtx(ctx, &users, bson.M{
+		"company_id": bson.M{operator.Gt: 0},
+		"expiresat":  bson.M{operator.Lte: before},
+		"revoked":    bson.M{operator.Ne: true},
+	}, opts); err != nil {
+		return nil, err
+	}
+
+	return toUserAccess(users), nil
+}
 
-	if uid == "" {
+func (m *mongoUserAdapter) RevokeUser(ctx context.Context, id domain.UserIdentity) error {
+	if err := id.Validate(); err != nil {
 		return ErrInvalidUserId
 	}
 
-	_, err := mgm.Coll(&userAccessCollection{}).DeleteMany(ctx, bson.M{"uid": bson.M{operator.Eq: uid}})
+	_, err := mgm.Coll(&userAccessCollection{}).UpdateMany(ctx, identityFilter(id), bson.M{
+		operator.Set: bson.M{"revoked": true, "updated_at": time.Now()},
+	})
 	return err
 }
+
+func toUserAccess(users []userAccessCollection) []domain.UserAccess {
+	result := make([]domain.UserAccess, 0, len(users))
+	for _, user := range users {
+		result = append(result, domain.UserAccess{
+			ID: domain.UserIdentity{
+				CompanyID: user.CompanyID,
+				UserID:    user.UserID,
+			},
+			AccessToken:  user.AccessToken,
+			RefreshToken: user.RefreshToken,
+			TokenType:    user.TokenType,
+			Scope:        user.Scope,
+			ExpiresAt:    user.ExpiresAt,
+			ApiDomain:    user.ApiDomain,
+			Revoked:      user.Revoked,
+		})
+	}
+
+	return result
+}
+
+func identityFilter(id domain.UserIdentity) bson.M {
+	return bson.M{"company_id": id.CompanyID, "user_id": id.UserID}
+}
@@ -24,11 +24,17 @@ import (
 	"time"
 
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
+	"github.com/kamva/mgm/v3"
 	"github.com/stretchr/testify/assert"
 )
 
+var id = domain.UserIdentity{
+	CompanyID: 1,
+	UserID:    2,
+}
+
 var user = domain.UserAccess{
-	ID:           "mock",
+	ID:           id,
 	AccessToken:  "mock",
 	RefreshToken: "mock",
 	TokenType:    "mock",
@@ -56,12 +62,12 @@ func TestMongoAdapter(t *testing.T) {
 	t.Run("get user by id with timeout", func(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 0*time.Second)
 		defer cancel()
-		_, err := adapter.SelectUser(ctx, "mock")
+		_, err := adapter.SelectUser(ctx, id)
 		assert.Error(t, err)
 	})
 
 	t.Run("get user by id", func(t *testing.T) {
-		u, err := adapter.SelectUser(context.Background(), "mock")
+		u, err := adapter.SelectUser(context.Background(), id)
 		assert.NoError(t, err)
 		assert.Equal(t, user, u)
 	})
@@ -69,21 +75,21 @@ func TestMongoAdapter(t *testing.T) {
 	t.Run("delete user by id with timeout", func(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 0*time.Second)
 		defer cancel()
-		assert.Error(t, adapter.DeleteUser(ctx, "mock"))
+		assert.Error(t, adapter.DeleteUser(ctx, id))
 	})
 
 	t.Run("delete user by id", func(t *testing.T) {
-		assert.NoError(t, adapter.DeleteUser(context.Background(), "mock"))
+		assert.NoError(t, adapter.DeleteUser(context.Background(), id))
 	})
 
 	t.Run("get invalid user", func(t *testing.T) {
-		_, err := adapter.SelectUser(context.Background(), "mock")
+		_, err := adapter.SelectUser(context.Background(), id)
 		assert.Error(t, err)
 	})
 
 	t.Run("invald user update", func(t *testing.T) {
 		_, err := adapter.UpsertUser(context.Background(), domain.UserAccess{
-			ID:          "mock",
+			ID:          id,
 			AccessToken: "BRuh",
 		})
 		assert.Error(t, err)
@@ -93,7 +99,7 @@ func TestMongoAdapter(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 0*time.Second)
 		defer cancel()
 		_, err := adapter.UpsertUser(ctx, domain.UserAccess{
-			ID:           "mock",
+			ID:           id,
 			AccessToken:  "BRuh",
 			RefreshToken: "BRUH",
 			TokenType:    "mock",
@@ -105,7 +111,7 @@ func TestMongoAdapter(t *testing.T) {
 
 	t.Run("update user", func(t *testing.T) {
 		_, err := adapter.UpsertUser(context.Background(), domain.UserAccess{
-			ID:           "mock",
+			ID:           id,
 			AccessToken:  "BRuh",
 			RefreshToken: "BRUH",
 			TokenType:    "mock",
@@ -116,10 +122,39 @@ func TestMongoAdapter(t *testing.T) {
 	})
 
 	t.Run("get updated user", func(t *testing.T) {
-		u, err := adapter.SelectUser(context.Background(), "mock")
+		u, err := adapter.SelectUser(context.Background(), id)
+		assert.NoError(t, err)
+		assert.Equal(t, "BRuh", u.AccessToken)
+	})
+
+	t.Run("keep users who signed in before their migration", func(t *testing.T) {
+		migration := NewMongoUserMigrationAdapter("mongodb://localhost:27017")
+		assert.NoError(t, mgm.Coll(&userAccessCollection{}).CreateWithCtx(context.Background(), &userAccessCollection{
+			UID:          "3",
+			AccessToken:  "legacy",
+			RefreshToken: "legacy",
+			TokenType:    "mock",
+			Scope:        "mock",
+			ExpiresAt:    123456,
+		}))
+
+		assert.NoError(t, migration.MigrateUser(context.Background(), "3", domain.UserAccess{
+			ID:           id,
+			AccessToken:  "legacy",
+			RefreshToken: "legacy",
+			TokenType:    "mock",
+			Scope:        "mock",
+			ExpiresAt:    123456,
+		}))
+
+		u, err := adapter.SelectUser(context.Background(), id)
 		assert.NoError(t, err)
 		assert.Equal(t, "BRuh", u.AccessToken)
+
+		legacy, err := migration.SelectLegacyUsers(context.Background())
+		assert.NoError(t, err)
+		assert.NotContains(t, legacy, "3")
 	})
 
-	adapter.DeleteUser(context.Background(), "mock")
+	adapter.DeleteUser(context.Background(), id)
 }
@@ -0,0 +1,104 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/port"
+	"github.com/kamva/mgm/v3"
+	"github.com/kamva/mgm/v3/operator"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoUserKeyRotationAdapter struct {
+}
+
+func NewMongoUserKeyRotationAdapter(url string) port.UserKeyRotationAdapter {
+	if err := mgm.SetDefaultConfig(
+		&mgm.Config{CtxTimeout: 3 * time.Second}, "pipedrive",
+		options.Client().ApplyURI(url),
+	); err != nil {
+		log.Fatalf("mongo initialization error: %s", err.Error())
+	}
+
+	return &mongoUserKeyRotationAdapter{}
+}
+
+func (m *mongoUserKeyRotationAdapter) SelectUsers(ctx context.Context) ([]domain.UserAccess, error) {
+	var users []userAccessCollection
+	if err := mgm.Coll(&userAccessCollection{}).SimpleFindWithCtx(ctx, &users, bson.M{
+		"company_id": bson.M{operator.Gt: 0},
+	}); err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.UserAccess, 0, len(users))
+	for _, user := range users {
+		result = append(result, domain.UserAccess{
+			ID: domain.UserIdentity{
+				CompanyID: user.CompanyID,
+				UserID:    user.UserID,
+			},
+			AccessToken:  user.AccessToken,
+			RefreshToken: user.RefreshToken,
+			TokenType:    user.TokenType,
+			Scope:        user.Scope,
+			ExpiresAt:    user.ExpiresAt,
+			ApiDomain:    user.ApiDomain,
+		})
+	}
+
+	return result, nil
+}
+
+// RotateUser replaces the encrypted tokens of a user record only if they still match
+// the previously selected ones, so that tokens refreshed in the meantime are never overwritten.
+func (m *mongoUserKeyRotationAdapter) RotateUser(ctx context.Context, previous, rotated domain.UserAccess) error {
+	if err := rotated.Validate(); err != nil {
+		return err
+	}
+
+	return mgm.Transaction(func(session mongo.Session, sc mongo.SessionContext) error {
+		u := &userAccessCollection{}
+		collection := mgm.Coll(&userAccessCollection{})
+		if err := collection.FirstWithCtx(sc, identityFilter(previous.ID), u); err != nil {
+			return err
+		}
+
+		if u.AccessToken != previous.AccessToken || u.RefreshToken != previous.RefreshToken {
+			return ErrUserModified
+		}
+
+		u.AccessToken = rotated.AccessToken
+		u.RefreshToken = rotated.RefreshToken
+		u.UpdatedAt = time.Now()
+
+		if err := collection.UpdateWithCtx(sc, u); err != nil {
+			return err
+		}
+
+		return session.CommitTransaction(sc)
+	})
+}
@@ -0,0 +1,92 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	AuditActionUninstall = "uninstall"
+	AuditScopeUser       = "user"
+	AuditScopeCompany    = "company"
+)
+
+// AuditEvent records an administrative action performed on behalf of a Pipedrive user.
+// Events are identified by their source so that retried actions are recorded once.
+type AuditEvent struct {
+	ID           string    `json:"id" mapstructure:"id"`
+	CompanyID    int       `json:"company_id" mapstructure:"company_id"`
+	UserID       int       `json:"user_id" mapstructure:"user_id"`
+	Action       string    `json:"action" mapstructure:"action"`
+	Scope        string    `json:"scope" mapstructure:"scope"`
+	RemovedUsers int       `json:"removed_users" mapstructure:"removed_users"`
+	CreatedAt    time.Time `json:"created_at" mapstructure:"created_at"`
+}
+
+func NewUninstallEvent(id UserIdentity, timestamp, scope string, removed int) AuditEvent {
+	return AuditEvent{
+		ID:           fmt.Sprintf("%s:%s:%s", AuditActionUninstall, id.Key(), strings.TrimSpace(timestamp)),
+		CompanyID:    id.CompanyID,
+		UserID:       id.UserID,
+		Action:       AuditActionUninstall,
+		Scope:        scope,
+		RemovedUsers: removed,
+		CreatedAt:    time.Now(),
+	}
+}
+
+func (e AuditEvent) ToJSON() []byte {
+	buf, _ := json.Marshal(e)
+	return buf
+}
+
+func (e *AuditEvent) Validate() error {
+	e.ID = strings.TrimSpace(e.ID)
+	e.Action = strings.TrimSpace(e.Action)
+
+	if e.ID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Audit",
+			Field:  "ID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if e.CompanyID <= 0 {
+		return &InvalidModelFieldError{
+			Model:  "Audit",
+			Field:  "Company ID",
+			Reason: "Should be a positive number",
+		}
+	}
+
+	if e.Action == "" {
+		return &InvalidModelFieldError{
+			Model:  "Audit",
+			Field:  "Action",
+			Reason: "Should not be empty",
+		}
+	}
+
+	return nil
+}
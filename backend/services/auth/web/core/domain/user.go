@@ -20,17 +20,48 @@ package domain
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 )
 
+type UserIdentity struct {
+	CompanyID int `json:"company_id" mapstructure:"company_id"`
+	UserID    int `json:"user_id" mapstructure:"user_id"`
+}
+
+func (i UserIdentity) Key() string {
+	return fmt.Sprintf("%d:%d", i.CompanyID, i.UserID)
+}
+
+func (i UserIdentity) Validate() error {
+	if i.CompanyID <= 0 {
+		return &InvalidModelFieldError{
+			Model:  "User",
+			Field:  "Company ID",
+			Reason: "Should be a positive number",
+		}
+	}
+
+	if i.UserID <= 0 {
+		return &InvalidModelFieldError{
+			Model:  "User",
+			Field:  "User ID",
+			Reason: "Should be a positive number",
+		}
+	}
+
+	return nil
+}
+
 type UserAccess struct {
-	ID           string `json:"id" mapstructure:"id"`
-	AccessToken  string `json:"access_token" mapstructure:"access_token"`
-	RefreshToken string `json:"refresh_token" mapstructure:"refresh_token"`
-	TokenType    string `json:"token_type" mapstructure:"token_type"`
-	Scope        string `json:"scope" mapstructure:"scope"`
-	ExpiresAt    int64  `json:"expires_at" mapstructure:"expires_at"`
-	ApiDomain    string `json:"api_domain" mapstructure:"api_domain"`
+	ID           UserIdentity `json:"id" mapstructure:"id"`
+	AccessToken  string       `json:"access_token" mapstructure:"access_token"`
+	RefreshToken string       `json:"refresh_token" mapstructure:"refresh_token"`
+	TokenType    string       `json:"token_type" mapstructure:"token_type"`
+	Scope        string       `json:"scope" mapstructure:"scope"`
+	ExpiresAt    int64        `json:"expires_at" mapstructure:"expires_at"`
+	ApiDomain    string       `json:"api_domain" mapstructure:"api_domain"`
+	Revoked      bool         `json:"revoked" mapstructure:"revoked"`
 }
 
 func (u UserAccess) ToJSON() []byte {
@@ -39,19 +70,14 @@ func (u UserAccess) ToJSON() []byte {
 }
 
 func (u *UserAccess) Validate() error {
-	u.ID = strings.TrimSpace(u.ID)
 	u.AccessToken = strings.TrimSpace(u.AccessToken)
 	u.RefreshToken = strings.TrimSpace(u.RefreshToken)
 	u.TokenType = strings.TrimSpace(u.TokenType)
 	u.Scope = strings.TrimSpace(u.Scope)
 	u.ApiDomain = strings.TrimSpace(u.ApiDomain)
 
-	if u.ID == "" {
-		return &InvalidModelFieldError{
-			Model:  "User",
-			Field:  "ID",
-			Reason: "Should not be empty",
-		}
+	if err := u.ID.Validate(); err != nil {
+		return err
 	}
 
 	if u.AccessToken == "" {
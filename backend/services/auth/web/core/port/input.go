@@ -20,13 +20,35 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
 )
 
 type UserAccessService interface {
 	CreateUser(ctx context.Context, user domain.UserAccess) error
-	GetUser(ctx context.Context, uid string) (domain.UserAccess, error)
+	GetUser(ctx context.Context, id domain.UserIdentity) (domain.UserAccess, error)
+	ReloadUser(ctx context.Context, id domain.UserIdentity) (domain.UserAccess, error)
 	UpdateUser(ctx context.Context, user domain.UserAccess) (domain.UserAccess, error)
-	RemoveUser(ctx context.Context, uid string) error
+	RemoveUser(ctx context.Context, id domain.UserIdentity) error
+	GetCompanyUsers(ctx context.Context, cid int) ([]domain.UserAccess, error)
+	GetExpiringUsers(ctx context.Context, before time.Time, limit int) ([]domain.UserAccess, error)
+	RevokeUser(ctx context.Context, id domain.UserIdentity) error
+}
+
+type AuditService interface {
+	RecordEvent(ctx context.Context, event domain.AuditEvent) error
+}
+
+type UserRefreshService interface {
+	RefreshUser(ctx context.Context, user domain.UserAccess) (domain.UserAccess, error)
+	RefreshExpiringUsers(ctx context.Context) (int, error)
+}
+
+type UserMigrationService interface {
+	MigrateUsers(ctx context.Context) (int, error)
+}
+
+type UserKeyRotationService interface {
+	RotateUsers(ctx context.Context) (int, error)
 }
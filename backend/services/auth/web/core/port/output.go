@@ -20,13 +20,36 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
 )
 
 type UserAccessServiceAdapter interface {
 	InsertUser(ctx context.Context, user domain.UserAccess) error
-	SelectUser(ctx context.Context, uid string) (domain.UserAccess, error)
+	SelectUser(ctx context.Context, id domain.UserIdentity) (domain.UserAccess, error)
 	UpsertUser(ctx context.Context, user domain.UserAccess) (domain.UserAccess, error)
-	DeleteUser(ctx context.Context, uid string) error
+	DeleteUser(ctx context.Context, id domain.UserIdentity) error
+	SelectCompanyUsers(ctx context.Context, cid int) ([]domain.UserAccess, error)
+	SelectExpiringUsers(ctx context.Context, before int64, limit int) ([]domain.UserAccess, error)
+	RevokeUser(ctx context.Context, id domain.UserIdentity) error
+}
+
+type UserMigrationAdapter interface {
+	SelectLegacyUsers(ctx context.Context) (map[string]domain.UserAccess, error)
+	MigrateUser(ctx context.Context, legacyID string, user domain.UserAccess) error
+}
+
+type UserKeyRotationAdapter interface {
+	SelectUsers(ctx context.Context) ([]domain.UserAccess, error)
+	RotateUser(ctx context.Context, previous, rotated domain.UserAccess) error
+}
+
+type UserLeaseAdapter interface {
+	AcquireLease(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, key, owner string) error
+}
+
+type AuditEventAdapter interface {
+	InsertEvent(ctx context.Context, event domain.AuditEvent) error
 }
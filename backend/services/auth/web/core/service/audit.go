@@ -0,0 +1,52 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/port"
+)
+
+type auditService struct {
+	adapter port.AuditEventAdapter
+	logger  plog.Logger
+}
+
+func NewAuditService(
+	adapter port.AuditEventAdapter,
+	logger plog.Logger,
+) port.AuditService {
+	return auditService{
+		adapter: adapter,
+		logger:  logger,
+	}
+}
+
+func (s auditService) RecordEvent(ctx context.Context, event domain.AuditEvent) error {
+	s.logger.Debugf("validating audit event %s to perform a persist action", event.ID)
+	if err := event.Validate(); err != nil {
+		return err
+	}
+
+	s.logger.Infof("recording %s audit event for company %d (scope: %s)", event.Action, event.CompanyID, event.Scope)
+	return s.adapter.InsertEvent(ctx, event)
+}
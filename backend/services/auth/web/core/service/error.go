@@ -23,7 +23,11 @@ import (
 	"fmt"
 )
 
-var ErrOperationTimeout = errors.New("operation timeout")
+var (
+	ErrOperationTimeout = errors.New("operation timeout")
+	ErrUserRevoked      = errors.New("user refresh token has been revoked")
+	ErrRefreshLocked    = errors.New("user token refresh is locked by another instance")
+)
 
 type InvalidServiceParameterError struct {
 	Name   string
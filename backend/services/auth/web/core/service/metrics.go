@@ -0,0 +1,45 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	tokenRefreshes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "pipedrive",
+		Subsystem: "auth",
+		Name:      "token_refreshes_total",
+		Help:      "Number of OAuth token refreshes partitioned by result (success, failure, revoked).",
+	}, []string{"result"})
+	tokenRefreshRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "pipedrive",
+		Subsystem: "auth",
+		Name:      "token_refresh_runs_total",
+		Help:      "Number of scheduled token refresh runs partitioned by result (success, failure).",
+	}, []string{"result"})
+	tokenRefreshLastRun = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "pipedrive",
+		Subsystem: "auth",
+		Name:      "token_refresh_last_run_timestamp_seconds",
+		Help:      "Unix time of the last completed scheduled token refresh run.",
+	})
+)
@@ -0,0 +1,139 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"time"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/port"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
+)
+
+type userMigrationService struct {
+	adapter       port.UserMigrationAdapter
+	keyring       keyring.Keyring
+	pipedriveAPI  pclient.PipedriveApiClient
+	pipedriveAuth pclient.PipedriveAuthClient
+	logger        plog.Logger
+}
+
+func NewUserMigrationService(
+	adapter port.UserMigrationAdapter,
+	keyring keyring.Keyring,
+	pipedriveAPI pclient.PipedriveApiClient,
+	pipedriveAuth pclient.PipedriveAuthClient,
+	logger plog.Logger,
+) port.UserMigrationService {
+	return userMigrationService{
+		adapter:       adapter,
+		keyring:       keyring,
+		pipedriveAPI:  pipedriveAPI,
+		pipedriveAuth: pipedriveAuth,
+		logger:        logger,
+	}
+}
+
+// MigrateUsers rewrites records keyed by the sum of user and company ids into
+// records keyed by both ids. The real ids are resolved via Pipedrive's /users/me.
+// Records that cannot be resolved are left untouched so that the migration may be retried.
+func (s userMigrationService) MigrateUsers(ctx context.Context) (int, error) {
+	users, err := s.adapter.SelectLegacyUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	s.logger.Debugf("found %d legacy user records to migrate", len(users))
+
+	migrated := 0
+	for legacyID, user := range users {
+		if err := s.migrateUser(ctx, legacyID, user); err != nil {
+			s.logger.Warnf("could not migrate legacy user %s: %s", legacyID, err.Error())
+			continue
+		}
+
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+func (s userMigrationService) migrateUser(ctx context.Context, legacyID string, user domain.UserAccess) error {
+	aToken, err := s.keyring.Decrypt(user.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	rToken, err := s.keyring.Decrypt(user.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	token := model.Token{
+		AccessToken:  aToken,
+		RefreshToken: rToken,
+		TokenType:    user.TokenType,
+		Scope:        user.Scope,
+		ApiDomain:    user.ApiDomain,
+	}
+
+	expiresAt := user.ExpiresAt
+	if expiresAt <= time.Now().UnixMilli() {
+		s.logger.Debugf("legacy user %s token has expired. Trying to refresh", legacyID)
+		token, err = s.pipedriveAuth.RefreshAccessToken(ctx, rToken)
+		if err != nil {
+			return err
+		}
+
+		expiresAt = time.Now().Local().Add(time.Second * time.Duration(token.ExpiresIn-700)).UnixMilli()
+	}
+
+	usr, err := s.pipedriveAPI.GetMe(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	aToken, err = s.keyring.Encrypt(token.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	rToken, err = s.keyring.Encrypt(token.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debugf("migrating legacy user %s to %d:%d", legacyID, usr.CompanyID, usr.ID)
+	return s.adapter.MigrateUser(ctx, legacyID, domain.UserAccess{
+		ID: domain.UserIdentity{
+			CompanyID: usr.CompanyID,
+			UserID:    usr.ID,
+		},
+		AccessToken:  aToken,
+		RefreshToken: rToken,
+		TokenType:    token.TokenType,
+		Scope:        token.Scope,
+		ExpiresAt:    expiresAt,
+		ApiDomain:    token.ApiDomain,
+	})
+}
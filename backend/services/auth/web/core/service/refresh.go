@@ -0,0 +1,197 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+)
+
+const leasePollInterval = 200 * time.Millisecond
+
+type userRefreshService struct {
+	service       port.UserAccessService
+	lease         port.UserLeaseAdapter
+	pipedriveAuth pclient.PipedriveAuthClient
+	config        *shared.TokenRefresherConfig
+	group         *singleflight.Group
+	logger        plog.Logger
+}
+
+func NewUserRefreshService(
+	service port.UserAccessService,
+	lease port.UserLeaseAdapter,
+	pipedriveAuth pclient.PipedriveAuthClient,
+	config *shared.TokenRefresherConfig,
+	logger plog.Logger,
+) port.UserRefreshService {
+	return userRefreshService{
+		service:       service,
+		lease:         lease,
+		pipedriveAuth: pipedriveAuth,
+		config:        config,
+		group:         &singleflight.Group{},
+		logger:        logger,
+	}
+}
+
+// RefreshUser exchanges the user's refresh token for a new token pair. Concurrent refreshes
+// of the same user are collapsed within the process and serialized across replicas by a lease.
+// The record is re-read once the lease is held, and a user refreshed in the meantime is returned
+// as is, since Pipedrive invalidates a refresh token once it has been used.
+func (s userRefreshService) RefreshUser(ctx context.Context, user domain.UserAccess) (domain.UserAccess, error) {
+	res, err, _ := s.group.Do(user.ID.Key(), func() (interface{}, error) {
+		release, err := s.acquireLease(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		current, err := s.service.ReloadUser(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if current.Revoked {
+			return nil, ErrUserRevoked
+		}
+
+		if current.RefreshToken != user.RefreshToken {
+			s.logger.Debugf("user %s token has already been refreshed", user.ID.Key())
+			return current, nil
+		}
+
+		token, err := s.pipedriveAuth.RefreshAccessToken(ctx, current.RefreshToken)
+		if err != nil {
+			if errors.Is(err, pclient.ErrRefreshTokenRevoked) {
+				tokenRefreshes.WithLabelValues("revoked").Inc()
+				s.logger.Warnf("user %s refresh token has been revoked", user.ID.Key())
+				if err := s.service.RevokeUser(ctx, user.ID); err != nil {
+					s.logger.Errorf("could not mark user %s as revoked: %s", user.ID.Key(), err.Error())
+				}
+
+				return nil, ErrUserRevoked
+			}
+
+			tokenRefreshes.WithLabelValues("failure").Inc()
+			return nil, err
+		}
+
+		access := domain.UserAccess{
+			ID:           current.ID,
+			AccessToken:  token.AccessToken,
+			RefreshToken: token.RefreshToken,
+			TokenType:    token.TokenType,
+			Scope:        token.Scope,
+			ApiDomain:    token.ApiDomain,
+			ExpiresAt:    time.Now().Local().Add(time.Second * time.Duration(token.ExpiresIn-700)).UnixMilli(),
+		}
+
+		if _, err := s.service.UpdateUser(ctx, access); err != nil {
+			tokenRefreshes.WithLabelValues("failure").Inc()
+			return nil, err
+		}
+
+		tokenRefreshes.WithLabelValues("success").Inc()
+		s.logger.Debugf("user's %s token has been refreshed", user.ID.Key())
+		return access, nil
+	})
+
+	if usr, ok := res.(domain.UserAccess); ok {
+		return usr, nil
+	}
+
+	return domain.UserAccess{}, err
+}
+
+// acquireLease waits for the user's refresh lease up to the configured lock wait.
+// The returned function releases the lease.
+func (s userRefreshService) acquireLease(ctx context.Context, id domain.UserIdentity) (func(), error) {
+	key := fmt.Sprintf("refresh:%s", id.Key())
+	owner := uuid.NewString()
+	ttl := time.Duration(s.config.Refresher.LockTTL) * time.Second
+	deadline := time.Now().Add(time.Duration(s.config.Refresher.LockWait) * time.Second)
+
+	for {
+		acquired, err := s.lease.AcquireLease(ctx, key, owner, ttl)
+		if err != nil {
+			return nil, err
+		}
+
+		if acquired {
+			return func() {
+				// the lease is released even if the refresh context has been canceled
+				rctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+				defer cancel()
+				if err := s.lease.ReleaseLease(rctx, key, owner); err != nil {
+					s.logger.Warnf("could not release user %s refresh lease: %s", id.Key(), err.Error())
+				}
+			}, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, ErrRefreshLocked
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(leasePollInterval):
+		}
+	}
+}
+
+// RefreshExpiringUsers refreshes a batch of users whose tokens expire within the configured window.
+// Failed refreshes are logged and retried on the next run.
+func (s userRefreshService) RefreshExpiringUsers(ctx context.Context) (int, error) {
+	window := time.Duration(s.config.Refresher.Window) * time.Second
+	users, err := s.service.GetExpiringUsers(ctx, time.Now().Add(window), s.config.Refresher.BatchSize)
+	if err != nil {
+		tokenRefreshRuns.WithLabelValues("failure").Inc()
+		return 0, err
+	}
+
+	refreshed := 0
+	for _, user := range users {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if _, err := s.RefreshUser(ctx, user); err != nil {
+			s.logger.Warnf("could not refresh user %s token: %s", user.ID.Key(), err.Error())
+			continue
+		}
+
+		refreshed++
+	}
+
+	tokenRefreshRuns.WithLabelValues("success").Inc()
+	tokenRefreshLastRun.SetToCurrentTime()
+	return refreshed, nil
+}
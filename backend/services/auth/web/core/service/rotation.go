@@ -0,0 +1,89 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
+)
+
+type userKeyRotationService struct {
+	adapter port.UserKeyRotationAdapter
+	keyring keyring.Keyring
+	logger  plog.Logger
+}
+
+func NewUserKeyRotationService(
+	adapter port.UserKeyRotationAdapter,
+	keyring keyring.Keyring,
+	logger plog.Logger,
+) port.UserKeyRotationService {
+	return userKeyRotationService{
+		adapter: adapter,
+		keyring: keyring,
+		logger:  logger,
+	}
+}
+
+// RotateUsers re-encrypts user tokens that were not encrypted with the active key.
+func (s userKeyRotationService) RotateUsers(ctx context.Context) (int, error) {
+	users, err := s.adapter.SelectUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return keyring.RecordRotation[domain.UserAccess]{
+		Keyring: s.keyring,
+		Logger:  s.logger,
+		Describe: func(user domain.UserAccess) string {
+			return fmt.Sprintf("user %s tokens", user.ID.Key())
+		},
+		Ciphertexts: func(user domain.UserAccess) []string {
+			return []string{user.AccessToken, user.RefreshToken}
+		},
+		Rotate: s.rotateUser,
+	}.Run(ctx, users)
+}
+
+func (s userKeyRotationService) rotateUser(ctx context.Context, user domain.UserAccess) error {
+	aToken, err := keyring.Reencrypt(s.keyring, user.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	rToken, err := keyring.Reencrypt(s.keyring, user.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return s.adapter.RotateUser(ctx, user, domain.UserAccess{
+		ID:           user.ID,
+		AccessToken:  aToken,
+		RefreshToken: rToken,
+		TokenType:    user.TokenType,
+		Scope:        user.Scope,
+		ExpiresAt:    user.ExpiresAt,
+		ApiDomain:    user.ApiDomain,
+	})
+}
@@ -0,0 +1,98 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+type mockRotationAdapter struct {
+	users   []domain.UserAccess
+	failing map[int]bool
+	rotated []domain.UserAccess
+}
+
+func (m *mockRotationAdapter) SelectUsers(ctx context.Context) ([]domain.UserAccess, error) {
+	return m.users, nil
+}
+
+func (m *mockRotationAdapter) RotateUser(ctx context.Context, previous, rotated domain.UserAccess) error {
+	if m.failing[previous.ID.UserID] {
+		return errors.New("could not rotate")
+	}
+
+	m.rotated = append(m.rotated, rotated)
+	return nil
+}
+
+func TestUserKeyRotationService(t *testing.T) {
+	var config keyring.KeyringConfig
+	config.Keyring.Active = "v1"
+	config.Keyring.Keys = []keyring.KeyConfig{{ID: "v1", Value: "rotation-key-0123456789abcdefghi"}}
+	ring, err := keyring.NewKeyring(&config, mockEncryptor{}, &oauth2.Config{ClientSecret: "mock"})
+	assert.NoError(t, err)
+
+	newUser := func(uid int, token string) domain.UserAccess {
+		return domain.UserAccess{
+			ID:           domain.UserIdentity{CompanyID: 1, UserID: uid},
+			AccessToken:  token,
+			RefreshToken: token,
+		}
+	}
+
+	t.Run("rotate users encrypted with previous keys", func(t *testing.T) {
+		adapter := &mockRotationAdapter{users: []domain.UserAccess{
+			newUser(1, "legacy"),
+			newUser(2, "v1:current"),
+		}}
+
+		rotated, err := NewUserKeyRotationService(adapter, ring, log.NewEmptyLogger()).RotateUsers(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, rotated)
+		assert.Len(t, adapter.rotated, 1)
+		assert.Equal(t, "v1:legacy", adapter.rotated[0].AccessToken)
+	})
+
+	t.Run("report users that could not be rotated", func(t *testing.T) {
+		adapter := &mockRotationAdapter{
+			users: []domain.UserAccess{
+				newUser(1, "legacy"),
+				newUser(2, "legacy"),
+				newUser(3, "legacy"),
+			},
+			failing: map[int]bool{2: true},
+		}
+
+		rotated, err := NewUserKeyRotationService(adapter, ring, log.NewEmptyLogger()).RotateUsers(context.Background())
+		assert.Equal(t, 2, rotated)
+
+		var rerr *keyring.RotationError
+		assert.ErrorAs(t, err, &rerr)
+		assert.Equal(t, 1, rerr.Failed)
+		assert.Equal(t, 3, rerr.Total)
+	})
+}
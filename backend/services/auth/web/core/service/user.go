@@ -20,59 +20,54 @@ package service
 
 import (
 	"context"
-	"strings"
 	"time"
 
-	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
 	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
 	"github.com/mitchellh/mapstructure"
 	"go-micro.dev/v4/cache"
-	"golang.org/x/oauth2"
 )
 
 type userService struct {
-	adapter     port.UserAccessServiceAdapter
-	encryptor   crypto.Encryptor
-	cache       cache.Cache
-	credentials *oauth2.Config
-	logger      plog.Logger
+	adapter port.UserAccessServiceAdapter
+	keyring keyring.Keyring
+	cache   cache.Cache
+	logger  plog.Logger
 }
 
 func NewUserService(
 	adapter port.UserAccessServiceAdapter,
-	encryptor crypto.Encryptor,
+	keyring keyring.Keyring,
 	cache cache.Cache,
-	credentials *oauth2.Config,
 	logger plog.Logger,
 ) port.UserAccessService {
 	return userService{
-		adapter:     adapter,
-		encryptor:   encryptor,
-		cache:       cache,
-		credentials: credentials,
-		logger:      logger,
+		adapter: adapter,
+		keyring: keyring,
+		cache:   cache,
+		logger:  logger,
 	}
 }
 
 func (s userService) CreateUser(ctx context.Context, user domain.UserAccess) error {
-	s.logger.Debugf("validating user %s to perform a persist action", user.ID)
+	s.logger.Debugf("validating user %s to perform a persist action", user.ID.Key())
 	if err := user.Validate(); err != nil {
 		return err
 	}
 
-	aToken, err := s.encryptor.Encrypt(user.AccessToken, []byte(s.credentials.ClientSecret))
+	aToken, err := s.keyring.Encrypt(user.AccessToken)
 	if err != nil {
 		return err
 	}
 
-	rToken, err := s.encryptor.Encrypt(user.RefreshToken, []byte(s.credentials.ClientSecret))
+	rToken, err := s.keyring.Encrypt(user.RefreshToken)
 	if err != nil {
 		return err
 	}
 
-	s.logger.Debugf("user %s is valid. Persisting to database: %s", user.ID, user.AccessToken)
+	s.logger.Debugf("user %s is valid. Persisting to database", user.ID.Key())
 	if err := s.adapter.InsertUser(ctx, domain.UserAccess{
 		ID:           user.ID,
 		AccessToken:  aToken,
@@ -88,21 +83,19 @@ func (s userService) CreateUser(ctx context.Context, user domain.UserAccess) err
 	return nil
 }
 
-func (s userService) GetUser(ctx context.Context, uid string) (domain.UserAccess, error) {
-	s.logger.Debugf("trying to select user with id: %s", uid)
-	id := strings.TrimSpace(uid)
-
-	if id == "" {
+func (s userService) GetUser(ctx context.Context, id domain.UserIdentity) (domain.UserAccess, error) {
+	s.logger.Debugf("trying to select user with id: %s", id.Key())
+	if err := id.Validate(); err != nil {
 		return domain.UserAccess{}, &InvalidServiceParameterError{
-			Name:   "UID",
-			Reason: "Should not be blank",
+			Name:   "ID",
+			Reason: err.Error(),
 		}
 	}
 
 	var user domain.UserAccess
 	var err error
-	if res, _, err := s.cache.Get(ctx, id); err == nil && res != nil {
-		s.logger.Debugf("found user %s in the cache", id)
+	if res, _, err := s.cache.Get(ctx, id.Key()); err == nil && res != nil {
+		s.logger.Debugf("found user %s in the cache", id.Key())
 		if err := mapstructure.Decode(res, &user); err != nil {
 			s.logger.Errorf("could not decode from cache: %s", err.Error())
 		}
@@ -114,17 +107,17 @@ func (s userService) GetUser(ctx context.Context, uid string) (domain.UserAccess
 			return user, err
 		}
 
-		s.cache.Put(ctx, id, user, time.Duration((user.ExpiresAt-time.Now().UnixMilli())*1e6/6))
+		s.cache.Put(ctx, id.Key(), user, time.Duration((user.ExpiresAt-time.Now().UnixMilli())*1e6/6))
 	}
 
 	s.logger.Debugf("found a user: %v", user)
 
-	aToken, err := s.encryptor.Decrypt(user.AccessToken, []byte(s.credentials.ClientSecret))
+	aToken, err := s.keyring.Decrypt(user.AccessToken)
 	if err != nil {
 		return domain.UserAccess{}, err
 	}
 
-	rToken, err := s.encryptor.Decrypt(user.RefreshToken, []byte(s.credentials.ClientSecret))
+	rToken, err := s.keyring.Decrypt(user.RefreshToken)
 	if err != nil {
 		return domain.UserAccess{}, err
 	}
@@ -137,21 +130,28 @@ func (s userService) GetUser(ctx context.Context, uid string) (domain.UserAccess
 		Scope:        user.Scope,
 		ExpiresAt:    user.ExpiresAt,
 		ApiDomain:    user.ApiDomain,
+		Revoked:      user.Revoked,
 	}, nil
 }
 
+// ReloadUser bypasses the cache, which may hold tokens already replaced by another replica.
+func (s userService) ReloadUser(ctx context.Context, id domain.UserIdentity) (domain.UserAccess, error) {
+	s.cache.Delete(ctx, id.Key())
+	return s.GetUser(ctx, id)
+}
+
 func (s userService) UpdateUser(ctx context.Context, user domain.UserAccess) (domain.UserAccess, error) {
-	s.logger.Debugf("validating user %s to perform an update action", user.ID)
+	s.logger.Debugf("validating user %s to perform an update action", user.ID.Key())
 	if err := user.Validate(); err != nil {
 		return domain.UserAccess{}, err
 	}
 
-	aToken, err := s.encryptor.Encrypt(user.AccessToken, []byte(s.credentials.ClientSecret))
+	aToken, err := s.keyring.Encrypt(user.AccessToken)
 	if err != nil {
 		return user, err
 	}
 
-	rToken, err := s.encryptor.Encrypt(user.RefreshToken, []byte(s.credentials.ClientSecret))
+	rToken, err := s.keyring.Encrypt(user.RefreshToken)
 	if err != nil {
 		return user, err
 	}
@@ -166,12 +166,12 @@ func (s userService) UpdateUser(ctx context.Context, user domain.UserAccess) (do
 		ApiDomain:    user.ApiDomain,
 	}
 
-	if err := s.cache.Put(ctx, euser.ID, euser, time.Duration((euser.ExpiresAt-time.Now().UnixMilli())*1e6/6)); err != nil {
-		s.logger.Warnf("could not populate cache with a user %s instance: %s", euser.ID, err.Error())
-		s.cache.Delete(ctx, euser.ID)
+	if err := s.cache.Put(ctx, euser.ID.Key(), euser, time.Duration((euser.ExpiresAt-time.Now().UnixMilli())*1e6/6)); err != nil {
+		s.logger.Warnf("could not populate cache with a user %s instance: %s", euser.ID.Key(), err.Error())
+		s.cache.Delete(ctx, euser.ID.Key())
 	}
 
-	s.logger.Debugf("user %s is valid to perform an update action", user.ID)
+	s.logger.Debugf("user %s is valid to perform an update action", user.ID.Key())
 	if _, err := s.adapter.UpsertUser(ctx, euser); err != nil {
 		return user, err
 	}
@@ -179,21 +179,102 @@ func (s userService) UpdateUser(ctx context.Context, user domain.UserAccess) (do
 	return user, nil
 }
 
-func (s userService) RemoveUser(ctx context.Context, uid string) error {
-	id := strings.TrimSpace(uid)
-	s.logger.Debugf("validating uid %s to perform a delete action", id)
+func (s userService) RemoveUser(ctx context.Context, id domain.UserIdentity) error {
+	s.logger.Debugf("validating id %s to perform a delete action", id.Key())
+	if err := id.Validate(); err != nil {
+		return &InvalidServiceParameterError{
+			Name:   "ID",
+			Reason: err.Error(),
+		}
+	}
+
+	if err := s.cache.Delete(ctx, id.Key()); err != nil {
+		return err
+	}
+
+	s.logger.Debugf("id %s is valid to perform a delete action", id.Key())
+	return s.adapter.DeleteUser(ctx, id)
+}
+
+// GetCompanyUsers returns all users of a company. Tokens that cannot be decrypted are left
+// blank so that the records can still be cleaned up.
+func (s userService) GetCompanyUsers(ctx context.Context, cid int) ([]domain.UserAccess, error) {
+	if cid <= 0 {
+		return nil, &InvalidServiceParameterError{
+			Name:   "CID",
+			Reason: "Should be a positive number",
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, ErrOperationTimeout
+	}
+
+	users, err := s.adapter.SelectCompanyUsers(ctx, cid)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range users {
+		aToken, aerr := s.keyring.Decrypt(users[i].AccessToken)
+		rToken, rerr := s.keyring.Decrypt(users[i].RefreshToken)
+		if aerr != nil || rerr != nil {
+			s.logger.Warnf("could not decrypt user %s tokens", users[i].ID.Key())
+			aToken, rToken = "", ""
+		}
 
-	if id == "" {
+		users[i].AccessToken = aToken
+		users[i].RefreshToken = rToken
+	}
+
+	return users, nil
+}
+
+func (s userService) GetExpiringUsers(ctx context.Context, before time.Time, limit int) ([]domain.UserAccess, error) {
+	if ctx.Err() != nil {
+		return nil, ErrOperationTimeout
+	}
+
+	users, err := s.adapter.SelectExpiringUsers(ctx, before.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.UserAccess, 0, len(users))
+	for _, user := range users {
+		aToken, err := s.keyring.Decrypt(user.AccessToken)
+		if err != nil {
+			s.logger.Warnf("could not decrypt user %s access token: %s", user.ID.Key(), err.Error())
+			continue
+		}
+
+		rToken, err := s.keyring.Decrypt(user.RefreshToken)
+		if err != nil {
+			s.logger.Warnf("could not decrypt user %s refresh token: %s", user.ID.Key(), err.Error())
+			continue
+		}
+
+		user.AccessToken = aToken
+		user.RefreshToken = rToken
+		result = append(result, user)
+	}
+
+	return result, nil
+}
+
+func (s userService) RevokeUser(ctx context.Context, id domain.UserIdentity) error {
+	s.logger.Debugf("validating id %s to perform a revoke action", id.Key())
+	if err := id.Validate(); err != nil {
 		return &InvalidServiceParameterError{
-			Name:   "UID",
-			Reason: "Should not be blank",
+			Name:   "ID",
+			Reason: err.Error(),
 		}
 	}
 
-	if err := s.cache.Delete(ctx, uid); err != nil {
+	if err := s.adapter.RevokeUser(ctx, id); err != nil {
 		return err
 	}
 
-	s.logger.Debugf("uid %s is valid to perform a delete action", id)
-	return s.adapter.DeleteUser(ctx, uid)
+	s.cache.Delete(ctx, id.Key())
+	return nil
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
 	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/oauth2"
 )
@@ -44,8 +45,13 @@ func (e mockEncryptor) Decrypt(ciphertext string, key []byte) (string, error) {
 type mockAdapter struct {
 }
 
+var id = domain.UserIdentity{
+	CompanyID: 1,
+	UserID:    1,
+}
+
 var user = domain.UserAccess{
-	ID:           "mock",
+	ID:           id,
 	AccessToken:  "mock",
 	RefreshToken: "mock",
 	TokenType:    "mock",
@@ -58,33 +64,46 @@ func (m mockAdapter) InsertUser(ctx context.Context, user domain.UserAccess) err
 	return nil
 }
 
-func (m mockAdapter) SelectUser(ctx context.Context, uid string) (domain.UserAccess, error) {
-	return user, nil
+func (m mockAdapter) SelectUser(ctx context.Context, id domain.UserIdentity) (domain.UserAccess, error) {
+	return user, ctx.Err()
 }
 
 func (m mockAdapter) UpsertUser(ctx context.Context, user domain.UserAccess) (domain.UserAccess, error) {
 	return domain.UserAccess{
-		ID:          "mock",
+		ID:          id,
 		AccessToken: "mock",
-	}, nil
+	}, ctx.Err()
+}
+
+func (m mockAdapter) DeleteUser(ctx context.Context, id domain.UserIdentity) error {
+	return nil
 }
 
-func (m mockAdapter) DeleteUser(ctx context.Context, uid string) error {
+func (m mockAdapter) SelectCompanyUsers(ctx context.Context, cid int) ([]domain.UserAccess, error) {
+	return []domain.UserAccess{user}, nil
+}
+
+func (m mockAdapter) SelectExpiringUsers(ctx context.Context, before int64, limit int) ([]domain.UserAccess, error) {
+	return []domain.UserAccess{user}, nil
+}
+
+func (m mockAdapter) RevokeUser(ctx context.Context, id domain.UserIdentity) error {
 	return nil
 }
 
 func TestUserService(t *testing.T) {
-	service := NewUserService(mockAdapter{}, mockEncryptor{}, cache.NewCache(&config.CacheConfig{}), &oauth2.Config{
+	ring, _ := keyring.NewKeyring(&keyring.KeyringConfig{}, mockEncryptor{}, &oauth2.Config{
 		ClientID:     "mock",
 		ClientSecret: "mock",
-	}, log.NewEmptyLogger())
+	})
+	service := NewUserService(mockAdapter{}, ring, cache.NewCache(&config.CacheConfig{}), log.NewEmptyLogger())
 
 	t.Run("save user", func(t *testing.T) {
 		assert.NoError(t, service.CreateUser(context.Background(), user))
 	})
 
 	t.Run("get user", func(t *testing.T) {
-		u, err := service.GetUser(context.Background(), "mock")
+		u, err := service.GetUser(context.Background(), id)
 		assert.NoError(t, err)
 		assert.Equal(t, user, u)
 	})
@@ -92,13 +111,13 @@ func TestUserService(t *testing.T) {
 	t.Run("get user with timeout", func(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 0*time.Second)
 		defer cancel()
-		_, err := service.GetUser(ctx, "mock")
+		_, err := service.GetUser(ctx, id)
 		assert.Error(t, err)
 	})
 
 	t.Run("update user token", func(t *testing.T) {
 		_, err := service.UpdateUser(context.Background(), domain.UserAccess{
-			ID:           "mock",
+			ID:           id,
 			AccessToken:  "mock",
 			RefreshToken: "mock",
 			TokenType:    "mock",
@@ -113,7 +132,7 @@ func TestUserService(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 0*time.Second)
 		defer cancel()
 		_, err := service.UpdateUser(ctx, domain.UserAccess{
-			ID:           "mock",
+			ID:           id,
 			AccessToken:  "mock",
 			RefreshToken: "mock",
 			TokenType:    "mock",
@@ -125,6 +144,6 @@ func TestUserService(t *testing.T) {
 	})
 
 	t.Run("delete user", func(t *testing.T) {
-		assert.NoError(t, service.RemoveUser(context.Background(), "mock"))
+		assert.NoError(t, service.RemoveUser(context.Background(), id))
 	})
 }
@@ -22,34 +22,51 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
 	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/port"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
 	"go-micro.dev/v4/client"
 )
 
 type UserDeleteHandler struct {
-	service port.UserAccessService
-	client  client.Client
-	logger  log.Logger
+	service       port.UserAccessService
+	audit         port.AuditService
+	client        client.Client
+	pipedriveAuth pclient.PipedriveAuthClient
+	config        *config.ServerConfig
+	logger        log.Logger
 }
 
 func NewUserDeleteHandler(
 	service port.UserAccessService,
+	audit port.AuditService,
 	client client.Client,
+	pipedriveAuth pclient.PipedriveAuthClient,
+	config *config.ServerConfig,
 	logger log.Logger,
 ) UserDeleteHandler {
 	return UserDeleteHandler{
-		service: service,
-		client:  client,
-		logger:  logger,
+		service:       service,
+		audit:         audit,
+		client:        client,
+		pipedriveAuth: pipedriveAuth,
+		config:        config,
+		logger:        logger,
 	}
 }
 
-func (u UserDeleteHandler) DeleteUser(ctx context.Context, uid *string, res *interface{}) error {
-	_, err, _ := group.Do(fmt.Sprintf("remove-%s", *uid), func() (interface{}, error) {
-		u.logger.Debugf("removing user %s", *uid)
-		if err := u.service.RemoveUser(ctx, *uid); err != nil {
-			u.logger.Debugf("could not delete user %s: %s", *uid, err.Error())
+func (u UserDeleteHandler) DeleteUser(ctx context.Context, id *request.UserIdentity, res *interface{}) error {
+	_, err, _ := group.Do(fmt.Sprintf("remove-%s", id.String()), func() (interface{}, error) {
+		u.logger.Debugf("removing user %s", id.String())
+		if err := u.service.RemoveUser(ctx, domain.UserIdentity{
+			CompanyID: id.CompanyID,
+			UserID:    id.UserID,
+		}); err != nil {
+			u.logger.Debugf("could not delete user %s: %s", id.String(), err.Error())
 			return nil, err
 		}
 
@@ -58,3 +75,88 @@ func (u UserDeleteHandler) DeleteUser(ctx context.Context, uid *string, res *int
 
 	return err
 }
+
+// UninstallUser removes the uninstalling user. Once no other active users of the company remain,
+// all of its user records and settings are removed as well. Every step is idempotent, so a failed
+// uninstall may be retried as a whole.
+func (u UserDeleteHandler) UninstallUser(ctx context.Context, req *request.UninstallRequest, res *response.UninstallResponse) error {
+	id := domain.UserIdentity{
+		CompanyID: req.CompanyID,
+		UserID:    req.UserID,
+	}
+
+	result, err, _ := group.Do(fmt.Sprintf("uninstall-%s", id.Key()), func() (interface{}, error) {
+		users, err := u.service.GetCompanyUsers(ctx, id.CompanyID)
+		if err != nil {
+			u.logger.Errorf("could not get company %d users: %s", id.CompanyID, err.Error())
+			return nil, err
+		}
+
+		remaining := 0
+		removals := make([]domain.UserAccess, 0, 1)
+		for _, user := range users {
+			if user.ID == id {
+				removals = append(removals, user)
+			} else if !user.Revoked {
+				remaining++
+			}
+		}
+
+		scope := domain.AuditScopeUser
+		if remaining == 0 {
+			scope = domain.AuditScopeCompany
+			removals = users
+		}
+
+		for _, user := range removals {
+			u.revokeUser(ctx, user)
+			if err := u.service.RemoveUser(ctx, user.ID); err != nil {
+				u.logger.Errorf("could not remove user %s: %s", user.ID.Key(), err.Error())
+				return nil, err
+			}
+		}
+
+		if scope == domain.AuditScopeCompany {
+			cid := fmt.Sprint(id.CompanyID)
+			var sres interface{}
+			if err := u.client.Call(ctx, u.client.NewRequest(
+				fmt.Sprintf("%s:settings", u.config.Namespace), "SettingsDeleteHandler.DeleteSettings", cid,
+			), &sres); err != nil {
+				u.logger.Errorf("could not remove company %s settings: %s", cid, err.Error())
+				return nil, err
+			}
+		}
+
+		if err := u.audit.RecordEvent(ctx, domain.NewUninstallEvent(id, req.Timestamp, scope, len(removals))); err != nil {
+			u.logger.Errorf("could not record uninstall audit event: %s", err.Error())
+			return nil, err
+		}
+
+		return response.UninstallResponse{
+			Scope:        scope,
+			RemovedUsers: len(removals),
+		}, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if r, ok := result.(response.UninstallResponse); ok {
+		*res = r
+	}
+
+	return nil
+}
+
+// revokeUser revokes the user's tokens at Pipedrive. Failures are only logged since
+// uninstalled apps usually have their tokens invalidated by Pipedrive already.
+func (u UserDeleteHandler) revokeUser(ctx context.Context, user domain.UserAccess) {
+	if user.RefreshToken == "" || user.Revoked {
+		return
+	}
+
+	if err := u.pipedriveAuth.RevokeToken(ctx, user.RefreshToken); err != nil {
+		u.logger.Warnf("could not revoke user %s tokens: %s", user.ID.Key(), err.Error())
+	}
+}
@@ -41,9 +41,12 @@ func NewUserInsertHandler(service port.UserAccessService, logger log.Logger) Use
 }
 
 func (i UserInsertHandler) InsertUser(ctx context.Context, req response.UserResponse, res *domain.UserAccess) error {
-	_, err, _ := group.Do(fmt.Sprintf("insert-%s", req.ID), func() (interface{}, error) {
+	_, err, _ := group.Do(fmt.Sprintf("insert-%s", req.ID.String()), func() (interface{}, error) {
 		usr, err := i.service.UpdateUser(ctx, domain.UserAccess{
-			ID:           req.ID,
+			ID: domain.UserIdentity{
+				CompanyID: req.ID.CompanyID,
+				UserID:    req.ID.UserID,
+			},
 			AccessToken:  req.AccessToken,
 			RefreshToken: req.RefreshToken,
 			TokenType:    req.TokenType,
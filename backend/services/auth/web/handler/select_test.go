@@ -29,7 +29,10 @@ import (
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/adapter"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/domain"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/service"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
 	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/oauth2"
 )
@@ -45,31 +48,42 @@ func (e mockEncryptor) Decrypt(ciphertext string, key []byte) (string, error) {
 }
 
 func TestSelectCaching(t *testing.T) {
+	leases := adapter.NewMemoryUserLeaseAdapter()
 	adapter := adapter.NewMemoryUserAdapter()
 	cache := cache.NewCache(&config.CacheConfig{})
-	service := service.NewUserService(adapter, mockEncryptor{}, cache, &oauth2.Config{
+	ring, _ := keyring.NewKeyring(&keyring.KeyringConfig{}, mockEncryptor{}, &oauth2.Config{
 		ClientID:     "mock",
 		ClientSecret: "mock",
-	}, log.NewEmptyLogger())
+	})
+	users := service.NewUserService(adapter, ring, cache, log.NewEmptyLogger())
 	pclient := pclient.NewPipedriveAuthClient(&oauth2.Config{
 		ClientID:     "mock",
 		ClientSecret: "mock",
 	})
 
-	sel := NewUserSelectHandler(service, nil, pclient, log.NewEmptyLogger())
+	refresher := service.NewUserRefreshService(
+		users, leases, pclient,
+		&shared.TokenRefresherConfig{}, log.NewEmptyLogger(),
+	)
+
+	sel := NewUserSelectHandler(users, nil, refresher, log.NewEmptyLogger())
 
-	service.CreateUser(context.Background(), domain.UserAccess{
-		ID:           "mock",
+	users.CreateUser(context.Background(), domain.UserAccess{
+		ID: domain.UserIdentity{
+			CompanyID: 1,
+			UserID:    1,
+		},
 		AccessToken:  "mock",
 		RefreshToken: "mock",
 		TokenType:    "mock",
 		Scope:        "mock",
 		ExpiresAt:    time.Now().Add(24 * time.Hour).UnixMilli(),
+		ApiDomain:    "mock",
 	})
 
 	t.Run("get user", func(t *testing.T) {
 		var res domain.UserAccess
-		id := "mock"
+		id := request.NewUserIdentity(1, 1)
 		assert.NoError(t, sel.GetUser(context.Background(), &id, &res))
 		assert.NotEmpty(t, res)
 	})
@@ -0,0 +1,129 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/auth/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	"go.uber.org/fx"
+)
+
+type TokenRefresher struct {
+	service port.UserRefreshService
+	config  *shared.TokenRefresherConfig
+	logger  plog.Logger
+	cancel  context.CancelFunc
+	wg      *sync.WaitGroup
+}
+
+func NewTokenRefresher(
+	service port.UserRefreshService,
+	config *shared.TokenRefresherConfig,
+	logger plog.Logger,
+) *TokenRefresher {
+	return &TokenRefresher{
+		service: service,
+		config:  config,
+		logger:  logger,
+		wg:      &sync.WaitGroup{},
+	}
+}
+
+// Start spawns the refresh scheduler unless it is disabled.
+func (r *TokenRefresher) Start() {
+	if !r.config.Refresher.Enabled {
+		r.logger.Info("token refresher is disabled")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go r.schedule(ctx)
+}
+
+// Stop waits for the current refresh run to finish.
+func (r *TokenRefresher) Stop(ctx context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *TokenRefresher) schedule(ctx context.Context) {
+	defer r.wg.Done()
+	interval := time.Duration(r.config.Refresher.Interval) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.run(ctx, interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *TokenRefresher) run(ctx context.Context, interval time.Duration) {
+	// a run never outlives its interval so that the next one starts on a fresh batch
+	tctx, cancel := context.WithTimeout(ctx, interval)
+	defer cancel()
+
+	refreshed, err := r.service.RefreshExpiringUsers(tctx)
+	if err != nil {
+		r.logger.Errorf("could not refresh expiring tokens: %s", err.Error())
+		return
+	}
+
+	if refreshed > 0 {
+		r.logger.Debugf("refreshed %d expiring user tokens", refreshed)
+	}
+}
+
+func RunTokenRefresher(lifecycle fx.Lifecycle, refresher *TokenRefresher) {
+	lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			refresher.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return refresher.Stop(ctx)
+		},
+	})
+}
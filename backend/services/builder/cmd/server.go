@@ -48,8 +48,15 @@ func Server() *cli.Command {
 			app := pkg.NewBootstrapper(CONFIG_PATH, pkg.WithModules(
 				rpc.NewService, web.NewConfigRPCServer,
 				handler.NewConfigHandler,
+				handler.NewHistoryHandler,
+				handler.NewConvertHandler,
+				handler.NewCommandHandler,
+				handler.NewGenerateHandler,
 				shared.BuildNewOnlyofficeConfig(CONFIG_PATH),
+				shared.BuildNewIntegrationCredentialsConfig(CONFIG_PATH),
 				client.NewPipedriveApiClient,
+				client.NewConvertClient,
+				client.NewCommandClient,
 				shared.NewMapFormatManager,
 			)).Bootstrap()
 
@@ -19,9 +19,9 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"path/filepath"
 	"strings"
 	"time"
@@ -32,29 +32,25 @@ import (
 	shared "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
 	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/mileusna/useragent"
 	"go-micro.dev/v4/client"
+	merrors "go-micro.dev/v4/errors"
+	"golang.org/x/oauth2"
 	"golang.org/x/sync/errgroup"
 )
 
-var (
-	defaultClient = &http.Client{
-		Timeout: 15 * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
-	}
-)
-
 type ConfigHandler struct {
 	client        client.Client
 	apiClient     pclient.PipedriveApiClient
+	converter     ConvertHandler
 	jwtManager    crypto.JwtManager
 	config        *config.ServerConfig
 	onlyoffice    *shared.OnlyofficeConfig
+	credentials   *oauth2.Config
 	logger        plog.Logger
 	formatManager shared.FormatManager
 }
@@ -63,127 +59,134 @@ func NewConfigHandler(
 	client client.Client,
 	jwtManager crypto.JwtManager,
 	apiClient pclient.PipedriveApiClient,
+	converter ConvertHandler,
 	config *config.ServerConfig,
 	onlyoffice *shared.OnlyofficeConfig,
+	credentials *oauth2.Config,
 	formatManager shared.FormatManager,
 	logger plog.Logger,
 ) ConfigHandler {
 	return ConfigHandler{
 		client:        client,
 		apiClient:     apiClient,
+		converter:     converter,
 		jwtManager:    jwtManager,
 		config:        config,
 		onlyoffice:    onlyoffice,
+		credentials:   credentials,
 		logger:        logger,
 		formatManager: formatManager,
 	}
 }
 
-func (c ConfigHandler) isDemoModeValid(settings response.DocSettingsResponse) bool {
-	if !settings.DemoEnabled {
-		return false
-	}
-
-	if settings.DemoStarted.IsZero() {
-		return true
-	}
+// access is what decides how a user opens the files of a Pipedrive record.
+type access struct {
+	user     model.User
+	settings response.DocSettingsResponse
+}
 
-	staleDate := time.Now().AddDate(0, 0, -30)
-	return settings.DemoStarted.After(staleDate)
+// policy narrows the company policy down to what the user may do with the record in Pipedrive.
+func (a access) policy() request.PermissionsPolicy {
+	return getPermissionsPolicy(a.settings, a.user).Restrict(getAccessMode(a.user))
 }
 
-func (c ConfigHandler) processConfig(user response.UserResponse, req request.BuildConfigRequest, ctx context.Context) (response.BuildConfigResponse, error) {
-	var config response.BuildConfigResponse
+// getAccess looks the user and the record up in Pipedrive along with the company settings.
+func (c ConfigHandler) getAccess(ctx context.Context, token model.Token, req request.BuildConfigRequest) (access, error) {
+	var acc access
 
 	tctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	g, gctx := errgroup.WithContext(tctx)
 
-	var usr model.User
-	var settings response.DocSettingsResponse
-
 	g.Go(func() error {
-		u, err := c.apiClient.GetMe(gctx, model.Token{
-			AccessToken:  user.AccessToken,
-			RefreshToken: user.RefreshToken,
-			TokenType:    user.TokenType,
-			Scope:        user.Scope,
-			ApiDomain:    user.ApiDomain,
-		})
+		u, err := c.apiClient.GetMe(gctx, token)
 		if err != nil {
 			c.logger.Debugf("could not get pipedrive user: %s", err.Error())
 			return err
 		}
-		usr = u
+		acc.user = u
 		return nil
 	})
 
 	g.Go(func() error {
-		var docs response.DocSettingsResponse
-		if err := c.client.Call(
-			gctx,
-			c.client.NewRequest(
-				fmt.Sprintf("%s:settings", c.config.Namespace),
-				"SettingsSelectHandler.GetSettings",
-				fmt.Sprint(req.CID),
-			),
-			&docs,
-		); err != nil {
-			c.logger.Debugf("could not document server settings: %s", err.Error())
+		// Records the user can not see are never opened
+		if _, err := c.apiClient.GetRecord(gctx, req.Entity, token); err != nil {
+			c.logger.Debugf("could not get pipedrive %s: %s", req.Entity.String(), err.Error())
+			var serr *pclient.UnexpectedStatusCodeError
+			if errors.As(err, &serr) && (serr.Code == http.StatusForbidden || serr.Code == http.StatusNotFound) {
+				return ErrUnauthorizedAccess
+			}
+
 			return err
 		}
 
-		if c.isDemoModeValid(docs) {
-			if c.onlyoffice.Onlyoffice.Demo.DocumentServerURL == "" ||
-				c.onlyoffice.Onlyoffice.Demo.DocumentServerSecret == "" ||
-				c.onlyoffice.Onlyoffice.Demo.DocumentServerHeader == "" {
-				c.logger.Errorf("demo mode is enabled but demo credentials are not configured")
-				return ErrNoSettingsFound
-			}
+		return nil
+	})
 
-			c.logger.Debugf("using demo mode for company %d", req.CID)
-			docs.DocAddress = c.onlyoffice.Onlyoffice.Demo.DocumentServerURL
-			docs.DocSecret = c.onlyoffice.Onlyoffice.Demo.DocumentServerSecret
-			docs.DocHeader = c.onlyoffice.Onlyoffice.Demo.DocumentServerHeader
-		} else {
-			if docs.DocAddress == "" || docs.DocSecret == "" || docs.DocHeader == "" {
-				c.logger.Debugf("no settings found and demo mode not valid")
-				return ErrNoSettingsFound
-			}
-			c.logger.Debugf("using regular document server settings for company %d", req.CID)
+	g.Go(func() error {
+		docs, err := shared.GetDocumentServerSettings(gctx, c.client, c.config.Namespace, c.onlyoffice, c.logger, fmt.Sprint(req.CID))
+		if err != nil {
+			return err
 		}
 
-		settings = docs
+		acc.settings = docs
 		return nil
 	})
 
-	if err := g.Wait(); err != nil {
+	return acc, g.Wait()
+}
+
+func (c ConfigHandler) processConfig(
+	ctx context.Context,
+	req request.BuildConfigRequest,
+	acc access,
+	policy request.PermissionsPolicy,
+) (response.BuildConfigResponse, error) {
+	var config response.BuildConfigResponse
+	usr, settings := acc.user, acc.settings
+
+	// Everyone opening the same file revision gets the same key to co-edit it
+	var key response.DocumentKeyResponse
+	if err := c.client.Call(ctx, c.client.NewRequest(
+		fmt.Sprintf("%s:documents", c.config.Namespace),
+		"KeyHandler.GetKey",
+		request.DocumentKey{
+			CompanyID: req.CID,
+			FileID:    req.FileID,
+		},
+	), &key); err != nil {
+		c.logger.Debugf("could not get file %s document key: %s", req.FileID, err.Error())
 		return config, err
 	}
 
+	req.DocKey = key.Key
+
 	t := "desktop"
 	ua := useragent.Parse(req.UserAgent)
 	if ua.Mobile || ua.Tablet {
 		t = "mobile"
 	}
 
-	dreq, err := http.NewRequestWithContext(tctx, "GET", fmt.Sprintf("%s/files/%s/download", user.ApiDomain, req.FileID), nil)
+	durl, err := buildDownloadURL(
+		c.jwtManager, c.credentials.ClientSecret, c.onlyoffice.Onlyoffice.Builder.GatewayURL,
+		request.NewUserIdentity(req.CID, req.UID), req.FileID,
+	)
 	if err != nil {
-		return config, fmt.Errorf("failed to create request: %w", err)
+		c.logger.Debugf("could not sign file %s download url: %s", req.FileID, err.Error())
+		return config, err
 	}
-	dreq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", user.AccessToken))
-	resp, err := defaultClient.Do(dreq)
+
+	filename := c.formatManager.EscapeFileName(req.Filename)
+	curl, err := buildCallbackURL(
+		c.jwtManager, c.credentials.ClientSecret, c.onlyoffice.Onlyoffice.Builder.CallbackURL,
+		request.NewCallbackTokenContext(usr.CompanyID, req.Entity, req.FileID, filename, req.DocKey),
+	)
 	if err != nil {
-		return config, fmt.Errorf("failed to execute request: %w", err)
+		c.logger.Debugf("could not sign callback token: %s", err.Error())
+		return config, err
 	}
-	defer func() {
-		if resp != nil && resp.Body != nil {
-			resp.Body.Close()
-		}
-	}()
 
-	filename := c.formatManager.EscapeFileName(req.Filename)
 	theme := "default-light"
 	if req.Dark {
 		theme = "default-dark"
@@ -193,23 +196,19 @@ func (c ConfigHandler) processConfig(user response.UserResponse, req request.Bui
 		Document: response.Document{
 			Key:   req.DocKey,
 			Title: filename,
-			URL:   resp.Header.Get("Location"),
+			URL:   durl,
 		},
 		EditorConfig: response.EditorConfig{
 			User: response.User{
-				ID:   fmt.Sprint(usr.ID + usr.CompanyID),
+				ID:   request.NewUserIdentity(usr.CompanyID, usr.ID).String(),
 				Name: usr.Name,
 			},
-			CallbackURL: fmt.Sprintf(
-				"%s/callback?cid=%d&did=%s&fid=%s&filename=%s",
-				c.onlyoffice.Onlyoffice.Builder.CallbackURL,
-				usr.CompanyID, req.Deal, req.FileID,
-				url.QueryEscape(filename),
-			),
+			CallbackURL: curl,
 			Customization: response.Customization{
 				Goback: response.Goback{
 					RequestClose: false,
 				},
+				Forcesave:     true,
 				Plugins:       false,
 				HideRightMenu: false,
 				UiTheme:       theme,
@@ -233,7 +232,7 @@ func (c ConfigHandler) processConfig(user response.UserResponse, req request.Bui
 		}
 
 		fileType = format.Type
-		isEditable = format.IsEditable()
+		isEditable = format.IsEditable() || (req.LossyEdit && format.IsLossyEditable())
 
 		config.Document.Permissions = response.Permissions{
 			Edit:                 isEditable,
@@ -245,39 +244,163 @@ func (c ConfigHandler) processConfig(user response.UserResponse, req request.Bui
 			ModifyContentControl: true,
 			ModifyFilter:         true,
 		}
+
+		// Fillable forms are opened by the pdf editor regardless of the type reported by the format list.
+		if format.IsFillable() {
+			config.Document.Permissions.FillForms = true
+			fileType = "pdf"
+		}
+
 		config.DocumentType = fileType
 	}
 
+	config.Document.Permissions = applyPermissionsPolicy(config.Document.Permissions, policy)
+
 	config.ExpiresAt = jwt.NewNumericDate(time.Now().Add(5 * time.Minute))
-	token, err := c.jwtManager.Sign(settings.DocSecret, config)
+	signature, err := c.jwtManager.Sign(settings.DocSecret, config)
 	if err != nil {
 		c.logger.Debugf("could not sign document server config: %s", err.Error())
 		return config, err
 	}
 
-	config.Token = token
+	config.Token = signature
 	return config, nil
 }
 
+// getConvertibleFormat returns a legacy format which can only be edited once converted to office open xml.
+func (c ConfigHandler) getConvertibleFormat(filename string) (shared.Format, bool) {
+	format, exists := c.formatManager.GetFormatByName(strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), ".")))
+	if !exists || format.IsEditable() {
+		return format, false
+	}
+
+	return format, format.IsAutoConvertable() && format.IsOpenXMLConvertable()
+}
+
+// convertToOpenXML waits for an office open xml copy of the file stored on the same parent
+// entity and points the request to it. Files converted before keep opening the same copy.
+func (c ConfigHandler) convertToOpenXML(ctx context.Context, req request.BuildConfigRequest, format shared.Format) (request.BuildConfigRequest, error) {
+	creq := request.ConvertFileRequest{
+		UID:        req.UID,
+		CID:        req.CID,
+		FileID:     req.FileID,
+		Filename:   req.Filename,
+		Entity:     req.Entity,
+		OutputType: format.GetOpenXMLExtension(),
+	}
+
+	for {
+		var res response.ConvertFileResponse
+		if err := c.converter.Convert(ctx, creq, &res); err != nil {
+			return req, err
+		}
+
+		if res.EndConvert {
+			req.FileID, req.Filename = res.FileID, res.Filename
+			return req, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return req, ErrOperationTimeout
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// openSession is best-effort. Sessions only inform users who else has the document open.
+func (c ConfigHandler) openSession(ctx context.Context, req request.BuildConfigRequest, config response.BuildConfigResponse) {
+	mode := "view"
+	if config.Document.Permissions.Edit {
+		mode = "edit"
+	}
+
+	var res response.DocumentSessionResponse
+	if err := c.client.Call(ctx, c.client.NewRequest(
+		fmt.Sprintf("%s:documents", c.config.Namespace),
+		"SessionHandler.OpenSession",
+		request.DocumentSession{
+			CompanyID:  req.CID,
+			FileID:     req.FileID,
+			EntityType: req.Entity.Type,
+			EntityID:   req.Entity.ID,
+			DocKey:     config.Document.Key,
+			UserID:     config.EditorConfig.User.ID,
+			Name:       config.EditorConfig.User.Name,
+			Mode:       mode,
+		},
+	), &res); err != nil {
+		c.logger.Warnf("could not open file %s session: %s", req.FileID, err.Error())
+	}
+}
+
 func (c ConfigHandler) BuildConfig(ctx context.Context, payload request.BuildConfigRequest, res *response.BuildConfigResponse) error {
 	c.logger.Debugf("processing a docs config: %s", payload.Filename)
 
+	id := request.NewUserIdentity(payload.CID, payload.UID)
 	req := c.client.NewRequest(
-		fmt.Sprintf("%s:auth", c.config.Namespace), "UserSelectHandler.GetUser",
-		fmt.Sprint(payload.UID+payload.CID),
+		fmt.Sprintf("%s:auth", c.config.Namespace), "UserSelectHandler.GetUser", id,
 	)
 
 	var ures response.UserResponse
 	if err := c.client.Call(ctx, req, &ures); err != nil {
-		c.logger.Debugf("could not get user %d access info: %s", payload.UID+payload.CID, err.Error())
+		c.logger.Debugf("could not get user %s access info: %s", id.String(), err.Error())
 		return err
 	}
 
-	config, err := c.processConfig(ures, payload, ctx)
+	acc, err := c.getAccess(ctx, model.Token{
+		AccessToken:  ures.AccessToken,
+		RefreshToken: ures.RefreshToken,
+		TokenType:    ures.TokenType,
+		Scope:        ures.Scope,
+		ApiDomain:    ures.ApiDomain,
+	}, payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthorizedAccess):
+			return merrors.Forbidden(fmt.Sprintf("%s:builder", c.config.Namespace), "%s", err.Error())
+		case errors.Is(err, shared.ErrNoSettingsFound):
+			return merrors.New(fmt.Sprintf("%s:builder", c.config.Namespace), err.Error(), http.StatusPreconditionFailed)
+		default:
+			return err
+		}
+	}
+
+	policy := acc.policy()
+	format, convertible := c.getConvertibleFormat(payload.Filename)
+	lossy, converted, askLossy := format.IsLossyEditable(), false, false
+	switch acc.settings.LossyEdit {
+	case constants.LossyEditAlways:
+		payload.LossyEdit = lossy
+	case constants.LossyEditAsk:
+		payload.LossyEdit = lossy && payload.LossyEdit
+		askLossy = lossy && !payload.LossyEdit
+	default:
+		payload.LossyEdit = false
+	}
+
+	// Converting stores a new file, which is up to users who may change the record
+	if convertible && !payload.LossyEdit && canEdit(policy) && (acc.settings.AutoConvert || payload.Convert) {
+		c.logger.Debugf("converting file %s to %s", payload.FileID, format.GetOpenXMLExtension())
+		if payload, err = c.convertToOpenXML(ctx, payload, format); err != nil {
+			c.logger.Debugf("could not convert file %s: %s", payload.FileID, err.Error())
+			return err
+		}
+
+		converted = true
+	}
+
+	config, err := c.processConfig(ctx, payload, acc, policy)
 	if err != nil {
 		return err
 	}
 
+	c.openSession(ctx, payload, config)
+	config.FileID = payload.FileID
+	config.Convertible = convertible && !converted && canEdit(policy)
+	config.Converted = converted
+	config.LossyEditable = askLossy && !converted && canEdit(policy)
+
 	*res = config
 	return nil
 }
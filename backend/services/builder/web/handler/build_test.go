@@ -0,0 +1,201 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	shared "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/stretchr/testify/assert"
+	merrors "go-micro.dev/v4/errors"
+)
+
+func newConfigHandler(t *testing.T, server *convertServer, settings response.DocSettingsResponse) ConfigHandler {
+	formatManager, err := shared.NewMapFormatManager()
+	assert.NoError(t, err)
+
+	rpc := newConvertRPC(server, settings)
+	rpc.Handlers["KeyHandler.GetKey"] = func(req interface{}, rsp interface{}) error {
+		*rsp.(*response.DocumentKeyResponse) = response.DocumentKeyResponse{
+			FileID: req.(request.DocumentKey).FileID,
+			Key:    "key-" + req.(request.DocumentKey).FileID,
+		}
+		return nil
+	}
+
+	return NewConfigHandler(rpc, testJwtManager, pclient.NewPipedriveApiClient(), newConvertHandler(t, rpc),
+		testConfig, newOnlyofficeConfig(), testCredentials, formatManager, log.NewEmptyLogger())
+}
+
+func newBuildConfigRequest(fileID, filename string) request.BuildConfigRequest {
+	return request.BuildConfigRequest{
+		UID:      2,
+		CID:      1,
+		Entity:   request.NewParentEntity(request.EntityDeal, "5"),
+		FileID:   fileID,
+		Filename: filename,
+	}
+}
+
+func TestBuildConfigConversion(t *testing.T) {
+	t.Run("open the same converted copy every time", func(t *testing.T) {
+		server := newConvertServer(100)
+		defer server.Close()
+		settings := newDocSettings()
+		settings.AutoConvert = true
+		handler := newConfigHandler(t, server, settings)
+
+		var first, second response.BuildConfigResponse
+		assert.NoError(t, handler.BuildConfig(context.Background(), newBuildConfigRequest("10", "Contract.doc"), &first))
+		assert.NoError(t, handler.BuildConfig(context.Background(), newBuildConfigRequest("10", "Contract.doc"), &second))
+
+		assert.True(t, first.Converted)
+		assert.Equal(t, "101", first.FileID)
+		assert.Equal(t, "Contract.docx", first.Document.Title)
+		assert.Equal(t, first.FileID, second.FileID)
+		assert.Equal(t, first.Document.Key, second.Document.Key)
+		assert.Equal(t, int32(1), server.uploads.Load())
+	})
+
+	t.Run("never convert for users who only view the record", func(t *testing.T) {
+		server := newConvertServer(100)
+		server.admin, server.app = false, "projects"
+		defer server.Close()
+		settings := newDocSettings()
+		settings.AutoConvert = true
+		handler := newConfigHandler(t, server, settings)
+
+		req := newBuildConfigRequest("10", "Contract.doc")
+		req.Convert = true
+
+		var res response.BuildConfigResponse
+		assert.NoError(t, handler.BuildConfig(context.Background(), req, &res))
+		assert.False(t, res.Converted)
+		assert.False(t, res.Convertible)
+		assert.False(t, res.Document.Permissions.Edit)
+		assert.Equal(t, "10", res.FileID)
+		assert.Equal(t, int32(0), server.uploads.Load())
+	})
+
+	t.Run("convert for users who can see a record they do not own", func(t *testing.T) {
+		server := newConvertServer(100)
+		server.admin = false
+		defer server.Close()
+		settings := newDocSettings()
+		settings.AutoConvert = true
+		handler := newConfigHandler(t, server, settings)
+
+		var res response.BuildConfigResponse
+		assert.NoError(t, handler.BuildConfig(context.Background(), newBuildConfigRequest("10", "Contract.doc"), &res))
+		assert.True(t, res.Converted)
+		assert.True(t, res.Document.Permissions.Edit)
+		assert.Equal(t, int32(1), server.uploads.Load())
+	})
+
+	t.Run("offer a conversion when it is not automatic", func(t *testing.T) {
+		server := newConvertServer(100)
+		defer server.Close()
+		handler := newConfigHandler(t, server, newDocSettings())
+
+		var res response.BuildConfigResponse
+		assert.NoError(t, handler.BuildConfig(context.Background(), newBuildConfigRequest("10", "Contract.doc"), &res))
+		assert.False(t, res.Converted)
+		assert.True(t, res.Convertible)
+		assert.Equal(t, "10", res.FileID)
+		assert.Equal(t, int32(0), server.uploads.Load())
+	})
+
+	t.Run("open office open xml files as they are", func(t *testing.T) {
+		server := newConvertServer(100)
+		defer server.Close()
+		settings := newDocSettings()
+		settings.AutoConvert = true
+		handler := newConfigHandler(t, server, settings)
+
+		var res response.BuildConfigResponse
+		assert.NoError(t, handler.BuildConfig(context.Background(), newBuildConfigRequest("10", "Contract.docx"), &res))
+		assert.False(t, res.Converted)
+		assert.False(t, res.Convertible)
+		assert.Equal(t, "10", res.FileID)
+		assert.Equal(t, int32(0), server.uploads.Load())
+	})
+}
+
+func TestBuildConfigAccess(t *testing.T) {
+	server := newConvertServer(100)
+	defer server.Close()
+	handler := newConfigHandler(t, server, newDocSettings())
+
+	req := newBuildConfigRequest("10", "Contract.docx")
+	req.Entity = request.NewParentEntity(request.EntityDeal, "private")
+
+	var res response.BuildConfigResponse
+	err := handler.BuildConfig(context.Background(), req, &res)
+	assert.Equal(t, int32(http.StatusForbidden), merrors.FromError(err).Code)
+}
+
+func TestBuildConfigLossyEdit(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    string
+		convert   bool
+		lossy     bool
+		edit      bool
+		ask       bool
+		converted bool
+	}{
+		{name: "never edit lossy formats", policy: constants.LossyEditNever, lossy: true},
+		{name: "ask before editing lossy formats", policy: constants.LossyEditAsk, ask: true},
+		{name: "edit lossy formats once confirmed", policy: constants.LossyEditAsk, lossy: true, edit: true},
+		{name: "always edit lossy formats", policy: constants.LossyEditAlways, edit: true},
+		{name: "always edit lossy formats instead of converting", policy: constants.LossyEditAlways, convert: true, edit: true},
+		{name: "convert instead of asking", policy: constants.LossyEditAsk, convert: true, edit: true, converted: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newConvertServer(100)
+			defer server.Close()
+			settings := newDocSettings()
+			settings.LossyEdit = test.policy
+			handler := newConfigHandler(t, server, settings)
+
+			req := newBuildConfigRequest("10", "Contract.odt")
+			req.Convert, req.LossyEdit = test.convert, test.lossy
+
+			var res response.BuildConfigResponse
+			assert.NoError(t, handler.BuildConfig(context.Background(), req, &res))
+			assert.Equal(t, test.edit, res.Document.Permissions.Edit)
+			assert.Equal(t, test.ask, res.LossyEditable)
+			assert.Equal(t, test.converted, res.Converted)
+			if test.converted {
+				assert.Equal(t, "docx", res.Document.FileType)
+			} else {
+				assert.Equal(t, "odt", res.Document.FileType)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallbackURL(t *testing.T) {
+	entity := request.NewParentEntity(request.EntityOrganization, "5")
+	curl, err := buildCallbackURL(testJwtManager, testSecret, "https://callback.example.com",
+		request.NewCallbackTokenContext(1, entity, "10", "Contract & Terms.docx", "key"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(curl, "https://callback.example.com/callback?token="))
+
+	link, err := url.Parse(curl)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"token"}, keys(link.Query()))
+
+	var tctx request.CallbackTokenContext
+	assert.NoError(t, testJwtManager.Verify(testSecret, link.Query().Get("token"), &tctx))
+	assert.NoError(t, tctx.Validate())
+	assert.Equal(t, 1, tctx.CID)
+	assert.Equal(t, entity, tctx.Entity())
+	assert.Equal(t, "10", tctx.FileID)
+	assert.Equal(t, "Contract & Terms.docx", tctx.Filename)
+	assert.Equal(t, "key", tctx.DocKey)
+
+	var download request.DownloadTokenContext
+	assert.NoError(t, testJwtManager.Verify(testSecret, link.Query().Get("token"), &download))
+	assert.ErrorIs(t, download.Validate(), request.ErrInvalidTokenPurpose)
+
+	assert.Error(t, testJwtManager.Verify("another", link.Query().Get("token"), &tctx))
+}
+
+func keys(values url.Values) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,172 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	shared "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"go-micro.dev/v4/client"
+)
+
+type CommandHandler struct {
+	client        client.Client
+	commandClient pclient.CommandClient
+	config        *config.ServerConfig
+	onlyoffice    *shared.OnlyofficeConfig
+	logger        plog.Logger
+}
+
+func NewCommandHandler(
+	client client.Client,
+	commandClient pclient.CommandClient,
+	config *config.ServerConfig,
+	onlyoffice *shared.OnlyofficeConfig,
+	logger plog.Logger,
+) CommandHandler {
+	return CommandHandler{
+		client:        client,
+		commandClient: commandClient,
+		config:        config,
+		onlyoffice:    onlyoffice,
+		logger:        logger,
+	}
+}
+
+// getKey resolves the document key the documents service has issued for the current revision of a file.
+func (c CommandHandler) getKey(ctx context.Context, req request.DocumentCommandRequest) (string, error) {
+	if req.Key != "" {
+		return req.Key, nil
+	}
+
+	var key response.DocumentKeyResponse
+	if err := c.client.Call(ctx, c.client.NewRequest(
+		fmt.Sprintf("%s:documents", c.config.Namespace),
+		"KeyHandler.GetKey",
+		request.DocumentKey{CompanyID: req.CID, FileID: req.FileID},
+	), &key); err != nil {
+		return "", err
+	}
+
+	if key.Key == "" {
+		return "", ErrNoDocumentKey
+	}
+
+	return key.Key, nil
+}
+
+// execute runs the command. The demo document server is shared by all companies, so there
+// documents are only addressed through company files and forgotten keys are never listed.
+func (c CommandHandler) execute(ctx context.Context, settings response.DocSettingsResponse, req request.DocumentCommandRequest, res *response.DocumentCommandResponse) error {
+	address, secret, header := settings.DocAddress, settings.DocSecret, settings.DocHeader
+	demo := settings.IsDemoModeValid()
+	switch req.Command {
+	case request.CommandVersion:
+		resp, err := c.commandClient.Version(ctx, address, secret, header)
+		res.Version = resp.Version
+		return err
+	case request.CommandLicense:
+		resp, err := c.commandClient.License(ctx, address, secret, header)
+		if err == nil {
+			res.License, res.Server, res.Quota = &resp.License, &resp.Server, resp.Quota
+		}
+
+		return err
+	case request.CommandGetForgottenList:
+		if demo {
+			res.Keys = []string{}
+			return nil
+		}
+
+		resp, err := c.commandClient.GetForgottenList(ctx, address, secret, header)
+		res.Keys = resp.Keys
+		return err
+	}
+
+	if demo && req.Key != "" {
+		return ErrNoDocumentKey
+	}
+
+	key, err := c.getKey(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	res.Key = key
+	switch req.Command {
+	case request.CommandForcesave:
+		_, err = c.commandClient.Forcesave(ctx, address, secret, header, key, req.UserData)
+	case request.CommandDrop:
+		_, err = c.commandClient.Drop(ctx, address, secret, header, key, req.Users)
+	case request.CommandInfo:
+		_, err = c.commandClient.Info(ctx, address, secret, header, key, req.UserData)
+	case request.CommandMeta:
+		_, err = c.commandClient.Meta(ctx, address, secret, header, key, req.Title)
+	case request.CommandGetForgotten:
+		var resp response.ForgottenCommandResponse
+		resp, err = c.commandClient.GetForgotten(ctx, address, secret, header, key)
+		res.URL = resp.URL
+	case request.CommandDeleteForgotten:
+		_, err = c.commandClient.DeleteForgotten(ctx, address, secret, header, key)
+	}
+
+	return err
+}
+
+// Command runs a command service command against the company document server.
+// Command service errors are returned in the response, anything else fails the call.
+func (c CommandHandler) Command(ctx context.Context, req request.DocumentCommandRequest, res *response.DocumentCommandResponse) error {
+	c.logger.Debugf("processing company %d %s command", req.CID, req.Command)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	settings, err := shared.GetDocumentServerSettings(ctx, c.client, c.config.Namespace, c.onlyoffice, c.logger, fmt.Sprint(req.CID))
+	if err != nil {
+		return err
+	}
+
+	res.Command = req.Command
+	if err := c.execute(ctx, settings, req, res); err != nil {
+		if errors.Is(err, ErrNoDocumentKey) {
+			c.logger.Debugf("could not resolve file %s document key", req.FileID)
+			res.Error = pclient.CommandErrorNoDocument
+			return nil
+		}
+
+		var cerr *pclient.CommandError
+		if errors.As(err, &cerr) {
+			c.logger.Debugf("company %d %s command has failed: %s", req.CID, req.Command, cerr.Error())
+			res.Error = cerr.Code
+			return nil
+		}
+
+		c.logger.Errorf("could not run company %d %s command: %s", req.CID, req.Command, err.Error())
+		return err
+	}
+
+	return nil
+}
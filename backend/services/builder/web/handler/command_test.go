@@ -0,0 +1,206 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDemoSecret = "demo-secret"
+
+// commandServer records the commands a document server has received.
+type commandServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	secret   string
+	code     int
+	commands []request.CommandRequest
+}
+
+func newCommandServer(secret string, code int) *commandServer {
+	server := &commandServer{secret: secret, code: code}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var body request.TokenCommandRequest
+		var cmd request.CommandRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil ||
+			testJwtManager.Verify(server.secret, body.Token, &cmd) != nil {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		server.mu.Lock()
+		server.commands = append(server.commands, cmd)
+		server.mu.Unlock()
+		rw.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(rw, `{"error":%d,"key":%q,"keys":["forgotten"]}`, server.code, cmd.Key)
+	}))
+
+	return server
+}
+
+func (s *commandServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	commands := make([]string, 0, len(s.commands))
+	for _, cmd := range s.commands {
+		commands = append(commands, cmd.C+":"+cmd.Key)
+	}
+
+	return commands
+}
+
+func newCommandHandler(demo bool, docs, demoDocs *commandServer) CommandHandler {
+	settings := newDocSettings()
+	settings.DocAddress = docs.URL
+	settings.DocSecret = docs.secret
+	settings.DemoEnabled = demo
+
+	onlyoffice := newOnlyofficeConfig()
+	onlyoffice.Onlyoffice.Demo.DocumentServerURL = demoDocs.URL
+	onlyoffice.Onlyoffice.Demo.DocumentServerSecret = demoDocs.secret
+	onlyoffice.Onlyoffice.Demo.DocumentServerHeader = "Authorization"
+
+	rpc := rpctest.NewClient().WithSettings(settings)
+	rpc.Handlers["KeyHandler.GetKey"] = func(req interface{}, rsp interface{}) error {
+		*rsp.(*response.DocumentKeyResponse) = response.DocumentKeyResponse{
+			FileID:   req.(request.DocumentKey).FileID,
+			Revision: 1,
+			Key:      "session",
+		}
+		return nil
+	}
+
+	return NewCommandHandler(rpc, pclient.NewCommandClient(testJwtManager), testConfig, onlyoffice, log.NewEmptyLogger())
+}
+
+func TestCommand(t *testing.T) {
+	run := func(t *testing.T, handler CommandHandler, req request.DocumentCommandRequest) response.DocumentCommandResponse {
+		var res response.DocumentCommandResponse
+		assert.NoError(t, handler.Command(context.Background(), req, &res))
+		return res
+	}
+
+	t.Run("list forgotten documents of the company document server", func(t *testing.T) {
+		docs, demoDocs := newCommandServer(testDocSecret, 0), newCommandServer(testDemoSecret, 0)
+		defer docs.Close()
+		defer demoDocs.Close()
+
+		res := run(t, newCommandHandler(false, docs, demoDocs), request.DocumentCommandRequest{
+			CID:     1,
+			Command: request.CommandGetForgottenList,
+		})
+
+		assert.Equal(t, []string{"forgotten"}, res.Keys)
+		assert.Equal(t, []string{request.CommandGetForgottenList + ":"}, docs.received())
+		assert.Empty(t, demoDocs.received())
+	})
+
+	t.Run("never list forgotten documents of the shared demo document server", func(t *testing.T) {
+		docs, demoDocs := newCommandServer(testDocSecret, 0), newCommandServer(testDemoSecret, 0)
+		defer docs.Close()
+		defer demoDocs.Close()
+
+		res := run(t, newCommandHandler(true, docs, demoDocs), request.DocumentCommandRequest{
+			CID:     1,
+			Command: request.CommandGetForgottenList,
+		})
+
+		assert.Equal(t, []string{}, res.Keys)
+		assert.Empty(t, docs.received())
+		assert.Empty(t, demoDocs.received())
+	})
+
+	t.Run("run commands against any key of the company document server", func(t *testing.T) {
+		docs, demoDocs := newCommandServer(testDocSecret, 0), newCommandServer(testDemoSecret, 0)
+		defer docs.Close()
+		defer demoDocs.Close()
+
+		res := run(t, newCommandHandler(false, docs, demoDocs), request.DocumentCommandRequest{
+			CID:     1,
+			Command: request.CommandGetForgotten,
+			Key:     "another",
+		})
+
+		assert.Equal(t, 0, res.Error)
+		assert.Equal(t, "another", res.Key)
+		assert.Equal(t, []string{request.CommandGetForgotten + ":another"}, docs.received())
+	})
+
+	t.Run("reject keys of other companies on the shared demo document server", func(t *testing.T) {
+		docs, demoDocs := newCommandServer(testDocSecret, 0), newCommandServer(testDemoSecret, 0)
+		defer docs.Close()
+		defer demoDocs.Close()
+
+		res := run(t, newCommandHandler(true, docs, demoDocs), request.DocumentCommandRequest{
+			CID:     1,
+			Command: request.CommandGetForgotten,
+			Key:     "another",
+		})
+
+		assert.Equal(t, pclient.CommandErrorNoDocument, res.Error)
+		assert.Empty(t, res.Key)
+		assert.Empty(t, docs.received())
+		assert.Empty(t, demoDocs.received())
+	})
+
+	t.Run("address company files on the demo document server", func(t *testing.T) {
+		docs, demoDocs := newCommandServer(testDocSecret, 0), newCommandServer(testDemoSecret, 0)
+		defer docs.Close()
+		defer demoDocs.Close()
+
+		res := run(t, newCommandHandler(true, docs, demoDocs), request.DocumentCommandRequest{
+			CID:     1,
+			Command: request.CommandForcesave,
+			FileID:  "2",
+		})
+
+		assert.Equal(t, 0, res.Error)
+		assert.Equal(t, "session", res.Key)
+		assert.Empty(t, docs.received())
+		assert.Equal(t, []string{request.CommandForcesave + ":session"}, demoDocs.received())
+	})
+
+	t.Run("report forcesaves without changes in the response", func(t *testing.T) {
+		docs, demoDocs := newCommandServer(testDocSecret, pclient.CommandErrorNoChanges), newCommandServer(testDemoSecret, 0)
+		defer docs.Close()
+		defer demoDocs.Close()
+
+		res := run(t, newCommandHandler(false, docs, demoDocs), request.DocumentCommandRequest{
+			CID:     1,
+			Command: request.CommandForcesave,
+			FileID:  "2",
+		})
+
+		assert.Equal(t, pclient.CommandErrorNoChanges, res.Error)
+		assert.Equal(t, []string{request.CommandForcesave + ":session"}, docs.received())
+	})
+}
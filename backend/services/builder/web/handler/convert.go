@@ -0,0 +1,253 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	shared "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/golang-jwt/jwt/v5"
+	"go-micro.dev/v4/client"
+	merrors "go-micro.dev/v4/errors"
+	"golang.org/x/oauth2"
+)
+
+type ConvertHandler struct {
+	client        client.Client
+	apiClient     pclient.PipedriveApiClient
+	convertClient pclient.ConvertClient
+	jwtManager    crypto.JwtManager
+	config        *config.ServerConfig
+	onlyoffice    *shared.OnlyofficeConfig
+	credentials   *oauth2.Config
+	formatManager shared.FormatManager
+	logger        plog.Logger
+}
+
+func NewConvertHandler(
+	client client.Client,
+	apiClient pclient.PipedriveApiClient,
+	convertClient pclient.ConvertClient,
+	jwtManager crypto.JwtManager,
+	config *config.ServerConfig,
+	onlyoffice *shared.OnlyofficeConfig,
+	credentials *oauth2.Config,
+	formatManager shared.FormatManager,
+	logger plog.Logger,
+) ConvertHandler {
+	return ConvertHandler{
+		client:        client,
+		apiClient:     apiClient,
+		convertClient: convertClient,
+		jwtManager:    jwtManager,
+		config:        config,
+		onlyoffice:    onlyoffice,
+		credentials:   credentials,
+		formatManager: formatManager,
+		logger:        logger,
+	}
+}
+
+// getConversionKey is stable for a file and an output type so that repeated
+// requests poll the same document server conversion. Pipedrive files are immutable,
+// so a new file id is all it takes to tell changed contents apart.
+func (c ConvertHandler) getConversionKey(req request.ConvertFileRequest) string {
+	return fmt.Sprintf("%d_%s_%s", req.CID, req.FileID, req.OutputType)
+}
+
+// getConversion returns the file an earlier request has already uploaded. A failed lookup
+// is treated as a miss, since converting again only costs a duplicate file.
+func (c ConvertHandler) getConversion(ctx context.Context, req request.ConvertFileRequest) (response.DocumentConversionResponse, bool) {
+	var res response.DocumentConversionResponse
+	if err := c.client.Call(ctx, c.client.NewRequest(
+		fmt.Sprintf("%s:documents", c.config.Namespace),
+		"ConversionHandler.GetConversion",
+		request.DocumentConversionSelect{
+			CompanyID: req.CID,
+			Key:       c.getConversionKey(req),
+		},
+	), &res); err != nil {
+		c.logger.Debugf("could not find file %s conversion to %s: %s", req.FileID, req.OutputType, err.Error())
+		return res, false
+	}
+
+	return res, res.ResultID != ""
+}
+
+// recordConversion returns the file that has been recorded first if another request has uploaded the same conversion.
+func (c ConvertHandler) recordConversion(ctx context.Context, req request.ConvertFileRequest, res response.ConvertFileResponse) response.ConvertFileResponse {
+	var cres response.DocumentConversionResponse
+	if err := c.client.Call(ctx, c.client.NewRequest(
+		fmt.Sprintf("%s:documents", c.config.Namespace),
+		"ConversionHandler.InsertConversion",
+		request.DocumentConversion{
+			CompanyID:  req.CID,
+			Key:        c.getConversionKey(req),
+			FileID:     req.FileID,
+			ResultID:   res.FileID,
+			Filename:   res.Filename,
+			EntityType: req.Entity.Type,
+			EntityID:   req.Entity.ID,
+			UpdateTime: res.UpdateTime,
+		},
+	), &cres); err != nil {
+		c.logger.Warnf("could not record file %s conversion to %s: %s", req.FileID, res.FileID, err.Error())
+		return res
+	}
+
+	res.FileID, res.Filename, res.UpdateTime = cres.ResultID, cres.Filename, cres.UpdateTime
+	return res
+}
+
+func (c ConvertHandler) convert(ctx context.Context, req request.ConvertFileRequest) (response.ConvertFileResponse, error) {
+	var res response.ConvertFileResponse
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(req.Filename), "."))
+	format, exists := c.formatManager.GetFormatByName(ext)
+	if !exists {
+		return res, ErrUnsupportedConvert
+	}
+
+	if _, ok := format.Convert[req.OutputType]; !ok {
+		return res, ErrUnsupportedConvert
+	}
+
+	if conversion, ok := c.getConversion(ctx, req); ok {
+		c.logger.Debugf("file %s has already been converted to %s", req.FileID, conversion.ResultID)
+		return response.ConvertFileResponse{
+			EndConvert: true,
+			Percent:    100,
+			FileID:     conversion.ResultID,
+			Filename:   conversion.Filename,
+			UpdateTime: conversion.UpdateTime,
+		}, nil
+	}
+
+	id := request.NewUserIdentity(req.CID, req.UID)
+	var ures response.UserResponse
+	if err := c.client.Call(ctx, c.client.NewRequest(
+		fmt.Sprintf("%s:auth", c.config.Namespace), "UserSelectHandler.GetUser", id,
+	), &ures); err != nil {
+		c.logger.Debugf("could not get user %s access info: %s", id.String(), err.Error())
+		return res, err
+	}
+
+	settings, err := shared.GetDocumentServerSettings(ctx, c.client, c.config.Namespace, c.onlyoffice, c.logger, fmt.Sprint(req.CID))
+	if err != nil {
+		return res, err
+	}
+
+	token := model.Token{
+		AccessToken:  ures.AccessToken,
+		RefreshToken: ures.RefreshToken,
+		TokenType:    ures.TokenType,
+		Scope:        ures.Scope,
+		ApiDomain:    ures.ApiDomain,
+	}
+
+	url, err := buildDownloadURL(c.jwtManager, c.credentials.ClientSecret, c.onlyoffice.Onlyoffice.Builder.GatewayURL, id, req.FileID)
+	if err != nil {
+		c.logger.Debugf("could not sign file %s download url: %s", req.FileID, err.Error())
+		return res, err
+	}
+
+	title := c.formatManager.EscapeFileName(req.Filename)
+	cres, err := c.convertClient.Convert(ctx, settings.DocAddress, settings.DocSecret, settings.DocHeader, request.ConvertRequest{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
+		},
+		Async:      true,
+		Filetype:   ext,
+		Key:        c.getConversionKey(req),
+		Outputtype: req.OutputType,
+		Title:      title,
+		URL:        url,
+	})
+	if err != nil {
+		c.logger.Debugf("could not convert file %s to %s: %s", req.FileID, req.OutputType, err.Error())
+		return res, err
+	}
+
+	res.Percent = cres.Percent
+	if !cres.EndConvert {
+		return res, nil
+	}
+
+	filename := fmt.Sprintf("%s.%s", strings.TrimSuffix(title, filepath.Ext(title)), req.OutputType)
+	file, err := c.apiClient.UploadFile(ctx, cres.FileURL, req.Entity, filename, token)
+	if err != nil {
+		c.logger.Debugf("could not upload converted file %s: %s", filename, err.Error())
+		return res, err
+	}
+
+	return c.recordConversion(ctx, req, response.ConvertFileResponse{
+		EndConvert: true,
+		Percent:    100,
+		FileID:     fmt.Sprint(file.Data.ID),
+		Filename:   filename,
+		UpdateTime: file.Data.UpdateTime,
+	}), nil
+}
+
+// Convert converts a Pipedrive file and stores the result next to the original one.
+// Unfinished conversions report their progress and are expected to be requested again,
+// finished ones keep returning the same file.
+func (c ConvertHandler) Convert(ctx context.Context, req request.ConvertFileRequest, res *response.ConvertFileResponse) error {
+	req.FileID, req.Filename = strings.TrimSpace(req.FileID), strings.TrimSpace(req.Filename)
+	req.OutputType = strings.ToLower(strings.TrimSpace(req.OutputType))
+	req.Entity = request.NewParentEntity(req.Entity.Type, req.Entity.ID)
+	if req.FileID == "" || req.Filename == "" || req.OutputType == "" {
+		return ErrEmptyIdValue
+	}
+
+	if err := req.Entity.Validate(); err != nil {
+		return err
+	}
+
+	c.logger.Debugf("processing file %s conversion to %s", req.FileID, req.OutputType)
+	result, err, _ := group.Do(c.getConversionKey(req), func() (interface{}, error) {
+		return c.convert(ctx, req)
+	})
+
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedConvert) {
+			return merrors.BadRequest(fmt.Sprintf("%s:builder", c.config.Namespace), "%s", err.Error())
+		}
+
+		return err
+	}
+
+	if resp, ok := result.(response.ConvertFileResponse); ok {
+		*res = resp
+		return nil
+	}
+
+	return ErrOperationTimeout
+}
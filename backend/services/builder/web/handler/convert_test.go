@@ -0,0 +1,209 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	shared "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/rpctest"
+	"github.com/stretchr/testify/assert"
+	merrors "go-micro.dev/v4/errors"
+)
+
+// convertServer plays both the document server converter and the Pipedrive api.
+type convertServer struct {
+	*httptest.Server
+	percent int
+	uploads atomic.Int32
+	admin   bool
+	app     string
+}
+
+// newConvertServer serves an admin user 2 of the sales app who can see deal 5 but not deal "private".
+func newConvertServer(percent int) *convertServer {
+	server := &convertServer{percent: percent, admin: true, app: model.AppSales}
+	mux := http.NewServeMux()
+	server.Server = httptest.NewServer(mux)
+	mux.HandleFunc("POST /converter", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(response.ConvertResponse{
+			EndConvert: server.percent == 100,
+			FileURL:    fmt.Sprintf("%s/result", server.URL),
+			Percent:    server.percent,
+		})
+	})
+	mux.HandleFunc("GET /result", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte("converted"))
+	})
+	mux.HandleFunc("GET /api/v1/users/me", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(rw, `{"data":{"id":2,"company_id":1,"name":"John","language":{"language_code":"en","country_code":"US"},"access":[{"app":%q,"admin":%t}]}}`, server.app, server.admin)
+	})
+	mux.HandleFunc("GET /api/v1/deals/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "private" {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write([]byte(`{"data":{"id":5,"owner_id":3,"visible_to":"3"}}`))
+	})
+	mux.HandleFunc("POST /api/v1/files", func(rw http.ResponseWriter, r *http.Request) {
+		var res response.AddFileResponse
+		res.Success = true
+		res.Data.ID = 100 + int(server.uploads.Add(1))
+		res.Data.UpdateTime = "2026-01-01 00:00:00"
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(res)
+	})
+
+	return server
+}
+
+// withConversions makes the documents service keep conversion records in memory.
+func withConversions(c *rpctest.Client) *rpctest.Client {
+	var mu sync.Mutex
+	conversions := map[string]response.DocumentConversionResponse{}
+	c.Handlers["ConversionHandler.GetConversion"] = func(req interface{}, rsp interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		conversion, ok := conversions[req.(request.DocumentConversionSelect).Key]
+		if !ok {
+			return errors.New("conversion not found")
+		}
+
+		*rsp.(*response.DocumentConversionResponse) = conversion
+		return nil
+	}
+	c.Handlers["ConversionHandler.InsertConversion"] = func(req interface{}, rsp interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		conversion := req.(request.DocumentConversion)
+		if _, ok := conversions[conversion.Key]; !ok {
+			conversions[conversion.Key] = response.DocumentConversionResponse{
+				Key:        conversion.Key,
+				FileID:     conversion.FileID,
+				ResultID:   conversion.ResultID,
+				Filename:   conversion.Filename,
+				UpdateTime: conversion.UpdateTime,
+			}
+		}
+
+		*rsp.(*response.DocumentConversionResponse) = conversions[conversion.Key]
+		return nil
+	}
+
+	return c
+}
+
+// newConvertRPC points the user and the document server settings to the fake server.
+func newConvertRPC(server *convertServer, settings response.DocSettingsResponse) *rpctest.Client {
+	settings.DocAddress = server.URL
+	return withConversions(rpctest.NewClient().WithUser(server.URL).WithSettings(settings))
+}
+
+func newConvertHandler(t *testing.T, rpc *rpctest.Client) ConvertHandler {
+	formatManager, err := shared.NewMapFormatManager()
+	assert.NoError(t, err)
+
+	return NewConvertHandler(rpc, pclient.NewPipedriveApiClient(), pclient.NewConvertClient(testJwtManager),
+		testJwtManager, testConfig, newOnlyofficeConfig(), testCredentials, formatManager, log.NewEmptyLogger())
+}
+
+func newConvertRequest(fileID, filename, outputType string) request.ConvertFileRequest {
+	return request.ConvertFileRequest{
+		UID:        2,
+		CID:        1,
+		FileID:     fileID,
+		Filename:   filename,
+		Entity:     request.NewParentEntity(request.EntityDeal, "5"),
+		OutputType: outputType,
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Run("reject an unsupported conversion as a bad request", func(t *testing.T) {
+		server := newConvertServer(100)
+		defer server.Close()
+		handler := newConvertHandler(t, newConvertRPC(server, newDocSettings()))
+
+		var res response.ConvertFileResponse
+		err := handler.Convert(context.Background(), newConvertRequest("10", "Contract.docx", "doc"), &res)
+		var merr *merrors.Error
+		assert.True(t, errors.As(err, &merr))
+		assert.Equal(t, int32(http.StatusBadRequest), merr.Code)
+		assert.Equal(t, int32(0), server.uploads.Load())
+	})
+
+	t.Run("report the progress of an unfinished conversion", func(t *testing.T) {
+		server := newConvertServer(40)
+		defer server.Close()
+		handler := newConvertHandler(t, newConvertRPC(server, newDocSettings()))
+
+		var res response.ConvertFileResponse
+		assert.NoError(t, handler.Convert(context.Background(), newConvertRequest("10", "Contract.doc", "docx"), &res))
+		assert.False(t, res.EndConvert)
+		assert.Equal(t, 40, res.Percent)
+		assert.Empty(t, res.FileID)
+		assert.Equal(t, int32(0), server.uploads.Load())
+	})
+
+	t.Run("upload a finished conversion once", func(t *testing.T) {
+		server := newConvertServer(100)
+		defer server.Close()
+		handler := newConvertHandler(t, newConvertRPC(server, newDocSettings()))
+
+		var first, second response.ConvertFileResponse
+		assert.NoError(t, handler.Convert(context.Background(), newConvertRequest("10", "Contract.doc", "docx"), &first))
+		assert.True(t, first.EndConvert)
+		assert.Equal(t, "101", first.FileID)
+		assert.Equal(t, "Contract.docx", first.Filename)
+
+		assert.NoError(t, handler.Convert(context.Background(), newConvertRequest("10", "Contract.doc", "docx"), &second))
+		assert.Equal(t, first, second)
+		assert.Equal(t, int32(1), server.uploads.Load())
+	})
+
+	t.Run("convert other output types separately", func(t *testing.T) {
+		server := newConvertServer(100)
+		defer server.Close()
+		handler := newConvertHandler(t, newConvertRPC(server, newDocSettings()))
+
+		var docx, pdf response.ConvertFileResponse
+		assert.NoError(t, handler.Convert(context.Background(), newConvertRequest("10", "Contract.doc", "docx"), &docx))
+		assert.NoError(t, handler.Convert(context.Background(), newConvertRequest("10", "Contract.doc", "pdf"), &pdf))
+		assert.NotEqual(t, docx.FileID, pdf.FileID)
+		assert.Equal(t, "Contract.pdf", pdf.Filename)
+		assert.Equal(t, int32(2), server.uploads.Load())
+	})
+}
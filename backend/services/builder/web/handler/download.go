@@ -0,0 +1,57 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+)
+
+// buildDownloadURL signs a gateway link the document server downloads a Pipedrive file through,
+// so it never gets a Pipedrive download link of its own.
+func buildDownloadURL(
+	jwtManager crypto.JwtManager,
+	secret, gatewayURL string,
+	id request.UserIdentity,
+	fileID string,
+) (string, error) {
+	token, err := jwtManager.Sign(secret, request.NewDownloadTokenContext(id, fileID))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/files/proxy?token=%s", gatewayURL, url.QueryEscape(token)), nil
+}
+
+// buildCallbackURL signs the file a document server callback saves to into the callback url.
+func buildCallbackURL(
+	jwtManager crypto.JwtManager,
+	secret, callbackURL string,
+	tctx request.CallbackTokenContext,
+) (string, error) {
+	token, err := jwtManager.Sign(secret, tctx)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/callback?token=%s", callbackURL, url.QueryEscape(token)), nil
+}
@@ -24,6 +24,10 @@ var (
 	ErrInvalidContextValue = errors.New("could not extract context value")
 	ErrEmptyIdValue        = errors.New("could not perform current action with an empty id")
 	ErrUnauthorizedAccess  = errors.New("unauthorized file access")
-	ErrNoSettingsFound     = errors.New("could not find document server settings")
 	ErrOperationTimeout    = errors.New("operation timeout")
+	ErrNoDocumentVersion   = errors.New("could not find document version")
+	ErrUnsupportedConvert  = errors.New("file format could not be converted to the requested type")
+	ErrNoDocumentKey       = errors.New("could not find the document key of the file")
+	ErrUnsupportedTemplate = errors.New("template format could not be filled with record fields")
+	ErrFileNotCreated      = errors.New("could not create a pipedrive file")
 )
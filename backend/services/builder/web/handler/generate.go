@@ -0,0 +1,234 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/merge"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"go-micro.dev/v4/client"
+	"golang.org/x/sync/errgroup"
+)
+
+// mergeFileTypes lists template formats placeholders can be filled in.
+var mergeFileTypes = map[string]bool{
+	"docx": true,
+	"xlsx": true,
+	"pptx": true,
+}
+
+type GenerateHandler struct {
+	client    client.Client
+	apiClient pclient.PipedriveApiClient
+	config    *config.ServerConfig
+	logger    plog.Logger
+}
+
+func NewGenerateHandler(
+	client client.Client,
+	apiClient pclient.PipedriveApiClient,
+	config *config.ServerConfig,
+	logger plog.Logger,
+) GenerateHandler {
+	return GenerateHandler{
+		client:    client,
+		apiClient: apiClient,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+// relatedID extracts a linked record id, which Pipedrive sends either as an id or as an object.
+func relatedID(value interface{}) string {
+	switch id := value.(type) {
+	case float64:
+		if id > 0 {
+			return strconv.FormatInt(int64(id), 10)
+		}
+	case string:
+		return strings.TrimSpace(id)
+	case map[string]interface{}:
+		return relatedID(id["value"])
+	}
+
+	return ""
+}
+
+// getFields collects the fields of the entity and, for deals and persons, of their person,
+// organization and products. Linked records the user can not read are skipped.
+func (c GenerateHandler) getFields(ctx context.Context, entity request.ParentEntity, token model.Token) (merge.Fields, error) {
+	data, err := c.apiClient.GetRecordData(ctx, entity, token)
+	if err != nil {
+		c.logger.Debugf("could not get pipedrive %s: %s", entity.String(), err.Error())
+		return nil, err
+	}
+
+	fields := merge.Fields{
+		"today": time.Now().Format("2006-01-02"),
+	}
+	fields.AddRecord(entity.Type, data)
+
+	g, gctx := errgroup.WithContext(ctx)
+	related := map[string]merge.Fields{}
+	addRecord := func(entityType, id string) {
+		if id == "" {
+			return
+		}
+
+		values := merge.Fields{}
+		related[entityType] = values
+		g.Go(func() error {
+			record, err := c.apiClient.GetRecordData(gctx, request.NewParentEntity(entityType, id), token)
+			if err != nil {
+				c.logger.Warnf("could not get pipedrive %s %s to fill a template: %s", entityType, id, err.Error())
+				return nil
+			}
+
+			values.AddRecord(entityType, record)
+			return nil
+		})
+	}
+
+	switch entity.Type {
+	case request.EntityDeal:
+		addRecord(request.EntityPerson, relatedID(data["person_id"]))
+		addRecord(request.EntityOrganization, relatedID(data["org_id"]))
+		products := merge.Fields{}
+		related["products"] = products
+		g.Go(func() error {
+			records, err := c.apiClient.GetDealProducts(gctx, entity.ID, token)
+			if err != nil {
+				c.logger.Warnf("could not get pipedrive deal %s products to fill a template: %s", entity.ID, err.Error())
+				return nil
+			}
+
+			products.AddList("products", records)
+			return nil
+		})
+	case request.EntityPerson:
+		addRecord(request.EntityOrganization, relatedID(data["org_id"]))
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	for _, values := range related {
+		for name, value := range values {
+			fields[name] = value
+		}
+	}
+
+	return fields, nil
+}
+
+// Generate creates a file from a company template with its placeholders filled
+// and attaches it to the entity the fields were taken from.
+func (c GenerateHandler) Generate(ctx context.Context, req request.GenerateFileRequest, res *response.GenerateFileResponse) error {
+	req.TemplateID, req.Filename = strings.TrimSpace(req.TemplateID), strings.TrimSpace(req.Filename)
+	req.Entity = request.NewParentEntity(req.Entity.Type, req.Entity.ID)
+	if req.TemplateID == "" || req.Filename == "" {
+		return ErrEmptyIdValue
+	}
+
+	if err := req.Entity.Validate(); err != nil {
+		return err
+	}
+
+	id := request.NewUserIdentity(req.CID, req.UID)
+	var ures response.UserResponse
+	if err := c.client.Call(ctx, c.client.NewRequest(
+		fmt.Sprintf("%s:auth", c.config.Namespace), "UserSelectHandler.GetUser", id,
+	), &ures); err != nil {
+		c.logger.Debugf("could not get user %s access info: %s", id.String(), err.Error())
+		return err
+	}
+
+	var template response.DocTemplateResponse
+	if err := c.client.Call(ctx, c.client.NewRequest(
+		fmt.Sprintf("%s:settings", c.config.Namespace), "TemplateHandler.GetTemplate",
+		request.DocTemplateSelect{
+			CompanyID: fmt.Sprint(req.CID),
+			ID:        req.TemplateID,
+		},
+	), &template); err != nil {
+		c.logger.Debugf("could not get company %d template %s: %s", req.CID, req.TemplateID, err.Error())
+		return err
+	}
+
+	if !mergeFileTypes[template.FileType] {
+		return ErrUnsupportedTemplate
+	}
+
+	token := model.Token{
+		AccessToken:  ures.AccessToken,
+		RefreshToken: ures.RefreshToken,
+		TokenType:    ures.TokenType,
+		Scope:        ures.Scope,
+		ApiDomain:    ures.ApiDomain,
+	}
+
+	fields, err := c.getFields(ctx, req.Entity, token)
+	if err != nil {
+		return err
+	}
+
+	content, err := merge.Fill(template.Content, fields)
+	if err != nil {
+		c.logger.Debugf("could not fill company %d template %s: %s", req.CID, req.TemplateID, err.Error())
+		if errors.Is(err, merge.ErrUnsupportedDocument) {
+			return ErrUnsupportedTemplate
+		}
+
+		return err
+	}
+
+	filename := fmt.Sprintf("%s.%s", strings.TrimSuffix(req.Filename, filepath.Ext(req.Filename)), template.FileType)
+	file, err := c.apiClient.CreateFile(ctx, req.Entity, filename, io.NopCloser(bytes.NewReader(content)), token)
+	if err != nil {
+		c.logger.Debugf("could not upload generated file %s: %s", filename, err.Error())
+		return err
+	}
+
+	if !file.Success || file.Data.ID == 0 {
+		return ErrFileNotCreated
+	}
+
+	*res = response.GenerateFileResponse{
+		FileID:     fmt.Sprint(file.Data.ID),
+		Filename:   filename,
+		UpdateTime: file.Data.UpdateTime,
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	shared "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"golang.org/x/oauth2"
+)
+
+const (
+	testSecret    = "secret"
+	testDocSecret = "doc-secret"
+)
+
+var (
+	testConfig      = &config.ServerConfig{Namespace: "test"}
+	testJwtManager  = crypto.NewJwtManager(&config.CryptoConfig{})
+	testCredentials = &oauth2.Config{ClientID: "client", ClientSecret: testSecret}
+)
+
+func newOnlyofficeConfig() *shared.OnlyofficeConfig {
+	var config shared.OnlyofficeConfig
+	config.Onlyoffice.Builder.GatewayURL = "https://gateway.example.com"
+	config.Onlyoffice.Builder.CallbackURL = "https://callback.example.com"
+	return &config
+}
+
+func newDocSettings() response.DocSettingsResponse {
+	return response.DocSettingsResponse{
+		DocAddress: "https://docs.example.com",
+		DocSecret:  testDocSecret,
+		DocHeader:  "Authorization",
+	}
+}
@@ -0,0 +1,262 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	shared "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/golang-jwt/jwt/v5"
+	"go-micro.dev/v4/client"
+	"golang.org/x/oauth2"
+)
+
+type HistoryHandler struct {
+	client      client.Client
+	apiClient   pclient.PipedriveApiClient
+	jwtManager  crypto.JwtManager
+	config      *config.ServerConfig
+	onlyoffice  *shared.OnlyofficeConfig
+	credentials *oauth2.Config
+	logger      plog.Logger
+}
+
+func NewHistoryHandler(
+	client client.Client,
+	apiClient pclient.PipedriveApiClient,
+	jwtManager crypto.JwtManager,
+	config *config.ServerConfig,
+	onlyoffice *shared.OnlyofficeConfig,
+	credentials *oauth2.Config,
+	logger plog.Logger,
+) HistoryHandler {
+	return HistoryHandler{
+		client:      client,
+		apiClient:   apiClient,
+		jwtManager:  jwtManager,
+		config:      config,
+		onlyoffice:  onlyoffice,
+		credentials: credentials,
+		logger:      logger,
+	}
+}
+
+// getKey returns the document key of a version. The version the editor was opened with
+// must keep the key of the editor config while prior versions are keyed by their immutable file ids.
+func (h HistoryHandler) getKey(version response.DocumentVersionResponse, req request.BuildHistoryRequest) string {
+	if version.FileID == req.FileID {
+		return req.DocKey
+	}
+
+	return fmt.Sprintf("%s_v%d", version.FileID, version.Version)
+}
+
+// getVersions returns the lineage up to and including the requested file.
+func (h HistoryHandler) getVersions(ctx context.Context, req request.BuildHistoryRequest) ([]response.DocumentVersionResponse, error) {
+	var versions response.DocumentVersionsResponse
+	if err := h.client.Call(ctx, h.client.NewRequest(
+		fmt.Sprintf("%s:documents", h.config.Namespace),
+		"VersionHandler.GetVersions",
+		request.DocumentVersionSelect{CompanyID: req.CID, FileID: req.FileID},
+	), &versions); err != nil {
+		return nil, err
+	}
+
+	lineage := make([]response.DocumentVersionResponse, 0, len(versions.Versions))
+	for _, version := range versions.Versions {
+		lineage = append(lineage, version)
+		if version.FileID == req.FileID {
+			return lineage, nil
+		}
+	}
+
+	return []response.DocumentVersionResponse{{FileID: req.FileID, Version: 1}}, nil
+}
+
+func (h HistoryHandler) getHistories(ctx context.Context, cid int, versions []response.DocumentVersionResponse) (map[string]response.DocumentHistoryResponse, error) {
+	ids := make([]string, 0, len(versions))
+	for _, version := range versions {
+		ids = append(ids, version.FileID)
+	}
+
+	var res response.DocumentHistoriesResponse
+	if err := h.client.Call(ctx, h.client.NewRequest(
+		fmt.Sprintf("%s:documents", h.config.Namespace),
+		"HistoryHandler.GetHistories",
+		request.DocumentHistorySelect{CompanyID: cid, FileIDs: ids},
+	), &res); err != nil {
+		return nil, err
+	}
+
+	histories := make(map[string]response.DocumentHistoryResponse, len(res.Histories))
+	for _, history := range res.Histories {
+		histories[history.FileID] = history
+	}
+
+	return histories, nil
+}
+
+func (h HistoryHandler) BuildHistory(ctx context.Context, req request.BuildHistoryRequest, res *response.BuildHistoryResponse) error {
+	h.logger.Debugf("processing file %s history", req.FileID)
+	versions, err := h.getVersions(ctx, req)
+	if err != nil {
+		h.logger.Debugf("could not get file %s versions: %s", req.FileID, err.Error())
+		return err
+	}
+
+	histories, err := h.getHistories(ctx, req.CID, versions)
+	if err != nil {
+		h.logger.Debugf("could not get file %s histories: %s", req.FileID, err.Error())
+		return err
+	}
+
+	items := make([]response.HistoryItem, 0, len(versions))
+	for _, version := range versions {
+		item := response.HistoryItem{
+			Key:     h.getKey(version, req),
+			User:    response.HistoryUser{ID: version.Author},
+			Version: version.Version,
+		}
+
+		if !version.CreatedAt.IsZero() {
+			item.Created = version.CreatedAt.UTC().Format("2006-01-02 15:04:05")
+		}
+
+		if history, ok := histories[version.FileID]; ok && version.Version > 1 {
+			item.ServerVersion = history.ServerVersion
+			item.Changes = history.Changes
+			for _, change := range history.Changes {
+				if change.User.ID == version.Author {
+					item.User.Name = change.User.Name
+				}
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	*res = response.BuildHistoryResponse{
+		CurrentVersion: versions[len(versions)-1].Version,
+		History:        items,
+	}
+
+	return nil
+}
+
+// BuildHistoryData builds a signed payload for the editor's setHistoryData method.
+func (h HistoryHandler) BuildHistoryData(ctx context.Context, req request.BuildHistoryRequest, res *response.BuildHistoryDataResponse) error {
+	h.logger.Debugf("processing file %s version %d history data", req.FileID, req.Version)
+	var ures response.UserResponse
+	if err := h.client.Call(ctx, h.client.NewRequest(
+		fmt.Sprintf("%s:auth", h.config.Namespace),
+		"UserSelectHandler.GetUser",
+		request.NewUserIdentity(req.CID, req.UID),
+	), &ures); err != nil {
+		h.logger.Debugf("could not get user access info: %s", err.Error())
+		return err
+	}
+
+	settings, err := shared.GetDocumentServerSettings(ctx, h.client, h.config.Namespace, h.onlyoffice, h.logger, fmt.Sprint(req.CID))
+	if err != nil {
+		return err
+	}
+
+	versions, err := h.getVersions(ctx, req)
+	if err != nil {
+		h.logger.Debugf("could not get file %s versions: %s", req.FileID, err.Error())
+		return err
+	}
+
+	var current, previous *response.DocumentVersionResponse
+	for i := range versions {
+		if versions[i].Version == req.Version {
+			current = &versions[i]
+			if i > 0 {
+				previous = &versions[i-1]
+			}
+		}
+	}
+
+	if current == nil || current.Deleted {
+		return ErrNoDocumentVersion
+	}
+
+	id := request.NewUserIdentity(req.CID, req.UID)
+	durl, err := buildDownloadURL(h.jwtManager, h.credentials.ClientSecret, h.onlyoffice.Onlyoffice.Builder.GatewayURL, id, current.FileID)
+	if err != nil {
+		h.logger.Debugf("could not sign file %s download url: %s", current.FileID, err.Error())
+		return err
+	}
+
+	data := response.BuildHistoryDataResponse{
+		FileType: strings.ToLower(strings.TrimPrefix(filepath.Ext(current.Filename), ".")),
+		Key:      h.getKey(*current, req),
+		URL:      durl,
+		Version:  current.Version,
+	}
+
+	if previous != nil && !previous.Deleted {
+		purl, err := buildDownloadURL(h.jwtManager, h.credentials.ClientSecret, h.onlyoffice.Onlyoffice.Builder.GatewayURL, id, previous.FileID)
+		if err != nil {
+			h.logger.Debugf("could not sign file %s download url: %s", previous.FileID, err.Error())
+			return err
+		}
+
+		data.Previous = &response.HistoryPrevious{
+			FileType: strings.ToLower(strings.TrimPrefix(filepath.Ext(previous.Filename), ".")),
+			Key:      h.getKey(*previous, req),
+			URL:      purl,
+		}
+
+		histories, err := h.getHistories(ctx, req.CID, []response.DocumentVersionResponse{*current})
+		if err == nil && histories[current.FileID].HasArchive {
+			ctoken, err := h.jwtManager.Sign(h.credentials.ClientSecret, request.NewChangesTokenContext(req.CID, current.FileID))
+			if err != nil {
+				return err
+			}
+
+			data.ChangesURL = fmt.Sprintf(
+				"%s/files/changes?token=%s",
+				h.onlyoffice.Onlyoffice.Builder.GatewayURL, url.QueryEscape(ctoken),
+			)
+		}
+	}
+
+	data.ExpiresAt = jwt.NewNumericDate(time.Now().Add(5 * time.Minute))
+	signature, err := h.jwtManager.Sign(settings.DocSecret, data)
+	if err != nil {
+		h.logger.Debugf("could not sign history data: %s", err.Error())
+		return err
+	}
+
+	data.Token = signature
+	*res = data
+	return nil
+}
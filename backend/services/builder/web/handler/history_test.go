@@ -0,0 +1,141 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHistoryHandler(versions []response.DocumentVersionResponse, histories []response.DocumentHistoryResponse) HistoryHandler {
+	rpc := rpctest.NewClient().WithUser("https://company.pipedrive.com").WithSettings(newDocSettings())
+	rpc.Handlers["VersionHandler.GetVersions"] = func(req interface{}, rsp interface{}) error {
+		*rsp.(*response.DocumentVersionsResponse) = response.DocumentVersionsResponse{Versions: versions}
+		return nil
+	}
+	rpc.Handlers["HistoryHandler.GetHistories"] = func(req interface{}, rsp interface{}) error {
+		*rsp.(*response.DocumentHistoriesResponse) = response.DocumentHistoriesResponse{Histories: histories}
+		return nil
+	}
+
+	return NewHistoryHandler(rpc, pclient.NewPipedriveApiClient(), testJwtManager, testConfig,
+		newOnlyofficeConfig(), testCredentials, log.NewEmptyLogger())
+}
+
+// tokenOf extracts the token query parameter of a gateway link.
+func tokenOf(t *testing.T, link string) string {
+	u, err := url.Parse(link)
+	assert.NoError(t, err)
+	return u.Query().Get("token")
+}
+
+func TestHistory(t *testing.T) {
+	versions := []response.DocumentVersionResponse{
+		{RootID: "1", FileID: "1", Version: 1, Filename: "Contract.docx", Author: "1:2"},
+		{RootID: "1", FileID: "2", Version: 2, Filename: "Contract.docx", Author: "1:3"},
+		{RootID: "1", FileID: "3", Version: 3, Filename: "Contract.docx", Author: "1:3"},
+	}
+	histories := []response.DocumentHistoryResponse{
+		{FileID: "2", ServerVersion: "8.0", HasArchive: true},
+	}
+
+	t.Run("build the history up to the opened file", func(t *testing.T) {
+		var res response.BuildHistoryResponse
+		handler := newHistoryHandler(versions, histories)
+		assert.NoError(t, handler.BuildHistory(context.Background(), request.BuildHistoryRequest{
+			UID: 2, CID: 1, FileID: "2", DocKey: "key",
+		}, &res))
+		assert.Equal(t, 2, res.CurrentVersion)
+		assert.Len(t, res.History, 2)
+		assert.Equal(t, "1_v1", res.History[0].Key)
+		assert.Equal(t, "key", res.History[1].Key)
+		assert.Equal(t, "8.0", res.History[1].ServerVersion)
+	})
+
+	t.Run("sign history data with purpose bound links", func(t *testing.T) {
+		var res response.BuildHistoryDataResponse
+		handler := newHistoryHandler(versions, histories)
+		assert.NoError(t, handler.BuildHistoryData(context.Background(), request.BuildHistoryRequest{
+			UID: 2, CID: 1, FileID: "3", DocKey: "key", Version: 2,
+		}, &res))
+		assert.Equal(t, "docx", res.FileType)
+		assert.Equal(t, "2_v2", res.Key)
+		assert.NotNil(t, res.Previous)
+		assert.Equal(t, "1_v1", res.Previous.Key)
+
+		var download request.DownloadTokenContext
+		assert.NoError(t, testJwtManager.Verify(testSecret, tokenOf(t, res.URL), &download))
+		assert.NoError(t, download.Validate())
+		assert.Equal(t, "2", download.FileID)
+
+		var changes request.ChangesTokenContext
+		assert.NoError(t, testJwtManager.Verify(testSecret, tokenOf(t, res.ChangesURL), &changes))
+		assert.NoError(t, changes.Validate())
+		assert.Equal(t, "2", changes.FileID)
+		assert.Equal(t, 1, changes.CID)
+
+		var crossed request.ChangesTokenContext
+		assert.NoError(t, testJwtManager.Verify(testSecret, tokenOf(t, res.URL), &crossed))
+		assert.ErrorIs(t, crossed.Validate(), request.ErrInvalidTokenPurpose)
+
+		var signed response.BuildHistoryDataResponse
+		assert.NoError(t, testJwtManager.Verify(testDocSecret, res.Token, &signed))
+		assert.Equal(t, res.URL, signed.URL)
+	})
+
+	t.Run("skip the diff of the first version and of versions without archives", func(t *testing.T) {
+		handler := newHistoryHandler(versions, histories)
+		var first response.BuildHistoryDataResponse
+		assert.NoError(t, handler.BuildHistoryData(context.Background(), request.BuildHistoryRequest{
+			UID: 2, CID: 1, FileID: "3", DocKey: "key", Version: 1,
+		}, &first))
+		assert.Nil(t, first.Previous)
+		assert.Empty(t, first.ChangesURL)
+
+		var third response.BuildHistoryDataResponse
+		assert.NoError(t, handler.BuildHistoryData(context.Background(), request.BuildHistoryRequest{
+			UID: 2, CID: 1, FileID: "3", DocKey: "key", Version: 3,
+		}, &third))
+		assert.Equal(t, "key", third.Key)
+		assert.NotNil(t, third.Previous)
+		assert.Empty(t, third.ChangesURL)
+	})
+
+	t.Run("reject unknown and deleted versions", func(t *testing.T) {
+		deleted := append([]response.DocumentVersionResponse{}, versions...)
+		deleted[1].Deleted = true
+		handler := newHistoryHandler(deleted, histories)
+
+		var res response.BuildHistoryDataResponse
+		assert.ErrorIs(t, handler.BuildHistoryData(context.Background(), request.BuildHistoryRequest{
+			UID: 2, CID: 1, FileID: "3", DocKey: "key", Version: 2,
+		}, &res), ErrNoDocumentVersion)
+		assert.ErrorIs(t, handler.BuildHistoryData(context.Background(), request.BuildHistoryRequest{
+			UID: 2, CID: 1, FileID: "3", DocKey: "key", Version: 4,
+		}, &res), ErrNoDocumentVersion)
+	})
+}
@@ -0,0 +1,78 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+)
+
+// getPermissionsPolicy resolves the company policy for the Pipedrive permission sets a user is assigned to.
+func getPermissionsPolicy(settings response.DocSettingsResponse, usr model.User) request.PermissionsPolicy {
+	sets := make([]string, 0, len(usr.Access))
+	for _, access := range usr.Access {
+		if access.PermissionID != "" {
+			sets = append(sets, access.PermissionID)
+		}
+	}
+
+	return request.ResolvePermissions(settings.Permissions, settings.RolePermissions, sets...)
+}
+
+// getAccessMode maps a user's Pipedrive access entries onto an editor mode. Pipedrive lets users
+// change the records they can see, so only users without access to the sales app view files.
+// Users whose access entries are unknown keep whatever the company policy grants.
+func getAccessMode(usr model.User) string {
+	if len(usr.Access) == 0 || usr.IsAdmin() || usr.HasApp(model.AppSales) {
+		return constants.PermissionModeEdit
+	}
+
+	return constants.PermissionModeView
+}
+
+// applyPermissionsPolicy narrows editor permissions down. A policy never allows
+// editing a file the format does not allow to edit.
+func applyPermissionsPolicy(permissions response.Permissions, policy request.PermissionsPolicy) response.Permissions {
+	permissions.Download = !policy.DisableDownload
+	permissions.Copy = !policy.DisableCopy
+	permissions.Print = policy.AllowPrint
+
+	switch policy.Mode {
+	case constants.PermissionModeReview:
+		permissions.Review = permissions.Edit
+		permissions.Edit = false
+	case constants.PermissionModeComment:
+		permissions.Edit = false
+	case constants.PermissionModeView:
+		permissions.Edit = false
+		permissions.Comment = false
+		permissions.FillForms = false
+		permissions.ModifyContentControl = false
+		permissions.ModifyFilter = false
+	}
+
+	return permissions
+}
+
+// canEdit reports whether the policy lets users change documents at all.
+func canEdit(policy request.PermissionsPolicy) bool {
+	return policy.Mode == constants.PermissionModeEdit || policy.Mode == constants.PermissionModeReview
+}
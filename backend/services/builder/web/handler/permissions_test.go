@@ -0,0 +1,66 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAccessMode(t *testing.T) {
+	user := func(access ...model.Access) model.User {
+		return model.User{ID: 2, CompanyID: 1, Access: access}
+	}
+
+	tests := []struct {
+		name string
+		user model.User
+		mode string
+	}{
+		{
+			name: "global admins edit",
+			user: user(model.Access{App: model.AppGlobal, Admin: true}),
+			mode: constants.PermissionModeEdit,
+		},
+		{
+			name: "sales users edit the records they can see",
+			user: user(model.Access{App: model.AppSales, PermissionID: "regular"}),
+			mode: constants.PermissionModeEdit,
+		},
+		{
+			name: "users without the sales app view records",
+			user: user(model.Access{App: "projects"}),
+			mode: constants.PermissionModeView,
+		},
+		{
+			name: "users with unknown access keep the company policy",
+			user: user(),
+			mode: constants.PermissionModeEdit,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.mode, getAccessMode(test.user))
+		})
+	}
+}
@@ -24,14 +24,26 @@ import (
 )
 
 type ConfigRPCServer struct {
-	configHandler handler.ConfigHandler
+	configHandler   handler.ConfigHandler
+	historyHandler  handler.HistoryHandler
+	convertHandler  handler.ConvertHandler
+	commandHandler  handler.CommandHandler
+	generateHandler handler.GenerateHandler
 }
 
 func NewConfigRPCServer(
 	configHandler handler.ConfigHandler,
+	historyHandler handler.HistoryHandler,
+	convertHandler handler.ConvertHandler,
+	commandHandler handler.CommandHandler,
+	generateHandler handler.GenerateHandler,
 ) rpc.RPCEngine {
 	return ConfigRPCServer{
-		configHandler: configHandler,
+		configHandler:   configHandler,
+		historyHandler:  historyHandler,
+		convertHandler:  convertHandler,
+		commandHandler:  commandHandler,
+		generateHandler: generateHandler,
 	}
 }
 
@@ -40,5 +52,5 @@ func (a ConfigRPCServer) BuildMessageHandlers() []rpc.RPCMessageHandler {
 }
 
 func (a ConfigRPCServer) BuildHandlers() []interface{} {
-	return []interface{}{a.configHandler}
+	return []interface{}{a.configHandler, a.historyHandler, a.convertHandler, a.commandHandler, a.generateHandler}
 }
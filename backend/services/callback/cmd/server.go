@@ -23,6 +23,9 @@ import (
 	chttp "github.com/ONLYOFFICE/onlyoffice-integration-adapters/service/http"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/controller"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/service"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/worker"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
 	"github.com/urfave/cli/v2"
@@ -49,7 +52,15 @@ func Server() *cli.Command {
 				chttp.NewService, web.NewServer,
 				controller.NewCallbackController,
 				shared.BuildNewOnlyofficeConfig(CONFIG_PATH),
+				shared.BuildNewIntegrationCredentialsConfig(CONFIG_PATH),
 				client.NewPipedriveApiClient,
+				client.NewConvertClient,
+				adapter.BuildNewCallbackQueueAdapter,
+				service.NewCallbackQueueService,
+				worker.NewUploadProcessor,
+				worker.NewCallbackWorker,
+			), pkg.WithInvokables(
+				worker.RunCallbackWorker,
 			)).Bootstrap()
 
 			if err := app.Err(); err != nil {
@@ -21,69 +21,73 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
 	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
 	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/port"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
-	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
-	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
 	"go-micro.dev/v4/client"
-	"go-micro.dev/v4/util/backoff"
+	"golang.org/x/oauth2"
 )
 
 type CallbackController struct {
-	client       client.Client
-	pipedriveAPI pclient.PipedriveApiClient
-	jwtManager   crypto.JwtManager
-	config       *config.ServerConfig
-	onlyoffice   *shared.OnlyofficeConfig
-	logger       plog.Logger
+	client      client.Client
+	queue       port.CallbackQueueService
+	jwtManager  crypto.JwtManager
+	config      *config.ServerConfig
+	onlyoffice  *shared.OnlyofficeConfig
+	credentials *oauth2.Config
+	logger      plog.Logger
 }
 
 func NewCallbackController(
 	client client.Client,
-	pipedriveAPI pclient.PipedriveApiClient,
+	queue port.CallbackQueueService,
 	jwtManager crypto.JwtManager,
 	config *config.ServerConfig,
 	onlyoffice *shared.OnlyofficeConfig,
+	credentials *oauth2.Config,
 	logger plog.Logger,
 ) *CallbackController {
 	return &CallbackController{
-		client:       client,
-		pipedriveAPI: pipedriveAPI,
-		jwtManager:   jwtManager,
-		config:       config,
-		onlyoffice:   onlyoffice,
-		logger:       logger,
+		client:      client,
+		queue:       queue,
+		jwtManager:  jwtManager,
+		config:      config,
+		onlyoffice:  onlyoffice,
+		credentials: credentials,
+		logger:      logger,
 	}
 }
 
-func (c CallbackController) isDemoModeValid(settings response.DocSettingsResponse) bool {
-	if !settings.DemoEnabled {
-		return false
-	}
-
-	if settings.DemoStarted.IsZero() {
-		return true
-	}
-
-	staleDate := time.Now().AddDate(0, 0, -30)
-	return settings.DemoStarted.After(staleDate)
-}
-
 func (c CallbackController) BuildPostHandleCallback() http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
-		query := r.URL.Query()
-		cid, did, fid := strings.TrimSpace(query.Get("cid")), strings.TrimSpace(query.Get("did")), strings.TrimSpace(query.Get("fid"))
 		rw.Header().Set("Content-Type", "application/json")
 
+		tctx, legacy, err := c.getCallbackContext(r.URL.Query())
+		if err != nil {
+			c.logger.Errorf("could not verify callback url token: %s", err.Error())
+			rw.WriteHeader(http.StatusForbidden)
+			rw.Write(response.CallbackResponse{
+				Error: 1,
+			}.ToJSON())
+			return
+		}
+
+		cid, fid, entity := fmt.Sprint(tctx.CID), strings.TrimSpace(tctx.FileID), tctx.Entity()
+
 		var body request.CallbackRequest
 		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 			c.logger.Errorf("could not decode a callback body")
@@ -103,8 +107,8 @@ func (c CallbackController) BuildPostHandleCallback() http.HandlerFunc {
 			return
 		}
 
-		if cid == "" || did == "" || fid == "" {
-			c.logger.Error("invalid query parameter")
+		if tctx.CID <= 0 || fid == "" || entity.Validate() != nil {
+			c.logger.Error("invalid callback url token context")
 			rw.WriteHeader(http.StatusBadRequest)
 			rw.Write(response.CallbackResponse{
 				Error: 1,
@@ -112,10 +116,9 @@ func (c CallbackController) BuildPostHandleCallback() http.HandlerFunc {
 			return
 		}
 
-		req := c.client.NewRequest(fmt.Sprintf("%s:settings", c.config.Namespace), "SettingsSelectHandler.GetSettings", cid)
-		var res response.DocSettingsResponse
-		if err := c.client.Call(r.Context(), req, &res); err != nil {
-			c.logger.Errorf("could not extract doc server settings %s", cid)
+		settings, err := shared.GetDocumentServerSettings(r.Context(), c.client, c.config.Namespace, c.onlyoffice, c.logger, cid)
+		if err != nil {
+			c.logger.Errorf("could not extract doc server settings %s: %s", cid, err.Error())
 			rw.WriteHeader(http.StatusBadRequest)
 			rw.Write(response.CallbackResponse{
 				Error: 1,
@@ -123,32 +126,7 @@ func (c CallbackController) BuildPostHandleCallback() http.HandlerFunc {
 			return
 		}
 
-		var jwtSecret string
-		if c.isDemoModeValid(res) {
-			if c.onlyoffice.Onlyoffice.Demo.DocumentServerSecret == "" {
-				c.logger.Errorf("demo mode is enabled but demo secret is not configured")
-				rw.WriteHeader(http.StatusInternalServerError)
-				rw.Write(response.CallbackResponse{
-					Error: 1,
-				}.ToJSON())
-				return
-			}
-
-			jwtSecret = c.onlyoffice.Onlyoffice.Demo.DocumentServerSecret
-		} else {
-			if res.DocSecret == "" {
-				c.logger.Errorf("no document server secret found and demo mode not valid (company %s)", cid)
-				rw.WriteHeader(http.StatusBadRequest)
-				rw.Write(response.CallbackResponse{
-					Error: 1,
-				}.ToJSON())
-				return
-			}
-
-			jwtSecret = res.DocSecret
-		}
-
-		if err := c.jwtManager.Verify(jwtSecret, body.Token, &body); err != nil {
+		if err := c.jwtManager.Verify(settings.DocSecret, body.Token, &body); err != nil {
 			c.logger.Errorf("could not verify callback jwt (%s). Reason: %s", body.Token, err.Error())
 			rw.WriteHeader(http.StatusForbidden)
 			rw.Write(response.CallbackResponse{
@@ -166,60 +144,93 @@ func (c CallbackController) BuildPostHandleCallback() http.HandlerFunc {
 			return
 		}
 
-		if body.Status == 2 {
-			filename := strings.TrimSpace(r.URL.Query().Get("filename"))
-			if filename == "" {
+		key := tctx.DocKey
+		if legacy {
+			key, err = c.getIssuedKey(r.Context(), tctx.CID, fid)
+			if err != nil {
+				c.logger.Errorf("could not get file %d:%s document key: %s", tctx.CID, fid, err.Error())
 				rw.WriteHeader(http.StatusInternalServerError)
-				c.logger.Errorf("callback request %s does not contain a filename", body.Key)
 				rw.Write(response.CallbackResponse{
 					Error: 1,
 				}.ToJSON())
 				return
 			}
+		}
 
-			ctx, cancel := context.WithTimeout(r.Context(), time.Duration(c.onlyoffice.Onlyoffice.Callback.UploadTimeout)*time.Second)
-			defer cancel()
+		if body.Key != key {
+			c.logger.Errorf("callback document key %s does not match the issued key %s", body.Key, key)
+			rw.WriteHeader(http.StatusForbidden)
+			rw.Write(response.CallbackResponse{
+				Error: 1,
+			}.ToJSON())
+			return
+		}
 
-			usr := body.Users[0]
-			if usr != "" {
-				size, err := c.pipedriveAPI.ValidateFileSize(ctx, c.onlyoffice.Onlyoffice.Callback.MaxSize, body.URL)
-				if err != nil {
-					c.logger.Errorf("could not validate file %s: %s", filename, err.Error())
-					rw.WriteHeader(http.StatusBadRequest)
-					rw.Write(response.CallbackResponse{
-						Error: 1,
-					}.ToJSON())
-					return
-				}
+		activity := request.DocumentActivity{
+			CompanyID:  tctx.CID,
+			FileID:     fid,
+			EntityType: entity.Type,
+			EntityID:   entity.ID,
+			DocKey:     body.Key,
+			Status:     body.Status,
+			Users:      c.getActiveEditors(body),
+		}
 
-				req := c.client.NewRequest(fmt.Sprintf("%s:auth", c.config.Namespace), "UserSelectHandler.GetUser", usr)
-				var ures response.UserResponse
-				if err := c.client.Call(ctx, req, &ures, client.WithRetries(3), client.WithBackoff(func(ctx context.Context, req client.Request, attempts int) (time.Duration, error) {
-					return backoff.Do(attempts), nil
-				})); err != nil {
-					c.logger.Errorf("could not get user tokens: %s", err.Error())
-					rw.WriteHeader(http.StatusBadRequest)
-					rw.Write(response.CallbackResponse{
-						Error: 1,
-					}.ToJSON())
-					return
-				}
+		switch body.Status {
+		case constants.CallbackStatusMustSave, constants.CallbackStatusMustForcesave:
+			filename := strings.TrimSpace(tctx.Filename)
+			if filename == "" {
+				c.logger.Errorf("callback request %s does not contain a filename", body.Key)
+				rw.WriteHeader(http.StatusInternalServerError)
+				rw.Write(response.CallbackResponse{
+					Error: 1,
+				}.ToJSON())
+				return
+			}
 
-				if err := c.pipedriveAPI.UploadFile(ctx, body.URL, did, fid, filename, size, model.Token{
-					AccessToken:  ures.AccessToken,
-					RefreshToken: ures.RefreshToken,
-					TokenType:    ures.TokenType,
-					Scope:        ures.Scope,
-					ApiDomain:    ures.ApiDomain,
-				}); err != nil {
-					c.logger.Debugf("could not upload an onlyoffice file to pipedrive: %s", err.Error())
+			job, created, err := c.queue.EnqueueJob(r.Context(), domain.CallbackJob{
+				CompanyID:  cid,
+				EntityType: entity.Type,
+				EntityID:   entity.ID,
+				FileID:     fid,
+				Filename:   filename,
+				DocKey:     body.Key,
+				Status:     body.Status,
+				URL:        body.URL,
+				FileType:   body.FileType,
+				Form: body.Status == constants.CallbackStatusMustForcesave &&
+					body.ForceSaveType == constants.CallbackForcesaveTypeSubmitForm,
+				ChangesURL: body.ChangesURL,
+				History:    body.History,
+				Users:      body.Users,
+			})
+			if err != nil {
+				c.logger.Errorf("could not enqueue callback request %s: %s", body.Key, err.Error())
+				var ferr *domain.InvalidModelFieldError
+				if errors.As(err, &ferr) {
 					rw.WriteHeader(http.StatusBadRequest)
-					rw.Write(response.CallbackResponse{
-						Error: 1,
-					}.ToJSON())
-					return
+				} else {
+					rw.WriteHeader(http.StatusInternalServerError)
 				}
+				rw.Write(response.CallbackResponse{
+					Error: 1,
+				}.ToJSON())
+				return
+			}
+
+			if !created {
+				c.logger.Debugf("callback job %s is a duplicate and has been acknowledged", job.ID)
 			}
+
+			// Everyone has left the document even though the upload may still be pending
+			if body.Status == constants.CallbackStatusMustSave {
+				c.recordActivity(r.Context(), activity)
+			}
+		case constants.CallbackStatusSaveError, constants.CallbackStatusForcesaveError:
+			c.logger.Warnf("document server could not save file %s (status %d)", fid, body.Status)
+			c.recordActivity(r.Context(), activity)
+		default:
+			c.recordActivity(r.Context(), activity)
 		}
 
 		rw.WriteHeader(http.StatusOK)
@@ -228,3 +239,103 @@ func (c CallbackController) BuildPostHandleCallback() http.HandlerFunc {
 		}.ToJSON())
 	}
 }
+
+// BuildPostPurgeCompany drops the queued jobs of a company once the last of its users has uninstalled the app.
+// The gateway authorizes the request with a purge token signed with the client secret.
+func (c CallbackController) BuildPostPurgeCompany() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		var tctx request.PurgeTokenContext
+		token := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if err := c.jwtManager.Verify(c.credentials.ClientSecret, token, &tctx); err != nil {
+			c.logger.Errorf("could not verify purge token: %s", err.Error())
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if err := tctx.Validate(); err != nil {
+			c.logger.Errorf("invalid purge token: %s", err.Error())
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		purged, err := c.queue.PurgeCompany(r.Context(), fmt.Sprint(tctx.CID))
+		if err != nil {
+			c.logger.Errorf("could not purge company %d callback jobs: %s", tctx.CID, err.Error())
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		c.logger.Debugf("purged %d callback jobs of uninstalled company %d", purged, tctx.CID)
+		rw.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// getCallbackContext verifies the signed callback url token. Editors opened before callback urls
+// were signed still call back with plain query parameters, which are only accepted within the
+// configured migration window and for the document key issued to the file.
+func (c CallbackController) getCallbackContext(query url.Values) (request.CallbackTokenContext, bool, error) {
+	var tctx request.CallbackTokenContext
+	if token := query.Get("token"); token != "" || !c.onlyoffice.Onlyoffice.Callback.AcceptsLegacyURLs(time.Now()) {
+		if err := c.jwtManager.Verify(c.credentials.ClientSecret, token, &tctx); err != nil {
+			return tctx, false, err
+		}
+
+		return tctx, false, tctx.Validate()
+	}
+
+	cid, err := strconv.Atoi(strings.TrimSpace(query.Get("cid")))
+	if err != nil {
+		return tctx, true, request.ErrInvalidCompanyID
+	}
+
+	entity := request.NewParentEntity(request.EntityDeal, query.Get("did"))
+	if id := query.Get("eid"); id != "" {
+		entity = request.NewParentEntity(query.Get("etype"), id)
+	}
+
+	c.logger.Warnf("accepting a legacy callback url of company %d file %s", cid, query.Get("fid"))
+	return request.CallbackTokenContext{
+		CID:        cid,
+		EntityType: entity.Type,
+		EntityID:   entity.ID,
+		FileID:     strings.TrimSpace(query.Get("fid")),
+		Filename:   query.Get("filename"),
+	}, true, nil
+}
+
+// getIssuedKey returns the current document key the documents service has issued for the file.
+func (c CallbackController) getIssuedKey(ctx context.Context, cid int, fid string) (string, error) {
+	var key response.DocumentKeyResponse
+	if err := c.client.Call(ctx, c.client.NewRequest(
+		fmt.Sprintf("%s:documents", c.config.Namespace), "KeyHandler.GetKey",
+		request.DocumentKey{CompanyID: cid, FileID: fid},
+	), &key); err != nil {
+		return "", err
+	}
+
+	return key.Key, nil
+}
+
+// getActiveEditors returns the users reported by the document server. Actions are left out
+// since a user who disconnects one tab may still have the document open in another.
+func (c CallbackController) getActiveEditors(body request.CallbackRequest) []string {
+	editors := make([]string, 0, len(body.Users))
+	seen := make(map[string]bool, len(body.Users))
+	for _, user := range body.Users {
+		if !seen[user] {
+			seen[user] = true
+			editors = append(editors, user)
+		}
+	}
+
+	return editors
+}
+
+// recordActivity is best-effort. A documents service failure must not fail the callback itself.
+func (c CallbackController) recordActivity(ctx context.Context, activity request.DocumentActivity) {
+	req := c.client.NewRequest(fmt.Sprintf("%s:documents", c.config.Namespace), "ActivityInsertHandler.InsertActivity", activity)
+	var res interface{}
+	if err := c.client.Call(ctx, req, &res); err != nil {
+		c.logger.Warnf("could not record file %s activity: %s", activity.FileID, err.Error())
+	}
+}
@@ -0,0 +1,264 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go-micro.dev/v4/client"
+	"golang.org/x/oauth2"
+)
+
+const (
+	testSecret    = "secret"
+	testDocSecret = "doc-secret"
+)
+
+var testJwtManager = crypto.NewJwtManager(&config.CryptoConfig{})
+
+type rpcClient struct {
+	client.Client
+}
+
+func (c rpcClient) NewRequest(service, endpoint string, req interface{}, opts ...client.RequestOption) client.Request {
+	return client.NewRequest(service, endpoint, req, opts...)
+}
+
+func (c rpcClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	switch req.Endpoint() {
+	case "SettingsSelectHandler.GetSettings":
+		*rsp.(*response.DocSettingsResponse) = response.DocSettingsResponse{
+			DocAddress: "https://docs.example.com",
+			DocSecret:  testDocSecret,
+			DocHeader:  "Authorization",
+		}
+		return nil
+	case "ActivityInsertHandler.InsertActivity":
+		return nil
+	case "KeyHandler.GetKey":
+		*rsp.(*response.DocumentKeyResponse) = response.DocumentKeyResponse{
+			FileID:   req.Body().(request.DocumentKey).FileID,
+			Revision: 1,
+			Key:      "issued-key",
+		}
+		return nil
+	default:
+		return fmt.Errorf("unexpected call %s", req.Endpoint())
+	}
+}
+
+type mockQueue struct {
+	jobs   []domain.CallbackJob
+	purged []string
+}
+
+func (q *mockQueue) EnqueueJob(ctx context.Context, job domain.CallbackJob) (domain.CallbackJob, bool, error) {
+	q.jobs = append(q.jobs, job)
+	return job, true, nil
+}
+
+func (q *mockQueue) ProcessNext(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
+func (q *mockQueue) PurgeJobs(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (q *mockQueue) PurgeCompany(ctx context.Context, cid string) (int64, error) {
+	q.purged = append(q.purged, cid)
+	return 1, nil
+}
+
+func newCallbackController(queue *mockQueue, legacyURLsUntil time.Time) *CallbackController {
+	var onlyoffice shared.OnlyofficeConfig
+	if !legacyURLsUntil.IsZero() {
+		onlyoffice.Onlyoffice.Callback.LegacyURLsUntil = legacyURLsUntil.Format(time.RFC3339)
+	}
+	return NewCallbackController(
+		rpcClient{}, queue, testJwtManager, &config.ServerConfig{Namespace: "test"},
+		&onlyoffice, &oauth2.Config{ClientSecret: testSecret}, log.NewEmptyLogger(),
+	)
+}
+
+// callback posts a save callback the document server signed for the document key.
+func callback(t *testing.T, controller *CallbackController, query url.Values, key string) *httptest.ResponseRecorder {
+	token, err := testJwtManager.Sign(testDocSecret, jwt.MapClaims{
+		"key":    key,
+		"status": 2,
+		"url":    "https://docs.example.com/cache/file.docx",
+		"users":  []string{"1:2"},
+	})
+	assert.NoError(t, err)
+
+	body, _ := json.Marshal(map[string]string{"token": token})
+	rw := httptest.NewRecorder()
+	controller.BuildPostHandleCallback()(rw, httptest.NewRequest(
+		http.MethodPost, "/callback?"+query.Encode(), bytes.NewReader(body),
+	))
+	return rw
+}
+
+func signCallbackURL(t *testing.T, claims jwt.Claims) url.Values {
+	token, err := testJwtManager.Sign(testSecret, claims)
+	assert.NoError(t, err)
+	return url.Values{"token": {token}}
+}
+
+func TestCallback(t *testing.T) {
+	entity := request.NewParentEntity(request.EntityDeal, "5")
+
+	t.Run("save to the file of a signed callback url", func(t *testing.T) {
+		queue := &mockQueue{}
+		rw := callback(t, newCallbackController(queue, time.Time{}), signCallbackURL(t,
+			request.NewCallbackTokenContext(1, entity, "10", "Contract.docx", "key"),
+		), "key")
+
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Len(t, queue.jobs, 1)
+		assert.Equal(t, "1", queue.jobs[0].CompanyID)
+		assert.Equal(t, "10", queue.jobs[0].FileID)
+		assert.Equal(t, "5", queue.jobs[0].EntityID)
+		assert.Equal(t, "Contract.docx", queue.jobs[0].Filename)
+	})
+
+	t.Run("reject callbacks for another document key", func(t *testing.T) {
+		queue := &mockQueue{}
+		rw := callback(t, newCallbackController(queue, time.Time{}), signCallbackURL(t,
+			request.NewCallbackTokenContext(1, entity, "10", "Contract.docx", "key"),
+		), "another")
+
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+		assert.Empty(t, queue.jobs)
+	})
+
+	t.Run("reject tokens issued for another purpose", func(t *testing.T) {
+		queue := &mockQueue{}
+		controller := newCallbackController(queue, time.Time{})
+		assert.Equal(t, http.StatusForbidden, callback(t, controller, signCallbackURL(t,
+			request.NewDownloadTokenContext(request.NewUserIdentity(1, 2), "10"),
+		), "key").Code)
+		assert.Equal(t, http.StatusForbidden, callback(t, controller, signCallbackURL(t, jwt.MapClaims{
+			"exp": time.Now().Add(time.Minute).Unix(), "cid": 1, "etype": "deal", "eid": "5",
+			"fid": "10", "filename": "Contract.docx", "key": "key",
+		}), "key").Code)
+		assert.Empty(t, queue.jobs)
+	})
+
+	t.Run("reject callback urls signed with another secret", func(t *testing.T) {
+		queue := &mockQueue{}
+		token, err := testJwtManager.Sign("another", request.NewCallbackTokenContext(1, entity, "10", "Contract.docx", "key"))
+		assert.NoError(t, err)
+
+		rw := callback(t, newCallbackController(queue, time.Time{}), url.Values{"token": {token}}, "key")
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+		assert.Empty(t, queue.jobs)
+	})
+
+	legacy := url.Values{
+		"cid": {"1"}, "did": {"5"}, "fid": {"10"}, "filename": {"Contract.docx"},
+	}
+
+	t.Run("accept legacy callback urls of issued keys within the migration window", func(t *testing.T) {
+		queue := &mockQueue{}
+		rw := callback(t, newCallbackController(queue, time.Now().Add(time.Hour)), legacy, "issued-key")
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Len(t, queue.jobs, 1)
+		assert.Equal(t, request.EntityDeal, queue.jobs[0].EntityType)
+		assert.Equal(t, "5", queue.jobs[0].EntityID)
+	})
+
+	t.Run("reject legacy callback urls of keys that were not issued", func(t *testing.T) {
+		queue := &mockQueue{}
+		rw := callback(t, newCallbackController(queue, time.Now().Add(time.Hour)), legacy, "legacy-key")
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+		assert.Empty(t, queue.jobs)
+	})
+
+	t.Run("reject legacy callback urls outside the migration window", func(t *testing.T) {
+		queue := &mockQueue{}
+		assert.Equal(t, http.StatusForbidden, callback(t, newCallbackController(queue, time.Time{}), legacy, "issued-key").Code)
+		assert.Equal(t, http.StatusForbidden, callback(t, newCallbackController(queue, time.Now().Add(-time.Hour)), legacy, "issued-key").Code)
+		assert.Empty(t, queue.jobs)
+	})
+}
+
+func TestGetActiveEditors(t *testing.T) {
+	var body request.CallbackRequest
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"key": "mock",
+		"status": 1,
+		"users": ["1:1", "1:2", "1:1"],
+		"actions": [{"type": 0, "userid": "1:1"}]
+	}`), &body))
+
+	controller := newCallbackController(&mockQueue{}, time.Time{})
+	assert.Equal(t, []string{"1:1", "1:2"}, controller.getActiveEditors(body))
+}
+
+func TestPurgeCompany(t *testing.T) {
+	purge := func(controller *CallbackController, claims jwt.Claims, secret string) int {
+		token, err := testJwtManager.Sign(secret, claims)
+		assert.NoError(t, err)
+
+		rw := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/purge", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		controller.BuildPostPurgeCompany()(rw, r)
+		return rw.Code
+	}
+
+	t.Run("purge the jobs of the signed company", func(t *testing.T) {
+		queue := &mockQueue{}
+		assert.Equal(t, http.StatusNoContent, purge(newCallbackController(queue, time.Time{}), request.NewPurgeTokenContext(1), testSecret))
+		assert.Equal(t, []string{"1"}, queue.purged)
+	})
+
+	t.Run("reject purge tokens signed with another secret", func(t *testing.T) {
+		queue := &mockQueue{}
+		assert.Equal(t, http.StatusForbidden, purge(newCallbackController(queue, time.Time{}), request.NewPurgeTokenContext(1), "another"))
+		assert.Empty(t, queue.purged)
+	})
+
+	t.Run("reject tokens issued for another purpose", func(t *testing.T) {
+		queue := &mockQueue{}
+		assert.Equal(t, http.StatusForbidden, purge(newCallbackController(queue, time.Time{}),
+			request.NewCallbackTokenContext(1, request.NewParentEntity(request.EntityDeal, "5"), "10", "Contract.docx", "key"),
+			testSecret,
+		))
+		assert.Empty(t, queue.purged)
+	})
+}
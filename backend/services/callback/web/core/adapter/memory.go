@@ -0,0 +1,232 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/port"
+)
+
+// memoryCallbackQueueAdapter is guarded by a mutex since jobs are claimed by concurrent workers.
+// Pending and processing job ids are tracked separately so that claims do not scan finished jobs.
+type memoryCallbackQueueAdapter struct {
+	mu     sync.Mutex
+	kvs    map[string][]byte
+	active map[string]struct{}
+}
+
+func NewMemoryCallbackQueueAdapter() port.CallbackQueueAdapter {
+	return &memoryCallbackQueueAdapter{
+		kvs:    make(map[string][]byte),
+		active: make(map[string]struct{}),
+	}
+}
+
+func (m *memoryCallbackQueueAdapter) InsertJob(ctx context.Context, job domain.CallbackJob) (bool, error) {
+	if err := job.Validate(); err != nil {
+		return false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.kvs[job.ID]; ok {
+		return false, nil
+	}
+
+	return true, m.save(job)
+}
+
+func (m *memoryCallbackQueueAdapter) ClaimJob(ctx context.Context, now time.Time, lease time.Duration) (domain.CallbackJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var (
+		claimed domain.CallbackJob
+		found   bool
+	)
+
+	for id := range m.active {
+		var job domain.CallbackJob
+		if err := json.Unmarshal(m.kvs[id], &job); err != nil {
+			return job, err
+		}
+
+		due := (job.State == domain.CallbackJobPending && !job.NextAttemptAt.After(now)) ||
+			(job.State == domain.CallbackJobProcessing && job.LockedUntil.Before(now))
+		if due && (!found || job.NextAttemptAt.Before(claimed.NextAttemptAt)) {
+			claimed, found = job, true
+		}
+	}
+
+	if !found {
+		return claimed, ErrNoCallbackJobs
+	}
+
+	claimed.State = domain.CallbackJobProcessing
+	claimed.Attempts++
+	claimed.LockedUntil = now.Add(lease)
+	claimed.UpdatedAt = now
+	return claimed, m.save(claimed)
+}
+
+func (m *memoryCallbackQueueAdapter) MarkUploaded(ctx context.Context, id, fileID string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrInvalidJobID
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.get(id)
+	if err != nil {
+		return err
+	}
+
+	job.UploadedFileID = fileID
+	job.UpdatedAt = time.Now()
+	return m.save(job)
+}
+
+func (m *memoryCallbackQueueAdapter) CompleteJob(ctx context.Context, id string, expires time.Time) error {
+	return m.update(id, func(job *domain.CallbackJob) {
+		job.State = domain.CallbackJobCompleted
+		job.LastError = ""
+		job.ExpiresAt = expires
+	})
+}
+
+func (m *memoryCallbackQueueAdapter) RetryJob(ctx context.Context, id, reason string, next time.Time) error {
+	return m.update(id, func(job *domain.CallbackJob) {
+		job.State = domain.CallbackJobPending
+		job.LastError = reason
+		job.NextAttemptAt = next
+	})
+}
+
+func (m *memoryCallbackQueueAdapter) BuryJob(ctx context.Context, id, reason string, expires time.Time) error {
+	return m.update(id, func(job *domain.CallbackJob) {
+		job.State = domain.CallbackJobDead
+		job.LastError = reason
+		job.ExpiresAt = expires
+	})
+}
+
+func (m *memoryCallbackQueueAdapter) PurgeJobs(ctx context.Context, now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var purged int64
+	for id, buffer := range m.kvs {
+		if _, ok := m.active[id]; ok {
+			continue
+		}
+
+		var job domain.CallbackJob
+		if err := json.Unmarshal(buffer, &job); err != nil {
+			return purged, err
+		}
+
+		if !job.ExpiresAt.IsZero() && !job.ExpiresAt.After(now) {
+			delete(m.kvs, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func (m *memoryCallbackQueueAdapter) DeleteCompanyJobs(ctx context.Context, cid string) (int64, error) {
+	cid = strings.TrimSpace(cid)
+	if cid == "" {
+		return 0, ErrInvalidCompanyID
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	for id, buffer := range m.kvs {
+		var job domain.CallbackJob
+		if err := json.Unmarshal(buffer, &job); err != nil {
+			return deleted, err
+		}
+
+		if job.CompanyID == cid {
+			delete(m.kvs, id)
+			delete(m.active, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func (m *memoryCallbackQueueAdapter) update(id string, apply func(job *domain.CallbackJob)) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrInvalidJobID
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.get(id)
+	if err != nil {
+		return err
+	}
+
+	apply(&job)
+	job.LockedUntil = time.Time{}
+	job.UpdatedAt = time.Now()
+	return m.save(job)
+}
+
+func (m *memoryCallbackQueueAdapter) get(id string) (domain.CallbackJob, error) {
+	var job domain.CallbackJob
+	buffer, ok := m.kvs[id]
+	if !ok {
+		return job, ErrNoCallbackJobs
+	}
+
+	return job, json.Unmarshal(buffer, &job)
+}
+
+func (m *memoryCallbackQueueAdapter) save(job domain.CallbackJob) error {
+	buffer, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	m.kvs[job.ID] = buffer
+	if job.State == domain.CallbackJobPending || job.State == domain.CallbackJobProcessing {
+		m.active[job.ID] = struct{}{}
+	} else {
+		delete(m.active, job.ID)
+	}
+
+	return nil
+}
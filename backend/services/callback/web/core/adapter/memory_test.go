@@ -0,0 +1,156 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var job = domain.CallbackJob{
+	CompanyID:     "1",
+	EntityType:    "deal",
+	EntityID:      "2",
+	FileID:        "3",
+	Filename:      "mock.docx",
+	DocKey:        "mock",
+	Status:        2,
+	URL:           "https://example.com/mock.docx",
+	Users:         []string{"1:1"},
+	State:         domain.CallbackJobPending,
+	NextAttemptAt: time.Now().Add(-time.Second),
+}
+
+func TestMemoryAdapter(t *testing.T) {
+	adapter := NewMemoryCallbackQueueAdapter()
+
+	t.Run("claim from an empty queue", func(t *testing.T) {
+		_, err := adapter.ClaimJob(context.Background(), time.Now(), time.Minute)
+		assert.ErrorIs(t, err, ErrNoCallbackJobs)
+	})
+
+	t.Run("insert job", func(t *testing.T) {
+		created, err := adapter.InsertJob(context.Background(), job)
+		assert.NoError(t, err)
+		assert.True(t, created)
+	})
+
+	t.Run("insert duplicate job", func(t *testing.T) {
+		created, err := adapter.InsertJob(context.Background(), job)
+		assert.NoError(t, err)
+		assert.False(t, created)
+	})
+
+	t.Run("claim job", func(t *testing.T) {
+		j, err := adapter.ClaimJob(context.Background(), time.Now(), time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CallbackJobProcessing, j.State)
+		assert.Equal(t, 1, j.Attempts)
+	})
+
+	t.Run("claimed job is locked", func(t *testing.T) {
+		_, err := adapter.ClaimJob(context.Background(), time.Now(), time.Minute)
+		assert.ErrorIs(t, err, ErrNoCallbackJobs)
+	})
+
+	t.Run("expired lease can be claimed again", func(t *testing.T) {
+		j, err := adapter.ClaimJob(context.Background(), time.Now().Add(2*time.Minute), time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, j.Attempts)
+	})
+
+	t.Run("retry job is not due yet", func(t *testing.T) {
+		assert.NoError(t, adapter.RetryJob(context.Background(), job.Key(), "mock", time.Now().Add(time.Hour)))
+		_, err := adapter.ClaimJob(context.Background(), time.Now(), time.Minute)
+		assert.ErrorIs(t, err, ErrNoCallbackJobs)
+	})
+
+	t.Run("mark job as uploaded", func(t *testing.T) {
+		assert.NoError(t, adapter.MarkUploaded(context.Background(), job.Key(), "4"))
+		j, err := adapter.ClaimJob(context.Background(), time.Now().Add(2*time.Hour), time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, "4", j.UploadedFileID)
+	})
+
+	t.Run("complete job", func(t *testing.T) {
+		assert.NoError(t, adapter.CompleteJob(context.Background(), job.Key(), time.Now().Add(time.Hour)))
+		_, err := adapter.ClaimJob(context.Background(), time.Now().Add(2*time.Hour), time.Minute)
+		assert.ErrorIs(t, err, ErrNoCallbackJobs)
+	})
+
+	t.Run("complete unknown job", func(t *testing.T) {
+		assert.ErrorIs(t, adapter.CompleteJob(context.Background(), "unknown", time.Now()), ErrNoCallbackJobs)
+	})
+
+	t.Run("completed job is kept until it expires", func(t *testing.T) {
+		purged, err := adapter.PurgeJobs(context.Background(), time.Now())
+		assert.NoError(t, err)
+		assert.Zero(t, purged)
+		created, err := adapter.InsertJob(context.Background(), job)
+		assert.NoError(t, err)
+		assert.False(t, created)
+	})
+
+	t.Run("purge expired job", func(t *testing.T) {
+		purged, err := adapter.PurgeJobs(context.Background(), time.Now().Add(2*time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+		created, err := adapter.InsertJob(context.Background(), job)
+		assert.NoError(t, err)
+		assert.True(t, created)
+	})
+
+	t.Run("buried job expires", func(t *testing.T) {
+		assert.NoError(t, adapter.BuryJob(context.Background(), job.Key(), "mock", time.Now()))
+		_, err := adapter.ClaimJob(context.Background(), time.Now().Add(2*time.Hour), time.Minute)
+		assert.ErrorIs(t, err, ErrNoCallbackJobs)
+		purged, err := adapter.PurgeJobs(context.Background(), time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+	})
+
+	t.Run("delete company jobs", func(t *testing.T) {
+		other := job
+		other.CompanyID, other.DocKey = "2", "other"
+		for _, j := range []domain.CallbackJob{job, other} {
+			created, err := adapter.InsertJob(context.Background(), j)
+			assert.NoError(t, err)
+			assert.True(t, created)
+		}
+
+		deleted, err := adapter.DeleteCompanyJobs(context.Background(), job.CompanyID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		claimed, err := adapter.ClaimJob(context.Background(), time.Now(), time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, "2", claimed.CompanyID)
+		_, err = adapter.ClaimJob(context.Background(), time.Now(), time.Minute)
+		assert.ErrorIs(t, err, ErrNoCallbackJobs)
+	})
+
+	t.Run("delete jobs of a blank company", func(t *testing.T) {
+		_, err := adapter.DeleteCompanyJobs(context.Background(), " ")
+		assert.ErrorIs(t, err, ErrInvalidCompanyID)
+	})
+}
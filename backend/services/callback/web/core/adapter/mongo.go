@@ -0,0 +1,262 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type callbackJobCollection struct {
+	mgm.DefaultModel `bson:",inline"`
+	JobID            string                  `json:"job_id" bson:"job_id"`
+	CompanyID        string                  `json:"company_id" bson:"company_id"`
+	EntityType       string                  `json:"entity_type" bson:"entity_type"`
+	EntityID         string                  `json:"entity_id" bson:"entity_id"`
+	FileID           string                  `json:"file_id" bson:"file_id"`
+	Filename         string                  `json:"filename" bson:"filename"`
+	DocKey           string                  `json:"doc_key" bson:"doc_key"`
+	Status           int                     `json:"status" bson:"status"`
+	URL              string                  `json:"url" bson:"url"`
+	FileType         string                  `json:"filetype" bson:"filetype"`
+	Form             bool                    `json:"form" bson:"form"`
+	ChangesURL       string                  `json:"changes_url" bson:"changes_url"`
+	History          request.CallbackHistory `json:"history" bson:"history"`
+	Users            []string                `json:"users" bson:"users"`
+	State            string                  `json:"state" bson:"state"`
+	Attempts         int                     `json:"attempts" bson:"attempts"`
+	LastError        string                  `json:"last_error" bson:"last_error"`
+	NextAttemptAt    time.Time               `json:"next_attempt_at" bson:"next_attempt_at"`
+	LockedUntil      time.Time               `json:"locked_until" bson:"locked_until"`
+	UploadedFileID   string                  `json:"uploaded_file_id" bson:"uploaded_file_id"`
+	// ExpiresAt is only set on finished jobs, since the ttl index removes any document with a date in the past.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+}
+
+type mongoCallbackQueueAdapter struct {
+}
+
+func NewMongoCallbackQueueAdapter(url string) port.CallbackQueueAdapter {
+	if err := mgm.SetDefaultConfig(
+		&mgm.Config{CtxTimeout: 3 * time.Second}, "pipedrive",
+		options.Client().ApplyURI(url),
+	); err != nil {
+		log.Fatalf("mongo initialization error: %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := mgm.Coll(&callbackJobCollection{}).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "job_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "state", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	}); err != nil {
+		log.Fatalf("mongo index initialization error: %s", err.Error())
+	}
+
+	return &mongoCallbackQueueAdapter{}
+}
+
+func (m *mongoCallbackQueueAdapter) InsertJob(ctx context.Context, job domain.CallbackJob) (bool, error) {
+	if err := job.Validate(); err != nil {
+		return false, err
+	}
+
+	if err := mgm.Coll(&callbackJobCollection{}).CreateWithCtx(ctx, &callbackJobCollection{
+		JobID:         job.ID,
+		CompanyID:     job.CompanyID,
+		EntityType:    job.EntityType,
+		EntityID:      job.EntityID,
+		FileID:        job.FileID,
+		Filename:      job.Filename,
+		DocKey:        job.DocKey,
+		Status:        job.Status,
+		URL:           job.URL,
+		FileType:      job.FileType,
+		Form:          job.Form,
+		ChangesURL:    job.ChangesURL,
+		History:       job.History,
+		Users:         job.Users,
+		State:         job.State,
+		NextAttemptAt: job.NextAttemptAt,
+	}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (m *mongoCallbackQueueAdapter) ClaimJob(ctx context.Context, now time.Time, lease time.Duration) (domain.CallbackJob, error) {
+	job := &callbackJobCollection{}
+	if err := mgm.Coll(job).FindOneAndUpdate(ctx, bson.M{
+		"$or": bson.A{
+			bson.M{"state": domain.CallbackJobPending, "next_attempt_at": bson.M{"$lte": now}},
+			bson.M{"state": domain.CallbackJobProcessing, "locked_until": bson.M{"$lt": now}},
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"state":        domain.CallbackJobProcessing,
+			"locked_until": now.Add(lease),
+			"updated_at":   now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}, options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After),
+	).Decode(job); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.CallbackJob{}, ErrNoCallbackJobs
+		}
+
+		return domain.CallbackJob{}, err
+	}
+
+	return domain.CallbackJob{
+		ID:             job.JobID,
+		CompanyID:      job.CompanyID,
+		EntityType:     job.EntityType,
+		EntityID:       job.EntityID,
+		FileID:         job.FileID,
+		Filename:       job.Filename,
+		DocKey:         job.DocKey,
+		Status:         job.Status,
+		URL:            job.URL,
+		FileType:       job.FileType,
+		Form:           job.Form,
+		ChangesURL:     job.ChangesURL,
+		History:        job.History,
+		Users:          job.Users,
+		State:          job.State,
+		Attempts:       job.Attempts,
+		LastError:      job.LastError,
+		NextAttemptAt:  job.NextAttemptAt,
+		LockedUntil:    job.LockedUntil,
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+		UploadedFileID: job.UploadedFileID,
+	}, nil
+}
+
+// MarkUploaded keeps the job lease, since the job is still being processed.
+func (m *mongoCallbackQueueAdapter) MarkUploaded(ctx context.Context, id, fileID string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrInvalidJobID
+	}
+
+	res, err := mgm.Coll(&callbackJobCollection{}).UpdateOne(ctx, bson.M{"job_id": id}, bson.M{"$set": bson.M{
+		"uploaded_file_id": fileID,
+		"updated_at":       time.Now(),
+	}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrNoCallbackJobs
+	}
+
+	return nil
+}
+
+func (m *mongoCallbackQueueAdapter) CompleteJob(ctx context.Context, id string, expires time.Time) error {
+	return m.update(ctx, id, bson.M{
+		"state":      domain.CallbackJobCompleted,
+		"last_error": "",
+		"expires_at": expires,
+	})
+}
+
+func (m *mongoCallbackQueueAdapter) RetryJob(ctx context.Context, id, reason string, next time.Time) error {
+	return m.update(ctx, id, bson.M{
+		"state":           domain.CallbackJobPending,
+		"last_error":      reason,
+		"next_attempt_at": next,
+	})
+}
+
+func (m *mongoCallbackQueueAdapter) BuryJob(ctx context.Context, id, reason string, expires time.Time) error {
+	return m.update(ctx, id, bson.M{
+		"state":      domain.CallbackJobDead,
+		"last_error": reason,
+		"expires_at": expires,
+	})
+}
+
+// PurgeJobs backs up the ttl index, which mongo only runs once a minute.
+func (m *mongoCallbackQueueAdapter) PurgeJobs(ctx context.Context, now time.Time) (int64, error) {
+	res, err := mgm.Coll(&callbackJobCollection{}).DeleteMany(ctx, bson.M{
+		"expires_at": bson.M{"$lte": now},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
+func (m *mongoCallbackQueueAdapter) DeleteCompanyJobs(ctx context.Context, cid string) (int64, error) {
+	cid = strings.TrimSpace(cid)
+	if cid == "" {
+		return 0, ErrInvalidCompanyID
+	}
+
+	res, err := mgm.Coll(&callbackJobCollection{}).DeleteMany(ctx, bson.M{"company_id": cid})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
+func (m *mongoCallbackQueueAdapter) update(ctx context.Context, id string, fields bson.M) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrInvalidJobID
+	}
+
+	fields["locked_until"] = time.Time{}
+	fields["updated_at"] = time.Now()
+	res, err := mgm.Coll(&callbackJobCollection{}).UpdateOne(ctx, bson.M{"job_id": id}, bson.M{"$set": fields})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrNoCallbackJobs
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+)
+
+const (
+	CallbackJobPending    = "pending"
+	CallbackJobProcessing = "processing"
+	CallbackJobCompleted  = "completed"
+	CallbackJobDead       = "dead"
+)
+
+// CallbackJob is a persisted document server callback waiting to be applied to Pipedrive.
+type CallbackJob struct {
+	ID            string                  `json:"id" mapstructure:"id"`
+	CompanyID     string                  `json:"company_id" mapstructure:"company_id"`
+	EntityType    string                  `json:"entity_type" mapstructure:"entity_type"`
+	EntityID      string                  `json:"entity_id" mapstructure:"entity_id"`
+	FileID        string                  `json:"file_id" mapstructure:"file_id"`
+	Filename      string                  `json:"filename" mapstructure:"filename"`
+	DocKey        string                  `json:"doc_key" mapstructure:"doc_key"`
+	Status        int                     `json:"status" mapstructure:"status"`
+	URL           string                  `json:"url" mapstructure:"url"`
+	FileType      string                  `json:"filetype,omitempty" mapstructure:"filetype"`
+	Form          bool                    `json:"form,omitempty" mapstructure:"form"`
+	ChangesURL    string                  `json:"changes_url,omitempty" mapstructure:"changes_url"`
+	History       request.CallbackHistory `json:"history" mapstructure:"history"`
+	Users         []string                `json:"users" mapstructure:"users"`
+	State         string                  `json:"state" mapstructure:"state"`
+	Attempts      int                     `json:"attempts" mapstructure:"attempts"`
+	LastError     string                  `json:"last_error,omitempty" mapstructure:"last_error"`
+	NextAttemptAt time.Time               `json:"next_attempt_at" mapstructure:"next_attempt_at"`
+	LockedUntil   time.Time               `json:"locked_until" mapstructure:"locked_until"`
+	CreatedAt     time.Time               `json:"created_at" mapstructure:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at" mapstructure:"updated_at"`
+	// UploadedFileID is set as soon as the saved file is attached, so that a retried job does not attach it again.
+	UploadedFileID string    `json:"uploaded_file_id,omitempty" mapstructure:"uploaded_file_id"`
+	ExpiresAt      time.Time `json:"expires_at,omitempty" mapstructure:"expires_at"`
+}
+
+// Key deduplicates document server retries. A document key is reused by every
+// forcesave within a single editing session, so forcesaves are also told apart by their file url.
+func (j CallbackJob) Key() string {
+	if j.Status == constants.CallbackStatusMustForcesave {
+		hash := sha256.Sum256([]byte(j.URL))
+		return fmt.Sprintf("%s:%d:%s", j.DocKey, j.Status, hex.EncodeToString(hash[:8]))
+	}
+
+	return fmt.Sprintf("%s:%d", j.DocKey, j.Status)
+}
+
+// UploadFilename returns the name the saved file is uploaded under. Submitted forms are kept
+// next to their blank, so they are named after the time they were submitted.
+func (j CallbackJob) UploadFilename() string {
+	if !j.Form {
+		return j.Filename
+	}
+
+	submitted := j.CreatedAt
+	if submitted.IsZero() {
+		submitted = time.Now()
+	}
+
+	ext := filepath.Ext(j.Filename)
+	return fmt.Sprintf(
+		"%s (%s)%s",
+		strings.TrimSuffix(j.Filename, ext),
+		submitted.UTC().Format("2006-01-02 15-04-05"),
+		ext,
+	)
+}
+
+func (j CallbackJob) ToJSON() []byte {
+	buf, _ := json.Marshal(j)
+	return buf
+}
+
+func (j *CallbackJob) Validate() error {
+	j.CompanyID = strings.TrimSpace(j.CompanyID)
+	j.FileID = strings.TrimSpace(j.FileID)
+	j.Filename = strings.TrimSpace(j.Filename)
+	j.DocKey = strings.TrimSpace(j.DocKey)
+	j.URL = strings.TrimSpace(j.URL)
+	j.FileType = strings.ToLower(strings.TrimSpace(j.FileType))
+	j.ChangesURL = strings.TrimSpace(j.ChangesURL)
+
+	if j.CompanyID == "" {
+		return &InvalidModelFieldError{
+			Model:  "CallbackJob",
+			Field:  "CompanyID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	entity := request.NewParentEntity(j.EntityType, j.EntityID)
+	if err := entity.Validate(); err != nil {
+		return &InvalidModelFieldError{
+			Model:  "CallbackJob",
+			Field:  "Entity",
+			Reason: err.Error(),
+		}
+	}
+
+	j.EntityType, j.EntityID = entity.Type, entity.ID
+
+	if j.FileID == "" {
+		return &InvalidModelFieldError{
+			Model:  "CallbackJob",
+			Field:  "FileID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if j.Filename == "" {
+		return &InvalidModelFieldError{
+			Model:  "CallbackJob",
+			Field:  "Filename",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if j.DocKey == "" {
+		return &InvalidModelFieldError{
+			Model:  "CallbackJob",
+			Field:  "DocKey",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if j.URL == "" {
+		return &InvalidModelFieldError{
+			Model:  "CallbackJob",
+			Field:  "URL",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if len(j.Users) == 0 {
+		return &InvalidModelFieldError{
+			Model:  "CallbackJob",
+			Field:  "Users",
+			Reason: "Should contain at least one user",
+		}
+	}
+
+	if j.Status != constants.CallbackStatusMustSave && j.Status != constants.CallbackStatusMustForcesave {
+		return &InvalidModelFieldError{
+			Model:  "CallbackJob",
+			Field:  "Status",
+			Reason: "Only save and forcesave callbacks can be queued",
+		}
+	}
+
+	if j.ID == "" {
+		j.ID = j.Key()
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package port
+
+import (
+	"context"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/domain"
+)
+
+type CallbackQueueService interface {
+	// EnqueueJob persists a callback job. The returned flag is false when the job is a duplicate.
+	EnqueueJob(ctx context.Context, job domain.CallbackJob) (domain.CallbackJob, bool, error)
+	// ProcessNext claims and processes a single due job. The returned flag is false when there is nothing to do.
+	ProcessNext(ctx context.Context) (bool, error)
+	// PurgeJobs removes finished jobs once their retention has passed.
+	PurgeJobs(ctx context.Context) (int64, error)
+	// PurgeCompany drops the jobs of an uninstalled company, including those still pending.
+	PurgeCompany(ctx context.Context, cid string) (int64, error)
+}
+
+type CallbackJobProcessor interface {
+	// Upload attaches the saved file to Pipedrive and returns the new file id.
+	Upload(ctx context.Context, job domain.CallbackJob) (string, error)
+	// Finish records the uploaded file. It may run more than once for the same job.
+	Finish(ctx context.Context, job domain.CallbackJob)
+	Reject(ctx context.Context, job domain.CallbackJob, reason error)
+}
@@ -0,0 +1,38 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/domain"
+)
+
+type CallbackQueueAdapter interface {
+	InsertJob(ctx context.Context, job domain.CallbackJob) (bool, error)
+	ClaimJob(ctx context.Context, now time.Time, lease time.Duration) (domain.CallbackJob, error)
+	MarkUploaded(ctx context.Context, id, fileID string) error
+	CompleteJob(ctx context.Context, id string, expires time.Time) error
+	RetryJob(ctx context.Context, id, reason string, next time.Time) error
+	BuryJob(ctx context.Context, id, reason string, expires time.Time) error
+	PurgeJobs(ctx context.Context, now time.Time) (int64, error)
+	// DeleteCompanyJobs removes every job of the company, whatever its state.
+	DeleteCompanyJobs(ctx context.Context, cid string) (int64, error)
+}
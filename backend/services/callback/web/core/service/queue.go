@@ -0,0 +1,201 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+)
+
+const (
+	maxRetryDelay = time.Hour
+	finishTimeout = 15 * time.Second
+)
+
+type callbackQueueService struct {
+	adapter   port.CallbackQueueAdapter
+	processor port.CallbackJobProcessor
+	config    *shared.OnlyofficeConfig
+	logger    plog.Logger
+}
+
+func NewCallbackQueueService(
+	adapter port.CallbackQueueAdapter,
+	processor port.CallbackJobProcessor,
+	config *shared.OnlyofficeConfig,
+	logger plog.Logger,
+) port.CallbackQueueService {
+	return callbackQueueService{
+		adapter:   adapter,
+		processor: processor,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+func (s callbackQueueService) EnqueueJob(ctx context.Context, job domain.CallbackJob) (domain.CallbackJob, bool, error) {
+	s.logger.Debugf("validating callback job %s to perform an enqueue action", job.DocKey)
+	if err := job.Validate(); err != nil {
+		return job, false, err
+	}
+
+	if ctx.Err() != nil {
+		return job, false, ErrOperationTimeout
+	}
+
+	now := time.Now()
+	job.State = domain.CallbackJobPending
+	job.Attempts = 0
+	job.LastError = ""
+	job.NextAttemptAt = now
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	created, err := s.adapter.InsertJob(ctx, job)
+	if err != nil {
+		return job, false, err
+	}
+
+	if !created {
+		s.logger.Debugf("callback job %s has already been enqueued", job.ID)
+	}
+
+	return job, created, nil
+}
+
+// ProcessNext claims a due job and hands it to the processor. Failed jobs are retried
+// with an exponential backoff and buried once they run out of attempts. Finished jobs are
+// kept for the configured retention so that late document server retries are still deduplicated.
+func (s callbackQueueService) ProcessNext(ctx context.Context) (bool, error) {
+	timeout := time.Duration(s.config.Onlyoffice.Callback.UploadTimeout) * time.Second
+	job, err := s.adapter.ClaimJob(ctx, time.Now(), timeout+30*time.Second)
+	if err != nil {
+		if errors.Is(err, adapter.ErrNoCallbackJobs) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	s.logger.Debugf("processing callback job %s (attempt %d)", job.ID, job.Attempts)
+	pctx, cancel := context.WithTimeout(ctx, timeout)
+	perr := s.process(ctx, pctx, job)
+	cancel()
+
+	if perr == nil {
+		s.logger.Debugf("callback job %s has been processed", job.ID)
+		return true, s.adapter.CompleteJob(ctx, job.ID, s.getExpiration())
+	}
+
+	if job.Attempts >= s.config.Onlyoffice.Callback.MaxAttempts {
+		s.logger.Errorf("callback job %s has been moved to the dead letter state: %s", job.ID, perr.Error())
+		s.processor.Reject(ctx, job, perr)
+		return true, s.adapter.BuryJob(ctx, job.ID, perr.Error(), s.getExpiration())
+	}
+
+	next := time.Now().Add(s.getRetryDelay(job.Attempts))
+	s.logger.Warnf("callback job %s failed, retrying at %s: %s", job.ID, next.Format(time.RFC3339), perr.Error())
+	return true, s.adapter.RetryJob(ctx, job.ID, perr.Error(), next)
+}
+
+// process skips the upload of a job that has already attached its file, e.g. when the job
+// could not be completed or its lease expired after the upload.
+func (s callbackQueueService) process(ctx, pctx context.Context, job domain.CallbackJob) error {
+	if job.UploadedFileID == "" {
+		fileID, err := s.processor.Upload(pctx, job)
+		if err != nil {
+			return err
+		}
+
+		if err := s.adapter.MarkUploaded(ctx, job.ID, fileID); err != nil {
+			s.logger.Errorf("could not mark callback job %s as uploaded: %s", job.ID, err.Error())
+		}
+
+		job.UploadedFileID = fileID
+	} else {
+		s.logger.Debugf("callback job %s has already uploaded file %s", job.ID, job.UploadedFileID)
+	}
+
+	// The upload may have used up the processing timeout, which must not cut the file records short
+	fctx, cancel := context.WithTimeout(ctx, finishTimeout)
+	defer cancel()
+	s.processor.Finish(fctx, job)
+	return nil
+}
+
+func (s callbackQueueService) PurgeJobs(ctx context.Context) (int64, error) {
+	purged, err := s.adapter.PurgeJobs(ctx, time.Now())
+	if err != nil {
+		return purged, err
+	}
+
+	if purged > 0 {
+		s.logger.Debugf("purged %d expired callback jobs", purged)
+	}
+
+	return purged, nil
+}
+
+// PurgeCompany drops the company jobs. A job a worker has already claimed may still be uploaded,
+// but it can no longer be retried once it is gone.
+func (s callbackQueueService) PurgeCompany(ctx context.Context, cid string) (int64, error) {
+	if strings.TrimSpace(cid) == "" {
+		return 0, &InvalidServiceParameterError{
+			Name:   "CompanyID",
+			Reason: "Should not be blank",
+		}
+	}
+
+	if ctx.Err() != nil {
+		return 0, ErrOperationTimeout
+	}
+
+	deleted, err := s.adapter.DeleteCompanyJobs(ctx, cid)
+	if err != nil {
+		return deleted, err
+	}
+
+	s.logger.Debugf("purged %d callback jobs of company %s", deleted, cid)
+	return deleted, nil
+}
+
+func (s callbackQueueService) getExpiration() time.Time {
+	return time.Now().AddDate(0, 0, s.config.Onlyoffice.Callback.Retention)
+}
+
+func (s callbackQueueService) getRetryDelay(attempts int) time.Duration {
+	delay := time.Duration(s.config.Onlyoffice.Callback.RetryDelay) * time.Second
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+
+	return delay
+}
@@ -0,0 +1,253 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockProcessor struct {
+	err      error
+	slow     bool
+	calls    int
+	finished []string
+	expired  int
+	rejected int
+}
+
+func (p *mockProcessor) Upload(ctx context.Context, job domain.CallbackJob) (string, error) {
+	p.calls++
+	if p.slow {
+		<-ctx.Done()
+	}
+
+	return "4", p.err
+}
+
+func (p *mockProcessor) Finish(ctx context.Context, job domain.CallbackJob) {
+	if ctx.Err() != nil {
+		p.expired++
+	}
+
+	p.finished = append(p.finished, job.UploadedFileID)
+}
+
+func (p *mockProcessor) Reject(ctx context.Context, job domain.CallbackJob, reason error) {
+	p.rejected++
+}
+
+var job = domain.CallbackJob{
+	CompanyID:  "1",
+	EntityType: "deal",
+	EntityID:   "2",
+	FileID:     "3",
+	Filename:   "mock.docx",
+	DocKey:     "mock",
+	Status:     2,
+	URL:        "https://example.com/mock.docx",
+	Users:      []string{"1:1"},
+}
+
+func newConfig() *shared.OnlyofficeConfig {
+	config := &shared.OnlyofficeConfig{}
+	config.Onlyoffice.Callback.UploadTimeout = 1
+	config.Onlyoffice.Callback.MaxAttempts = 2
+	config.Onlyoffice.Callback.RetryDelay = 0
+	config.Onlyoffice.Callback.Retention = 7
+	return config
+}
+
+func TestCallbackQueueService(t *testing.T) {
+	t.Run("enqueue job with timeout", func(t *testing.T) {
+		service := NewCallbackQueueService(adapter.NewMemoryCallbackQueueAdapter(), &mockProcessor{}, newConfig(), log.NewEmptyLogger())
+		ctx, cancel := context.WithTimeout(context.Background(), 0*time.Second)
+		defer cancel()
+		_, _, err := service.EnqueueJob(ctx, job)
+		assert.ErrorIs(t, err, ErrOperationTimeout)
+	})
+
+	t.Run("enqueue invalid job", func(t *testing.T) {
+		service := NewCallbackQueueService(adapter.NewMemoryCallbackQueueAdapter(), &mockProcessor{}, newConfig(), log.NewEmptyLogger())
+		invalid := job
+		invalid.Status = 1
+		_, _, err := service.EnqueueJob(context.Background(), invalid)
+		assert.Error(t, err)
+	})
+
+	t.Run("deduplicate document server retries", func(t *testing.T) {
+		processor := &mockProcessor{}
+		service := NewCallbackQueueService(adapter.NewMemoryCallbackQueueAdapter(), processor, newConfig(), log.NewEmptyLogger())
+		_, created, err := service.EnqueueJob(context.Background(), job)
+		assert.NoError(t, err)
+		assert.True(t, created)
+		_, created, err = service.EnqueueJob(context.Background(), job)
+		assert.NoError(t, err)
+		assert.False(t, created)
+
+		processed, err := service.ProcessNext(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, processed)
+		processed, err = service.ProcessNext(context.Background())
+		assert.NoError(t, err)
+		assert.False(t, processed)
+		assert.Equal(t, 1, processor.calls)
+	})
+
+	t.Run("forcesaves with different files are not duplicates", func(t *testing.T) {
+		service := NewCallbackQueueService(adapter.NewMemoryCallbackQueueAdapter(), &mockProcessor{}, newConfig(), log.NewEmptyLogger())
+		first, second := job, job
+		first.Status, second.Status = 6, 6
+		second.URL = "https://example.com/another.docx"
+		_, created, err := service.EnqueueJob(context.Background(), first)
+		assert.NoError(t, err)
+		assert.True(t, created)
+		_, created, err = service.EnqueueJob(context.Background(), second)
+		assert.NoError(t, err)
+		assert.True(t, created)
+	})
+
+	t.Run("retry and bury a failing job", func(t *testing.T) {
+		processor := &mockProcessor{err: errors.New("mock")}
+		service := NewCallbackQueueService(adapter.NewMemoryCallbackQueueAdapter(), processor, newConfig(), log.NewEmptyLogger())
+		_, _, err := service.EnqueueJob(context.Background(), job)
+		assert.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			_, err := service.ProcessNext(context.Background())
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, 2, processor.calls)
+		assert.Empty(t, processor.finished)
+		assert.Equal(t, 1, processor.rejected)
+	})
+
+	t.Run("do not upload a job twice", func(t *testing.T) {
+		processor := &mockProcessor{}
+		queue := &unreliableAdapter{CallbackQueueAdapter: adapter.NewMemoryCallbackQueueAdapter(), failCompletion: true}
+		service := NewCallbackQueueService(queue, processor, newConfig(), log.NewEmptyLogger())
+		_, _, err := service.EnqueueJob(context.Background(), job)
+		assert.NoError(t, err)
+
+		_, err = service.ProcessNext(context.Background())
+		assert.Error(t, err)
+
+		queue.failCompletion = false
+		queue.clock = 2 * time.Minute
+		processed, err := service.ProcessNext(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, processed)
+		assert.Equal(t, 1, processor.calls)
+		assert.Equal(t, []string{"4", "4"}, processor.finished)
+	})
+
+	t.Run("finish a job whose upload used up the processing timeout", func(t *testing.T) {
+		processor := &mockProcessor{slow: true}
+		service := NewCallbackQueueService(adapter.NewMemoryCallbackQueueAdapter(), processor, newConfig(), log.NewEmptyLogger())
+		_, _, err := service.EnqueueJob(context.Background(), job)
+		assert.NoError(t, err)
+
+		processed, err := service.ProcessNext(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, processed)
+		assert.Equal(t, []string{"4"}, processor.finished)
+		assert.Zero(t, processor.expired)
+	})
+
+	t.Run("purge finished jobs once they expire", func(t *testing.T) {
+		config := newConfig()
+		service := NewCallbackQueueService(adapter.NewMemoryCallbackQueueAdapter(), &mockProcessor{}, config, log.NewEmptyLogger())
+		first, second := job, job
+		second.Status = 6
+		for _, j := range []domain.CallbackJob{first, second} {
+			_, _, err := service.EnqueueJob(context.Background(), j)
+			assert.NoError(t, err)
+		}
+
+		_, err := service.ProcessNext(context.Background())
+		assert.NoError(t, err)
+		config.Onlyoffice.Callback.Retention = -1
+		_, err = service.ProcessNext(context.Background())
+		assert.NoError(t, err)
+
+		purged, err := service.PurgeJobs(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		purged, err = service.PurgeJobs(context.Background())
+		assert.NoError(t, err)
+		assert.Zero(t, purged)
+	})
+
+	t.Run("purge pending jobs of an uninstalled company", func(t *testing.T) {
+		processor := &mockProcessor{}
+		service := NewCallbackQueueService(adapter.NewMemoryCallbackQueueAdapter(), processor, newConfig(), log.NewEmptyLogger())
+		first, second := job, job
+		second.CompanyID, second.DocKey = "2", "another"
+		for _, j := range []domain.CallbackJob{first, second} {
+			_, _, err := service.EnqueueJob(context.Background(), j)
+			assert.NoError(t, err)
+		}
+
+		purged, err := service.PurgeCompany(context.Background(), "1")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		for {
+			processed, err := service.ProcessNext(context.Background())
+			assert.NoError(t, err)
+			if !processed {
+				break
+			}
+		}
+		assert.Equal(t, 1, processor.calls)
+
+		_, err = service.PurgeCompany(context.Background(), " ")
+		assert.Error(t, err)
+	})
+}
+
+// unreliableAdapter fails job completions and moves the claim clock forward to expire leases.
+type unreliableAdapter struct {
+	port.CallbackQueueAdapter
+	failCompletion bool
+	clock          time.Duration
+}
+
+func (a *unreliableAdapter) ClaimJob(ctx context.Context, now time.Time, lease time.Duration) (domain.CallbackJob, error) {
+	return a.CallbackQueueAdapter.ClaimJob(ctx, now.Add(a.clock), lease)
+}
+
+func (a *unreliableAdapter) CompleteJob(ctx context.Context, id string, expires time.Time) error {
+	if a.failCompletion {
+		return errors.New("mock")
+	}
+
+	return a.CallbackQueueAdapter.CompleteJob(ctx, id, expires)
+}
@@ -75,5 +75,6 @@ func (s *CallbackService) InitializeRoutes() {
 			http.Redirect(rw, r.WithContext(r.Context()), "https://onlyoffice.com", http.StatusMovedPermanently)
 		})
 		r.Post("/callback", s.callbackController.BuildPostHandleCallback())
+		r.Post("/purge", s.callbackController.BuildPostPurgeCompany())
 	})
 }
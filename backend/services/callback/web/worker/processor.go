@@ -0,0 +1,377 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package worker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/golang-jwt/jwt/v5"
+	"go-micro.dev/v4/client"
+	"go-micro.dev/v4/util/backoff"
+)
+
+const maxChangesSize = 8 * 1024 * 1024
+
+var ErrChangesTooLarge = errors.New("changes archive is too large")
+
+type uploadProcessor struct {
+	client       client.Client
+	pipedriveAPI pclient.PipedriveApiClient
+	converter    pclient.ConvertClient
+	config       *config.ServerConfig
+	onlyoffice   *shared.OnlyofficeConfig
+	logger       plog.Logger
+}
+
+func NewUploadProcessor(
+	client client.Client,
+	pipedriveAPI pclient.PipedriveApiClient,
+	converter pclient.ConvertClient,
+	config *config.ServerConfig,
+	onlyoffice *shared.OnlyofficeConfig,
+	logger plog.Logger,
+) port.CallbackJobProcessor {
+	return uploadProcessor{
+		client:       client,
+		pipedriveAPI: pipedriveAPI,
+		converter:    converter,
+		config:       config,
+		onlyoffice:   onlyoffice,
+		logger:       logger,
+	}
+}
+
+// Upload attaches the document server file to Pipedrive on behalf of the first callback user.
+func (p uploadProcessor) Upload(ctx context.Context, job domain.CallbackJob) (string, error) {
+	if _, err := p.pipedriveAPI.ValidateFileSize(ctx, p.onlyoffice.Onlyoffice.Callback.MaxSize, job.URL); err != nil {
+		return "", fmt.Errorf("could not validate file %s: %w", job.Filename, err)
+	}
+
+	token, err := p.getToken(ctx, job)
+	if err != nil {
+		return "", err
+	}
+
+	url, err := p.restoreFormat(ctx, job)
+	if err != nil {
+		return "", fmt.Errorf("could not convert file %s back to its original format: %w", job.Filename, err)
+	}
+
+	file, err := p.pipedriveAPI.UploadFile(ctx, url, request.NewParentEntity(job.EntityType, job.EntityID), job.UploadFilename(), token)
+	if err != nil {
+		return "", fmt.Errorf("could not upload an onlyoffice file to pipedrive: %w", err)
+	}
+
+	return fmt.Sprint(file.Data.ID), nil
+}
+
+// Finish records the uploaded file. None of its steps fail the job, since the file is already attached.
+func (p uploadProcessor) Finish(ctx context.Context, job domain.CallbackJob) {
+	if job.Form {
+		p.replaceBlankForm(ctx, job)
+		p.rotateKey(ctx, job)
+		p.recordActivity(ctx, job, "")
+		return
+	}
+
+	p.recordVersion(ctx, job, job.UploadedFileID)
+	p.recordHistory(ctx, job, job.UploadedFileID)
+	p.rotateKey(ctx, job)
+	p.recordActivity(ctx, job, "")
+	p.pruneVersions(ctx, job)
+}
+
+func (p uploadProcessor) getToken(ctx context.Context, job domain.CallbackJob) (model.Token, error) {
+	usr, err := request.ParseUserIdentity(job.Users[0])
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	req := p.client.NewRequest(fmt.Sprintf("%s:auth", p.config.Namespace), "UserSelectHandler.GetUser", usr)
+	var ures response.UserResponse
+	if err := p.client.Call(ctx, req, &ures, client.WithRetries(3), client.WithBackoff(func(ctx context.Context, req client.Request, attempts int) (time.Duration, error) {
+		return backoff.Do(attempts), nil
+	})); err != nil {
+		return model.Token{}, fmt.Errorf("could not get user tokens: %w", err)
+	}
+
+	return model.Token{
+		AccessToken:  ures.AccessToken,
+		RefreshToken: ures.RefreshToken,
+		TokenType:    ures.TokenType,
+		Scope:        ures.Scope,
+		ApiDomain:    ures.ApiDomain,
+	}, nil
+}
+
+func (p uploadProcessor) getSettings(ctx context.Context, cid string) (response.DocSettingsResponse, error) {
+	var settings response.DocSettingsResponse
+	err := p.client.Call(ctx, p.client.NewRequest(
+		fmt.Sprintf("%s:settings", p.config.Namespace), "SettingsSelectHandler.GetSettings", cid,
+	), &settings)
+	return settings, err
+}
+
+// restoreFormat returns a link to the saved file in the format it was opened in. Lossy edited
+// files are saved by the document server as office open xml and have to be converted back.
+func (p uploadProcessor) restoreFormat(ctx context.Context, job domain.CallbackJob) (string, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(job.Filename), "."))
+	if job.FileType == "" || job.FileType == ext {
+		return job.URL, nil
+	}
+
+	settings, err := shared.GetDocumentServerSettings(ctx, p.client, p.config.Namespace, p.onlyoffice, p.logger, job.CompanyID)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(job.Key()))
+	req := request.ConvertRequest{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
+		},
+		Async:      true,
+		Filetype:   job.FileType,
+		Key:        hex.EncodeToString(hash[:16]),
+		Outputtype: ext,
+		Title:      job.Filename,
+		URL:        job.URL,
+	}
+
+	for {
+		res, err := p.converter.Convert(ctx, settings.DocAddress, settings.DocSecret, settings.DocHeader, req)
+		if err != nil {
+			return "", err
+		}
+
+		if res.EndConvert {
+			return res.FileURL, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// replaceBlankForm removes the blank form once it has been submitted if the company does not keep blanks around.
+func (p uploadProcessor) replaceBlankForm(ctx context.Context, job domain.CallbackJob) {
+	settings, err := p.getSettings(ctx, job.CompanyID)
+	if err != nil {
+		p.logger.Warnf("could not get company %s settings to replace blank form %s: %s", job.CompanyID, job.FileID, err.Error())
+		return
+	}
+
+	if !settings.ReplaceBlankForms {
+		return
+	}
+
+	token, err := p.getToken(ctx, job)
+	if err != nil {
+		p.logger.Errorf("could not delete blank form %s: %s", job.FileID, err.Error())
+		return
+	}
+
+	if err := p.pipedriveAPI.DeleteFile(ctx, job.FileID, token); err != nil {
+		p.logger.Errorf("could not delete blank form %s: %s", job.FileID, err.Error())
+	}
+}
+
+func (p uploadProcessor) recordVersion(ctx context.Context, job domain.CallbackJob, fileID string) {
+	cid, err := strconv.Atoi(job.CompanyID)
+	if err != nil {
+		p.logger.Warnf("could not record callback job %s version: invalid company id %s", job.ID, job.CompanyID)
+		return
+	}
+
+	var res response.DocumentVersionResponse
+	if err := p.client.Call(ctx, p.client.NewRequest(
+		fmt.Sprintf("%s:documents", p.config.Namespace),
+		"VersionHandler.InsertVersion",
+		request.DocumentVersion{
+			CompanyID:  cid,
+			ParentID:   job.FileID,
+			FileID:     fileID,
+			EntityType: job.EntityType,
+			EntityID:   job.EntityID,
+			Filename:   job.Filename,
+			Author:     job.Users[0],
+		},
+	), &res); err != nil {
+		p.logger.Errorf("could not record file %s as a new version of %s: %s", fileID, job.FileID, err.Error())
+	}
+}
+
+// recordHistory stores the changes archive while the document server link is still valid.
+func (p uploadProcessor) recordHistory(ctx context.Context, job domain.CallbackJob, fileID string) {
+	cid, err := strconv.Atoi(job.CompanyID)
+	if err != nil {
+		p.logger.Warnf("could not record callback job %s history: invalid company id %s", job.ID, job.CompanyID)
+		return
+	}
+
+	var archive []byte
+	if job.ChangesURL != "" {
+		if archive, err = p.downloadChanges(ctx, job.ChangesURL); err != nil {
+			p.logger.Warnf("could not download file %s changes archive: %s", fileID, err.Error())
+		}
+	}
+
+	var res interface{}
+	if err := p.client.Call(ctx, p.client.NewRequest(
+		fmt.Sprintf("%s:documents", p.config.Namespace),
+		"HistoryHandler.InsertHistory",
+		request.DocumentHistory{
+			CompanyID:     cid,
+			FileID:        fileID,
+			ServerVersion: job.History.ServerVersion,
+			Changes:       job.History.Changes,
+			Archive:       archive,
+		},
+	), &res); err != nil {
+		p.logger.Errorf("could not record file %s history: %s", fileID, err.Error())
+	}
+}
+
+// rotateKey starts a new file revision once the editing session has been saved, so the next
+// session does not get the document server's cached copy. Forcesaves keep the session open.
+func (p uploadProcessor) rotateKey(ctx context.Context, job domain.CallbackJob) {
+	if job.Status != constants.CallbackStatusMustSave {
+		return
+	}
+
+	cid, err := strconv.Atoi(job.CompanyID)
+	if err != nil {
+		p.logger.Warnf("could not rotate callback job %s key: invalid company id %s", job.ID, job.CompanyID)
+		return
+	}
+
+	var res response.DocumentKeyResponse
+	if err := p.client.Call(ctx, p.client.NewRequest(
+		fmt.Sprintf("%s:documents", p.config.Namespace),
+		"KeyHandler.RotateKey",
+		request.DocumentKey{
+			CompanyID: cid,
+			FileID:    job.FileID,
+			Key:       job.DocKey,
+		},
+	), &res); err != nil {
+		p.logger.Warnf("could not rotate file %s key %s: %s", job.FileID, job.DocKey, err.Error())
+	}
+}
+
+// pruneVersions removes superseded attachments once the editing session is over. Forcesaves
+// keep the opened file around since the editor still refers to it.
+func (p uploadProcessor) pruneVersions(ctx context.Context, job domain.CallbackJob) {
+	if job.Status != constants.CallbackStatusMustSave {
+		return
+	}
+
+	cid, err := strconv.Atoi(job.CompanyID)
+	if err != nil {
+		p.logger.Warnf("could not prune callback job %s versions: invalid company id %s", job.ID, job.CompanyID)
+		return
+	}
+
+	token, err := p.getToken(ctx, job)
+	if err != nil {
+		p.logger.Warnf("could not prune file %s versions: %s", job.UploadedFileID, err.Error())
+		return
+	}
+
+	if err := shared.PruneVersions(
+		ctx, p.client, p.pipedriveAPI, p.config.Namespace, p.logger,
+		token, cid, job.UploadedFileID, p.onlyoffice.Onlyoffice.Callback.KeepVersions,
+	); err != nil {
+		p.logger.Warnf("could not prune file %s versions: %s", job.UploadedFileID, err.Error())
+	}
+}
+
+func (p uploadProcessor) downloadChanges(ctx context.Context, url string) ([]byte, error) {
+	body, err := p.pipedriveAPI.GetFile(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	archive, err := io.ReadAll(io.LimitReader(body, maxChangesSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(archive) > maxChangesSize {
+		return nil, ErrChangesTooLarge
+	}
+
+	return archive, nil
+}
+
+// Reject reports a dead lettered job as the file's last error. A save that could not be attached
+// still ends the editing session, so the next session starts a new revision as well.
+func (p uploadProcessor) Reject(ctx context.Context, job domain.CallbackJob, reason error) {
+	p.rotateKey(ctx, job)
+	p.recordActivity(ctx, job, reason.Error())
+}
+
+func (p uploadProcessor) recordActivity(ctx context.Context, job domain.CallbackJob, reason string) {
+	cid, err := strconv.Atoi(job.CompanyID)
+	if err != nil {
+		p.logger.Warnf("could not record callback job %s activity: invalid company id %s", job.ID, job.CompanyID)
+		return
+	}
+
+	var res interface{}
+	if err := p.client.Call(ctx, p.client.NewRequest(
+		fmt.Sprintf("%s:documents", p.config.Namespace),
+		"ActivityInsertHandler.InsertActivity",
+		request.DocumentActivity{
+			CompanyID:  cid,
+			FileID:     job.FileID,
+			EntityType: job.EntityType,
+			EntityID:   job.EntityID,
+			DocKey:     job.DocKey,
+			Status:     job.Status,
+			Users:      job.Users,
+			Error:      reason,
+		},
+	), &res); err != nil {
+		p.logger.Warnf("could not record file %s activity: %s", job.FileID, err.Error())
+	}
+}
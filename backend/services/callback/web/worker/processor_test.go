@@ -0,0 +1,379 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/stretchr/testify/assert"
+	"go-micro.dev/v4/client"
+)
+
+var testJwtManager = crypto.NewJwtManager(&config.CryptoConfig{})
+
+// rpcClient returns the company settings, file versions and a user whose Pipedrive api is served by domain.
+// Document events and key rotations are recorded if events and keys are set.
+type rpcClient struct {
+	client.Client
+	settings response.DocSettingsResponse
+	versions response.DocumentVersionsResponse
+	events   *[]request.DocumentEvent
+	keys     *[]request.DocumentKey
+	domain   string
+}
+
+func (c rpcClient) NewRequest(service, endpoint string, req interface{}, opts ...client.RequestOption) client.Request {
+	return client.NewRequest(service, endpoint, req, opts...)
+}
+
+func (c rpcClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	switch req.Endpoint() {
+	case "SettingsSelectHandler.GetSettings":
+		*rsp.(*response.DocSettingsResponse) = c.settings
+		return nil
+	case "UserSelectHandler.GetUser":
+		*rsp.(*response.UserResponse) = response.UserResponse{
+			ID:          req.Body().(request.UserIdentity),
+			AccessToken: "access",
+			TokenType:   "Bearer",
+			ApiDomain:   c.domain,
+		}
+		return nil
+	case "VersionHandler.GetVersions":
+		*rsp.(*response.DocumentVersionsResponse) = c.versions
+		return nil
+	case "EventHandler.HandleEvent":
+		if c.events != nil {
+			*c.events = append(*c.events, req.Body().(request.DocumentEvent))
+		}
+		return nil
+	case "KeyHandler.RotateKey":
+		if c.keys != nil {
+			*c.keys = append(*c.keys, req.Body().(request.DocumentKey))
+		}
+		return nil
+	case "ActivityInsertHandler.InsertActivity":
+		return nil
+	default:
+		return fmt.Errorf("unexpected call %s", req.Endpoint())
+	}
+}
+
+// docServer converts files and remembers the conversion requests it has been sent.
+type docServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []request.ConvertRequest
+	err      int
+}
+
+func newDocServer() *docServer {
+	server := &docServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var req request.ConvertRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		server.mu.Lock()
+		server.requests = append(server.requests, req)
+		server.mu.Unlock()
+
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(response.ConvertResponse{
+			EndConvert: server.err == 0,
+			FileURL:    fmt.Sprintf("%s/result.%s", server.URL, req.Outputtype),
+			Percent:    100,
+			Error:      server.err,
+		})
+	}))
+
+	return server
+}
+
+func newProcessor(rpc rpcClient, onlyoffice *shared.OnlyofficeConfig) uploadProcessor {
+	return uploadProcessor{
+		client:       rpc,
+		pipedriveAPI: pclient.NewPipedriveApiClient(),
+		converter:    pclient.NewConvertClient(testJwtManager),
+		config:       &config.ServerConfig{Namespace: "test"},
+		onlyoffice:   onlyoffice,
+		logger:       log.NewEmptyLogger(),
+	}
+}
+
+func newSavedJob(filename, filetype string) domain.CallbackJob {
+	return domain.CallbackJob{
+		CompanyID:  "1",
+		EntityType: request.EntityDeal,
+		EntityID:   "5",
+		FileID:     "10",
+		Filename:   filename,
+		DocKey:     "key",
+		Status:     2,
+		URL:        "https://docs.example.com/cache/saved",
+		FileType:   filetype,
+		Users:      []string{"1:2"},
+	}
+}
+
+func TestRestoreFormat(t *testing.T) {
+	t.Run("keep files saved in their own format", func(t *testing.T) {
+		server := newDocServer()
+		defer server.Close()
+		processor := newProcessor(rpcClient{settings: response.DocSettingsResponse{
+			DocAddress: server.URL, DocSecret: "secret", DocHeader: "Authorization",
+		}}, &shared.OnlyofficeConfig{})
+
+		url, err := processor.restoreFormat(context.Background(), newSavedJob("Contract.docx", "docx"))
+		assert.NoError(t, err)
+		assert.Equal(t, "https://docs.example.com/cache/saved", url)
+		assert.Empty(t, server.requests)
+	})
+
+	t.Run("convert lossy edited files back", func(t *testing.T) {
+		server := newDocServer()
+		defer server.Close()
+		processor := newProcessor(rpcClient{settings: response.DocSettingsResponse{
+			DocAddress: server.URL, DocSecret: "secret", DocHeader: "Authorization",
+		}}, &shared.OnlyofficeConfig{})
+
+		job := newSavedJob("Contract.odt", "docx")
+		url, err := processor.restoreFormat(context.Background(), job)
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/result.odt", url)
+		assert.Len(t, server.requests, 1)
+		assert.Equal(t, "docx", server.requests[0].Filetype)
+		assert.Equal(t, "odt", server.requests[0].Outputtype)
+		assert.Equal(t, job.URL, server.requests[0].URL)
+		assert.NotEmpty(t, server.requests[0].Token)
+
+		_, err = processor.restoreFormat(context.Background(), job)
+		assert.NoError(t, err)
+		assert.Equal(t, server.requests[0].Key, server.requests[1].Key)
+	})
+
+	t.Run("convert with the demo document server", func(t *testing.T) {
+		server := newDocServer()
+		defer server.Close()
+		var onlyoffice shared.OnlyofficeConfig
+		onlyoffice.Onlyoffice.Demo.DocumentServerURL = server.URL
+		onlyoffice.Onlyoffice.Demo.DocumentServerSecret = "demo-secret"
+		onlyoffice.Onlyoffice.Demo.DocumentServerHeader = "AuthorizationJwt"
+		processor := newProcessor(rpcClient{settings: response.DocSettingsResponse{
+			DemoEnabled: true, DemoStarted: time.Now(),
+		}}, &onlyoffice)
+
+		url, err := processor.restoreFormat(context.Background(), newSavedJob("Contract.odt", "docx"))
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/result.odt", url)
+	})
+
+	t.Run("fail without a document server", func(t *testing.T) {
+		processor := newProcessor(rpcClient{settings: response.DocSettingsResponse{
+			DemoEnabled: true, DemoStarted: time.Now().AddDate(0, 0, -31),
+		}}, &shared.OnlyofficeConfig{})
+
+		_, err := processor.restoreFormat(context.Background(), newSavedJob("Contract.odt", "docx"))
+		assert.ErrorIs(t, err, shared.ErrNoSettingsFound)
+	})
+
+	t.Run("fail when the document server could not convert", func(t *testing.T) {
+		server := newDocServer()
+		server.err = -3
+		defer server.Close()
+		processor := newProcessor(rpcClient{settings: response.DocSettingsResponse{
+			DocAddress: server.URL, DocSecret: "secret", DocHeader: "Authorization",
+		}}, &shared.OnlyofficeConfig{})
+
+		_, err := processor.restoreFormat(context.Background(), newSavedJob("Contract.odt", "docx"))
+		var cerr *pclient.ConversionError
+		assert.ErrorAs(t, err, &cerr)
+	})
+}
+
+// pipedriveServer serves saved files and remembers the files uploaded to and deleted from Pipedrive.
+type pipedriveServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	uploaded []string
+	deleted  []string
+}
+
+func newPipedriveServer() *pipedriveServer {
+	server := &pipedriveServer{}
+	mux := http.NewServeMux()
+	server.Server = httptest.NewServer(mux)
+	mux.HandleFunc("/saved", func(rw http.ResponseWriter, r *http.Request) {
+		http.ServeContent(rw, r, "saved", time.Time{}, strings.NewReader("saved"))
+	})
+	mux.HandleFunc("POST /api/v1/files", func(rw http.ResponseWriter, r *http.Request) {
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		server.mu.Lock()
+		server.uploaded = append(server.uploaded, header.Filename)
+		id := 100 + len(server.uploaded)
+		server.mu.Unlock()
+
+		var res response.AddFileResponse
+		res.Success = true
+		res.Data.ID = id
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(res)
+	})
+	mux.HandleFunc("DELETE /api/v1/files/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		server.deleted = append(server.deleted, r.PathValue("id"))
+		server.mu.Unlock()
+		rw.Write([]byte(`{"success":true}`))
+	})
+
+	return server
+}
+
+func TestUploadForm(t *testing.T) {
+	server := newPipedriveServer()
+	defer server.Close()
+	var onlyoffice shared.OnlyofficeConfig
+	onlyoffice.Onlyoffice.Callback.MaxSize = 1024
+	processor := newProcessor(rpcClient{domain: server.URL}, &onlyoffice)
+
+	form := newSavedJob("Application.pdf", "pdf")
+	form.URL, form.Form = server.URL+"/saved", true
+	form.CreatedAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	id, err := processor.Upload(context.Background(), form)
+	assert.NoError(t, err)
+	assert.Equal(t, "101", id)
+	assert.Equal(t, []string{"Application (2026-01-02 03-04-05).pdf"}, server.uploaded)
+}
+
+func TestReplaceBlankForm(t *testing.T) {
+	form := newSavedJob("Application.pdf", "pdf")
+	form.Form = true
+
+	t.Run("delete the blank form once it has been submitted", func(t *testing.T) {
+		server := newPipedriveServer()
+		defer server.Close()
+		processor := newProcessor(rpcClient{
+			settings: response.DocSettingsResponse{ReplaceBlankForms: true}, domain: server.URL,
+		}, &shared.OnlyofficeConfig{})
+
+		processor.replaceBlankForm(context.Background(), form)
+		assert.Equal(t, []string{"10"}, server.deleted)
+	})
+
+	t.Run("keep blank forms around by default", func(t *testing.T) {
+		server := newPipedriveServer()
+		defer server.Close()
+		processor := newProcessor(rpcClient{domain: server.URL}, &shared.OnlyofficeConfig{})
+
+		processor.replaceBlankForm(context.Background(), form)
+		assert.Empty(t, server.deleted)
+	})
+}
+
+func TestPruneVersions(t *testing.T) {
+	versions := response.DocumentVersionsResponse{Versions: []response.DocumentVersionResponse{
+		{FileID: "9", Version: 1, Deleted: true},
+		{FileID: "10", Version: 2},
+		{FileID: "11", Version: 3},
+		{FileID: "12", Version: 4},
+	}}
+
+	newJob := func(status int) domain.CallbackJob {
+		job := newSavedJob("Contract.docx", "docx")
+		job.Status, job.UploadedFileID = status, "12"
+		return job
+	}
+
+	prune := func(job domain.CallbackJob, keep int) ([]string, []request.DocumentEvent) {
+		server := newPipedriveServer()
+		defer server.Close()
+		var events []request.DocumentEvent
+		var onlyoffice shared.OnlyofficeConfig
+		onlyoffice.Onlyoffice.Callback.KeepVersions = keep
+		processor := newProcessor(rpcClient{versions: versions, events: &events, domain: server.URL}, &onlyoffice)
+
+		processor.pruneVersions(context.Background(), job)
+		return server.deleted, events
+	}
+
+	t.Run("remove superseded attachments once the session is over", func(t *testing.T) {
+		deleted, events := prune(newJob(2), 0)
+		assert.Equal(t, []string{"10", "11"}, deleted)
+		assert.Equal(t, []request.DocumentEvent{
+			{CompanyID: 1, Action: request.DocumentEventFileDeleted, FileID: "10"},
+			{CompanyID: 1, Action: request.DocumentEventFileDeleted, FileID: "11"},
+		}, events)
+	})
+
+	t.Run("keep the configured number of restorable versions", func(t *testing.T) {
+		deleted, _ := prune(newJob(2), 1)
+		assert.Equal(t, []string{"10"}, deleted)
+	})
+
+	t.Run("keep the opened file while the editor is still open", func(t *testing.T) {
+		deleted, events := prune(newJob(6), 0)
+		assert.Empty(t, deleted)
+		assert.Empty(t, events)
+	})
+}
+
+func TestReject(t *testing.T) {
+	t.Run("start a new revision once a save has been dead lettered", func(t *testing.T) {
+		var keys []request.DocumentKey
+		processor := newProcessor(rpcClient{keys: &keys}, &shared.OnlyofficeConfig{})
+		processor.Reject(context.Background(), newSavedJob("Contract.docx", "docx"), errors.New("mock"))
+
+		assert.Equal(t, []request.DocumentKey{{CompanyID: 1, FileID: "10", Key: "key"}}, keys)
+	})
+
+	t.Run("keep the session of a dead lettered forcesave", func(t *testing.T) {
+		var keys []request.DocumentKey
+		job := newSavedJob("Contract.docx", "docx")
+		job.Status = 6
+		processor := newProcessor(rpcClient{keys: &keys}, &shared.OnlyofficeConfig{})
+		processor.Reject(context.Background(), job, errors.New("mock"))
+
+		assert.Empty(t, keys)
+	})
+}
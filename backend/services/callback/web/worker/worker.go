@@ -0,0 +1,136 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/callback/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	"go.uber.org/fx"
+)
+
+const purgeInterval = time.Hour
+
+type CallbackWorker struct {
+	service    port.CallbackQueueService
+	onlyoffice *shared.OnlyofficeConfig
+	logger     plog.Logger
+	cancel     context.CancelFunc
+	wg         *sync.WaitGroup
+}
+
+func NewCallbackWorker(
+	service port.CallbackQueueService,
+	onlyoffice *shared.OnlyofficeConfig,
+	logger plog.Logger,
+) *CallbackWorker {
+	return &CallbackWorker{
+		service:    service,
+		onlyoffice: onlyoffice,
+		logger:     logger,
+		wg:         &sync.WaitGroup{},
+	}
+}
+
+// Start spawns the configured number of queue consumers and a purge of finished jobs.
+func (w *CallbackWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.wg.Add(1)
+	go w.purge(ctx)
+
+	for i := 0; i < w.onlyoffice.Onlyoffice.Callback.Workers; i++ {
+		w.wg.Add(1)
+		go w.consume(ctx)
+	}
+}
+
+// Stop waits for in-flight jobs to finish. Unfinished jobs are picked up again once their lease expires.
+func (w *CallbackWorker) Stop(ctx context.Context) error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *CallbackWorker) consume(ctx context.Context) {
+	defer w.wg.Done()
+	interval := time.Duration(w.onlyoffice.Onlyoffice.Callback.PollInterval) * time.Second
+	for ctx.Err() == nil {
+		// in-flight jobs are not bound to the worker context so that stopping does not abort an upload
+		processed, err := w.service.ProcessNext(context.Background())
+		if err != nil {
+			w.logger.Errorf("could not process callback queue: %s", err.Error())
+		}
+
+		if processed && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (w *CallbackWorker) purge(ctx context.Context) {
+	defer w.wg.Done()
+	for {
+		if _, err := w.service.PurgeJobs(ctx); err != nil {
+			w.logger.Errorf("could not purge callback queue: %s", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(purgeInterval):
+		}
+	}
+}
+
+func RunCallbackWorker(lifecycle fx.Lifecycle, worker *CallbackWorker) {
+	lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			worker.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return worker.Stop(ctx)
+		},
+	})
+}
@@ -0,0 +1,69 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+)
+
+func BuildNewActivityAdapter(config *config.StorageConfig) port.DocumentActivityServiceAdapter {
+	adapter := NewMemoryActivityAdapter()
+	if config.Storage.URL != "" {
+		adapter = NewMongoActivityAdapter(config.Storage.URL)
+	}
+
+	return adapter
+}
+
+func BuildNewVersionAdapter(config *config.StorageConfig) port.DocumentVersionServiceAdapter {
+	adapter := NewMemoryVersionAdapter()
+	if config.Storage.URL != "" {
+		adapter = NewMongoVersionAdapter(config.Storage.URL)
+	}
+
+	return adapter
+}
+
+func BuildNewConversionAdapter(config *config.StorageConfig) port.DocumentConversionServiceAdapter {
+	adapter := NewMemoryConversionAdapter()
+	if config.Storage.URL != "" {
+		adapter = NewMongoConversionAdapter(config.Storage.URL)
+	}
+
+	return adapter
+}
+
+func BuildNewKeyAdapter(config *config.StorageConfig) port.DocumentKeyServiceAdapter {
+	adapter := NewMemoryKeyAdapter()
+	if config.Storage.URL != "" {
+		adapter = NewMongoKeyAdapter(config.Storage.URL)
+	}
+
+	return adapter
+}
+
+func BuildNewHistoryAdapter(config *config.StorageConfig) port.DocumentHistoryServiceAdapter {
+	adapter := NewMemoryHistoryAdapter()
+	if config.Storage.URL != "" {
+		adapter = NewMongoHistoryAdapter(config.Storage.URL)
+	}
+
+	return adapter
+}
@@ -0,0 +1,36 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import "errors"
+
+var (
+	ErrNoDocumentActivity = errors.New("no document activity")
+	ErrInvalidFileID      = errors.New("invalid file id format")
+	ErrInvalidCompanyID   = errors.New("invalid company id format")
+	ErrNoDocumentVersion  = errors.New("no document version")
+	ErrVersionExists      = errors.New("document version already exists")
+	ErrNoDocumentHistory  = errors.New("no document history")
+	ErrNoDocumentKey      = errors.New("no document key")
+	ErrDocumentKeyExists  = errors.New("document key already exists")
+	ErrDocumentKeyChanged = errors.New("document key has been changed")
+
+	ErrNoDocumentConversion = errors.New("no document conversion")
+	ErrConversionExists     = errors.New("document conversion already exists")
+)
@@ -0,0 +1,150 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+)
+
+type memoryActivityAdapter struct {
+	kvs map[string][]byte
+}
+
+func NewMemoryActivityAdapter() port.DocumentActivityServiceAdapter {
+	return &memoryActivityAdapter{
+		kvs: make(map[string][]byte),
+	}
+}
+
+func (m *memoryActivityAdapter) UpsertActivity(ctx context.Context, activity domain.DocumentActivity) (domain.DocumentActivity, error) {
+	buffer, err := json.Marshal(activity)
+	if err != nil {
+		return activity, err
+	}
+
+	m.kvs[activity.Key()] = buffer
+	return activity, nil
+}
+
+func (m *memoryActivityAdapter) SelectActivity(ctx context.Context, cid, fid string) (domain.DocumentActivity, error) {
+	var activity domain.DocumentActivity
+	buffer, ok := m.kvs[fmt.Sprintf("%s:%s", cid, fid)]
+	if !ok {
+		return activity, ErrNoDocumentActivity
+	}
+
+	if err := json.Unmarshal(buffer, &activity); err != nil {
+		return activity, err
+	}
+
+	return activity, nil
+}
+
+func (m *memoryActivityAdapter) SelectActivities(ctx context.Context, filter domain.DocumentFilter) ([]domain.DocumentActivity, error) {
+	if strings.TrimSpace(filter.CompanyID) == "" {
+		return nil, ErrInvalidCompanyID
+	}
+
+	activities := make([]domain.DocumentActivity, 0)
+	for _, buffer := range m.kvs {
+		var activity domain.DocumentActivity
+		if err := json.Unmarshal(buffer, &activity); err != nil {
+			return nil, err
+		}
+
+		if filter.Matches(activity.CompanyID, activity.FileID, activity.EntityType, activity.EntityID) {
+			activities = append(activities, activity)
+		}
+	}
+
+	return activities, nil
+}
+
+// deleteCompanyRecords removes the records of a company from a store keyed by the company id first.
+func deleteCompanyRecords[T any](records map[string]T, cid string) (int, error) {
+	cid = strings.TrimSpace(cid)
+	if cid == "" {
+		return 0, ErrInvalidCompanyID
+	}
+
+	deleted := 0
+	for key := range records {
+		if strings.HasPrefix(key, cid+":") {
+			delete(records, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func (m *memoryActivityAdapter) updateActivities(filter domain.DocumentFilter, update func(*domain.DocumentActivity)) (int, error) {
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for key, buffer := range m.kvs {
+		var activity domain.DocumentActivity
+		if err := json.Unmarshal(buffer, &activity); err != nil {
+			return updated, err
+		}
+
+		if !filter.Matches(activity.CompanyID, activity.FileID, activity.EntityType, activity.EntityID) {
+			continue
+		}
+
+		update(&activity)
+		activity.UpdatedAt = time.Now()
+		buffer, err := json.Marshal(activity)
+		if err != nil {
+			return updated, err
+		}
+
+		m.kvs[key] = buffer
+		updated++
+	}
+
+	return updated, nil
+}
+
+func (m *memoryActivityAdapter) CloseActivities(ctx context.Context, filter domain.DocumentFilter) (int, error) {
+	return m.updateActivities(filter, func(activity *domain.DocumentActivity) {
+		activity.Editors = []string{}
+		activity.Sessions = []domain.DocumentSession{}
+		activity.Deleted = true
+	})
+}
+
+func (m *memoryActivityAdapter) MoveActivities(ctx context.Context, filter domain.DocumentFilter, entityID string) (int, error) {
+	return m.updateActivities(filter, func(activity *domain.DocumentActivity) {
+		activity.EntityID = entityID
+	})
+}
+
+func (m *memoryActivityAdapter) DeleteCompany(ctx context.Context, cid string) (int, error) {
+	return deleteCompanyRecords(m.kvs, cid)
+}
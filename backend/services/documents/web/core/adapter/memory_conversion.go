@@ -0,0 +1,114 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+)
+
+type memoryConversionAdapter struct {
+	mu          sync.Mutex
+	conversions map[string]domain.DocumentConversion
+}
+
+func NewMemoryConversionAdapter() port.DocumentConversionServiceAdapter {
+	return &memoryConversionAdapter{
+		conversions: make(map[string]domain.DocumentConversion),
+	}
+}
+
+func (m *memoryConversionAdapter) InsertConversion(ctx context.Context, conversion domain.DocumentConversion) error {
+	if err := conversion.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.conversions[conversion.ID()]; ok {
+		return ErrConversionExists
+	}
+
+	m.conversions[conversion.ID()] = conversion
+	return nil
+}
+
+func (m *memoryConversionAdapter) SelectConversion(ctx context.Context, cid, key string) (domain.DocumentConversion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	conversion, ok := m.conversions[fmt.Sprintf("%s:%s", cid, key)]
+	if !ok {
+		return conversion, ErrNoDocumentConversion
+	}
+
+	return conversion, nil
+}
+
+func (m *memoryConversionAdapter) DeleteConversions(ctx context.Context, filter domain.DocumentFilter) (int, error) {
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deleted := 0
+	for id, conversion := range m.conversions {
+		if filter.Matches(conversion.CompanyID, conversion.FileID, conversion.EntityType, conversion.EntityID) ||
+			filter.Matches(conversion.CompanyID, conversion.ResultID, conversion.EntityType, conversion.EntityID) {
+			delete(m.conversions, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func (m *memoryConversionAdapter) MoveConversions(ctx context.Context, filter domain.DocumentFilter, entityID string) (int, error) {
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	moved := 0
+	for id, conversion := range m.conversions {
+		if filter.Matches(conversion.CompanyID, conversion.FileID, conversion.EntityType, conversion.EntityID) {
+			conversion.EntityID = entityID
+			m.conversions[id] = conversion
+			moved++
+		}
+	}
+
+	return moved, nil
+}
+
+func (m *memoryConversionAdapter) DeleteCompany(ctx context.Context, cid string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return deleteCompanyRecords(m.conversions, cid)
+}
@@ -0,0 +1,70 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+)
+
+type memoryHistoryAdapter struct {
+	kvs map[string][]byte
+}
+
+func NewMemoryHistoryAdapter() port.DocumentHistoryServiceAdapter {
+	return &memoryHistoryAdapter{
+		kvs: make(map[string][]byte),
+	}
+}
+
+func (m *memoryHistoryAdapter) UpsertHistory(ctx context.Context, history domain.DocumentHistory) error {
+	if err := history.Validate(); err != nil {
+		return err
+	}
+
+	buffer, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+
+	m.kvs[history.Key()] = buffer
+	return nil
+}
+
+func (m *memoryHistoryAdapter) SelectHistory(ctx context.Context, cid, fid string) (domain.DocumentHistory, error) {
+	var history domain.DocumentHistory
+	buffer, ok := m.kvs[fmt.Sprintf("%s:%s", cid, fid)]
+	if !ok {
+		return history, ErrNoDocumentHistory
+	}
+
+	if err := json.Unmarshal(buffer, &history); err != nil {
+		return history, err
+	}
+
+	return history, nil
+}
+
+func (m *memoryHistoryAdapter) DeleteCompany(ctx context.Context, cid string) (int, error) {
+	return deleteCompanyRecords(m.kvs, cid)
+}
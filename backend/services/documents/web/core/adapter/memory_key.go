@@ -0,0 +1,91 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+)
+
+type memoryKeyAdapter struct {
+	mu   sync.Mutex
+	keys map[string]domain.DocumentKey
+}
+
+func NewMemoryKeyAdapter() port.DocumentKeyServiceAdapter {
+	return &memoryKeyAdapter{
+		keys: make(map[string]domain.DocumentKey),
+	}
+}
+
+func (m *memoryKeyAdapter) InsertKey(ctx context.Context, key domain.DocumentKey) error {
+	if err := key.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.keys[key.ID()]; ok {
+		return ErrDocumentKeyExists
+	}
+
+	m.keys[key.ID()] = key
+	return nil
+}
+
+func (m *memoryKeyAdapter) SelectKey(ctx context.Context, cid, fid string) (domain.DocumentKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.keys[fmt.Sprintf("%s:%s", cid, fid)]
+	if !ok {
+		return key, ErrNoDocumentKey
+	}
+
+	return key, nil
+}
+
+func (m *memoryKeyAdapter) UpdateKey(ctx context.Context, previous, key domain.DocumentKey) error {
+	if err := key.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.keys[key.ID()]
+	if !ok || current.Key != previous.Key {
+		return ErrDocumentKeyChanged
+	}
+
+	m.keys[key.ID()] = key
+	return nil
+}
+
+func (m *memoryKeyAdapter) DeleteCompany(ctx context.Context, cid string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return deleteCompanyRecords(m.keys, cid)
+}
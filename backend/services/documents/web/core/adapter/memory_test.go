@@ -0,0 +1,96 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var activity = domain.DocumentActivity{
+	CompanyID:  "1",
+	FileID:     "2",
+	EntityType: "deal",
+	EntityID:   "3",
+	DocKey:     "mock",
+	Status:     1,
+	Editors:    []string{"1:1"},
+}
+
+func TestMemoryAdapter(t *testing.T) {
+	adapter := NewMemoryActivityAdapter()
+
+	t.Run("get missing activity", func(t *testing.T) {
+		_, err := adapter.SelectActivity(context.Background(), activity.CompanyID, activity.FileID)
+		assert.ErrorIs(t, err, ErrNoDocumentActivity)
+	})
+
+	t.Run("save activity", func(t *testing.T) {
+		_, err := adapter.UpsertActivity(context.Background(), activity)
+		assert.NoError(t, err)
+	})
+
+	t.Run("get activity", func(t *testing.T) {
+		a, err := adapter.SelectActivity(context.Background(), activity.CompanyID, activity.FileID)
+		assert.NoError(t, err)
+		assert.Equal(t, activity, a)
+	})
+
+	t.Run("update activity", func(t *testing.T) {
+		updated := activity
+		updated.Status = 4
+		updated.Editors = []string{}
+		_, err := adapter.UpsertActivity(context.Background(), updated)
+		assert.NoError(t, err)
+		a, err := adapter.SelectActivity(context.Background(), activity.CompanyID, activity.FileID)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, a.Status)
+		assert.Empty(t, a.Editors)
+	})
+}
+
+func TestMemoryVersionAdapter(t *testing.T) {
+	adapter := NewMemoryVersionAdapter()
+	root := domain.DocumentVersion{CompanyID: "1", RootID: "1", FileID: "1", Version: 1, Filename: "mock.docx"}
+
+	t.Run("insert version", func(t *testing.T) {
+		assert.NoError(t, adapter.InsertVersion(context.Background(), root))
+	})
+
+	t.Run("insert the same file twice", func(t *testing.T) {
+		version := root
+		version.ParentID, version.Version = "1", 2
+		assert.ErrorIs(t, adapter.InsertVersion(context.Background(), version), ErrVersionExists)
+	})
+
+	t.Run("insert a taken version number", func(t *testing.T) {
+		version := root
+		version.ParentID, version.FileID = "1", "2"
+		assert.ErrorIs(t, adapter.InsertVersion(context.Background(), version), ErrVersionExists)
+	})
+
+	t.Run("same version number in another company", func(t *testing.T) {
+		version := root
+		version.CompanyID = "2"
+		assert.NoError(t, adapter.InsertVersion(context.Background(), version))
+	})
+}
@@ -0,0 +1,164 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+)
+
+type memoryVersionAdapter struct {
+	mu  sync.Mutex
+	kvs map[string][]byte
+}
+
+func NewMemoryVersionAdapter() port.DocumentVersionServiceAdapter {
+	return &memoryVersionAdapter{
+		kvs: make(map[string][]byte),
+	}
+}
+
+func (m *memoryVersionAdapter) InsertVersion(ctx context.Context, version domain.DocumentVersion) error {
+	if err := version.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.kvs[version.Key()]; ok {
+		return ErrVersionExists
+	}
+
+	for _, buffer := range m.kvs {
+		var existing domain.DocumentVersion
+		if err := json.Unmarshal(buffer, &existing); err != nil {
+			return err
+		}
+
+		if existing.CompanyID == version.CompanyID && existing.RootID == version.RootID && existing.Version == version.Version {
+			return ErrVersionExists
+		}
+	}
+
+	buffer, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+
+	m.kvs[version.Key()] = buffer
+	return nil
+}
+
+func (m *memoryVersionAdapter) SelectVersion(ctx context.Context, cid, fid string) (domain.DocumentVersion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var version domain.DocumentVersion
+	buffer, ok := m.kvs[fmt.Sprintf("%s:%s", cid, fid)]
+	if !ok {
+		return version, ErrNoDocumentVersion
+	}
+
+	if err := json.Unmarshal(buffer, &version); err != nil {
+		return version, err
+	}
+
+	return version, nil
+}
+
+func (m *memoryVersionAdapter) SelectVersions(ctx context.Context, cid, rid string) ([]domain.DocumentVersion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	versions := make([]domain.DocumentVersion, 0)
+	for _, buffer := range m.kvs {
+		var version domain.DocumentVersion
+		if err := json.Unmarshal(buffer, &version); err != nil {
+			return nil, err
+		}
+
+		if version.CompanyID == cid && version.RootID == rid {
+			versions = append(versions, version)
+		}
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+
+	return versions, nil
+}
+
+func (m *memoryVersionAdapter) updateVersions(filter domain.DocumentFilter, update func(*domain.DocumentVersion)) (int, error) {
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updated := 0
+	for key, buffer := range m.kvs {
+		var version domain.DocumentVersion
+		if err := json.Unmarshal(buffer, &version); err != nil {
+			return updated, err
+		}
+
+		if !filter.Matches(version.CompanyID, version.FileID, version.EntityType, version.EntityID) {
+			continue
+		}
+
+		update(&version)
+		buffer, err := json.Marshal(version)
+		if err != nil {
+			return updated, err
+		}
+
+		m.kvs[key] = buffer
+		updated++
+	}
+
+	return updated, nil
+}
+
+func (m *memoryVersionAdapter) DeleteVersions(ctx context.Context, filter domain.DocumentFilter) (int, error) {
+	return m.updateVersions(filter, func(version *domain.DocumentVersion) {
+		version.Deleted = true
+	})
+}
+
+func (m *memoryVersionAdapter) MoveVersions(ctx context.Context, filter domain.DocumentFilter, entityID string) (int, error) {
+	return m.updateVersions(filter, func(version *domain.DocumentVersion) {
+		version.EntityID = entityID
+	})
+}
+
+func (m *memoryVersionAdapter) DeleteCompany(ctx context.Context, cid string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return deleteCompanyRecords(m.kvs, cid)
+}
@@ -0,0 +1,265 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type documentActivityCollection struct {
+	mgm.DefaultModel `bson:",inline"`
+	CompanyID        string                  `json:"company_id" bson:"company_id"`
+	FileID           string                  `json:"file_id" bson:"file_id"`
+	EntityType       string                  `json:"entity_type" bson:"entity_type"`
+	EntityID         string                  `json:"entity_id" bson:"entity_id"`
+	DocKey           string                  `json:"doc_key" bson:"doc_key"`
+	Status           int                     `json:"status" bson:"status"`
+	Editors          []string                `json:"editors" bson:"editors"`
+	Sessions         []documentSessionRecord `json:"sessions" bson:"sessions"`
+	LastError        string                  `json:"last_error" bson:"last_error"`
+	LastErrorAt      time.Time               `json:"last_error_at" bson:"last_error_at"`
+	Deleted          bool                    `json:"deleted" bson:"deleted"`
+}
+
+// documentSessionRecord is a session embedded in its file activity.
+type documentSessionRecord struct {
+	DocKey    string    `json:"doc_key" bson:"doc_key"`
+	UserID    string    `json:"user_id" bson:"user_id"`
+	Name      string    `json:"name" bson:"name"`
+	Mode      string    `json:"mode" bson:"mode"`
+	State     string    `json:"state" bson:"state"`
+	OpenedAt  time.Time `json:"opened_at" bson:"opened_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+}
+
+func toSessionRecords(sessions []domain.DocumentSession) []documentSessionRecord {
+	records := make([]documentSessionRecord, 0, len(sessions))
+	for _, session := range sessions {
+		records = append(records, documentSessionRecord{
+			DocKey:    session.DocKey,
+			UserID:    session.UserID,
+			Name:      session.Name,
+			Mode:      session.Mode,
+			State:     session.State,
+			OpenedAt:  session.OpenedAt,
+			UpdatedAt: session.UpdatedAt,
+			ExpiresAt: session.ExpiresAt,
+		})
+	}
+
+	return records
+}
+
+func toDocumentActivity(activity *documentActivityCollection) domain.DocumentActivity {
+	sessions := make([]domain.DocumentSession, 0, len(activity.Sessions))
+	for _, record := range activity.Sessions {
+		sessions = append(sessions, domain.DocumentSession{
+			CompanyID:  activity.CompanyID,
+			FileID:     activity.FileID,
+			EntityType: activity.EntityType,
+			EntityID:   activity.EntityID,
+			DocKey:     record.DocKey,
+			UserID:     record.UserID,
+			Name:       record.Name,
+			Mode:       record.Mode,
+			State:      record.State,
+			OpenedAt:   record.OpenedAt,
+			UpdatedAt:  record.UpdatedAt,
+			ExpiresAt:  record.ExpiresAt,
+		})
+	}
+
+	return domain.DocumentActivity{
+		CompanyID:   activity.CompanyID,
+		FileID:      activity.FileID,
+		EntityType:  activity.EntityType,
+		EntityID:    activity.EntityID,
+		DocKey:      activity.DocKey,
+		Status:      activity.Status,
+		Editors:     activity.Editors,
+		Sessions:    sessions,
+		LastError:   activity.LastError,
+		LastErrorAt: activity.LastErrorAt,
+		Deleted:     activity.Deleted,
+		UpdatedAt:   activity.UpdatedAt,
+	}
+}
+
+type mongoActivityAdapter struct {
+}
+
+func NewMongoActivityAdapter(url string) port.DocumentActivityServiceAdapter {
+	if err := mgm.SetDefaultConfig(
+		&mgm.Config{CtxTimeout: 3 * time.Second}, "pipedrive",
+		options.Client().ApplyURI(url),
+	); err != nil {
+		log.Fatalf("mongo initialization error: %s", err.Error())
+	}
+
+	return &mongoActivityAdapter{}
+}
+
+func (m *mongoActivityAdapter) UpsertActivity(ctx context.Context, activity domain.DocumentActivity) (domain.DocumentActivity, error) {
+	if err := activity.Validate(); err != nil {
+		return activity, err
+	}
+
+	return activity, mgm.Transaction(func(session mongo.Session, sc mongo.SessionContext) error {
+		a := &documentActivityCollection{}
+		collection := mgm.Coll(a)
+
+		if err := collection.FirstWithCtx(ctx, bson.M{"company_id": activity.CompanyID, "file_id": activity.FileID}, a); err != nil {
+			if cerr := collection.CreateWithCtx(ctx, &documentActivityCollection{
+				CompanyID:   activity.CompanyID,
+				FileID:      activity.FileID,
+				EntityType:  activity.EntityType,
+				EntityID:    activity.EntityID,
+				DocKey:      activity.DocKey,
+				Status:      activity.Status,
+				Editors:     activity.Editors,
+				Sessions:    toSessionRecords(activity.Sessions),
+				LastError:   activity.LastError,
+				LastErrorAt: activity.LastErrorAt,
+				Deleted:     activity.Deleted,
+			}); cerr != nil {
+				return cerr
+			}
+
+			return session.CommitTransaction(sc)
+		}
+
+		a.EntityType = activity.EntityType
+		a.EntityID = activity.EntityID
+		a.DocKey = activity.DocKey
+		a.Status = activity.Status
+		a.Editors = activity.Editors
+		a.Sessions = toSessionRecords(activity.Sessions)
+		a.LastError = activity.LastError
+		a.LastErrorAt = activity.LastErrorAt
+		a.Deleted = activity.Deleted
+		a.UpdatedAt = time.Now()
+
+		if err := collection.UpdateWithCtx(ctx, a); err != nil {
+			return err
+		}
+
+		return session.CommitTransaction(sc)
+	})
+}
+
+func (m *mongoActivityAdapter) SelectActivity(ctx context.Context, cid, fid string) (domain.DocumentActivity, error) {
+	cid, fid = strings.TrimSpace(cid), strings.TrimSpace(fid)
+	if cid == "" || fid == "" {
+		return domain.DocumentActivity{}, ErrInvalidFileID
+	}
+
+	activity := &documentActivityCollection{}
+	if err := mgm.Coll(activity).FirstWithCtx(ctx, bson.M{"company_id": cid, "file_id": fid}, activity); err != nil {
+		return domain.DocumentActivity{}, err
+	}
+
+	return toDocumentActivity(activity), nil
+}
+
+func (m *mongoActivityAdapter) SelectActivities(ctx context.Context, filter domain.DocumentFilter) ([]domain.DocumentActivity, error) {
+	if strings.TrimSpace(filter.CompanyID) == "" {
+		return nil, ErrInvalidCompanyID
+	}
+
+	var records []documentActivityCollection
+	if err := mgm.Coll(&documentActivityCollection{}).SimpleFindWithCtx(ctx, &records, toFilterQuery(filter)); err != nil {
+		return nil, err
+	}
+
+	activities := make([]domain.DocumentActivity, 0, len(records))
+	for i := range records {
+		activities = append(activities, toDocumentActivity(&records[i]))
+	}
+
+	return activities, nil
+}
+
+// toFilterQuery expects a validated filter.
+func toFilterQuery(filter domain.DocumentFilter) bson.M {
+	if filter.FileID != "" {
+		return bson.M{"company_id": filter.CompanyID, "file_id": filter.FileID}
+	}
+
+	if filter.IsCompany() {
+		return bson.M{"company_id": filter.CompanyID}
+	}
+
+	return bson.M{
+		"company_id":  filter.CompanyID,
+		"entity_type": filter.EntityType,
+		"entity_id":   filter.EntityID,
+	}
+}
+
+// deleteCompanyDocuments removes every document of the company from the collection of the model.
+func deleteCompanyDocuments(ctx context.Context, model mgm.Model, cid string) (int, error) {
+	cid = strings.TrimSpace(cid)
+	if cid == "" {
+		return 0, ErrInvalidCompanyID
+	}
+
+	res, err := mgm.Coll(model).DeleteMany(ctx, bson.M{"company_id": cid})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.DeletedCount), nil
+}
+
+func (m *mongoActivityAdapter) updateActivities(ctx context.Context, filter domain.DocumentFilter, fields bson.M) (int, error) {
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	fields["updated_at"] = time.Now()
+	res, err := mgm.Coll(&documentActivityCollection{}).UpdateMany(ctx, toFilterQuery(filter), bson.M{"$set": fields})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+func (m *mongoActivityAdapter) CloseActivities(ctx context.Context, filter domain.DocumentFilter) (int, error) {
+	return m.updateActivities(ctx, filter, bson.M{"editors": []string{}, "sessions": []documentSessionRecord{}, "deleted": true})
+}
+
+func (m *mongoActivityAdapter) MoveActivities(ctx context.Context, filter domain.DocumentFilter, entityID string) (int, error) {
+	return m.updateActivities(ctx, filter, bson.M{"entity_id": entityID})
+}
+
+func (m *mongoActivityAdapter) DeleteCompany(ctx context.Context, cid string) (int, error) {
+	return deleteCompanyDocuments(ctx, &documentActivityCollection{}, cid)
+}
@@ -0,0 +1,172 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type documentConversionCollection struct {
+	mgm.DefaultModel `bson:",inline"`
+	CompanyID        string `json:"company_id" bson:"company_id"`
+	Key              string `json:"key" bson:"key"`
+	FileID           string `json:"file_id" bson:"file_id"`
+	ResultID         string `json:"result_id" bson:"result_id"`
+	Filename         string `json:"filename" bson:"filename"`
+	EntityType       string `json:"entity_type" bson:"entity_type"`
+	EntityID         string `json:"entity_id" bson:"entity_id"`
+	UpdateTime       string `json:"update_time" bson:"update_time"`
+}
+
+type mongoConversionAdapter struct {
+}
+
+func NewMongoConversionAdapter(url string) port.DocumentConversionServiceAdapter {
+	if err := mgm.SetDefaultConfig(
+		&mgm.Config{CtxTimeout: 3 * time.Second}, "pipedrive",
+		options.Client().ApplyURI(url),
+	); err != nil {
+		log.Fatalf("mongo initialization error: %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := mgm.Coll(&documentConversionCollection{}).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "file_id", Value: 1}}},
+		{Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "result_id", Value: 1}}},
+	}); err != nil {
+		log.Fatalf("mongo index initialization error: %s", err.Error())
+	}
+
+	return &mongoConversionAdapter{}
+}
+
+func (m *mongoConversionAdapter) InsertConversion(ctx context.Context, conversion domain.DocumentConversion) error {
+	if err := conversion.Validate(); err != nil {
+		return err
+	}
+
+	if err := mgm.Coll(&documentConversionCollection{}).CreateWithCtx(ctx, &documentConversionCollection{
+		CompanyID:  conversion.CompanyID,
+		Key:        conversion.Key,
+		FileID:     conversion.FileID,
+		ResultID:   conversion.ResultID,
+		Filename:   conversion.Filename,
+		EntityType: conversion.EntityType,
+		EntityID:   conversion.EntityID,
+		UpdateTime: conversion.UpdateTime,
+	}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrConversionExists
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (m *mongoConversionAdapter) SelectConversion(ctx context.Context, cid, key string) (domain.DocumentConversion, error) {
+	cid, key = strings.TrimSpace(cid), strings.TrimSpace(key)
+	if cid == "" || key == "" {
+		return domain.DocumentConversion{}, ErrInvalidFileID
+	}
+
+	conversion := &documentConversionCollection{}
+	if err := mgm.Coll(conversion).FirstWithCtx(ctx, bson.M{"company_id": cid, "key": key}, conversion); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.DocumentConversion{}, ErrNoDocumentConversion
+		}
+
+		return domain.DocumentConversion{}, err
+	}
+
+	return domain.DocumentConversion{
+		CompanyID:  conversion.CompanyID,
+		Key:        conversion.Key,
+		FileID:     conversion.FileID,
+		ResultID:   conversion.ResultID,
+		Filename:   conversion.Filename,
+		EntityType: conversion.EntityType,
+		EntityID:   conversion.EntityID,
+		UpdateTime: conversion.UpdateTime,
+		CreatedAt:  conversion.CreatedAt,
+	}, nil
+}
+
+// DeleteConversions removes the conversions of a deleted file, whether it is their source or their result.
+func (m *mongoConversionAdapter) DeleteConversions(ctx context.Context, filter domain.DocumentFilter) (int, error) {
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	query := toFilterQuery(filter)
+	if filter.FileID != "" {
+		query = bson.M{
+			"company_id": filter.CompanyID,
+			"$or": bson.A{
+				bson.M{"file_id": filter.FileID},
+				bson.M{"result_id": filter.FileID},
+			},
+		}
+	}
+
+	res, err := mgm.Coll(&documentConversionCollection{}).DeleteMany(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.DeletedCount), nil
+}
+
+func (m *mongoConversionAdapter) MoveConversions(ctx context.Context, filter domain.DocumentFilter, entityID string) (int, error) {
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	res, err := mgm.Coll(&documentConversionCollection{}).UpdateMany(ctx, toFilterQuery(filter), bson.M{"$set": bson.M{
+		"entity_id":  entityID,
+		"updated_at": time.Now(),
+	}})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+func (m *mongoConversionAdapter) DeleteCompany(ctx context.Context, cid string) (int, error) {
+	return deleteCompanyDocuments(ctx, &documentConversionCollection{}, cid)
+}
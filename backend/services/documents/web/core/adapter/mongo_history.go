@@ -0,0 +1,109 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type documentHistoryCollection struct {
+	mgm.DefaultModel `bson:",inline"`
+	CompanyID        string                 `json:"company_id" bson:"company_id"`
+	FileID           string                 `json:"file_id" bson:"file_id"`
+	ServerVersion    string                 `json:"server_version" bson:"server_version"`
+	Changes          []domain.HistoryChange `json:"changes" bson:"changes"`
+	Archive          []byte                 `json:"archive" bson:"archive"`
+}
+
+type mongoHistoryAdapter struct {
+}
+
+func NewMongoHistoryAdapter(url string) port.DocumentHistoryServiceAdapter {
+	if err := mgm.SetDefaultConfig(
+		&mgm.Config{CtxTimeout: 3 * time.Second}, "pipedrive",
+		options.Client().ApplyURI(url),
+	); err != nil {
+		log.Fatalf("mongo initialization error: %s", err.Error())
+	}
+
+	return &mongoHistoryAdapter{}
+}
+
+func (m *mongoHistoryAdapter) UpsertHistory(ctx context.Context, history domain.DocumentHistory) error {
+	if err := history.Validate(); err != nil {
+		return err
+	}
+
+	_, err := mgm.Coll(&documentHistoryCollection{}).UpdateOne(ctx, bson.M{
+		"company_id": history.CompanyID,
+		"file_id":    history.FileID,
+	}, bson.M{
+		"$set": bson.M{
+			"server_version": history.ServerVersion,
+			"changes":        history.Changes,
+			"archive":        history.Archive,
+			"updated_at":     time.Now(),
+		},
+		"$setOnInsert": bson.M{
+			"created_at": time.Now(),
+		},
+	}, options.Update().SetUpsert(true))
+
+	return err
+}
+
+func (m *mongoHistoryAdapter) SelectHistory(ctx context.Context, cid, fid string) (domain.DocumentHistory, error) {
+	cid, fid = strings.TrimSpace(cid), strings.TrimSpace(fid)
+	if cid == "" || fid == "" {
+		return domain.DocumentHistory{}, ErrInvalidFileID
+	}
+
+	history := &documentHistoryCollection{}
+	if err := mgm.Coll(history).FirstWithCtx(ctx, bson.M{"company_id": cid, "file_id": fid}, history); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.DocumentHistory{}, ErrNoDocumentHistory
+		}
+
+		return domain.DocumentHistory{}, err
+	}
+
+	return domain.DocumentHistory{
+		CompanyID:     history.CompanyID,
+		FileID:        history.FileID,
+		ServerVersion: history.ServerVersion,
+		Changes:       history.Changes,
+		Archive:       history.Archive,
+		CreatedAt:     history.CreatedAt,
+	}, nil
+}
+
+func (m *mongoHistoryAdapter) DeleteCompany(ctx context.Context, cid string) (int, error) {
+	return deleteCompanyDocuments(ctx, &documentHistoryCollection{}, cid)
+}
@@ -0,0 +1,144 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type documentKeyCollection struct {
+	mgm.DefaultModel `bson:",inline"`
+	CompanyID        string `json:"company_id" bson:"company_id"`
+	FileID           string `json:"file_id" bson:"file_id"`
+	Revision         int    `json:"revision" bson:"revision"`
+	Key              string `json:"key" bson:"key"`
+}
+
+type mongoKeyAdapter struct {
+}
+
+func NewMongoKeyAdapter(url string) port.DocumentKeyServiceAdapter {
+	if err := mgm.SetDefaultConfig(
+		&mgm.Config{CtxTimeout: 3 * time.Second}, "pipedrive",
+		options.Client().ApplyURI(url),
+	); err != nil {
+		log.Fatalf("mongo initialization error: %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := mgm.Coll(&documentKeyCollection{}).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "file_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		log.Fatalf("mongo index initialization error: %s", err.Error())
+	}
+
+	return &mongoKeyAdapter{}
+}
+
+func (m *mongoKeyAdapter) InsertKey(ctx context.Context, key domain.DocumentKey) error {
+	if err := key.Validate(); err != nil {
+		return err
+	}
+
+	if err := mgm.Coll(&documentKeyCollection{}).CreateWithCtx(ctx, &documentKeyCollection{
+		CompanyID: key.CompanyID,
+		FileID:    key.FileID,
+		Revision:  key.Revision,
+		Key:       key.Key,
+	}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDocumentKeyExists
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (m *mongoKeyAdapter) SelectKey(ctx context.Context, cid, fid string) (domain.DocumentKey, error) {
+	cid, fid = strings.TrimSpace(cid), strings.TrimSpace(fid)
+	if cid == "" || fid == "" {
+		return domain.DocumentKey{}, ErrInvalidFileID
+	}
+
+	key := &documentKeyCollection{}
+	if err := mgm.Coll(key).FirstWithCtx(ctx, bson.M{"company_id": cid, "file_id": fid}, key); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.DocumentKey{}, ErrNoDocumentKey
+		}
+
+		return domain.DocumentKey{}, err
+	}
+
+	return domain.DocumentKey{
+		CompanyID: key.CompanyID,
+		FileID:    key.FileID,
+		Revision:  key.Revision,
+		Key:       key.Key,
+		UpdatedAt: key.UpdatedAt,
+	}, nil
+}
+
+// UpdateKey only matches the previous key, so concurrent rotations of the same revision
+// update the record once.
+func (m *mongoKeyAdapter) UpdateKey(ctx context.Context, previous, key domain.DocumentKey) error {
+	if err := key.Validate(); err != nil {
+		return err
+	}
+
+	res, err := mgm.Coll(&documentKeyCollection{}).UpdateOne(ctx, bson.M{
+		"company_id": key.CompanyID,
+		"file_id":    key.FileID,
+		"key":        previous.Key,
+	}, bson.M{
+		"$set": bson.M{
+			"revision":   key.Revision,
+			"key":        key.Key,
+			"updated_at": time.Now(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrDocumentKeyChanged
+	}
+
+	return nil
+}
+
+func (m *mongoKeyAdapter) DeleteCompany(ctx context.Context, cid string) (int, error) {
+	return deleteCompanyDocuments(ctx, &documentKeyCollection{}, cid)
+}
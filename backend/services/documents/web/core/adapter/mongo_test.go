@@ -0,0 +1,49 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMongoAdapter(t *testing.T) {
+	adapter := NewMongoActivityAdapter("mongodb://localhost:27017")
+
+	t.Run("save activity with timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0*time.Second)
+		defer cancel()
+		_, err := adapter.UpsertActivity(ctx, activity)
+		assert.Error(t, err)
+	})
+
+	t.Run("save activity", func(t *testing.T) {
+		_, err := adapter.UpsertActivity(context.Background(), activity)
+		assert.NoError(t, err)
+	})
+
+	t.Run("get activity", func(t *testing.T) {
+		a, err := adapter.SelectActivity(context.Background(), activity.CompanyID, activity.FileID)
+		assert.NoError(t, err)
+		assert.Equal(t, activity.Editors, a.Editors)
+	})
+}
@@ -0,0 +1,187 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type documentVersionCollection struct {
+	mgm.DefaultModel `bson:",inline"`
+	CompanyID        string `json:"company_id" bson:"company_id"`
+	RootID           string `json:"root_id" bson:"root_id"`
+	ParentID         string `json:"parent_id" bson:"parent_id"`
+	FileID           string `json:"file_id" bson:"file_id"`
+	EntityType       string `json:"entity_type" bson:"entity_type"`
+	EntityID         string `json:"entity_id" bson:"entity_id"`
+	Version          int    `json:"version" bson:"version"`
+	Filename         string `json:"filename" bson:"filename"`
+	Author           string `json:"author" bson:"author"`
+	Deleted          bool   `json:"deleted" bson:"deleted"`
+}
+
+type mongoVersionAdapter struct {
+}
+
+func NewMongoVersionAdapter(url string) port.DocumentVersionServiceAdapter {
+	if err := mgm.SetDefaultConfig(
+		&mgm.Config{CtxTimeout: 3 * time.Second}, "pipedrive",
+		options.Client().ApplyURI(url),
+	); err != nil {
+		log.Fatalf("mongo initialization error: %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := mgm.Coll(&documentVersionCollection{}).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "file_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "root_id", Value: 1}, {Key: "version", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}); err != nil {
+		log.Fatalf("mongo index initialization error: %s", err.Error())
+	}
+
+	return &mongoVersionAdapter{}
+}
+
+func (m *mongoVersionAdapter) toDomain(version *documentVersionCollection) domain.DocumentVersion {
+	return domain.DocumentVersion{
+		CompanyID:  version.CompanyID,
+		RootID:     version.RootID,
+		ParentID:   version.ParentID,
+		FileID:     version.FileID,
+		EntityType: version.EntityType,
+		EntityID:   version.EntityID,
+		Version:    version.Version,
+		Filename:   version.Filename,
+		Author:     version.Author,
+		Deleted:    version.Deleted,
+		CreatedAt:  version.CreatedAt,
+	}
+}
+
+func (m *mongoVersionAdapter) InsertVersion(ctx context.Context, version domain.DocumentVersion) error {
+	if err := version.Validate(); err != nil {
+		return err
+	}
+
+	if err := mgm.Coll(&documentVersionCollection{}).CreateWithCtx(ctx, &documentVersionCollection{
+		CompanyID:  version.CompanyID,
+		RootID:     version.RootID,
+		ParentID:   version.ParentID,
+		FileID:     version.FileID,
+		EntityType: version.EntityType,
+		EntityID:   version.EntityID,
+		Version:    version.Version,
+		Filename:   version.Filename,
+		Author:     version.Author,
+		Deleted:    version.Deleted,
+	}); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrVersionExists
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (m *mongoVersionAdapter) SelectVersion(ctx context.Context, cid, fid string) (domain.DocumentVersion, error) {
+	cid, fid = strings.TrimSpace(cid), strings.TrimSpace(fid)
+	if cid == "" || fid == "" {
+		return domain.DocumentVersion{}, ErrInvalidFileID
+	}
+
+	version := &documentVersionCollection{}
+	if err := mgm.Coll(version).FirstWithCtx(ctx, bson.M{"company_id": cid, "file_id": fid}, version); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.DocumentVersion{}, ErrNoDocumentVersion
+		}
+
+		return domain.DocumentVersion{}, err
+	}
+
+	return m.toDomain(version), nil
+}
+
+func (m *mongoVersionAdapter) SelectVersions(ctx context.Context, cid, rid string) ([]domain.DocumentVersion, error) {
+	cid, rid = strings.TrimSpace(cid), strings.TrimSpace(rid)
+	if cid == "" || rid == "" {
+		return nil, ErrInvalidFileID
+	}
+
+	var records []documentVersionCollection
+	if err := mgm.Coll(&documentVersionCollection{}).SimpleFindWithCtx(
+		ctx, &records, bson.M{"company_id": cid, "root_id": rid},
+		options.Find().SetSort(bson.D{{Key: "version", Value: 1}}),
+	); err != nil {
+		return nil, err
+	}
+
+	versions := make([]domain.DocumentVersion, 0, len(records))
+	for i := range records {
+		versions = append(versions, m.toDomain(&records[i]))
+	}
+
+	return versions, nil
+}
+
+func (m *mongoVersionAdapter) updateVersions(ctx context.Context, filter domain.DocumentFilter, fields bson.M) (int, error) {
+	if err := filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	fields["updated_at"] = time.Now()
+	res, err := mgm.Coll(&documentVersionCollection{}).UpdateMany(ctx, toFilterQuery(filter), bson.M{"$set": fields})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(res.ModifiedCount), nil
+}
+
+func (m *mongoVersionAdapter) DeleteVersions(ctx context.Context, filter domain.DocumentFilter) (int, error) {
+	return m.updateVersions(ctx, filter, bson.M{"deleted": true})
+}
+
+func (m *mongoVersionAdapter) MoveVersions(ctx context.Context, filter domain.DocumentFilter, entityID string) (int, error) {
+	return m.updateVersions(ctx, filter, bson.M{"entity_id": entityID})
+}
+
+func (m *mongoVersionAdapter) DeleteCompany(ctx context.Context, cid string) (int, error) {
+	return deleteCompanyDocuments(ctx, &documentVersionCollection{}, cid)
+}
@@ -0,0 +1,95 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DocumentActivity is the latest known document server state of a Pipedrive file along with
+// its editing sessions. Files whose editor has been opened before the first callback have
+// no status yet. Deleted activities belong to files removed in Pipedrive and never have editors.
+type DocumentActivity struct {
+	CompanyID   string            `json:"company_id" mapstructure:"company_id"`
+	FileID      string            `json:"file_id" mapstructure:"file_id"`
+	EntityType  string            `json:"entity_type" mapstructure:"entity_type"`
+	EntityID    string            `json:"entity_id" mapstructure:"entity_id"`
+	DocKey      string            `json:"doc_key" mapstructure:"doc_key"`
+	Status      int               `json:"status" mapstructure:"status"`
+	Editors     []string          `json:"editors" mapstructure:"editors"`
+	Sessions    []DocumentSession `json:"sessions" mapstructure:"sessions"`
+	LastError   string            `json:"last_error,omitempty" mapstructure:"last_error"`
+	LastErrorAt time.Time         `json:"last_error_at,omitempty" mapstructure:"last_error_at"`
+	Deleted     bool              `json:"deleted" mapstructure:"deleted"`
+	UpdatedAt   time.Time         `json:"updated_at" mapstructure:"updated_at"`
+}
+
+func (a DocumentActivity) Key() string {
+	return fmt.Sprintf("%s:%s", a.CompanyID, a.FileID)
+}
+
+func (a DocumentActivity) ToJSON() []byte {
+	buf, _ := json.Marshal(a)
+	return buf
+}
+
+func (a *DocumentActivity) Validate() error {
+	a.CompanyID = strings.TrimSpace(a.CompanyID)
+	a.FileID = strings.TrimSpace(a.FileID)
+	a.EntityType = strings.TrimSpace(a.EntityType)
+	a.EntityID = strings.TrimSpace(a.EntityID)
+	a.DocKey = strings.TrimSpace(a.DocKey)
+
+	if a.CompanyID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Activity",
+			Field:  "CompanyID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if a.FileID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Activity",
+			Field:  "FileID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if a.Status < 0 || a.Status > 7 {
+		return &InvalidModelFieldError{
+			Model:  "Activity",
+			Field:  "Status",
+			Reason: "Invalid status. Exptected 0 <= status <= 7",
+		}
+	}
+
+	if a.Editors == nil {
+		a.Editors = []string{}
+	}
+
+	if a.Sessions == nil {
+		a.Sessions = []DocumentSession{}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DocumentConversion is the Pipedrive file a finished conversion has been uploaded as. It is
+// looked up by the conversion key so that repeated requests reuse the file instead of uploading it again.
+type DocumentConversion struct {
+	CompanyID  string    `json:"company_id" mapstructure:"company_id"`
+	Key        string    `json:"key" mapstructure:"key"`
+	FileID     string    `json:"file_id" mapstructure:"file_id"`
+	ResultID   string    `json:"result_id" mapstructure:"result_id"`
+	Filename   string    `json:"filename" mapstructure:"filename"`
+	EntityType string    `json:"entity_type" mapstructure:"entity_type"`
+	EntityID   string    `json:"entity_id" mapstructure:"entity_id"`
+	UpdateTime string    `json:"update_time,omitempty" mapstructure:"update_time"`
+	CreatedAt  time.Time `json:"created_at" mapstructure:"created_at"`
+}
+
+func (c DocumentConversion) ID() string {
+	return fmt.Sprintf("%s:%s", c.CompanyID, c.Key)
+}
+
+func (c DocumentConversion) ToJSON() []byte {
+	buf, _ := json.Marshal(c)
+	return buf
+}
+
+func (c *DocumentConversion) Validate() error {
+	c.CompanyID = strings.TrimSpace(c.CompanyID)
+	c.Key = strings.TrimSpace(c.Key)
+	c.FileID = strings.TrimSpace(c.FileID)
+	c.ResultID = strings.TrimSpace(c.ResultID)
+	c.Filename = strings.TrimSpace(c.Filename)
+	c.EntityType = strings.TrimSpace(c.EntityType)
+	c.EntityID = strings.TrimSpace(c.EntityID)
+
+	if c.CompanyID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Conversion",
+			Field:  "CompanyID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if c.Key == "" {
+		return &InvalidModelFieldError{
+			Model:  "Conversion",
+			Field:  "Key",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if c.FileID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Conversion",
+			Field:  "FileID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if c.ResultID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Conversion",
+			Field:  "ResultID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if c.Filename == "" {
+		return &InvalidModelFieldError{
+			Model:  "Conversion",
+			Field:  "Filename",
+			Reason: "Should not be empty",
+		}
+	}
+
+	return nil
+}
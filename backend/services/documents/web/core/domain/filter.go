@@ -0,0 +1,92 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DocumentFilter selects the documents of a company either by a single file
+// or by the Pipedrive entity the files are attached to. A filter with neither
+// selects every document of the company and is only valid for reads.
+type DocumentFilter struct {
+	CompanyID  string
+	FileID     string
+	EntityType string
+	EntityID   string
+}
+
+func (f DocumentFilter) String() string {
+	if f.FileID != "" {
+		return fmt.Sprintf("%s:%s", f.CompanyID, f.FileID)
+	}
+
+	if f.IsCompany() {
+		return f.CompanyID
+	}
+
+	return fmt.Sprintf("%s:%s:%s", f.CompanyID, f.EntityType, f.EntityID)
+}
+
+// Matches reports whether a document attached to the entity passes the filter.
+func (f DocumentFilter) Matches(cid, fid, entityType, entityID string) bool {
+	if f.CompanyID != cid {
+		return false
+	}
+
+	if f.FileID != "" {
+		return f.FileID == fid
+	}
+
+	if f.IsCompany() {
+		return true
+	}
+
+	return f.EntityType == entityType && f.EntityID == entityID
+}
+
+func (f DocumentFilter) IsCompany() bool {
+	return f.FileID == "" && f.EntityType == "" && f.EntityID == ""
+}
+
+func (f *DocumentFilter) Validate() error {
+	f.CompanyID = strings.TrimSpace(f.CompanyID)
+	f.FileID = strings.TrimSpace(f.FileID)
+	f.EntityType = strings.TrimSpace(f.EntityType)
+	f.EntityID = strings.TrimSpace(f.EntityID)
+
+	if f.CompanyID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Filter",
+			Field:  "CompanyID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if f.FileID == "" && (f.EntityType == "" || f.EntityID == "") {
+		return &InvalidModelFieldError{
+			Model:  "Filter",
+			Field:  "FileID",
+			Reason: "Should not be empty without an entity",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type HistoryChange struct {
+	Created  string `json:"created" mapstructure:"created"`
+	UserID   string `json:"user_id" mapstructure:"user_id"`
+	UserName string `json:"user_name" mapstructure:"user_name"`
+}
+
+// DocumentHistory is the document server history of a saved version. The changes archive
+// is what the editor uses to highlight the differences from the previous version.
+type DocumentHistory struct {
+	CompanyID     string          `json:"company_id" mapstructure:"company_id"`
+	FileID        string          `json:"file_id" mapstructure:"file_id"`
+	ServerVersion string          `json:"server_version" mapstructure:"server_version"`
+	Changes       []HistoryChange `json:"changes" mapstructure:"changes"`
+	Archive       []byte          `json:"archive,omitempty" mapstructure:"archive"`
+	CreatedAt     time.Time       `json:"created_at" mapstructure:"created_at"`
+}
+
+func (h DocumentHistory) Key() string {
+	return fmt.Sprintf("%s:%s", h.CompanyID, h.FileID)
+}
+
+func (h DocumentHistory) ToJSON() []byte {
+	buf, _ := json.Marshal(h)
+	return buf
+}
+
+func (h *DocumentHistory) Validate() error {
+	h.CompanyID = strings.TrimSpace(h.CompanyID)
+	h.FileID = strings.TrimSpace(h.FileID)
+	h.ServerVersion = strings.TrimSpace(h.ServerVersion)
+
+	if h.CompanyID == "" {
+		return &InvalidModelFieldError{
+			Model:  "History",
+			Field:  "CompanyID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if h.FileID == "" {
+		return &InvalidModelFieldError{
+			Model:  "History",
+			Field:  "FileID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if h.Changes == nil {
+		h.Changes = []HistoryChange{}
+	}
+
+	return nil
+}
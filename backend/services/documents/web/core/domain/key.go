@@ -0,0 +1,85 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DocumentKey is the document server key of a file revision. Every user opening the same
+// revision gets the same key so that they co-edit, and a saved revision never reuses a key.
+type DocumentKey struct {
+	CompanyID string    `json:"company_id" mapstructure:"company_id"`
+	FileID    string    `json:"file_id" mapstructure:"file_id"`
+	Revision  int       `json:"revision" mapstructure:"revision"`
+	Key       string    `json:"key" mapstructure:"key"`
+	UpdatedAt time.Time `json:"updated_at" mapstructure:"updated_at"`
+}
+
+func (k DocumentKey) ID() string {
+	return fmt.Sprintf("%s:%s", k.CompanyID, k.FileID)
+}
+
+func (k DocumentKey) ToJSON() []byte {
+	buf, _ := json.Marshal(k)
+	return buf
+}
+
+func (k *DocumentKey) Validate() error {
+	k.CompanyID = strings.TrimSpace(k.CompanyID)
+	k.FileID = strings.TrimSpace(k.FileID)
+	k.Key = strings.TrimSpace(k.Key)
+
+	if k.CompanyID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Key",
+			Field:  "CompanyID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if k.FileID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Key",
+			Field:  "FileID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if k.Revision <= 0 {
+		return &InvalidModelFieldError{
+			Model:  "Key",
+			Field:  "Revision",
+			Reason: "Should be positive",
+		}
+	}
+
+	if k.Key == "" || len(k.Key) > 128 {
+		return &InvalidModelFieldError{
+			Model:  "Key",
+			Field:  "Key",
+			Reason: "Should not be empty or longer than 128 characters",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// A session is opening from the moment its editor config is built until the document
+// server reports its user as connected.
+const (
+	SessionStateOpening   string = "opening"
+	SessionStateConnected string = "connected"
+)
+
+const (
+	SessionModeEdit string = "edit"
+	SessionModeView string = "view"
+)
+
+// DocumentSession is a user having a Pipedrive file open in the editor. Sessions are kept
+// in the file activity and expire on their own in case the document server never reports
+// them closed.
+type DocumentSession struct {
+	CompanyID  string    `json:"company_id" mapstructure:"company_id"`
+	FileID     string    `json:"file_id" mapstructure:"file_id"`
+	EntityType string    `json:"entity_type" mapstructure:"entity_type"`
+	EntityID   string    `json:"entity_id" mapstructure:"entity_id"`
+	DocKey     string    `json:"doc_key" mapstructure:"doc_key"`
+	UserID     string    `json:"user_id" mapstructure:"user_id"`
+	Name       string    `json:"name" mapstructure:"name"`
+	Mode       string    `json:"mode" mapstructure:"mode"`
+	State      string    `json:"state" mapstructure:"state"`
+	OpenedAt   time.Time `json:"opened_at" mapstructure:"opened_at"`
+	UpdatedAt  time.Time `json:"updated_at" mapstructure:"updated_at"`
+	ExpiresAt  time.Time `json:"expires_at" mapstructure:"expires_at"`
+}
+
+func (s DocumentSession) Expired(now time.Time) bool {
+	return !s.ExpiresAt.After(now)
+}
+
+func (s *DocumentSession) Validate() error {
+	s.CompanyID = strings.TrimSpace(s.CompanyID)
+	s.FileID = strings.TrimSpace(s.FileID)
+	s.EntityType = strings.TrimSpace(s.EntityType)
+	s.EntityID = strings.TrimSpace(s.EntityID)
+	s.DocKey = strings.TrimSpace(s.DocKey)
+	s.UserID = strings.TrimSpace(s.UserID)
+	s.Name = strings.TrimSpace(s.Name)
+
+	if s.CompanyID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Session",
+			Field:  "CompanyID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if s.FileID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Session",
+			Field:  "FileID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if s.UserID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Session",
+			Field:  "UserID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if s.Mode != SessionModeEdit && s.Mode != SessionModeView {
+		return &InvalidModelFieldError{
+			Model:  "Session",
+			Field:  "Mode",
+			Reason: "Invalid mode. Expected edit or view",
+		}
+	}
+
+	if s.State != SessionStateOpening && s.State != SessionStateConnected {
+		return &InvalidModelFieldError{
+			Model:  "Session",
+			Field:  "State",
+			Reason: "Invalid state. Expected opening or connected",
+		}
+	}
+
+	if s.ExpiresAt.IsZero() {
+		return &InvalidModelFieldError{
+			Model:  "Session",
+			Field:  "ExpiresAt",
+			Reason: "Should not be empty",
+		}
+	}
+
+	return nil
+}
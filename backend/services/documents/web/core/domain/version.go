@@ -0,0 +1,97 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DocumentVersion links a Pipedrive file to the lineage of the document it was saved from.
+// The original file is the root of its own lineage with version 1. Versions whose file
+// has been removed in Pipedrive stay in the lineage as deleted.
+type DocumentVersion struct {
+	CompanyID  string    `json:"company_id" mapstructure:"company_id"`
+	RootID     string    `json:"root_id" mapstructure:"root_id"`
+	ParentID   string    `json:"parent_id" mapstructure:"parent_id"`
+	FileID     string    `json:"file_id" mapstructure:"file_id"`
+	EntityType string    `json:"entity_type" mapstructure:"entity_type"`
+	EntityID   string    `json:"entity_id" mapstructure:"entity_id"`
+	Version    int       `json:"version" mapstructure:"version"`
+	Filename   string    `json:"filename" mapstructure:"filename"`
+	Author     string    `json:"author" mapstructure:"author"`
+	Deleted    bool      `json:"deleted" mapstructure:"deleted"`
+	CreatedAt  time.Time `json:"created_at" mapstructure:"created_at"`
+}
+
+func (v DocumentVersion) Key() string {
+	return fmt.Sprintf("%s:%s", v.CompanyID, v.FileID)
+}
+
+func (v DocumentVersion) ToJSON() []byte {
+	buf, _ := json.Marshal(v)
+	return buf
+}
+
+func (v *DocumentVersion) Validate() error {
+	v.CompanyID = strings.TrimSpace(v.CompanyID)
+	v.RootID = strings.TrimSpace(v.RootID)
+	v.ParentID = strings.TrimSpace(v.ParentID)
+	v.FileID = strings.TrimSpace(v.FileID)
+	v.EntityType = strings.TrimSpace(v.EntityType)
+	v.EntityID = strings.TrimSpace(v.EntityID)
+	v.Filename = strings.TrimSpace(v.Filename)
+	v.Author = strings.TrimSpace(v.Author)
+
+	if v.CompanyID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Version",
+			Field:  "CompanyID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if v.FileID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Version",
+			Field:  "FileID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if v.Filename == "" {
+		return &InvalidModelFieldError{
+			Model:  "Version",
+			Field:  "Filename",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if v.Version > 1 && (v.RootID == "" || v.ParentID == "") {
+		return &InvalidModelFieldError{
+			Model:  "Version",
+			Field:  "ParentID",
+			Reason: "Should not be empty for derived versions",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package port
+
+import (
+	"context"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+)
+
+type DocumentActivityService interface {
+	UpdateActivity(ctx context.Context, activity domain.DocumentActivity) (domain.DocumentActivity, error)
+	GetActivity(ctx context.Context, cid, fid string) (domain.DocumentActivity, error)
+}
+
+type DocumentVersionService interface {
+	// AddVersion appends a file to the lineage of its parent file.
+	AddVersion(ctx context.Context, version domain.DocumentVersion) (domain.DocumentVersion, error)
+	// GetVersions returns the whole lineage of any file in it, oldest version first.
+	GetVersions(ctx context.Context, cid, fid string) ([]domain.DocumentVersion, error)
+	GetVersion(ctx context.Context, cid, fid string, version int) (domain.DocumentVersion, error)
+}
+
+type DocumentKeyService interface {
+	// GetKey returns the key of the current file revision, creating the first one if needed.
+	GetKey(ctx context.Context, cid, fid string) (domain.DocumentKey, error)
+	// RotateKey starts a new revision if the key is still current and returns the current key.
+	RotateKey(ctx context.Context, cid, fid, key string) (domain.DocumentKey, error)
+}
+
+type DocumentConversionService interface {
+	// AddConversion records a converted file. If the key has already been recorded the earlier file is returned.
+	AddConversion(ctx context.Context, conversion domain.DocumentConversion) (domain.DocumentConversion, error)
+	GetConversion(ctx context.Context, cid, key string) (domain.DocumentConversion, error)
+}
+
+type DocumentSessionService interface {
+	// OpenSession registers a user the editor config has been built for.
+	OpenSession(ctx context.Context, session domain.DocumentSession) (domain.DocumentSession, error)
+	GetSessions(ctx context.Context, filter domain.DocumentFilter) ([]domain.DocumentSession, error)
+}
+
+// DocumentEventService keeps the stored documents consistent with changes made in Pipedrive.
+type DocumentEventService interface {
+	// DeleteFile closes the file editing sessions and marks its version deleted.
+	DeleteFile(ctx context.Context, cid, fid string) error
+	// DeleteEntity does the same for every file attached to the entity.
+	DeleteEntity(ctx context.Context, cid, entityType, entityID string) error
+	// MergeEntity moves the files of a merged entity to the one it was merged into.
+	MergeEntity(ctx context.Context, cid, entityType, from, to string) error
+	// PurgeCompany removes every document record of an uninstalled company.
+	PurgeCompany(ctx context.Context, cid string) error
+}
+
+type DocumentHistoryService interface {
+	SaveHistory(ctx context.Context, history domain.DocumentHistory) (domain.DocumentHistory, error)
+	GetHistory(ctx context.Context, cid, fid string) (domain.DocumentHistory, error)
+}
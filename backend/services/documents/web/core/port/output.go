@@ -0,0 +1,72 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package port
+
+import (
+	"context"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+)
+
+type DocumentActivityServiceAdapter interface {
+	UpsertActivity(ctx context.Context, activity domain.DocumentActivity) (domain.DocumentActivity, error)
+	SelectActivity(ctx context.Context, cid, fid string) (domain.DocumentActivity, error)
+	// SelectActivities accepts company filters.
+	SelectActivities(ctx context.Context, filter domain.DocumentFilter) ([]domain.DocumentActivity, error)
+	// CloseActivities removes all editors and sessions of the matching files and marks them deleted.
+	CloseActivities(ctx context.Context, filter domain.DocumentFilter) (int, error)
+	// MoveActivities reattaches the matching files to another entity of the same type.
+	MoveActivities(ctx context.Context, filter domain.DocumentFilter, entityID string) (int, error)
+	// DeleteCompany removes the records of an uninstalled company.
+	DeleteCompany(ctx context.Context, cid string) (int, error)
+}
+
+type DocumentVersionServiceAdapter interface {
+	InsertVersion(ctx context.Context, version domain.DocumentVersion) error
+	SelectVersion(ctx context.Context, cid, fid string) (domain.DocumentVersion, error)
+	SelectVersions(ctx context.Context, cid, rid string) ([]domain.DocumentVersion, error)
+	DeleteVersions(ctx context.Context, filter domain.DocumentFilter) (int, error)
+	MoveVersions(ctx context.Context, filter domain.DocumentFilter, entityID string) (int, error)
+	DeleteCompany(ctx context.Context, cid string) (int, error)
+}
+
+type DocumentKeyServiceAdapter interface {
+	// InsertKey fails with ErrDocumentKeyExists if the file already has a key.
+	InsertKey(ctx context.Context, key domain.DocumentKey) error
+	SelectKey(ctx context.Context, cid, fid string) (domain.DocumentKey, error)
+	// UpdateKey replaces the previous key and fails with ErrDocumentKeyChanged if it is no longer current.
+	UpdateKey(ctx context.Context, previous, key domain.DocumentKey) error
+	DeleteCompany(ctx context.Context, cid string) (int, error)
+}
+
+type DocumentConversionServiceAdapter interface {
+	// InsertConversion fails with ErrConversionExists if the conversion key has already been recorded.
+	InsertConversion(ctx context.Context, conversion domain.DocumentConversion) error
+	SelectConversion(ctx context.Context, cid, key string) (domain.DocumentConversion, error)
+	// DeleteConversions removes the conversions whose source or result file matches the filter.
+	DeleteConversions(ctx context.Context, filter domain.DocumentFilter) (int, error)
+	MoveConversions(ctx context.Context, filter domain.DocumentFilter, entityID string) (int, error)
+	DeleteCompany(ctx context.Context, cid string) (int, error)
+}
+
+type DocumentHistoryServiceAdapter interface {
+	UpsertHistory(ctx context.Context, history domain.DocumentHistory) error
+	SelectHistory(ctx context.Context, cid, fid string) (domain.DocumentHistory, error)
+	DeleteCompany(ctx context.Context, cid string) (int, error)
+}
@@ -0,0 +1,135 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
+)
+
+type activityService struct {
+	adapter port.DocumentActivityServiceAdapter
+	logger  plog.Logger
+}
+
+func NewActivityService(
+	adapter port.DocumentActivityServiceAdapter,
+	logger plog.Logger,
+) port.DocumentActivityService {
+	return activityService{
+		adapter: adapter,
+		logger:  logger,
+	}
+}
+
+// UpdateActivity merges a callback status into the persisted file activity.
+// Status 1 carries the full list of editors, statuses 2, 3 and 4 mean the document
+// has been closed and forcesave statuses do not change who is editing. The editing
+// sessions follow the editors of the updated activity.
+func (s activityService) UpdateActivity(ctx context.Context, activity domain.DocumentActivity) (domain.DocumentActivity, error) {
+	s.logger.Debugf("validating file %s activity to perform an update action", activity.Key())
+	if err := activity.Validate(); err != nil {
+		return activity, err
+	}
+
+	if activity.Status == 0 {
+		return activity, &InvalidServiceParameterError{
+			Name:   "Status",
+			Reason: "Should be a callback status",
+		}
+	}
+
+	if ctx.Err() != nil {
+		return activity, ErrOperationTimeout
+	}
+
+	previous, err := s.adapter.SelectActivity(ctx, activity.CompanyID, activity.FileID)
+	if err != nil {
+		s.logger.Debugf("no previous activity found for file %s", activity.Key())
+		previous = domain.DocumentActivity{}
+	}
+
+	switch activity.Status {
+	case constants.CallbackStatusMustSave, constants.CallbackStatusSaveError, constants.CallbackStatusClosed:
+		activity.Editors = []string{}
+	case constants.CallbackStatusMustForcesave, constants.CallbackStatusForcesaveError:
+		activity.Editors = previous.Editors
+	}
+
+	if activity.Editors == nil {
+		activity.Editors = []string{}
+	}
+
+	if activity.EntityID == "" {
+		activity.EntityType, activity.EntityID = previous.EntityType, previous.EntityID
+	}
+
+	now := time.Now()
+	activity.Deleted = previous.Deleted
+	activity.Sessions = []domain.DocumentSession{}
+	if !activity.Deleted {
+		activity.Sessions = syncSessions(previous.Sessions, activity, now)
+	}
+
+	if activity.LastError == "" {
+		switch activity.Status {
+		case constants.CallbackStatusSaveError:
+			activity.LastError = "document saving error"
+		case constants.CallbackStatusForcesaveError:
+			activity.LastError = "document forcesaving error"
+		}
+	}
+
+	if strings.TrimSpace(activity.LastError) != "" {
+		activity.LastErrorAt = now
+	} else if activity.Status != constants.CallbackStatusMustSave {
+		activity.LastError = previous.LastError
+		activity.LastErrorAt = previous.LastErrorAt
+	}
+
+	activity.UpdatedAt = now
+	s.logger.Debugf("file %s activity is valid to perform an update action", activity.Key())
+	return s.adapter.UpsertActivity(ctx, activity)
+}
+
+func (s activityService) GetActivity(ctx context.Context, cid, fid string) (domain.DocumentActivity, error) {
+	cid, fid = strings.TrimSpace(cid), strings.TrimSpace(fid)
+	s.logger.Debugf("trying to select file %s:%s activity", cid, fid)
+	if cid == "" {
+		return domain.DocumentActivity{}, &InvalidServiceParameterError{
+			Name:   "CID",
+			Reason: "Should not be blank",
+		}
+	}
+
+	if fid == "" {
+		return domain.DocumentActivity{}, &InvalidServiceParameterError{
+			Name:   "FID",
+			Reason: "Should not be blank",
+		}
+	}
+
+	return s.adapter.SelectActivity(ctx, cid, fid)
+}
@@ -0,0 +1,99 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivityService(t *testing.T) {
+	service := NewActivityService(adapter.NewMemoryActivityAdapter(), log.NewEmptyLogger())
+
+	t.Run("update activity with timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0*time.Second)
+		defer cancel()
+		_, err := service.UpdateActivity(ctx, domain.DocumentActivity{
+			CompanyID: "1", FileID: "1", Status: 1,
+		})
+		assert.ErrorIs(t, err, ErrOperationTimeout)
+	})
+
+	t.Run("update activity with invalid status", func(t *testing.T) {
+		for _, status := range []int{0, 8} {
+			_, err := service.UpdateActivity(context.Background(), domain.DocumentActivity{
+				CompanyID: "1", FileID: "1", Status: status,
+			})
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("track editors", func(t *testing.T) {
+		a, err := service.UpdateActivity(context.Background(), domain.DocumentActivity{
+			CompanyID: "1", FileID: "1", EntityType: "deal", EntityID: "1", Status: 1, Editors: []string{"1:1", "1:2"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1:1", "1:2"}, a.Editors)
+	})
+
+	t.Run("forcesave keeps editors", func(t *testing.T) {
+		a, err := service.UpdateActivity(context.Background(), domain.DocumentActivity{
+			CompanyID: "1", FileID: "1", Status: 6, Editors: []string{"1:1"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1:1", "1:2"}, a.Editors)
+		assert.Equal(t, "1", a.EntityID)
+	})
+
+	t.Run("forcesave error is recorded", func(t *testing.T) {
+		a, err := service.UpdateActivity(context.Background(), domain.DocumentActivity{
+			CompanyID: "1", FileID: "1", Status: 7,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "document forcesaving error", a.LastError)
+		assert.False(t, a.LastErrorAt.IsZero())
+		assert.Len(t, a.Editors, 2)
+	})
+
+	t.Run("successful save clears editors and errors", func(t *testing.T) {
+		a, err := service.UpdateActivity(context.Background(), domain.DocumentActivity{
+			CompanyID: "1", FileID: "1", Status: 2,
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, a.Editors)
+		assert.Empty(t, a.LastError)
+	})
+
+	t.Run("get activity with blank file id", func(t *testing.T) {
+		_, err := service.GetActivity(context.Background(), "1", " ")
+		assert.Error(t, err)
+	})
+
+	t.Run("get activity", func(t *testing.T) {
+		a, err := service.GetActivity(context.Background(), "1", "1")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, a.Status)
+	})
+}
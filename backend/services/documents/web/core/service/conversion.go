@@ -0,0 +1,86 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+)
+
+type conversionService struct {
+	adapter port.DocumentConversionServiceAdapter
+	logger  plog.Logger
+}
+
+func NewConversionService(
+	adapter port.DocumentConversionServiceAdapter,
+	logger plog.Logger,
+) port.DocumentConversionService {
+	return conversionService{
+		adapter: adapter,
+		logger:  logger,
+	}
+}
+
+func (s conversionService) AddConversion(ctx context.Context, conversion domain.DocumentConversion) (domain.DocumentConversion, error) {
+	s.logger.Debugf("validating conversion %s to perform an insert action", conversion.ID())
+	if err := conversion.Validate(); err != nil {
+		return conversion, err
+	}
+
+	if ctx.Err() != nil {
+		return conversion, ErrOperationTimeout
+	}
+
+	conversion.CreatedAt = time.Now()
+	if err := s.adapter.InsertConversion(ctx, conversion); err != nil {
+		// Another instance has uploaded the same conversion concurrently
+		if errors.Is(err, adapter.ErrConversionExists) {
+			return s.adapter.SelectConversion(ctx, conversion.CompanyID, conversion.Key)
+		}
+
+		return conversion, err
+	}
+
+	s.logger.Debugf("file %s:%s has been converted to %s", conversion.CompanyID, conversion.FileID, conversion.ResultID)
+	return conversion, nil
+}
+
+func (s conversionService) GetConversion(ctx context.Context, cid, key string) (domain.DocumentConversion, error) {
+	cid, key = strings.TrimSpace(cid), strings.TrimSpace(key)
+	if cid == "" || key == "" {
+		return domain.DocumentConversion{}, &InvalidServiceParameterError{
+			Name:   "Conversion",
+			Reason: "Should not be blank",
+		}
+	}
+
+	if ctx.Err() != nil {
+		return domain.DocumentConversion{}, ErrOperationTimeout
+	}
+
+	return s.adapter.SelectConversion(ctx, cid, key)
+}
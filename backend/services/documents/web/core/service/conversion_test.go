@@ -0,0 +1,82 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversionService(t *testing.T) {
+	service := NewConversionService(adapter.NewMemoryConversionAdapter(), log.NewEmptyLogger())
+	conversion := domain.DocumentConversion{
+		CompanyID: "1", Key: "1_2_docx", FileID: "2", ResultID: "3", Filename: "mock.docx", EntityType: "deal", EntityID: "1",
+	}
+
+	t.Run("add conversion with timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0*time.Second)
+		defer cancel()
+		_, err := service.AddConversion(ctx, conversion)
+		assert.ErrorIs(t, err, ErrOperationTimeout)
+	})
+
+	t.Run("add conversion without a result", func(t *testing.T) {
+		invalid := conversion
+		invalid.ResultID = ""
+		_, err := service.AddConversion(context.Background(), invalid)
+		assert.Error(t, err)
+	})
+
+	t.Run("get missing conversion", func(t *testing.T) {
+		_, err := service.GetConversion(context.Background(), "1", "1_2_docx")
+		assert.ErrorIs(t, err, adapter.ErrNoDocumentConversion)
+	})
+
+	t.Run("add conversion", func(t *testing.T) {
+		c, err := service.AddConversion(context.Background(), conversion)
+		assert.NoError(t, err)
+		assert.Equal(t, "3", c.ResultID)
+	})
+
+	t.Run("a concurrent upload returns the first result", func(t *testing.T) {
+		duplicate := conversion
+		duplicate.ResultID = "4"
+		c, err := service.AddConversion(context.Background(), duplicate)
+		assert.NoError(t, err)
+		assert.Equal(t, "3", c.ResultID)
+	})
+
+	t.Run("get conversion", func(t *testing.T) {
+		c, err := service.GetConversion(context.Background(), "1", "1_2_docx")
+		assert.NoError(t, err)
+		assert.Equal(t, "3", c.ResultID)
+		assert.Equal(t, "mock.docx", c.Filename)
+	})
+
+	t.Run("conversions are scoped by company", func(t *testing.T) {
+		_, err := service.GetConversion(context.Background(), "2", "1_2_docx")
+		assert.ErrorIs(t, err, adapter.ErrNoDocumentConversion)
+	})
+}
@@ -0,0 +1,185 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"strings"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+)
+
+type eventService struct {
+	activityAdapter port.DocumentActivityServiceAdapter
+	versionAdapter  port.DocumentVersionServiceAdapter
+	convertAdapter  port.DocumentConversionServiceAdapter
+	keyAdapter      port.DocumentKeyServiceAdapter
+	historyAdapter  port.DocumentHistoryServiceAdapter
+	logger          plog.Logger
+}
+
+func NewEventService(
+	activityAdapter port.DocumentActivityServiceAdapter,
+	versionAdapter port.DocumentVersionServiceAdapter,
+	convertAdapter port.DocumentConversionServiceAdapter,
+	keyAdapter port.DocumentKeyServiceAdapter,
+	historyAdapter port.DocumentHistoryServiceAdapter,
+	logger plog.Logger,
+) port.DocumentEventService {
+	return eventService{
+		activityAdapter: activityAdapter,
+		versionAdapter:  versionAdapter,
+		convertAdapter:  convertAdapter,
+		keyAdapter:      keyAdapter,
+		historyAdapter:  historyAdapter,
+		logger:          logger,
+	}
+}
+
+// deleteDocuments is idempotent, so a redelivered event only refreshes the update time.
+func (s eventService) deleteDocuments(ctx context.Context, filter domain.DocumentFilter) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return ErrOperationTimeout
+	}
+
+	closed, err := s.activityAdapter.CloseActivities(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.versionAdapter.DeleteVersions(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.convertAdapter.DeleteConversions(ctx, filter); err != nil {
+		return err
+	}
+
+	s.logger.Debugf("closed %d activities and deleted %d versions of %s", closed, deleted, filter.String())
+	return nil
+}
+
+func (s eventService) DeleteFile(ctx context.Context, cid, fid string) error {
+	return s.deleteDocuments(ctx, domain.DocumentFilter{
+		CompanyID: cid,
+		FileID:    fid,
+	})
+}
+
+func (s eventService) DeleteEntity(ctx context.Context, cid, entityType, entityID string) error {
+	if strings.TrimSpace(entityType) == "" || strings.TrimSpace(entityID) == "" {
+		return &InvalidServiceParameterError{
+			Name:   "Entity",
+			Reason: "Should not be blank",
+		}
+	}
+
+	return s.deleteDocuments(ctx, domain.DocumentFilter{
+		CompanyID:  cid,
+		EntityType: entityType,
+		EntityID:   entityID,
+	})
+}
+
+func (s eventService) MergeEntity(ctx context.Context, cid, entityType, from, to string) error {
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if strings.TrimSpace(entityType) == "" || from == "" || to == "" {
+		return &InvalidServiceParameterError{
+			Name:   "Entity",
+			Reason: "Should not be blank",
+		}
+	}
+
+	if from == to {
+		return nil
+	}
+
+	filter := domain.DocumentFilter{
+		CompanyID:  cid,
+		EntityType: entityType,
+		EntityID:   from,
+	}
+
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return ErrOperationTimeout
+	}
+
+	moved, err := s.activityAdapter.MoveActivities(ctx, filter, to)
+	if err != nil {
+		return err
+	}
+
+	versions, err := s.versionAdapter.MoveVersions(ctx, filter, to)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.convertAdapter.MoveConversions(ctx, filter, to); err != nil {
+		return err
+	}
+
+	s.logger.Debugf("moved %d activities and %d versions of %s to %s", moved, versions, filter.String(), to)
+	return nil
+}
+
+// PurgeCompany removes every stored document of a company. Unlike deleted files, nothing is
+// kept since the company has uninstalled the app. Purging an already purged company is a no-op.
+func (s eventService) PurgeCompany(ctx context.Context, cid string) error {
+	cid = strings.TrimSpace(cid)
+	if cid == "" {
+		return &InvalidServiceParameterError{
+			Name:   "CompanyID",
+			Reason: "Should not be blank",
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ErrOperationTimeout
+	}
+
+	purged := 0
+	for _, purge := range []func(context.Context, string) (int, error){
+		s.activityAdapter.DeleteCompany,
+		s.versionAdapter.DeleteCompany,
+		s.convertAdapter.DeleteCompany,
+		s.keyAdapter.DeleteCompany,
+		s.historyAdapter.DeleteCompany,
+	} {
+		deleted, err := purge(ctx, cid)
+		if err != nil {
+			return err
+		}
+
+		purged += deleted
+	}
+
+	s.logger.Debugf("purged %d document records of company %s", purged, cid)
+	return nil
+}
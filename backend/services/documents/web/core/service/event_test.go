@@ -0,0 +1,264 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventService(t *testing.T) {
+	activityAdapter := adapter.NewMemoryActivityAdapter()
+	versionAdapter := adapter.NewMemoryVersionAdapter()
+	activities := NewActivityService(activityAdapter, log.NewEmptyLogger())
+	versions := NewVersionService(versionAdapter, log.NewEmptyLogger())
+	sessions := NewSessionService(activityAdapter, log.NewEmptyLogger())
+	conversionAdapter := adapter.NewMemoryConversionAdapter()
+	conversions := NewConversionService(conversionAdapter, log.NewEmptyLogger())
+	service := NewEventService(
+		activityAdapter, versionAdapter, conversionAdapter,
+		adapter.NewMemoryKeyAdapter(), adapter.NewMemoryHistoryAdapter(), log.NewEmptyLogger(),
+	)
+
+	for _, fid := range []string{"1", "2"} {
+		_, err := activities.UpdateActivity(context.Background(), domain.DocumentActivity{
+			CompanyID: "1", FileID: fid, EntityType: "deal", EntityID: "1", Status: 1, Editors: []string{"1:1"},
+		})
+		assert.NoError(t, err)
+	}
+
+	for _, fid := range []string{"1", "2"} {
+		_, err := sessions.OpenSession(context.Background(), domain.DocumentSession{
+			CompanyID: "1", FileID: fid, EntityType: "deal", EntityID: "1", UserID: "1:1", Mode: domain.SessionModeEdit,
+		})
+		assert.NoError(t, err)
+	}
+
+	for _, conversion := range []domain.DocumentConversion{
+		{CompanyID: "1", Key: "1_1_docx", FileID: "1", ResultID: "7", Filename: "mock.docx", EntityType: "deal", EntityID: "1"},
+		{CompanyID: "1", Key: "1_6_docx", FileID: "6", ResultID: "2", Filename: "mock.docx", EntityType: "deal", EntityID: "1"},
+	} {
+		_, err := conversions.AddConversion(context.Background(), conversion)
+		assert.NoError(t, err)
+	}
+
+	_, err := versions.AddVersion(context.Background(), domain.DocumentVersion{
+		CompanyID: "1", ParentID: "1", FileID: "2", EntityType: "deal", EntityID: "1", Filename: "mock.docx",
+	})
+	assert.NoError(t, err)
+
+	t.Run("delete file with an invalid company", func(t *testing.T) {
+		assert.Error(t, service.DeleteFile(context.Background(), "", "1"))
+	})
+
+	t.Run("merge entity without a target", func(t *testing.T) {
+		assert.Error(t, service.MergeEntity(context.Background(), "1", "deal", "1", ""))
+	})
+
+	t.Run("delete file closes its session", func(t *testing.T) {
+		assert.NoError(t, service.DeleteFile(context.Background(), "1", "2"))
+		a, err := activities.GetActivity(context.Background(), "1", "2")
+		assert.NoError(t, err)
+		assert.True(t, a.Deleted)
+		assert.Empty(t, a.Editors)
+
+		a, err = activities.GetActivity(context.Background(), "1", "1")
+		assert.NoError(t, err)
+		assert.False(t, a.Deleted)
+		assert.Equal(t, []string{"1:1"}, a.Editors)
+
+		ss, err := sessions.GetSessions(context.Background(), domain.DocumentFilter{CompanyID: "1"})
+		assert.NoError(t, err)
+		assert.Len(t, ss, 1)
+		assert.Equal(t, "1", ss[0].FileID)
+	})
+
+	t.Run("delete file marks its version deleted", func(t *testing.T) {
+		vs, err := versions.GetVersions(context.Background(), "1", "1")
+		assert.NoError(t, err)
+		assert.Len(t, vs, 2)
+		assert.False(t, vs[0].Deleted)
+		assert.True(t, vs[1].Deleted)
+	})
+
+	t.Run("delete file removes the conversions it is the result of", func(t *testing.T) {
+		_, err := conversions.GetConversion(context.Background(), "1", "1_6_docx")
+		assert.ErrorIs(t, err, adapter.ErrNoDocumentConversion)
+		_, err = conversions.GetConversion(context.Background(), "1", "1_1_docx")
+		assert.NoError(t, err)
+	})
+
+	t.Run("deleted files stay deleted on callbacks", func(t *testing.T) {
+		a, err := activities.UpdateActivity(context.Background(), domain.DocumentActivity{
+			CompanyID: "1", FileID: "2", Status: 4,
+		})
+		assert.NoError(t, err)
+		assert.True(t, a.Deleted)
+	})
+
+	t.Run("merge entity moves its files", func(t *testing.T) {
+		assert.NoError(t, service.MergeEntity(context.Background(), "1", "deal", "1", "5"))
+		a, err := activities.GetActivity(context.Background(), "1", "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "5", a.EntityID)
+
+		ss, err := sessions.GetSessions(context.Background(), domain.DocumentFilter{
+			CompanyID: "1", EntityType: "deal", EntityID: "5",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, ss, 1)
+
+		vs, err := versions.GetVersions(context.Background(), "1", "2")
+		assert.NoError(t, err)
+		for _, v := range vs {
+			assert.Equal(t, "5", v.EntityID)
+		}
+
+		c, err := conversionAdapter.SelectConversion(context.Background(), "1", "1_1_docx")
+		assert.NoError(t, err)
+		assert.Equal(t, "5", c.EntityID)
+	})
+
+	t.Run("delete entity closes all its sessions", func(t *testing.T) {
+		assert.NoError(t, service.DeleteEntity(context.Background(), "1", "deal", "5"))
+		assert.NoError(t, service.DeleteEntity(context.Background(), "1", "deal", "5"))
+		a, err := activities.GetActivity(context.Background(), "1", "1")
+		assert.NoError(t, err)
+		assert.True(t, a.Deleted)
+		assert.Empty(t, a.Editors)
+
+		ss, err := sessions.GetSessions(context.Background(), domain.DocumentFilter{CompanyID: "1"})
+		assert.NoError(t, err)
+		assert.Empty(t, ss)
+
+		vs, err := versions.GetVersions(context.Background(), "1", "1")
+		assert.NoError(t, err)
+		for _, v := range vs {
+			assert.True(t, v.Deleted)
+		}
+
+		_, err = conversions.GetConversion(context.Background(), "1", "1_1_docx")
+		assert.ErrorIs(t, err, adapter.ErrNoDocumentConversion)
+	})
+
+	t.Run("events are scoped by company", func(t *testing.T) {
+		_, err := activities.UpdateActivity(context.Background(), domain.DocumentActivity{
+			CompanyID: "2", FileID: "1", EntityType: "deal", EntityID: "5", Status: 1, Editors: []string{"2:1"},
+		})
+		assert.NoError(t, err)
+		a, err := activities.GetActivity(context.Background(), "2", "1")
+		assert.NoError(t, err)
+		assert.False(t, a.Deleted)
+	})
+}
+
+func TestEventServicePurgeCompany(t *testing.T) {
+	activityAdapter := adapter.NewMemoryActivityAdapter()
+	versionAdapter := adapter.NewMemoryVersionAdapter()
+	conversionAdapter := adapter.NewMemoryConversionAdapter()
+	keyAdapter := adapter.NewMemoryKeyAdapter()
+	historyAdapter := adapter.NewMemoryHistoryAdapter()
+	service := NewEventService(
+		activityAdapter, versionAdapter, conversionAdapter,
+		keyAdapter, historyAdapter, log.NewEmptyLogger(),
+	)
+
+	activities := NewActivityService(activityAdapter, log.NewEmptyLogger())
+	versions := NewVersionService(versionAdapter, log.NewEmptyLogger())
+	sessions := NewSessionService(activityAdapter, log.NewEmptyLogger())
+	conversions := NewConversionService(conversionAdapter, log.NewEmptyLogger())
+	keys := NewKeyService(keyAdapter, log.NewEmptyLogger())
+	histories := NewHistoryService(historyAdapter, log.NewEmptyLogger())
+
+	for _, cid := range []string{"1", "2"} {
+		_, err := activities.UpdateActivity(context.Background(), domain.DocumentActivity{
+			CompanyID: cid, FileID: "1", EntityType: "deal", EntityID: "1", Status: 1, Editors: []string{cid + ":1"},
+		})
+		assert.NoError(t, err)
+
+		_, err = versions.AddVersion(context.Background(), domain.DocumentVersion{
+			CompanyID: cid, ParentID: "1", FileID: "2", EntityType: "deal", EntityID: "1", Filename: "mock.docx",
+		})
+		assert.NoError(t, err)
+
+		_, err = sessions.OpenSession(context.Background(), domain.DocumentSession{
+			CompanyID: cid, FileID: "1", EntityType: "deal", EntityID: "1", UserID: cid + ":1", Mode: domain.SessionModeEdit,
+		})
+		assert.NoError(t, err)
+
+		_, err = conversions.AddConversion(context.Background(), domain.DocumentConversion{
+			CompanyID: cid, Key: "1_1_docx", FileID: "1", ResultID: "3", Filename: "mock.docx", EntityType: "deal", EntityID: "1",
+		})
+		assert.NoError(t, err)
+
+		_, err = keys.GetKey(context.Background(), cid, "1")
+		assert.NoError(t, err)
+
+		_, err = histories.SaveHistory(context.Background(), domain.DocumentHistory{
+			CompanyID: cid, FileID: "2", ServerVersion: "7.0.0", Archive: []byte("changes"),
+		})
+		assert.NoError(t, err)
+	}
+
+	t.Run("purge a blank company", func(t *testing.T) {
+		assert.Error(t, service.PurgeCompany(context.Background(), " "))
+	})
+
+	t.Run("purge every document record of the company", func(t *testing.T) {
+		assert.NoError(t, service.PurgeCompany(context.Background(), "1"))
+		assert.NoError(t, service.PurgeCompany(context.Background(), "1"))
+
+		_, err := activityAdapter.SelectActivity(context.Background(), "1", "1")
+		assert.ErrorIs(t, err, adapter.ErrNoDocumentActivity)
+		_, err = versionAdapter.SelectVersion(context.Background(), "1", "2")
+		assert.ErrorIs(t, err, adapter.ErrNoDocumentVersion)
+		_, err = conversionAdapter.SelectConversion(context.Background(), "1", "1_1_docx")
+		assert.ErrorIs(t, err, adapter.ErrNoDocumentConversion)
+		_, err = keyAdapter.SelectKey(context.Background(), "1", "1")
+		assert.ErrorIs(t, err, adapter.ErrNoDocumentKey)
+		_, err = historyAdapter.SelectHistory(context.Background(), "1", "2")
+		assert.ErrorIs(t, err, adapter.ErrNoDocumentHistory)
+
+		ss, err := sessions.GetSessions(context.Background(), domain.DocumentFilter{CompanyID: "1"})
+		assert.NoError(t, err)
+		assert.Empty(t, ss)
+	})
+
+	t.Run("purge keeps other companies", func(t *testing.T) {
+		_, err := activityAdapter.SelectActivity(context.Background(), "2", "1")
+		assert.NoError(t, err)
+		_, err = versionAdapter.SelectVersion(context.Background(), "2", "2")
+		assert.NoError(t, err)
+		_, err = conversionAdapter.SelectConversion(context.Background(), "2", "1_1_docx")
+		assert.NoError(t, err)
+		_, err = keyAdapter.SelectKey(context.Background(), "2", "1")
+		assert.NoError(t, err)
+		_, err = historyAdapter.SelectHistory(context.Background(), "2", "2")
+		assert.NoError(t, err)
+
+		ss, err := sessions.GetSessions(context.Background(), domain.DocumentFilter{CompanyID: "2"})
+		assert.NoError(t, err)
+		assert.Len(t, ss, 1)
+	})
+}
@@ -0,0 +1,91 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+)
+
+// maxArchiveSize keeps changes archives well below the mongo document size limit.
+// Larger archives are dropped and the version is shown without highlighted changes.
+const maxArchiveSize = 8 * 1024 * 1024
+
+type historyService struct {
+	adapter port.DocumentHistoryServiceAdapter
+	logger  plog.Logger
+}
+
+func NewHistoryService(
+	adapter port.DocumentHistoryServiceAdapter,
+	logger plog.Logger,
+) port.DocumentHistoryService {
+	return historyService{
+		adapter: adapter,
+		logger:  logger,
+	}
+}
+
+func (s historyService) SaveHistory(ctx context.Context, history domain.DocumentHistory) (domain.DocumentHistory, error) {
+	s.logger.Debugf("validating file %s history to perform an upsert action", history.Key())
+	if err := history.Validate(); err != nil {
+		return history, err
+	}
+
+	if ctx.Err() != nil {
+		return history, ErrOperationTimeout
+	}
+
+	if len(history.Archive) > maxArchiveSize {
+		s.logger.Warnf("file %s changes archive exceeds %d bytes and will not be stored", history.Key(), maxArchiveSize)
+		history.Archive = nil
+	}
+
+	history.CreatedAt = time.Now()
+	if err := s.adapter.UpsertHistory(ctx, history); err != nil {
+		return history, err
+	}
+
+	return history, nil
+}
+
+func (s historyService) GetHistory(ctx context.Context, cid, fid string) (domain.DocumentHistory, error) {
+	cid, fid = strings.TrimSpace(cid), strings.TrimSpace(fid)
+	s.logger.Debugf("trying to select file %s:%s history", cid, fid)
+	if cid == "" {
+		return domain.DocumentHistory{}, &InvalidServiceParameterError{
+			Name:   "CID",
+			Reason: "Should not be blank",
+		}
+	}
+
+	if fid == "" {
+		return domain.DocumentHistory{}, &InvalidServiceParameterError{
+			Name:   "FID",
+			Reason: "Should not be blank",
+		}
+	}
+
+	return s.adapter.SelectHistory(ctx, cid, fid)
+}
@@ -0,0 +1,74 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryService(t *testing.T) {
+	service := NewHistoryService(adapter.NewMemoryHistoryAdapter(), log.NewEmptyLogger())
+
+	t.Run("save invalid history", func(t *testing.T) {
+		_, err := service.SaveHistory(context.Background(), domain.DocumentHistory{CompanyID: "1"})
+		assert.Error(t, err)
+	})
+
+	t.Run("save history", func(t *testing.T) {
+		h, err := service.SaveHistory(context.Background(), domain.DocumentHistory{
+			CompanyID:     "1",
+			FileID:        "2",
+			ServerVersion: "7.0.0",
+			Changes:       []domain.HistoryChange{{Created: "2026-01-01 00:00:00", UserID: "1:1", UserName: "mock"}},
+			Archive:       []byte("mock"),
+		})
+		assert.NoError(t, err)
+		assert.False(t, h.CreatedAt.IsZero())
+	})
+
+	t.Run("oversized archives are dropped", func(t *testing.T) {
+		h, err := service.SaveHistory(context.Background(), domain.DocumentHistory{
+			CompanyID: "1",
+			FileID:    "3",
+			Archive:   bytes.Repeat([]byte{0}, maxArchiveSize+1),
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, h.Archive)
+	})
+
+	t.Run("get history", func(t *testing.T) {
+		h, err := service.GetHistory(context.Background(), "1", "2")
+		assert.NoError(t, err)
+		assert.Equal(t, "7.0.0", h.ServerVersion)
+		assert.Equal(t, []byte("mock"), h.Archive)
+		assert.Len(t, h.Changes, 1)
+	})
+
+	t.Run("get missing history", func(t *testing.T) {
+		_, err := service.GetHistory(context.Background(), "2", "2")
+		assert.ErrorIs(t, err, adapter.ErrNoDocumentHistory)
+	})
+}
@@ -0,0 +1,142 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/google/uuid"
+)
+
+type keyService struct {
+	adapter port.DocumentKeyServiceAdapter
+	logger  plog.Logger
+}
+
+func NewKeyService(
+	adapter port.DocumentKeyServiceAdapter,
+	logger plog.Logger,
+) port.DocumentKeyService {
+	return keyService{
+		adapter: adapter,
+		logger:  logger,
+	}
+}
+
+// newKey derives a key from the file and its revision. The random suffix keeps keys of
+// files that were deleted and re-created with the same revision apart.
+func newKey(cid, fid string, revision int) domain.DocumentKey {
+	return domain.DocumentKey{
+		CompanyID: cid,
+		FileID:    fid,
+		Revision:  revision,
+		Key: fmt.Sprintf(
+			"%s-%d-%s", fid, revision,
+			strings.ReplaceAll(uuid.NewString(), "-", "")[:12],
+		),
+	}
+}
+
+func (s keyService) validate(cid, fid string) (string, string, error) {
+	cid, fid = strings.TrimSpace(cid), strings.TrimSpace(fid)
+	if cid == "" || fid == "" {
+		return cid, fid, &InvalidServiceParameterError{
+			Name:   "File",
+			Reason: "Should not be blank",
+		}
+	}
+
+	return cid, fid, nil
+}
+
+func (s keyService) GetKey(ctx context.Context, cid, fid string) (domain.DocumentKey, error) {
+	cid, fid, err := s.validate(cid, fid)
+	if err != nil {
+		return domain.DocumentKey{}, err
+	}
+
+	if ctx.Err() != nil {
+		return domain.DocumentKey{}, ErrOperationTimeout
+	}
+
+	key, err := s.adapter.SelectKey(ctx, cid, fid)
+	if err == nil {
+		return key, nil
+	}
+
+	if !errors.Is(err, adapter.ErrNoDocumentKey) {
+		return key, err
+	}
+
+	key = newKey(cid, fid, 1)
+	if err := s.adapter.InsertKey(ctx, key); err != nil {
+		// Another request has registered the first revision concurrently
+		if errors.Is(err, adapter.ErrDocumentKeyExists) {
+			return s.adapter.SelectKey(ctx, cid, fid)
+		}
+
+		return domain.DocumentKey{}, err
+	}
+
+	s.logger.Debugf("registered file %s:%s key %s", cid, fid, key.Key)
+	return key, nil
+}
+
+func (s keyService) RotateKey(ctx context.Context, cid, fid, key string) (domain.DocumentKey, error) {
+	cid, fid, err := s.validate(cid, fid)
+	if err != nil {
+		return domain.DocumentKey{}, err
+	}
+
+	if strings.TrimSpace(key) == "" {
+		return domain.DocumentKey{}, &InvalidServiceParameterError{
+			Name:   "Key",
+			Reason: "Should not be blank",
+		}
+	}
+
+	previous, err := s.GetKey(ctx, cid, fid)
+	if err != nil {
+		return previous, err
+	}
+
+	// A retried callback of an already rotated revision keeps the current key
+	if previous.Key != key {
+		return previous, nil
+	}
+
+	rotated := newKey(cid, fid, previous.Revision+1)
+	if err := s.adapter.UpdateKey(ctx, previous, rotated); err != nil {
+		if errors.Is(err, adapter.ErrDocumentKeyChanged) {
+			return s.adapter.SelectKey(ctx, cid, fid)
+		}
+
+		return domain.DocumentKey{}, err
+	}
+
+	s.logger.Debugf("rotated file %s:%s key to revision %d", cid, fid, rotated.Revision)
+	return rotated, nil
+}
@@ -0,0 +1,84 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/adapter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyService(t *testing.T) {
+	service := NewKeyService(adapter.NewMemoryKeyAdapter(), log.NewEmptyLogger())
+
+	t.Run("get key with an invalid file", func(t *testing.T) {
+		_, err := service.GetKey(context.Background(), "1", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("concurrent requests get the same key", func(t *testing.T) {
+		var wg sync.WaitGroup
+		keys := make([]string, 10)
+		for i := range keys {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				key, err := service.GetKey(context.Background(), "1", "1")
+				assert.NoError(t, err)
+				keys[i] = key.Key
+			}(i)
+		}
+
+		wg.Wait()
+		for _, key := range keys {
+			assert.Equal(t, keys[0], key)
+		}
+	})
+
+	t.Run("files get different keys", func(t *testing.T) {
+		first, err := service.GetKey(context.Background(), "1", "1")
+		assert.NoError(t, err)
+		second, err := service.GetKey(context.Background(), "1", "2")
+		assert.NoError(t, err)
+		assert.NotEqual(t, first.Key, second.Key)
+		assert.Equal(t, 1, first.Revision)
+	})
+
+	t.Run("rotate key starts a new revision once", func(t *testing.T) {
+		previous, err := service.GetKey(context.Background(), "1", "1")
+		assert.NoError(t, err)
+
+		rotated, err := service.RotateKey(context.Background(), "1", "1", previous.Key)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, rotated.Revision)
+		assert.NotEqual(t, previous.Key, rotated.Key)
+
+		retried, err := service.RotateKey(context.Background(), "1", "1", previous.Key)
+		assert.NoError(t, err)
+		assert.Equal(t, rotated, retried)
+
+		current, err := service.GetKey(context.Background(), "1", "1")
+		assert.NoError(t, err)
+		assert.Equal(t, rotated.Key, current.Key)
+	})
+}
@@ -0,0 +1,202 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+)
+
+const (
+	// sessionOpeningTTL matches the editor config expiration.
+	sessionOpeningTTL = 5 * time.Minute
+	// sessionConnectedTTL bounds sessions the document server never reports closed.
+	sessionConnectedTTL = 12 * time.Hour
+)
+
+type sessionService struct {
+	adapter port.DocumentActivityServiceAdapter
+	logger  plog.Logger
+}
+
+func NewSessionService(
+	adapter port.DocumentActivityServiceAdapter,
+	logger plog.Logger,
+) port.DocumentSessionService {
+	return sessionService{
+		adapter: adapter,
+		logger:  logger,
+	}
+}
+
+// OpenSession keeps users who open the same document again connected. Files opened before
+// their first callback get an activity without a status.
+func (s sessionService) OpenSession(ctx context.Context, session domain.DocumentSession) (domain.DocumentSession, error) {
+	now := time.Now()
+	session.State = domain.SessionStateOpening
+	session.OpenedAt, session.UpdatedAt = now, now
+	session.ExpiresAt = now.Add(sessionOpeningTTL)
+	if err := session.Validate(); err != nil {
+		return session, err
+	}
+
+	if ctx.Err() != nil {
+		return session, ErrOperationTimeout
+	}
+
+	activity, err := s.adapter.SelectActivity(ctx, session.CompanyID, session.FileID)
+	if err != nil {
+		s.logger.Debugf("no previous activity found for file %s:%s", session.CompanyID, session.FileID)
+		activity = domain.DocumentActivity{
+			CompanyID: session.CompanyID,
+			FileID:    session.FileID,
+			DocKey:    session.DocKey,
+		}
+	}
+
+	if activity.EntityID == "" {
+		activity.EntityType, activity.EntityID = session.EntityType, session.EntityID
+	}
+
+	sessions := make([]domain.DocumentSession, 0, len(activity.Sessions)+1)
+	for _, previous := range activity.Sessions {
+		if previous.Expired(now) {
+			continue
+		}
+
+		if previous.UserID != session.UserID {
+			sessions = append(sessions, previous)
+			continue
+		}
+
+		if previous.State == domain.SessionStateConnected && previous.DocKey == session.DocKey {
+			session.State = previous.State
+			session.OpenedAt = previous.OpenedAt
+			session.ExpiresAt = previous.ExpiresAt
+		}
+	}
+
+	activity.Sessions = append(sessions, session)
+	s.logger.Debugf("opening file %s:%s session of user %s", session.CompanyID, session.FileID, session.UserID)
+	if _, err := s.adapter.UpsertActivity(ctx, activity); err != nil {
+		return session, err
+	}
+
+	return session, nil
+}
+
+// syncSessions connects the activity editors and closes sessions of editors who have left.
+// Opening sessions are kept until they expire since their users may still connect.
+func syncSessions(sessions []domain.DocumentSession, activity domain.DocumentActivity, now time.Time) []domain.DocumentSession {
+	editors := make(map[string]bool, len(activity.Editors))
+	for _, editor := range activity.Editors {
+		if editor = strings.TrimSpace(editor); editor != "" {
+			editors[editor] = true
+		}
+	}
+
+	connect := func(session domain.DocumentSession) domain.DocumentSession {
+		session.DocKey = activity.DocKey
+		session.State = domain.SessionStateConnected
+		session.UpdatedAt = now
+		session.ExpiresAt = now.Add(sessionConnectedTTL)
+		return session
+	}
+
+	synced := make([]domain.DocumentSession, 0, len(sessions)+len(editors))
+	for _, session := range sessions {
+		switch {
+		case session.Expired(now):
+		case editors[session.UserID]:
+			delete(editors, session.UserID)
+			synced = append(synced, connect(session))
+		case session.State == domain.SessionStateOpening:
+			synced = append(synced, session)
+		}
+	}
+
+	for _, editor := range activity.Editors {
+		editor = strings.TrimSpace(editor)
+		if !editors[editor] {
+			continue
+		}
+
+		// The document server only reports editors
+		delete(editors, editor)
+		synced = append(synced, connect(domain.DocumentSession{
+			UserID:   editor,
+			Mode:     domain.SessionModeEdit,
+			OpenedAt: now,
+		}))
+	}
+
+	return synced
+}
+
+func (s sessionService) GetSessions(ctx context.Context, filter domain.DocumentFilter) ([]domain.DocumentSession, error) {
+	filter.CompanyID = strings.TrimSpace(filter.CompanyID)
+	if filter.CompanyID == "" {
+		return nil, &InvalidServiceParameterError{
+			Name:   "CID",
+			Reason: "Should not be blank",
+		}
+	}
+
+	if !filter.IsCompany() {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, ErrOperationTimeout
+	}
+
+	s.logger.Debugf("trying to select %s sessions", filter.String())
+	activities, err := s.adapter.SelectActivities(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]domain.DocumentSession, 0)
+	for _, activity := range activities {
+		for _, session := range activity.Sessions {
+			if session.Expired(now) {
+				continue
+			}
+
+			session.CompanyID, session.FileID = activity.CompanyID, activity.FileID
+			session.EntityType, session.EntityID = activity.EntityType, activity.EntityID
+			sessions = append(sessions, session)
+		}
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].OpenedAt.Before(sessions[j].OpenedAt)
+	})
+
+	return sessions, nil
+}
@@ -0,0 +1,124 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionService(t *testing.T) {
+	activityAdapter := adapter.NewMemoryActivityAdapter()
+	activities := NewActivityService(activityAdapter, log.NewEmptyLogger())
+	service := NewSessionService(activityAdapter, log.NewEmptyLogger())
+
+	t.Run("open session with an invalid mode", func(t *testing.T) {
+		_, err := service.OpenSession(context.Background(), domain.DocumentSession{
+			CompanyID: "1", FileID: "1", UserID: "1:1", Mode: "mock",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("open sessions", func(t *testing.T) {
+		for _, uid := range []string{"1:1", "1:2"} {
+			session, err := service.OpenSession(context.Background(), domain.DocumentSession{
+				CompanyID: "1", FileID: "1", EntityType: "deal", EntityID: "1",
+				DocKey: "1-1", UserID: uid, Name: "Mock", Mode: domain.SessionModeEdit,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, domain.SessionStateOpening, session.State)
+		}
+
+		_, err := service.OpenSession(context.Background(), domain.DocumentSession{
+			CompanyID: "1", FileID: "2", EntityType: "person", EntityID: "1",
+			DocKey: "2-1", UserID: "1:1", Name: "Mock", Mode: domain.SessionModeView,
+		})
+		assert.NoError(t, err)
+
+		a, err := activities.GetActivity(context.Background(), "1", "2")
+		assert.NoError(t, err)
+		assert.Zero(t, a.Status)
+		assert.Equal(t, "person", a.EntityType)
+		assert.Len(t, a.Sessions, 1)
+	})
+
+	t.Run("connected editors", func(t *testing.T) {
+		_, err := activities.UpdateActivity(context.Background(), domain.DocumentActivity{
+			CompanyID: "1", FileID: "1", DocKey: "1-1", Status: 1, Editors: []string{"1:1", "1:3"},
+		})
+		assert.NoError(t, err)
+
+		sessions, err := service.GetSessions(context.Background(), domain.DocumentFilter{CompanyID: "1", FileID: "1"})
+		assert.NoError(t, err)
+		assert.Len(t, sessions, 3)
+		for _, session := range sessions {
+			switch session.UserID {
+			case "1:1":
+				assert.Equal(t, domain.SessionStateConnected, session.State)
+				assert.Equal(t, "Mock", session.Name)
+			case "1:2":
+				assert.Equal(t, domain.SessionStateOpening, session.State)
+			case "1:3":
+				assert.Equal(t, domain.SessionStateConnected, session.State)
+				assert.Equal(t, "1", session.EntityID)
+			}
+		}
+	})
+
+	t.Run("reopening keeps the session connected", func(t *testing.T) {
+		session, err := service.OpenSession(context.Background(), domain.DocumentSession{
+			CompanyID: "1", FileID: "1", EntityType: "deal", EntityID: "1",
+			DocKey: "1-1", UserID: "1:1", Name: "Mock", Mode: domain.SessionModeEdit,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SessionStateConnected, session.State)
+	})
+
+	t.Run("closed document removes connected sessions", func(t *testing.T) {
+		_, err := activities.UpdateActivity(context.Background(), domain.DocumentActivity{
+			CompanyID: "1", FileID: "1", DocKey: "1-1", Status: 4,
+		})
+		assert.NoError(t, err)
+
+		sessions, err := service.GetSessions(context.Background(), domain.DocumentFilter{
+			CompanyID: "1", EntityType: "deal", EntityID: "1",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, sessions, 1)
+		assert.Equal(t, "1:2", sessions[0].UserID)
+	})
+
+	t.Run("company sessions", func(t *testing.T) {
+		sessions, err := service.GetSessions(context.Background(), domain.DocumentFilter{CompanyID: "1"})
+		assert.NoError(t, err)
+		assert.Len(t, sessions, 2)
+
+		sessions, err = service.GetSessions(context.Background(), domain.DocumentFilter{CompanyID: "2"})
+		assert.NoError(t, err)
+		assert.Empty(t, sessions)
+
+		_, err = service.GetSessions(context.Background(), domain.DocumentFilter{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,169 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+)
+
+type versionService struct {
+	adapter port.DocumentVersionServiceAdapter
+	logger  plog.Logger
+}
+
+func NewVersionService(
+	adapter port.DocumentVersionServiceAdapter,
+	logger plog.Logger,
+) port.DocumentVersionService {
+	return versionService{
+		adapter: adapter,
+		logger:  logger,
+	}
+}
+
+// maxVersionAttempts bounds the retries of concurrent saves that pick the same version number.
+const maxVersionAttempts = 5
+
+// AddVersion resolves the lineage of the parent file. A parent without a lineage becomes
+// its root so that files created before versioning was introduced are tracked as version 1.
+func (s versionService) AddVersion(ctx context.Context, version domain.DocumentVersion) (domain.DocumentVersion, error) {
+	s.logger.Debugf("validating file %s version to perform an insert action", version.Key())
+	if err := version.Validate(); err != nil {
+		return version, err
+	}
+
+	if version.ParentID == "" {
+		return version, &InvalidServiceParameterError{
+			Name:   "ParentID",
+			Reason: "Should not be blank",
+		}
+	}
+
+	if ctx.Err() != nil {
+		return version, ErrOperationTimeout
+	}
+
+	for attempt := 1; ; attempt++ {
+		saved, err := s.addVersion(ctx, version)
+		if !errors.Is(err, adapter.ErrVersionExists) || attempt >= maxVersionAttempts {
+			return saved, err
+		}
+
+		s.logger.Debugf("file %s version has been taken by a concurrent save, retrying", version.Key())
+	}
+}
+
+func (s versionService) addVersion(ctx context.Context, version domain.DocumentVersion) (domain.DocumentVersion, error) {
+	if existing, err := s.adapter.SelectVersion(ctx, version.CompanyID, version.FileID); err == nil {
+		s.logger.Debugf("file %s is already a part of lineage %s", version.Key(), existing.RootID)
+		return existing, nil
+	}
+
+	parent, err := s.adapter.SelectVersion(ctx, version.CompanyID, version.ParentID)
+	if err != nil {
+		if !errors.Is(err, adapter.ErrNoDocumentVersion) {
+			return version, err
+		}
+
+		parent = domain.DocumentVersion{
+			CompanyID:  version.CompanyID,
+			RootID:     version.ParentID,
+			FileID:     version.ParentID,
+			EntityType: version.EntityType,
+			EntityID:   version.EntityID,
+			Version:    1,
+			Filename:   version.Filename,
+		}
+
+		s.logger.Debugf("file %s:%s is the root of a new lineage", version.CompanyID, version.ParentID)
+		if err := s.adapter.InsertVersion(ctx, parent); err != nil {
+			return version, err
+		}
+	}
+
+	versions, err := s.adapter.SelectVersions(ctx, version.CompanyID, parent.RootID)
+	if err != nil {
+		return version, err
+	}
+
+	latest := parent.Version
+	for _, v := range versions {
+		if v.Version > latest {
+			latest = v.Version
+		}
+	}
+
+	version.RootID = parent.RootID
+	version.Version = latest + 1
+	version.CreatedAt = time.Now()
+	if err := s.adapter.InsertVersion(ctx, version); err != nil {
+		return version, err
+	}
+
+	s.logger.Debugf("file %s has been saved as version %d of lineage %s", version.Key(), version.Version, version.RootID)
+	return version, nil
+}
+
+func (s versionService) GetVersions(ctx context.Context, cid, fid string) ([]domain.DocumentVersion, error) {
+	cid, fid = strings.TrimSpace(cid), strings.TrimSpace(fid)
+	s.logger.Debugf("trying to select file %s:%s versions", cid, fid)
+	if cid == "" {
+		return nil, &InvalidServiceParameterError{
+			Name:   "CID",
+			Reason: "Should not be blank",
+		}
+	}
+
+	if fid == "" {
+		return nil, &InvalidServiceParameterError{
+			Name:   "FID",
+			Reason: "Should not be blank",
+		}
+	}
+
+	version, err := s.adapter.SelectVersion(ctx, cid, fid)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.adapter.SelectVersions(ctx, cid, version.RootID)
+}
+
+func (s versionService) GetVersion(ctx context.Context, cid, fid string, version int) (domain.DocumentVersion, error) {
+	versions, err := s.GetVersions(ctx, cid, fid)
+	if err != nil {
+		return domain.DocumentVersion{}, err
+	}
+
+	for _, v := range versions {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+
+	return domain.DocumentVersion{}, adapter.ErrNoDocumentVersion
+}
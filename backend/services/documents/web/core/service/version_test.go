@@ -0,0 +1,140 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/adapter"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionService(t *testing.T) {
+	service := NewVersionService(adapter.NewMemoryVersionAdapter(), log.NewEmptyLogger())
+
+	t.Run("add version with timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0*time.Second)
+		defer cancel()
+		_, err := service.AddVersion(ctx, domain.DocumentVersion{
+			CompanyID: "1", ParentID: "1", FileID: "2", Filename: "mock.docx",
+		})
+		assert.ErrorIs(t, err, ErrOperationTimeout)
+	})
+
+	t.Run("add version without parent", func(t *testing.T) {
+		_, err := service.AddVersion(context.Background(), domain.DocumentVersion{
+			CompanyID: "1", FileID: "2", Filename: "mock.docx",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("first save starts a lineage", func(t *testing.T) {
+		v, err := service.AddVersion(context.Background(), domain.DocumentVersion{
+			CompanyID: "1", ParentID: "1", FileID: "2", EntityType: "deal", EntityID: "1", Filename: "mock.docx", Author: "1:1",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", v.RootID)
+		assert.Equal(t, 2, v.Version)
+	})
+
+	t.Run("next save extends the lineage", func(t *testing.T) {
+		v, err := service.AddVersion(context.Background(), domain.DocumentVersion{
+			CompanyID: "1", ParentID: "2", FileID: "3", EntityType: "deal", EntityID: "1", Filename: "mock.docx", Author: "1:2",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", v.RootID)
+		assert.Equal(t, 3, v.Version)
+	})
+
+	t.Run("adding the same file twice is idempotent", func(t *testing.T) {
+		v, err := service.AddVersion(context.Background(), domain.DocumentVersion{
+			CompanyID: "1", ParentID: "2", FileID: "3", EntityType: "deal", EntityID: "1", Filename: "mock.docx",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v.Version)
+	})
+
+	t.Run("get versions from any file in the lineage", func(t *testing.T) {
+		versions, err := service.GetVersions(context.Background(), "1", "2")
+		assert.NoError(t, err)
+		assert.Len(t, versions, 3)
+		assert.Equal(t, "1", versions[0].FileID)
+		assert.Equal(t, "3", versions[2].FileID)
+	})
+
+	t.Run("get version", func(t *testing.T) {
+		v, err := service.GetVersion(context.Background(), "1", "3", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "2", v.FileID)
+	})
+
+	t.Run("get missing version", func(t *testing.T) {
+		_, err := service.GetVersion(context.Background(), "1", "3", 5)
+		assert.ErrorIs(t, err, adapter.ErrNoDocumentVersion)
+	})
+
+	t.Run("versions are scoped by company", func(t *testing.T) {
+		_, err := service.GetVersions(context.Background(), "2", "2")
+		assert.Error(t, err)
+	})
+
+	t.Run("concurrent save takes the next version", func(t *testing.T) {
+		racing := &racingVersionAdapter{
+			DocumentVersionServiceAdapter: adapter.NewMemoryVersionAdapter(),
+			competitor: domain.DocumentVersion{
+				CompanyID: "1", RootID: "1", ParentID: "1", FileID: "3", Version: 2, Filename: "mock.docx",
+			},
+		}
+
+		service := NewVersionService(racing, log.NewEmptyLogger())
+		v, err := service.AddVersion(context.Background(), domain.DocumentVersion{
+			CompanyID: "1", ParentID: "1", FileID: "2", Filename: "mock.docx",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v.Version)
+
+		versions, err := service.GetVersions(context.Background(), "1", "1")
+		assert.NoError(t, err)
+		assert.Len(t, versions, 3)
+		assert.Equal(t, "3", versions[1].FileID)
+		assert.Equal(t, "2", versions[2].FileID)
+	})
+}
+
+// racingVersionAdapter inserts a competing version right after the lineage has been read once.
+type racingVersionAdapter struct {
+	port.DocumentVersionServiceAdapter
+	competitor domain.DocumentVersion
+	raced      bool
+}
+
+func (a *racingVersionAdapter) SelectVersions(ctx context.Context, cid, rid string) ([]domain.DocumentVersion, error) {
+	versions, err := a.DocumentVersionServiceAdapter.SelectVersions(ctx, cid, rid)
+	if err == nil && !a.raced {
+		a.raced = true
+		err = a.DocumentVersionServiceAdapter.InsertVersion(ctx, a.competitor)
+	}
+
+	return versions, err
+}
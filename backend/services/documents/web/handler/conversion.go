@@ -0,0 +1,86 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+)
+
+type ConversionHandler struct {
+	service port.DocumentConversionService
+	logger  log.Logger
+}
+
+func NewConversionHandler(
+	service port.DocumentConversionService,
+	logger log.Logger,
+) ConversionHandler {
+	return ConversionHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func toConversionResponse(conversion domain.DocumentConversion) response.DocumentConversionResponse {
+	return response.DocumentConversionResponse{
+		Key:        conversion.Key,
+		FileID:     conversion.FileID,
+		ResultID:   conversion.ResultID,
+		Filename:   conversion.Filename,
+		UpdateTime: conversion.UpdateTime,
+	}
+}
+
+func (c ConversionHandler) InsertConversion(ctx context.Context, req request.DocumentConversion, res *response.DocumentConversionResponse) error {
+	conversion, err := c.service.AddConversion(ctx, domain.DocumentConversion{
+		CompanyID:  fmt.Sprint(req.CompanyID),
+		Key:        req.Key,
+		FileID:     req.FileID,
+		ResultID:   req.ResultID,
+		Filename:   req.Filename,
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		UpdateTime: req.UpdateTime,
+	})
+	if err != nil {
+		c.logger.Errorf("could not add conversion %s: %s", req.Key, err.Error())
+		return err
+	}
+
+	*res = toConversionResponse(conversion)
+	return nil
+}
+
+func (c ConversionHandler) GetConversion(ctx context.Context, req request.DocumentConversionSelect, res *response.DocumentConversionResponse) error {
+	conversion, err := c.service.GetConversion(ctx, fmt.Sprint(req.CompanyID), req.Key)
+	if err != nil {
+		c.logger.Debugf("could not get conversion %d:%s. Reason: %s", req.CompanyID, req.Key, err.Error())
+		return err
+	}
+
+	*res = toConversionResponse(conversion)
+	return nil
+}
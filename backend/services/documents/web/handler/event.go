@@ -0,0 +1,79 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+)
+
+type EventHandler struct {
+	service port.DocumentEventService
+	logger  log.Logger
+}
+
+func NewEventHandler(
+	service port.DocumentEventService,
+	logger log.Logger,
+) EventHandler {
+	return EventHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (e EventHandler) HandleEvent(ctx context.Context, req request.DocumentEvent, res *interface{}) error {
+	cid := fmt.Sprint(req.CompanyID)
+	var err error
+	switch req.Action {
+	case request.DocumentEventFileDeleted:
+		err = e.service.DeleteFile(ctx, cid, req.FileID)
+	case request.DocumentEventEntityDeleted:
+		err = e.service.DeleteEntity(ctx, cid, req.Entity.Type, req.Entity.ID)
+	case request.DocumentEventEntityMerged:
+		err = e.service.MergeEntity(ctx, cid, req.Entity.Type, req.Entity.ID, req.MergedInto)
+	default:
+		err = fmt.Errorf("unsupported document event action %s", req.Action)
+	}
+
+	if err != nil {
+		e.logger.Errorf("could not handle company %s document event %s: %s", cid, req.Action, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// PurgeCompany removes the documents of a company whose last user has uninstalled the app.
+func (e EventHandler) PurgeCompany(ctx context.Context, cid *string, res *interface{}) error {
+	_, err, _ := group.Do(fmt.Sprintf("purge-%s", *cid), func() (interface{}, error) {
+		if err := e.service.PurgeCompany(ctx, *cid); err != nil {
+			e.logger.Errorf("could not purge company %s documents: %s", *cid, err.Error())
+			return nil, err
+		}
+
+		return nil, nil
+	})
+
+	return err
+}
@@ -0,0 +1,123 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+)
+
+type HistoryHandler struct {
+	service port.DocumentHistoryService
+	logger  log.Logger
+}
+
+func NewHistoryHandler(
+	service port.DocumentHistoryService,
+	logger log.Logger,
+) HistoryHandler {
+	return HistoryHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func toHistoryResponse(history domain.DocumentHistory) response.DocumentHistoryResponse {
+	changes := make([]response.HistoryChange, 0, len(history.Changes))
+	for _, change := range history.Changes {
+		changes = append(changes, response.HistoryChange{
+			Created: change.Created,
+			User: response.HistoryUser{
+				ID:   change.UserID,
+				Name: change.UserName,
+			},
+		})
+	}
+
+	return response.DocumentHistoryResponse{
+		FileID:        history.FileID,
+		ServerVersion: history.ServerVersion,
+		Changes:       changes,
+		HasArchive:    len(history.Archive) > 0,
+		CreatedAt:     history.CreatedAt,
+	}
+}
+
+func (h HistoryHandler) InsertHistory(ctx context.Context, req request.DocumentHistory, res *interface{}) error {
+	changes := make([]domain.HistoryChange, 0, len(req.Changes))
+	for _, change := range req.Changes {
+		changes = append(changes, domain.HistoryChange{
+			Created:  change.Created,
+			UserID:   change.User.ID,
+			UserName: change.User.Name,
+		})
+	}
+
+	if _, err := h.service.SaveHistory(ctx, domain.DocumentHistory{
+		CompanyID:     fmt.Sprint(req.CompanyID),
+		FileID:        req.FileID,
+		ServerVersion: req.ServerVersion,
+		Changes:       changes,
+		Archive:       req.Archive,
+	}); err != nil {
+		h.logger.Errorf("could not save file %s history: %s", req.FileID, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetHistories skips files without a stored history. Archives are not included.
+func (h HistoryHandler) GetHistories(ctx context.Context, req *request.DocumentHistorySelect, res *response.DocumentHistoriesResponse) error {
+	cid := fmt.Sprint(req.CompanyID)
+	res.Histories = make([]response.DocumentHistoryResponse, 0, len(req.FileIDs))
+	for _, fid := range req.FileIDs {
+		history, err := h.service.GetHistory(ctx, cid, fid)
+		if err != nil {
+			h.logger.Debugf("could not get file %s:%s history. Reason: %s", cid, fid, err.Error())
+			continue
+		}
+
+		res.Histories = append(res.Histories, toHistoryResponse(history))
+	}
+
+	return nil
+}
+
+func (h HistoryHandler) GetArchive(ctx context.Context, req *request.DocumentHistorySelect, res *response.DocumentHistoryResponse) error {
+	if len(req.FileIDs) != 1 {
+		return fmt.Errorf("expected a single file id, got %d", len(req.FileIDs))
+	}
+
+	history, err := h.service.GetHistory(ctx, fmt.Sprint(req.CompanyID), req.FileIDs[0])
+	if err != nil {
+		h.logger.Debugf("could not get file %d:%s history. Reason: %s", req.CompanyID, req.FileIDs[0], err.Error())
+		return err
+	}
+
+	*res = toHistoryResponse(history)
+	res.Archive = history.Archive
+	return nil
+}
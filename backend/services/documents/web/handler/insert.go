@@ -0,0 +1,63 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+)
+
+type ActivityInsertHandler struct {
+	service port.DocumentActivityService
+	logger  log.Logger
+}
+
+func NewActivityInsertHandler(
+	service port.DocumentActivityService,
+	logger log.Logger,
+) ActivityInsertHandler {
+	return ActivityInsertHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// InsertActivity is not deduplicated since every callback status must be applied in order.
+func (i ActivityInsertHandler) InsertActivity(ctx context.Context, req request.DocumentActivity, res *interface{}) error {
+	if _, err := i.service.UpdateActivity(ctx, domain.DocumentActivity{
+		CompanyID:  fmt.Sprint(req.CompanyID),
+		FileID:     req.FileID,
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		DocKey:     req.DocKey,
+		Status:     req.Status,
+		Editors:    req.Users,
+		LastError:  req.Error,
+	}); err != nil {
+		i.logger.Errorf("could not update file %s activity: %s", req.FileID, err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+)
+
+type KeyHandler struct {
+	service port.DocumentKeyService
+	logger  log.Logger
+}
+
+func NewKeyHandler(
+	service port.DocumentKeyService,
+	logger log.Logger,
+) KeyHandler {
+	return KeyHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func toKeyResponse(key domain.DocumentKey) response.DocumentKeyResponse {
+	return response.DocumentKeyResponse{
+		FileID:   key.FileID,
+		Revision: key.Revision,
+		Key:      key.Key,
+	}
+}
+
+func (k KeyHandler) GetKey(ctx context.Context, req request.DocumentKey, res *response.DocumentKeyResponse) error {
+	cid := fmt.Sprint(req.CompanyID)
+	key, err, _ := group.Do(fmt.Sprintf("key-%s:%s", cid, req.FileID), func() (interface{}, error) {
+		return k.service.GetKey(ctx, cid, req.FileID)
+	})
+
+	if err != nil {
+		k.logger.Errorf("could not get file %s:%s key: %s", cid, req.FileID, err.Error())
+		return err
+	}
+
+	if dk, ok := key.(domain.DocumentKey); ok {
+		*res = toKeyResponse(dk)
+		return nil
+	}
+
+	return fmt.Errorf("could not cast file %s:%s key", cid, req.FileID)
+}
+
+func (k KeyHandler) RotateKey(ctx context.Context, req request.DocumentKey, res *response.DocumentKeyResponse) error {
+	key, err := k.service.RotateKey(ctx, fmt.Sprint(req.CompanyID), req.FileID, req.Key)
+	if err != nil {
+		k.logger.Errorf("could not rotate file %d:%s key: %s", req.CompanyID, req.FileID, err.Error())
+		return err
+	}
+
+	*res = toKeyResponse(key)
+	return nil
+}
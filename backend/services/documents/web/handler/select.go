@@ -0,0 +1,76 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+)
+
+type ActivitySelectHandler struct {
+	service port.DocumentActivityService
+	logger  log.Logger
+}
+
+func NewActivitySelectHandler(
+	service port.DocumentActivityService,
+	logger log.Logger,
+) ActivitySelectHandler {
+	return ActivitySelectHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (u ActivitySelectHandler) GetActivity(ctx context.Context, req *request.DocumentActivitySelect, res *response.DocumentActivityResponse) error {
+	cid := fmt.Sprint(req.CompanyID)
+	activity, err, _ := group.Do(fmt.Sprintf("activity-%s:%s", cid, req.FileID), func() (interface{}, error) {
+		activity, err := u.service.GetActivity(ctx, cid, req.FileID)
+		if err != nil {
+			u.logger.Debugf("could not get file %s:%s activity. Reason: %s", cid, req.FileID, err.Error())
+			return domain.DocumentActivity{FileID: req.FileID, Editors: []string{}}, nil
+		}
+
+		return activity, nil
+	})
+
+	if act, ok := activity.(domain.DocumentActivity); ok {
+		*res = response.DocumentActivityResponse{
+			FileID:      act.FileID,
+			EntityType:  act.EntityType,
+			EntityID:    act.EntityID,
+			DocKey:      act.DocKey,
+			Status:      act.Status,
+			Editors:     act.Editors,
+			LastError:   act.LastError,
+			LastErrorAt: act.LastErrorAt,
+			Deleted:     act.Deleted,
+			UpdatedAt:   act.UpdatedAt,
+		}
+		return nil
+	}
+
+	return err
+}
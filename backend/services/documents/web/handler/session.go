@@ -0,0 +1,108 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+)
+
+type SessionHandler struct {
+	service port.DocumentSessionService
+	logger  log.Logger
+}
+
+func NewSessionHandler(
+	service port.DocumentSessionService,
+	logger log.Logger,
+) SessionHandler {
+	return SessionHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func toSessionResponse(session domain.DocumentSession) response.DocumentSessionResponse {
+	return response.DocumentSessionResponse{
+		FileID:     session.FileID,
+		EntityType: session.EntityType,
+		EntityID:   session.EntityID,
+		UserID:     session.UserID,
+		Name:       session.Name,
+		Mode:       session.Mode,
+		State:      session.State,
+		OpenedAt:   session.OpenedAt,
+		UpdatedAt:  session.UpdatedAt,
+	}
+}
+
+func (s SessionHandler) OpenSession(ctx context.Context, req request.DocumentSession, res *response.DocumentSessionResponse) error {
+	session, err := s.service.OpenSession(ctx, domain.DocumentSession{
+		CompanyID:  fmt.Sprint(req.CompanyID),
+		FileID:     req.FileID,
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		DocKey:     req.DocKey,
+		UserID:     req.UserID,
+		Name:       req.Name,
+		Mode:       req.Mode,
+	})
+	if err != nil {
+		s.logger.Errorf("could not open file %s session: %s", req.FileID, err.Error())
+		return err
+	}
+
+	*res = toSessionResponse(session)
+	return nil
+}
+
+func (s SessionHandler) GetSessions(ctx context.Context, req request.DocumentSessionSelect, res *response.DocumentSessionsResponse) error {
+	filter := domain.DocumentFilter{
+		CompanyID:  fmt.Sprint(req.CompanyID),
+		FileID:     req.FileID,
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+	}
+
+	sessions, err, _ := group.Do(fmt.Sprintf("sessions-%s", filter.String()), func() (interface{}, error) {
+		return s.service.GetSessions(ctx, filter)
+	})
+
+	if err != nil {
+		s.logger.Debugf("could not get %s sessions. Reason: %s", filter.String(), err.Error())
+		return err
+	}
+
+	if ss, ok := sessions.([]domain.DocumentSession); ok {
+		res.Sessions = make([]response.DocumentSessionResponse, 0, len(ss))
+		for _, session := range ss {
+			res.Sessions = append(res.Sessions, toSessionResponse(session))
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("could not cast %s sessions", filter.String())
+}
@@ -0,0 +1,114 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+)
+
+type VersionHandler struct {
+	service port.DocumentVersionService
+	logger  log.Logger
+}
+
+func NewVersionHandler(
+	service port.DocumentVersionService,
+	logger log.Logger,
+) VersionHandler {
+	return VersionHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func toVersionResponse(version domain.DocumentVersion) response.DocumentVersionResponse {
+	return response.DocumentVersionResponse{
+		RootID:     version.RootID,
+		ParentID:   version.ParentID,
+		FileID:     version.FileID,
+		EntityType: version.EntityType,
+		EntityID:   version.EntityID,
+		Version:    version.Version,
+		Filename:   version.Filename,
+		Author:     version.Author,
+		Deleted:    version.Deleted,
+		CreatedAt:  version.CreatedAt,
+	}
+}
+
+func (v VersionHandler) InsertVersion(ctx context.Context, req request.DocumentVersion, res *response.DocumentVersionResponse) error {
+	version, err := v.service.AddVersion(ctx, domain.DocumentVersion{
+		CompanyID:  fmt.Sprint(req.CompanyID),
+		ParentID:   req.ParentID,
+		FileID:     req.FileID,
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		Filename:   req.Filename,
+		Author:     req.Author,
+	})
+	if err != nil {
+		v.logger.Errorf("could not add file %s version: %s", req.FileID, err.Error())
+		return err
+	}
+
+	*res = toVersionResponse(version)
+	return nil
+}
+
+func (v VersionHandler) GetVersions(ctx context.Context, req *request.DocumentVersionSelect, res *response.DocumentVersionsResponse) error {
+	cid := fmt.Sprint(req.CompanyID)
+	versions, err, _ := group.Do(fmt.Sprintf("versions-%s:%s", cid, req.FileID), func() (interface{}, error) {
+		versions, err := v.service.GetVersions(ctx, cid, req.FileID)
+		if err != nil {
+			v.logger.Debugf("could not get file %s:%s versions. Reason: %s", cid, req.FileID, err.Error())
+			return []domain.DocumentVersion{}, nil
+		}
+
+		return versions, nil
+	})
+
+	if vs, ok := versions.([]domain.DocumentVersion); ok {
+		res.Versions = make([]response.DocumentVersionResponse, 0, len(vs))
+		for _, version := range vs {
+			res.Versions = append(res.Versions, toVersionResponse(version))
+		}
+
+		return nil
+	}
+
+	return err
+}
+
+func (v VersionHandler) GetVersion(ctx context.Context, req *request.DocumentVersionSelect, res *response.DocumentVersionResponse) error {
+	version, err := v.service.GetVersion(ctx, fmt.Sprint(req.CompanyID), req.FileID, req.Version)
+	if err != nil {
+		v.logger.Debugf("could not get file %d:%s version %d. Reason: %s", req.CompanyID, req.FileID, req.Version, err.Error())
+		return err
+	}
+
+	*res = toVersionResponse(version)
+	return nil
+}
@@ -0,0 +1,69 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package web
+
+import (
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/service/rpc"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/documents/web/handler"
+)
+
+type DocumentsRPCServer struct {
+	activitySelectHandler handler.ActivitySelectHandler
+	activityInsertHandler handler.ActivityInsertHandler
+	versionHandler        handler.VersionHandler
+	historyHandler        handler.HistoryHandler
+	eventHandler          handler.EventHandler
+	keyHandler            handler.KeyHandler
+	sessionHandler        handler.SessionHandler
+	conversionHandler     handler.ConversionHandler
+}
+
+func NewDocumentsRPCServer(
+	activitySelectHandler handler.ActivitySelectHandler,
+	activityInsertHandler handler.ActivityInsertHandler,
+	versionHandler handler.VersionHandler,
+	historyHandler handler.HistoryHandler,
+	eventHandler handler.EventHandler,
+	keyHandler handler.KeyHandler,
+	sessionHandler handler.SessionHandler,
+	conversionHandler handler.ConversionHandler,
+) rpc.RPCEngine {
+	return DocumentsRPCServer{
+		activitySelectHandler: activitySelectHandler,
+		activityInsertHandler: activityInsertHandler,
+		versionHandler:        versionHandler,
+		historyHandler:        historyHandler,
+		eventHandler:          eventHandler,
+		keyHandler:            keyHandler,
+		sessionHandler:        sessionHandler,
+		conversionHandler:     conversionHandler,
+	}
+}
+
+func (a DocumentsRPCServer) BuildMessageHandlers() []rpc.RPCMessageHandler {
+	return nil
+}
+
+func (a DocumentsRPCServer) BuildHandlers() []interface{} {
+	return []interface{}{
+		a.activitySelectHandler, a.activityInsertHandler, a.versionHandler,
+		a.historyHandler, a.eventHandler, a.keyHandler, a.sessionHandler,
+		a.conversionHandler,
+	}
+}
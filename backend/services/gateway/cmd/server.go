@@ -51,13 +51,23 @@ func Server() *cli.Command {
 				controller.NewApiController,
 				controller.NewAuthController,
 				controller.NewFileController,
+				controller.NewVersionController,
+				controller.NewConvertController,
+				controller.NewGenerateController,
+				controller.NewWebhookController,
+				controller.NewCommandController,
+				controller.NewSessionController,
+				controller.NewTemplateController,
 				middleware.BuildHandleAuthMiddleware,
 				middleware.BuildHandleContextMiddleware,
+				middleware.BuildHandleWebhookMiddleware,
+				client.NewCallbackClient,
 				client.NewCommandClient,
 				client.NewPipedriveApiClient,
 				client.NewPipedriveAuthClient,
 				shared.BuildNewIntegrationCredentialsConfig(CONFIG_PATH),
 				shared.BuildNewOnlyofficeConfig(CONFIG_PATH),
+				shared.BuildNewWebhookConfig(CONFIG_PATH),
 			)).Bootstrap()
 
 			if err := app.Err(); err != nil {
@@ -46,6 +46,7 @@ type ApiController struct {
 	client        client.Client
 	apiClient     pclient.PipedriveApiClient
 	commandClient pclient.CommandClient
+	webhooks      WebhookController
 	jwtManager    crypto.JwtManager
 	config        *config.ServerConfig
 	logger        log.Logger
@@ -55,6 +56,7 @@ func NewApiController(
 	client client.Client,
 	apiClient pclient.PipedriveApiClient,
 	commandClient pclient.CommandClient,
+	webhooks WebhookController,
 	jwtManager crypto.JwtManager,
 	serverConfig *config.ServerConfig,
 	logger log.Logger,
@@ -63,13 +65,14 @@ func NewApiController(
 		client:        client,
 		apiClient:     apiClient,
 		commandClient: commandClient,
+		webhooks:      webhooks,
 		jwtManager:    jwtManager,
 		config:        serverConfig,
 		logger:        logger,
 	}
 }
 
-func (c *ApiController) getUser(ctx context.Context, id string) (response.UserResponse, int, error) {
+func (c *ApiController) getUser(ctx context.Context, id request.UserIdentity) (response.UserResponse, int, error) {
 	var ures response.UserResponse
 	if err := c.client.Call(ctx, c.client.NewRequest(fmt.Sprintf("%s:auth", c.config.Namespace), "UserSelectHandler.GetUser", id), &ures); err != nil {
 		c.logger.Errorf("could not get user access info: %s", err.Error())
@@ -101,14 +104,14 @@ func (c ApiController) BuildGetMe() http.HandlerFunc {
 		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 		defer cancel()
 
-		ures, status, _ := c.getUser(ctx, fmt.Sprint(pctx.UID+pctx.CID))
+		ures, status, _ := c.getUser(ctx, pctx.Identity())
 		if status != http.StatusOK {
 			rw.WriteHeader(status)
 			return
 		}
 
 		rw.Write(response.UserTokenResponse{
-			ID:          ures.ID,
+			ID:          ures.ID.String(),
 			AccessToken: ures.AccessToken,
 			ExpiresAt:   ures.ExpiresAt,
 		}.ToJSON())
@@ -158,7 +161,7 @@ func (c ApiController) BuildPostSettings() http.HandlerFunc {
 				case <-ectx.Done():
 					return ectx.Err()
 				default:
-					if err := c.commandClient.License(ectx, settings.DocAddress, settings.DocSecret); err != nil {
+					if _, err := c.commandClient.Version(ectx, settings.DocAddress, settings.DocSecret, settings.DocHeader); err != nil {
 						c.logger.Errorf("could not validate ONLYOFFICE document server credentials: %s", err.Error())
 						return err
 					}
@@ -174,7 +177,7 @@ func (c ApiController) BuildPostSettings() http.HandlerFunc {
 			case <-ectx.Done():
 				return ectx.Err()
 			default:
-				ures, _, err := c.getUser(ectx, fmt.Sprint(pctx.UID+pctx.CID))
+				ures, _, err := c.getUser(ectx, pctx.Identity())
 				if err != nil {
 					return err
 				}
@@ -192,7 +195,7 @@ func (c ApiController) BuildPostSettings() http.HandlerFunc {
 				}
 
 				for _, access := range urs.Access {
-					if access.App == "global" && !access.Admin {
+					if access.App == model.AppGlobal && !access.Admin {
 						return ErrNotAdmin
 					}
 				}
@@ -218,11 +221,16 @@ func (c ApiController) BuildPostSettings() http.HandlerFunc {
 		}
 
 		sreq := request.DocSettings{
-			CompanyID:   int(atomic.LoadInt64(&companyID)),
-			DocAddress:  settings.DocAddress,
-			DocHeader:   settings.DocHeader,
-			DocSecret:   settings.DocSecret,
-			DemoEnabled: settings.DemoEnabled,
+			CompanyID:         int(atomic.LoadInt64(&companyID)),
+			DocAddress:        settings.DocAddress,
+			DocHeader:         settings.DocHeader,
+			DocSecret:         settings.DocSecret,
+			DemoEnabled:       settings.DemoEnabled,
+			AutoConvert:       settings.AutoConvert,
+			ReplaceBlankForms: settings.ReplaceBlankForms,
+			LossyEdit:         settings.LossyEdit,
+			Permissions:       settings.Permissions,
+			RolePermissions:   settings.RolePermissions,
 		}
 
 		tctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -270,27 +278,33 @@ func (c ApiController) BuildGetSettings() http.HandlerFunc {
 		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 		defer cancel()
 
-		ures, status, _ := c.getUser(ctx, fmt.Sprint(pctx.UID+pctx.CID))
+		ures, status, _ := c.getUser(ctx, pctx.Identity())
 		if status != http.StatusOK {
 			rw.WriteHeader(status)
 			return
 		}
 
-		urs, _ := c.apiClient.GetMe(ctx, model.Token{
+		token := model.Token{
 			AccessToken:  ures.AccessToken,
 			RefreshToken: ures.RefreshToken,
 			TokenType:    ures.TokenType,
 			Scope:        ures.Scope,
 			ApiDomain:    ures.ApiDomain,
-		})
+		}
+		urs, _ := c.apiClient.GetMe(ctx, token)
 
 		for _, access := range urs.Access {
-			if access.App == "global" && !access.Admin {
+			if access.App == model.AppGlobal && !access.Admin {
 				rw.WriteHeader(http.StatusForbidden)
 				return
 			}
 		}
 
+		// Companies installed before webhooks were introduced get them once an admin opens the settings
+		if urs.IsAdmin() {
+			c.webhooks.EnsureWebhooks(pctx.CID, token)
+		}
+
 		var docs response.DocSettingsResponse
 		if err := c.client.Call(
 			ctx,
@@ -350,9 +364,7 @@ func (c ApiController) BuildCheckSettings() http.HandlerFunc {
 		}
 
 		hasCredentials := docs.DocAddress != "" && docs.DocSecret != "" && docs.DocHeader != ""
-		hasDemoMode := docs.DemoEnabled && (docs.DemoStarted.IsZero() || docs.DemoStarted.After(time.Now().AddDate(0, 0, -30)))
-
-		rw.Write(response.SettingsConfiguredResponse{Configured: hasCredentials || hasDemoMode}.ToJSON())
+		rw.Write(response.SettingsConfiguredResponse{Configured: hasCredentials || docs.IsDemoModeValid()}.ToJSON())
 	}
 }
 
@@ -361,8 +373,9 @@ func (c ApiController) BuildGetConfig() http.HandlerFunc {
 		rw.Header().Set("Content-Type", "application/json")
 
 		query := r.URL.Query()
-		id, filename, key, dealID, dark := strings.TrimSpace(query.Get("id")), strings.TrimSpace(query.Get("name")),
-			strings.TrimSpace(query.Get("key")), strings.TrimSpace(query.Get("deal_id")), query.Get("dark") == "true"
+		id, filename := strings.TrimSpace(query.Get("id")), strings.TrimSpace(query.Get("name"))
+		dark, convert, lossy := query.Get("dark") == "true", query.Get("convert") == "true", query.Get("lossy") == "true"
+		entity := getParentEntity(query, "deal_id")
 
 		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
 		if !ok {
@@ -383,13 +396,21 @@ func (c ApiController) BuildGetConfig() http.HandlerFunc {
 			return
 		}
 
-		if key == "" {
+		// Document keys are registered by the documents service, so the file id is what identifies a document
+		if id == "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Error("could not extract file id from URL Query")
+			return
+		}
+
+		if err := entity.Validate(); err != nil {
 			rw.WriteHeader(http.StatusBadRequest)
-			c.logger.Error("could not extract doc key from URL Query")
+			c.logger.Errorf("invalid parent entity %s: %s", entity.String(), err.Error())
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(r.Context(), 6*time.Second)
+		// Legacy formats may be converted before the config is built
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 		defer cancel()
 
 		var resp response.BuildConfigResponse
@@ -401,17 +422,63 @@ func (c ApiController) BuildGetConfig() http.HandlerFunc {
 				request.BuildConfigRequest{
 					UID:       pctx.UID,
 					CID:       pctx.CID,
-					Deal:      dealID,
+					Entity:    entity,
 					UserAgent: r.UserAgent(),
 					Filename:  filename,
 					FileID:    id,
-					DocKey:    key,
 					Dark:      dark,
+					Convert:   convert,
+					LossyEdit: lossy,
 				},
 			),
 			&resp,
+			client.WithRequestTimeout(30*time.Second),
 		); err != nil {
 			c.logger.Errorf("could not build onlyoffice config: %s", err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+		rw.Write(resp.ToJSON())
+	}
+}
+
+func (c ApiController) BuildGetEditors() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+
+		id := strings.TrimSpace(r.URL.Query().Get("id"))
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		if id == "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Error("could not extract file id from URL Query")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+		defer cancel()
+
+		var resp response.DocumentActivityResponse
+		if err := c.client.Call(
+			ctx,
+			c.client.NewRequest(
+				fmt.Sprintf("%s:documents", c.config.Namespace),
+				"ActivitySelectHandler.GetActivity",
+				request.DocumentActivitySelect{
+					CompanyID: pctx.CID,
+					FileID:    id,
+				},
+			),
+			&resp,
+		); err != nil {
+			c.logger.Errorf("could not get file activity: %s", err.Error())
 			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 				rw.WriteHeader(http.StatusRequestTimeout)
 				return
@@ -423,12 +490,6 @@ func (c ApiController) BuildGetConfig() http.HandlerFunc {
 				return
 			}
 
-			if strings.Contains(err.Error(), "could not find document server settings") {
-				rw.WriteHeader(http.StatusPreconditionFailed)
-				return
-			}
-
-			c.logger.Errorf("build config micro error: %s", microErr.Detail)
 			rw.WriteHeader(microErr.Code)
 			return
 		}
@@ -31,6 +31,7 @@ import (
 
 	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
 	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
 	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
@@ -42,7 +43,10 @@ type AuthController struct {
 	client        client.Client
 	pipedriveAuth pclient.PipedriveAuthClient
 	pipedriveAPI  pclient.PipedriveApiClient
+	callback      pclient.CallbackClient
+	webhooks      WebhookController
 	config        *config.ServerConfig
+	onlyoffice    *shared.OnlyofficeConfig
 	credentials   *oauth2.Config
 	logger        log.Logger
 }
@@ -51,7 +55,10 @@ func NewAuthController(
 	client client.Client,
 	pipedriveAuth pclient.PipedriveAuthClient,
 	pipedriveAPI pclient.PipedriveApiClient,
+	callback pclient.CallbackClient,
+	webhooks WebhookController,
 	config *config.ServerConfig,
+	onlyoffice *shared.OnlyofficeConfig,
 	credentials *oauth2.Config,
 	logger log.Logger,
 ) AuthController {
@@ -59,7 +66,10 @@ func NewAuthController(
 		client:        client,
 		pipedriveAuth: pipedriveAuth,
 		pipedriveAPI:  pipedriveAPI,
+		callback:      callback,
+		webhooks:      webhooks,
 		config:        config,
+		onlyoffice:    onlyoffice,
 		credentials:   credentials,
 		logger:        logger,
 	}
@@ -114,7 +124,7 @@ func (c AuthController) BuildGetAuth() http.HandlerFunc {
 				fmt.Sprintf("%s:auth", c.config.Namespace),
 				"UserInsertHandler.InsertUser",
 				response.UserResponse{
-					ID:           fmt.Sprint(usr.ID + usr.CompanyID),
+					ID:           request.NewUserIdentity(usr.CompanyID, usr.ID),
 					AccessToken:  token.AccessToken,
 					RefreshToken: token.RefreshToken,
 					TokenType:    token.TokenType,
@@ -141,6 +151,10 @@ func (c AuthController) BuildGetAuth() http.HandlerFunc {
 			return
 		}
 
+		if usr.IsAdmin() {
+			c.webhooks.UpdateWebhooks(usr.CompanyID, token)
+		}
+
 		c.logger.Debugf("redirecting to api domain: %s", token.ApiDomain)
 		http.Redirect(rw, r, token.ApiDomain, http.StatusMovedPermanently)
 	}
@@ -163,17 +177,17 @@ func (c AuthController) BuildDeleteAuth() http.HandlerFunc {
 			return
 		}
 
-		var res interface{}
+		var res response.UninstallResponse
 		if err := c.client.Call(
 			r.Context(),
 			c.client.NewRequest(
 				fmt.Sprintf("%s:auth", c.config.Namespace),
-				"UserDeleteHandler.DeleteUser",
-				fmt.Sprint(ureq.UserID+ureq.CompanyID),
+				"UserDeleteHandler.UninstallUser",
+				ureq,
 			),
 			&res,
 		); err != nil {
-			c.logger.Errorf("could not delete user: %s", err.Error())
+			c.logger.Errorf("could not uninstall user: %s", err.Error())
 			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 				rw.WriteHeader(http.StatusRequestTimeout)
 				return
@@ -189,7 +203,35 @@ func (c AuthController) BuildDeleteAuth() http.HandlerFunc {
 			return
 		}
 
-		c.logger.Debugf("successfully published delete-auth message for user %s", fmt.Sprint(ureq.UserID+ureq.CompanyID))
+		if res.Scope == response.UninstallScopeCompany {
+			if err := c.purgeCompany(r.Context(), ureq.CompanyID); err != nil {
+				c.logger.Errorf("could not purge company %d: %s", ureq.CompanyID, err.Error())
+				rw.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		c.logger.Debugf("successfully uninstalled user %s (scope: %s, removed users: %d)", ureq.Identity().String(), res.Scope, res.RemovedUsers)
 		rw.WriteHeader(http.StatusOK)
 	}
 }
+
+// purgeCompany removes the documents and pending callback jobs of a company whose last user has
+// uninstalled the app. A failure fails the uninstall request, so that Pipedrive retries it and
+// the purge with it, which the auth service allows by reporting the company scope again.
+func (c AuthController) purgeCompany(ctx context.Context, cid int) error {
+	id := fmt.Sprint(cid)
+	var res interface{}
+	if err := c.client.Call(ctx, c.client.NewRequest(
+		fmt.Sprintf("%s:documents", c.config.Namespace), "EventHandler.PurgeCompany", id,
+	), &res); err != nil {
+		return err
+	}
+
+	if c.onlyoffice.Onlyoffice.Builder.CallbackURL == "" {
+		c.logger.Warnf("callback url is not configured, company %d callback jobs are not purged", cid)
+		return nil
+	}
+
+	return c.callback.PurgeCompany(ctx, c.onlyoffice.Onlyoffice.Builder.CallbackURL, c.credentials.ClientSecret, cid)
+}
@@ -0,0 +1,129 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+// callbackServer records the companies the callback service has been asked to purge.
+type callbackServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	purged []int
+}
+
+func newCallbackServer() *callbackServer {
+	server := &callbackServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /purge", func(rw http.ResponseWriter, r *http.Request) {
+		var tctx request.PurgeTokenContext
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if err := testJwtManager.Verify(testSecret, token, &tctx); err != nil || tctx.Validate() != nil {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		server.mu.Lock()
+		server.purged = append(server.purged, tctx.CID)
+		server.mu.Unlock()
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	server.Server = httptest.NewServer(mux)
+	return server
+}
+
+func newUninstallRequest(cid, uid int) *http.Request {
+	body := request.UninstallRequest{ClientID: "client", CompanyID: cid, UserID: uid, Timestamp: "2026-01-02T03:04:05Z"}.ToJSON()
+	r := httptest.NewRequest(http.MethodDelete, "/oauth/uninstall", bytes.NewReader(body))
+	r.Header.Set("Content-Length", fmt.Sprint(len(body)))
+	return r
+}
+
+func TestUninstall(t *testing.T) {
+	callback := newCallbackServer()
+	defer callback.Close()
+
+	onlyoffice := newOnlyofficeConfig("")
+	onlyoffice.Onlyoffice.Builder.CallbackURL = callback.URL
+
+	newController := func(scope string, purgeErr error) (AuthController, *[]string) {
+		var purged []string
+		rpc := rpctest.NewClient()
+		rpc.Handlers["UserDeleteHandler.UninstallUser"] = func(req interface{}, rsp interface{}) error {
+			*rsp.(*response.UninstallResponse) = response.UninstallResponse{Scope: scope, RemovedUsers: 1}
+			return nil
+		}
+		rpc.Handlers["EventHandler.PurgeCompany"] = func(req interface{}, rsp interface{}) error {
+			purged = append(purged, req.(string))
+			return purgeErr
+		}
+
+		return NewAuthController(
+			rpc, pclient.PipedriveAuthClient{}, pclient.PipedriveApiClient{},
+			pclient.NewCallbackClient(testJwtManager), WebhookController{},
+			testConfig, onlyoffice, testCredentials, log.NewEmptyLogger(),
+		), &purged
+	}
+
+	t.Run("keep company data while other users remain", func(t *testing.T) {
+		controller, purged := newController(response.UninstallScopeUser, nil)
+		rw := httptest.NewRecorder()
+		controller.BuildDeleteAuth()(rw, newUninstallRequest(1, 2))
+
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Empty(t, *purged)
+		assert.Empty(t, callback.purged)
+	})
+
+	t.Run("purge documents and callback jobs after the last user", func(t *testing.T) {
+		controller, purged := newController(response.UninstallScopeCompany, nil)
+		rw := httptest.NewRecorder()
+		controller.BuildDeleteAuth()(rw, newUninstallRequest(1, 2))
+
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, []string{"1"}, *purged)
+		assert.Equal(t, []int{1}, callback.purged)
+	})
+
+	t.Run("fail the uninstall so that a failed purge is retried", func(t *testing.T) {
+		controller, purged := newController(response.UninstallScopeCompany, errors.New("documents are unavailable"))
+		rw := httptest.NewRecorder()
+		controller.BuildDeleteAuth()(rw, newUninstallRequest(3, 4))
+
+		assert.Equal(t, http.StatusInternalServerError, rw.Code)
+		assert.Equal(t, []string{"3"}, *purged)
+		assert.NotContains(t, callback.purged, 3)
+	})
+}
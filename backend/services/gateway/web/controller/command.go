@@ -0,0 +1,186 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"go-micro.dev/v4/client"
+)
+
+// maxCommandSize is enough for a drop command with a few hundred users.
+const maxCommandSize = 64 * 1024
+
+type CommandController struct {
+	client    client.Client
+	apiClient pclient.PipedriveApiClient
+	config    *config.ServerConfig
+	logger    log.Logger
+}
+
+func NewCommandController(
+	client client.Client,
+	apiClient pclient.PipedriveApiClient,
+	config *config.ServerConfig,
+	logger log.Logger,
+) CommandController {
+	return CommandController{
+		client:    client,
+		apiClient: apiClient,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+// getCommandStatus maps command service errors. A forcesave without changes is not a failure,
+// the error code in the body is enough to tell nothing has been saved.
+func (c CommandController) getCommandStatus(code int) int {
+	switch code {
+	case 0, pclient.CommandErrorNoChanges:
+		return http.StatusOK
+	case pclient.CommandErrorNoDocument:
+		return http.StatusNotFound
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// buildCommand runs a command for company admins. Document commands address either a document
+// key or a file id with the key query parameter taking precedence, other command arguments
+// (users, title and userdata) are read from an optional json body.
+func (c CommandController) buildCommand(command string) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		var creq request.DocumentCommandRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxCommandSize)).Decode(&creq); err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				c.logger.Errorf("could not unmarshal %s command body: %s", command, err.Error())
+				return
+			}
+		}
+
+		query := r.URL.Query()
+		creq.CID, creq.UID, creq.Command = pctx.CID, pctx.UID, command
+		if key := strings.TrimSpace(query.Get("key")); key != "" {
+			creq.Key = key
+		}
+
+		if id := strings.TrimSpace(query.Get("id")); id != "" {
+			creq.FileID = id
+		}
+
+		if err := creq.Validate(); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Errorf("invalid %s command: %s", command, err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		admin, err := isAdmin(ctx, c.client, c.apiClient, c.config, pctx)
+		if err != nil {
+			c.logger.Errorf("could not check user %s permissions: %s", pctx.Identity().String(), err.Error())
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if !admin {
+			c.logger.Errorf("user %s is not allowed to run %s commands", pctx.Identity().String(), command)
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		var resp response.DocumentCommandResponse
+		if err := c.client.Call(ctx, c.client.NewRequest(
+			fmt.Sprintf("%s:builder", c.config.Namespace),
+			"CommandHandler.Command",
+			creq,
+		), &resp); err != nil {
+			c.logger.Errorf("could not run %s command: %s", command, err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		c.logger.Debugf("user %s has run %s command with result %d", pctx.Identity().String(), command, resp.Error)
+		rw.WriteHeader(c.getCommandStatus(resp.Error))
+		rw.Write(resp.ToJSON())
+	}
+}
+
+func (c CommandController) BuildPostForcesave() http.HandlerFunc {
+	return c.buildCommand(request.CommandForcesave)
+}
+
+func (c CommandController) BuildPostDrop() http.HandlerFunc {
+	return c.buildCommand(request.CommandDrop)
+}
+
+func (c CommandController) BuildPostInfo() http.HandlerFunc {
+	return c.buildCommand(request.CommandInfo)
+}
+
+func (c CommandController) BuildPostMeta() http.HandlerFunc {
+	return c.buildCommand(request.CommandMeta)
+}
+
+func (c CommandController) BuildGetVersion() http.HandlerFunc {
+	return c.buildCommand(request.CommandVersion)
+}
+
+func (c CommandController) BuildGetLicense() http.HandlerFunc {
+	return c.buildCommand(request.CommandLicense)
+}
+
+// BuildGetForgotten returns a forgotten document when a key or file id is given
+// and the keys of all forgotten documents otherwise.
+func (c CommandController) BuildGetForgotten() http.HandlerFunc {
+	get, list := c.buildCommand(request.CommandGetForgotten), c.buildCommand(request.CommandGetForgottenList)
+	return func(rw http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if strings.TrimSpace(query.Get("key")) == "" && strings.TrimSpace(query.Get("id")) == "" {
+			list(rw, r)
+			return
+		}
+
+		get(rw, r)
+	}
+}
+
+func (c CommandController) BuildDeleteForgotten() http.HandlerFunc {
+	return c.buildCommand(request.CommandDeleteForgotten)
+}
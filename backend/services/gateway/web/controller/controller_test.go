@@ -0,0 +1,133 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"golang.org/x/oauth2"
+)
+
+const testSecret = "secret"
+
+var (
+	testConfig      = &config.ServerConfig{Namespace: "test"}
+	testJwtManager  = crypto.NewJwtManager(&config.CryptoConfig{})
+	testCredentials = &oauth2.Config{ClientID: "client", ClientSecret: testSecret}
+)
+
+func newOnlyofficeConfig(gatewayURL string) *shared.OnlyofficeConfig {
+	var config shared.OnlyofficeConfig
+	config.Onlyoffice.Builder.GatewayURL = gatewayURL
+	return &config
+}
+
+// withPipedriveContext attaches the context the context middleware extracts from Pipedrive tokens.
+func withPipedriveContext(r *http.Request, cid, uid int) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), "X-Pipedrive-App-Context", request.PipedriveTokenContext{
+		CID: cid,
+		UID: uid,
+	}))
+}
+
+// newPipedriveServer serves Pipedrive file downloads for the files listed, redirecting to storage.
+// Uploaded files are added to the list under the next numeric id and deleted ones are removed.
+// Records are visible to the user unless their id starts with "private".
+func newPipedriveServer(files map[string]string) *httptest.Server {
+	var mu sync.Mutex
+	file := func(id string) (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		content, ok := files[id]
+		return content, ok
+	}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/api/v1/files/{id}/download", func(rw http.ResponseWriter, r *http.Request) {
+		if _, ok := file(r.PathValue("id")); !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(rw, r, fmt.Sprintf("%s/storage/%s", server.URL, url.PathEscape(r.PathValue("id"))), http.StatusFound)
+	})
+	mux.HandleFunc("/storage/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		content, _ := file(r.PathValue("id"))
+		http.ServeContent(rw, r, "file.docx", time.Time{}, strings.NewReader(content))
+	})
+	mux.HandleFunc("GET /api/v1/{resource}/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.PathValue("id"), "private") {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(rw, `{"success":true,"data":{"id":%q}}`, r.PathValue("id"))
+	})
+	mux.HandleFunc("DELETE /api/v1/files/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		delete(files, r.PathValue("id"))
+		mu.Unlock()
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write([]byte(`{"success":true}`))
+	})
+	mux.HandleFunc("POST /api/v1/files", func(rw http.ResponseWriter, r *http.Request) {
+		upload, header, err := r.FormFile("file")
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer upload.Close()
+
+		content, err := io.ReadAll(upload)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		id := len(files) + 100
+		files[fmt.Sprint(id)] = string(content)
+		mu.Unlock()
+
+		var res response.AddFileResponse
+		res.Success = true
+		res.Data.ID = id
+		res.Data.Filename = header.Filename
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(res)
+	})
+
+	return server
+}
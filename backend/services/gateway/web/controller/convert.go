@@ -0,0 +1,130 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"go-micro.dev/v4/client"
+)
+
+type ConvertController struct {
+	client client.Client
+	config *config.ServerConfig
+	logger log.Logger
+}
+
+func NewConvertController(
+	client client.Client,
+	config *config.ServerConfig,
+	logger log.Logger,
+) ConvertController {
+	return ConvertController{
+		client: client,
+		config: config,
+		logger: logger,
+	}
+}
+
+// BuildPostConvert converts a file and attaches the result to the same entity. Large files are
+// converted asynchronously: the endpoint answers with 202 and the progress until the same request
+// is repeated after the conversion has finished.
+func (c ConvertController) BuildPostConvert() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+
+		query := r.URL.Query()
+		id, filename, outputType := strings.TrimSpace(query.Get("id")), strings.TrimSpace(query.Get("name")),
+			strings.ToLower(strings.TrimSpace(query.Get("type")))
+		entity := getParentEntity(query, "deal_id")
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		if id == "" || filename == "" || outputType == "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Error("could not extract file id, name and output type from URL Query")
+			return
+		}
+
+		if err := entity.Validate(); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Errorf("invalid parent entity %s: %s", entity.String(), err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		var resp response.ConvertFileResponse
+		if err := c.client.Call(
+			ctx,
+			c.client.NewRequest(
+				fmt.Sprintf("%s:builder", c.config.Namespace),
+				"ConvertHandler.Convert",
+				request.ConvertFileRequest{
+					UID:        pctx.UID,
+					CID:        pctx.CID,
+					FileID:     id,
+					Filename:   filename,
+					Entity:     entity,
+					OutputType: outputType,
+				},
+			),
+			&resp,
+			client.WithRequestTimeout(30*time.Second),
+		); err != nil {
+			c.logger.Errorf("could not convert file %s: %s", id, err.Error())
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				rw.WriteHeader(http.StatusRequestTimeout)
+				return
+			}
+
+			microErr := response.MicroError{}
+			if err := json.Unmarshal([]byte(err.Error()), &microErr); err != nil || microErr.Code == 0 {
+				rw.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			rw.WriteHeader(microErr.Code)
+			return
+		}
+
+		if !resp.EndConvert {
+			rw.WriteHeader(http.StatusAccepted)
+		} else {
+			rw.WriteHeader(http.StatusCreated)
+		}
+
+		rw.Write(resp.ToJSON())
+	}
+}
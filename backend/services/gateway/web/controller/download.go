@@ -0,0 +1,86 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+)
+
+// downloadSessionTimeout is how long a download session lasts since it has been started.
+const downloadSessionTimeout = 5 * time.Minute
+
+// downloadSession remembers the Pipedrive url a download link resolved to. Range requests following
+// the first one reuse it, so they do not look the file up again. Links are still verified on every
+// request, so a replica that has not seen a link yet only resolves it once more.
+type downloadSession struct {
+	tctx      request.DownloadTokenContext
+	url       string
+	expiresAt time.Time
+}
+
+type downloadSessions struct {
+	mu       sync.Mutex
+	sessions map[string]downloadSession
+}
+
+func newDownloadSessions() *downloadSessions {
+	return &downloadSessions{sessions: make(map[string]downloadSession)}
+}
+
+// get returns the live session of a download link.
+func (s *downloadSessions) get(token string) (downloadSession, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[token]
+	if !ok || !time.Now().Before(session.expiresAt) {
+		delete(s.sessions, token)
+		return session, false
+	}
+
+	return session, true
+}
+
+// put starts a download session or points it to a new url, dropping expired sessions.
+// A session never outlives the expiration it has been started with.
+func (s *downloadSessions) put(token string, tctx request.DownloadTokenContext, url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, session := range s.sessions {
+		if !now.Before(session.expiresAt) {
+			delete(s.sessions, key)
+		}
+	}
+
+	expiresAt := now.Add(downloadSessionTimeout)
+	if session, ok := s.sessions[token]; ok {
+		expiresAt = session.expiresAt
+	}
+
+	s.sessions[token] = downloadSession{
+		tctx:      tctx,
+		url:       url,
+		expiresAt: expiresAt,
+	}
+}
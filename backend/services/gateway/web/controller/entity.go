@@ -0,0 +1,36 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+)
+
+// getParentEntity reads the entity a file belongs to. Links built before files could be
+// attached to other entities only carry a deal id under the legacy parameter.
+func getParentEntity(query url.Values, legacy string) request.ParentEntity {
+	if id := strings.TrimSpace(query.Get("entity_id")); id != "" {
+		return request.NewParentEntity(query.Get("entity_type"), id)
+	}
+
+	return request.NewParentEntity(request.EntityDeal, query.Get(legacy))
+}
@@ -19,11 +19,15 @@
 package controller
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -37,15 +41,37 @@ import (
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
 	"go-micro.dev/v4/client"
+	"golang.org/x/oauth2"
+)
+
+// newFileTypes lists the blank files shipped with the document templates. Pdf files are blank fillable forms.
+var newFileTypes = map[string]bool{
+	"docx": true,
+	"xlsx": true,
+	"pptx": true,
+	"pdf":  true,
+}
+
+// proxyRequestHeaders and proxyResponseHeaders are passed through by the download proxy.
+var (
+	proxyRequestHeaders = []string{
+		"Range", "If-Range", "If-None-Match", "If-Modified-Since",
+	}
+	proxyResponseHeaders = []string{
+		"Accept-Ranges", "Content-Disposition", "Content-Length", "Content-Range",
+		"Content-Type", "ETag", "Last-Modified",
+	}
 )
 
 type FileController struct {
-	client     client.Client
-	apiClient  pclient.PipedriveApiClient
-	jwtManager crypto.JwtManager
-	config     *config.ServerConfig
-	onlyoffice *shared.OnlyofficeConfig
-	logger     log.Logger
+	client      client.Client
+	apiClient   pclient.PipedriveApiClient
+	jwtManager  crypto.JwtManager
+	config      *config.ServerConfig
+	onlyoffice  *shared.OnlyofficeConfig
+	credentials *oauth2.Config
+	downloads   *downloadSessions
+	logger      log.Logger
 }
 
 func NewFileController(
@@ -54,19 +80,22 @@ func NewFileController(
 	jwtManager crypto.JwtManager,
 	config *config.ServerConfig,
 	onlyoffice *shared.OnlyofficeConfig,
+	credentials *oauth2.Config,
 	logger log.Logger,
 ) FileController {
 	return FileController{
-		client:     client,
-		apiClient:  apiClient,
-		jwtManager: jwtManager,
-		config:     config,
-		onlyoffice: onlyoffice,
-		logger:     logger,
+		client:      client,
+		apiClient:   apiClient,
+		jwtManager:  jwtManager,
+		config:      config,
+		onlyoffice:  onlyoffice,
+		credentials: credentials,
+		downloads:   newDownloadSessions(),
+		logger:      logger,
 	}
 }
 
-func (c *FileController) getUser(ctx context.Context, id string) (response.UserResponse, int) {
+func (c *FileController) getUser(ctx context.Context, id request.UserIdentity) (response.UserResponse, int) {
 	var ures response.UserResponse
 	if err := c.client.Call(
 		ctx,
@@ -98,10 +127,12 @@ func (c FileController) BuildGetFile() http.HandlerFunc {
 		rw.Header().Set("Content-Type", "application/json")
 
 		query := r.URL.Query()
-		lang, fileType, dealID, filename := strings.TrimSpace(query.Get("lang")),
-			strings.TrimSpace(query.Get("type")), strings.TrimSpace(query.Get("deal")),
-			strings.TrimSpace(query.Get("filename"))
-		if lang == "" || fileType == "" || dealID == "" || filename == "" {
+		lang, fileType, filename := strings.TrimSpace(query.Get("lang")),
+			strings.TrimSpace(query.Get("type")), strings.TrimSpace(query.Get("filename"))
+		entity := getParentEntity(query, "deal")
+		fileType = strings.ToLower(fileType)
+		templateID := strings.TrimSpace(query.Get("template"))
+		if (templateID == "" && (lang == "" || !newFileTypes[fileType])) || filename == "" || entity.Validate() != nil {
 			rw.WriteHeader(http.StatusBadRequest)
 			return
 		}
@@ -126,12 +157,17 @@ func (c FileController) BuildGetFile() http.HandlerFunc {
 		ctx, cancel := context.WithTimeout(r.Context(), 4*time.Second)
 		defer cancel()
 
-		ures, status := c.getUser(ctx, fmt.Sprint(pctx.UID+pctx.CID))
+		ures, status := c.getUser(ctx, pctx.Identity())
 		if status != http.StatusOK {
 			rw.WriteHeader(status)
 			return
 		}
 
+		if templateID != "" {
+			c.createFromTemplate(ctx, rw, pctx, ures, entity, templateID, filename)
+			return
+		}
+
 		file, err := assets.Files.Open(fmt.Sprintf("assets/%s/new.%s", lang, fileType))
 		if err != nil {
 			lang = "default"
@@ -143,7 +179,7 @@ func (c FileController) BuildGetFile() http.HandlerFunc {
 			}
 
 			defer file.Close()
-			res, ferr := c.apiClient.CreateFile(ctx, dealID, filename, file, model.Token{
+			res, ferr := c.apiClient.CreateFile(ctx, entity, filename, file, model.Token{
 				AccessToken:  ures.AccessToken,
 				RefreshToken: ures.AccessToken,
 				TokenType:    ures.TokenType,
@@ -162,7 +198,7 @@ func (c FileController) BuildGetFile() http.HandlerFunc {
 		}
 
 		defer file.Close()
-		res, ferr := c.apiClient.CreateFile(ctx, dealID, filename, file, model.Token{
+		res, ferr := c.apiClient.CreateFile(ctx, entity, filename, file, model.Token{
 			AccessToken:  ures.AccessToken,
 			RefreshToken: ures.AccessToken,
 			TokenType:    ures.TokenType,
@@ -180,37 +216,202 @@ func (c FileController) BuildGetFile() http.HandlerFunc {
 	}
 }
 
+// createFromTemplate creates a file from a company template. The file gets the template format
+// whatever extension the requested file name has.
+func (c FileController) createFromTemplate(
+	ctx context.Context,
+	rw http.ResponseWriter,
+	pctx request.PipedriveTokenContext,
+	ures response.UserResponse,
+	entity request.ParentEntity,
+	templateID string,
+	filename string,
+) {
+	tctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	var template response.DocTemplateResponse
+	if err := c.client.Call(tctx, c.client.NewRequest(
+		fmt.Sprintf("%s:settings", c.config.Namespace),
+		"TemplateHandler.GetTemplate",
+		request.DocTemplateSelect{
+			CompanyID: fmt.Sprint(pctx.CID),
+			ID:        templateID,
+		},
+	), &template); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		c.logger.Errorf("could not get company %d template %s: %s", pctx.CID, templateID, err.Error())
+		return
+	}
+
+	filename = fmt.Sprintf("%s.%s", strings.TrimSuffix(filename, filepath.Ext(filename)), template.FileType)
+	res, err := c.apiClient.CreateFile(tctx, entity, filename, io.NopCloser(bytes.NewReader(template.Content)), model.Token{
+		AccessToken:  ures.AccessToken,
+		RefreshToken: ures.RefreshToken,
+		TokenType:    ures.TokenType,
+		Scope:        ures.Scope,
+		ApiDomain:    ures.ApiDomain,
+	})
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		c.logger.Errorf("could not upload a pipedrive file: %s", err.Error())
+		return
+	}
+
+	rw.Write(res.ToJSON())
+}
+
+// BuildGetDownloadUrl returns a short-lived gateway link to a file the current user has access to.
 func (c FileController) BuildGetDownloadUrl() http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
 		rw.Header().Set("Content-Type", "plain/text")
-		query := r.URL.Query()
-		domain, fileID := query.Get("domain"), query.Get("file_id")
+		fileID := strings.TrimSpace(r.URL.Query().Get("file_id"))
+		if fileID == "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
 
-		client := &http.Client{
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		ures, status := c.getUser(ctx, pctx.Identity())
+		if status != http.StatusOK {
+			rw.WriteHeader(status)
+			return
+		}
+
+		if _, err := c.apiClient.GetDownloadURL(ctx, fileID, model.Token{
+			AccessToken:  ures.AccessToken,
+			RefreshToken: ures.RefreshToken,
+			TokenType:    ures.TokenType,
+			Scope:        ures.Scope,
+			ApiDomain:    ures.ApiDomain,
+		}); err != nil {
+			c.logger.Errorf("user %s could not access file %s: %s", pctx.Identity().String(), fileID, err.Error())
+			rw.WriteHeader(http.StatusForbidden)
+			return
 		}
 
-		dreq, _ := http.NewRequest("GET", fmt.Sprintf("%s/files/%s/download", strings.TrimSuffix(domain, "/"), fileID), nil)
-		dreq.Header.Add("Authorization", r.Header.Get("Authorization"))
-		resp, err := client.Do(dreq)
+		token, err := c.jwtManager.Sign(c.credentials.ClientSecret, request.NewDownloadTokenContext(pctx.Identity(), fileID))
 		if err != nil {
-			c.logger.Errorf("could not build a new download url: %s", err.Error())
-			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Errorf("could not sign file %s download token: %s", fileID, err.Error())
+			rw.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
-		if resp != nil {
-			defer resp.Body.Close()
+		rw.Write([]byte(fmt.Sprintf(
+			"%s/files/proxy?token=%s",
+			c.onlyoffice.Onlyoffice.Builder.GatewayURL, url.QueryEscape(token),
+		)))
+	}
+}
+
+// getProxyURL resolves the Pipedrive download url of the file a download link has been signed for.
+func (c FileController) getProxyURL(ctx context.Context, tctx request.DownloadTokenContext) (string, int) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	ures, status := c.getUser(ctx, tctx.Identity())
+	if status != http.StatusOK {
+		return "", status
+	}
+
+	durl, err := c.apiClient.GetDownloadURL(ctx, tctx.FileID, model.Token{
+		AccessToken:  ures.AccessToken,
+		RefreshToken: ures.RefreshToken,
+		TokenType:    ures.TokenType,
+		Scope:        ures.Scope,
+		ApiDomain:    ures.ApiDomain,
+	})
+	if err != nil {
+		c.logger.Errorf("could not get file %s download url: %s", tctx.FileID, err.Error())
+		return "", http.StatusBadRequest
+	}
+
+	return durl, http.StatusOK
+}
+
+// BuildGetProxyFile streams a file behind a signed download link. The Pipedrive api domain
+// and tokens come from the stored record of the user the link was signed for. Range and
+// conditional requests are passed through, so large files can be fetched in parts. The first
+// request starts a download session, which later requests reuse until the link expires.
+func (c FileController) BuildGetProxyFile() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		var tctx request.DownloadTokenContext
+		if err := c.jwtManager.Verify(c.credentials.ClientSecret, token, &tctx); err != nil {
+			c.logger.Errorf("could not verify download token: %s", err.Error())
+			rw.WriteHeader(http.StatusForbidden)
+			return
 		}
 
-		if resp.StatusCode != 302 {
-			c.logger.Errorf("unexpected status code while building a new download url: %d", resp.StatusCode)
-			rw.WriteHeader(resp.StatusCode)
+		if err := tctx.Validate(); err != nil {
+			c.logger.Errorf("invalid download token: %s", err.Error())
+			rw.WriteHeader(http.StatusForbidden)
 			return
 		}
 
-		rw.Write([]byte(resp.Header.Get("Location")))
+		session, ok := c.downloads.get(token)
+		if !ok {
+			session.tctx = tctx
+		}
+
+		header := make(http.Header)
+		for _, key := range proxyRequestHeaders {
+			if value := r.Header.Get(key); value != "" {
+				header.Set(key, value)
+			}
+		}
+
+		var resp *http.Response
+		for fresh := !ok; ; fresh = true {
+			if fresh {
+				durl, status := c.getProxyURL(r.Context(), session.tctx)
+				if status != http.StatusOK {
+					rw.WriteHeader(status)
+					return
+				}
+
+				session.url = durl
+				c.downloads.put(token, session.tctx, durl)
+			}
+
+			var err error
+			if resp, err = c.apiClient.StreamFile(r.Context(), r.Method, session.url, header); err == nil {
+				break
+			}
+
+			// Pipedrive download urls expire too, so a session resolves its file again once
+			if fresh {
+				c.logger.Errorf("could not stream file %s: %s", session.tctx.FileID, err.Error())
+				rw.WriteHeader(http.StatusBadGateway)
+				return
+			}
+
+			c.logger.Debugf("could not stream file %s with the session url: %s", session.tctx.FileID, err.Error())
+		}
+		defer resp.Body.Close()
+
+		for _, key := range proxyResponseHeaders {
+			if value := resp.Header.Get(key); value != "" {
+				rw.Header().Set(key, value)
+			}
+		}
+
+		rw.WriteHeader(resp.StatusCode)
+		if r.Method == http.MethodHead {
+			return
+		}
+
+		if _, err := io.Copy(rw, resp.Body); err != nil {
+			c.logger.Warnf("could not stream file %s: %s", session.tctx.FileID, err.Error())
+		}
 	}
 }
@@ -0,0 +1,306 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/rpctest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFileController(rpc *rpctest.Client) FileController {
+	return NewFileController(
+		rpc, pclient.NewPipedriveApiClient(), testJwtManager, testConfig,
+		newOnlyofficeConfig("https://gateway.example.com"), testCredentials, log.NewEmptyLogger(),
+	)
+}
+
+func TestDownloadUrl(t *testing.T) {
+	pipedrive := newPipedriveServer(map[string]string{"10": "content"})
+	defer pipedrive.Close()
+	controller := newFileController(rpctest.NewClient().WithUser(pipedrive.URL))
+
+	t.Run("sign a download link for a readable file", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		controller.BuildGetDownloadUrl()(rw, withPipedriveContext(
+			httptest.NewRequest(http.MethodGet, "/files/download?file_id=10", nil), 1, 2,
+		))
+
+		assert.Equal(t, http.StatusOK, rw.Code)
+		link, err := url.Parse(rw.Body.String())
+		assert.NoError(t, err)
+		assert.Equal(t, "/files/proxy", link.Path)
+
+		var tctx request.DownloadTokenContext
+		assert.NoError(t, testJwtManager.Verify(testSecret, link.Query().Get("token"), &tctx))
+		assert.NoError(t, tctx.Validate())
+		assert.Equal(t, "10", tctx.FileID)
+		assert.Equal(t, request.NewUserIdentity(1, 2), tctx.Identity())
+	})
+
+	t.Run("refuse to sign a link for a file the user can not read", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		controller.BuildGetDownloadUrl()(rw, withPipedriveContext(
+			httptest.NewRequest(http.MethodGet, "/files/download?file_id=11", nil), 1, 2,
+		))
+
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+		assert.Empty(t, rw.Body.String())
+	})
+}
+
+func TestProxyFile(t *testing.T) {
+	pipedrive := newPipedriveServer(map[string]string{"10": "content"})
+	defer pipedrive.Close()
+	controller := newFileController(rpctest.NewClient().WithUser(pipedrive.URL))
+
+	sign := func(secret string, claims jwt.Claims) string {
+		token, err := testJwtManager.Sign(secret, claims)
+		assert.NoError(t, err)
+		return token
+	}
+
+	proxy := func(token string) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		controller.BuildGetProxyFile()(rw, httptest.NewRequest(
+			http.MethodGet, "/files/proxy?token="+url.QueryEscape(token), nil,
+		))
+		return rw
+	}
+
+	expires := jwt.NewNumericDate(time.Now().Add(time.Minute))
+
+	t.Run("stream a file with a download token", func(t *testing.T) {
+		rw := proxy(sign(testSecret, request.NewDownloadTokenContext(request.NewUserIdentity(1, 2), "10")))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		body, _ := io.ReadAll(rw.Body)
+		assert.Equal(t, "content", string(body))
+	})
+
+	t.Run("reject a token issued for changes archives", func(t *testing.T) {
+		rw := proxy(sign(testSecret, request.NewChangesTokenContext(1, "10")))
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+	})
+
+	t.Run("reject a token without a purpose", func(t *testing.T) {
+		rw := proxy(sign(testSecret, jwt.MapClaims{
+			"exp": expires.Unix(), "cid": 1, "uid": 2, "fid": "10",
+		}))
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+	})
+
+	t.Run("reject a token signed with another secret", func(t *testing.T) {
+		rw := proxy(sign("another", request.NewDownloadTokenContext(request.NewUserIdentity(1, 2), "10")))
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+	})
+
+	t.Run("reject an expired token", func(t *testing.T) {
+		tctx := request.NewDownloadTokenContext(request.NewUserIdentity(1, 2), "10")
+		tctx.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+		rw := proxy(sign(testSecret, tctx))
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+	})
+
+	t.Run("pass range requests through", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/files/proxy?token="+url.QueryEscape(
+			sign(testSecret, request.NewDownloadTokenContext(request.NewUserIdentity(1, 2), "10")),
+		), nil)
+		req.Header.Set("Range", "bytes=0-2")
+		rw := httptest.NewRecorder()
+		controller.BuildGetProxyFile()(rw, req)
+		assert.Equal(t, http.StatusPartialContent, rw.Code)
+		assert.True(t, strings.HasPrefix(rw.Header().Get("Content-Range"), "bytes 0-2/"))
+		assert.Equal(t, "con", rw.Body.String())
+	})
+}
+
+func TestNewFile(t *testing.T) {
+	pipedrive := newPipedriveServer(map[string]string{})
+	defer pipedrive.Close()
+
+	newFile := func(controller FileController, query string) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		controller.BuildGetFile()(rw, withPipedriveContext(
+			httptest.NewRequest(http.MethodGet, "/files/create?"+query, nil), 1, 2,
+		))
+		return rw
+	}
+
+	t.Run("create a blank document", func(t *testing.T) {
+		rw := newFile(newFileController(rpctest.NewClient().WithUser(pipedrive.URL)),
+			"lang=en&type=docx&filename=Contract.docx&entity_type=deal&entity_id=5")
+
+		assert.Equal(t, http.StatusOK, rw.Code)
+		var res response.AddFileResponse
+		assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), &res))
+		assert.Equal(t, "Contract.docx", res.Data.Filename)
+	})
+
+	t.Run("accept blank forms", func(t *testing.T) {
+		// The user lookup fails, so a 500 proves the type passed validation without reading form assets.
+		rw := newFile(newFileController(rpctest.NewClient()),
+			"lang=en&type=pdf&filename=Application.pdf&entity_type=deal&entity_id=5")
+		assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	})
+
+	t.Run("reject unknown blank file types", func(t *testing.T) {
+		rw := newFile(newFileController(rpctest.NewClient().WithUser(pipedrive.URL)),
+			"lang=en&type=docxf&filename=Application.docxf&entity_type=deal&entity_id=5")
+		assert.Equal(t, http.StatusBadRequest, rw.Code)
+	})
+}
+
+// proxyServer is a Pipedrive server which logs the requests it gets, along with a proxy
+// counting the user lookups it makes.
+type proxyServer struct {
+	*httptest.Server
+	controller FileController
+	mu         sync.Mutex
+	requests   []string
+	lookups    atomic.Int32
+}
+
+func newProxyServer(t *testing.T) *proxyServer {
+	server := &proxyServer{Server: newPipedriveServer(map[string]string{"10": "content"})}
+	t.Cleanup(server.Close)
+
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		server.requests = append(server.requests, r.Method+" "+r.URL.Path)
+		server.mu.Unlock()
+		handler.ServeHTTP(rw, r)
+	})
+
+	rpc := rpctest.NewClient().WithUser(server.URL)
+	getUser := rpc.Handlers["UserSelectHandler.GetUser"]
+	rpc.Handlers["UserSelectHandler.GetUser"] = func(req interface{}, rsp interface{}) error {
+		server.lookups.Add(1)
+		return getUser(req, rsp)
+	}
+
+	server.controller = newFileController(rpc)
+	return server
+}
+
+func (s *proxyServer) log() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func TestProxyFileSession(t *testing.T) {
+	proxy := func(controller FileController, method, token, rng string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/files/proxy?token="+url.QueryEscape(token), nil)
+		if rng != "" {
+			req.Header.Set("Range", rng)
+		}
+
+		rw := httptest.NewRecorder()
+		controller.BuildGetProxyFile()(rw, req)
+		return rw
+	}
+
+	sign := func(tctx request.DownloadTokenContext) string {
+		token, err := testJwtManager.Sign(testSecret, tctx)
+		assert.NoError(t, err)
+		return token
+	}
+
+	t.Run("send head requests upstream as head requests", func(t *testing.T) {
+		server := newProxyServer(t)
+		rw := proxy(server.controller, http.MethodHead, sign(request.NewDownloadTokenContext(request.NewUserIdentity(1, 2), "10")), "")
+
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "7", rw.Header().Get("Content-Length"))
+		assert.Empty(t, rw.Body.String())
+		assert.Equal(t, []string{"GET /api/v1/files/10/download", "HEAD /storage/10"}, server.log())
+	})
+
+	t.Run("look the file up once per download session", func(t *testing.T) {
+		server := newProxyServer(t)
+		token := sign(request.NewDownloadTokenContext(request.NewUserIdentity(1, 2), "10"))
+
+		assert.Equal(t, "con", proxy(server.controller, http.MethodGet, token, "bytes=0-2").Body.String())
+		assert.Equal(t, "tent", proxy(server.controller, http.MethodGet, token, "bytes=3-6").Body.String())
+		assert.Equal(t, int32(1), server.lookups.Load())
+		assert.Equal(t, []string{"GET /api/v1/files/10/download", "GET /storage/10", "GET /storage/10"}, server.log())
+	})
+
+	t.Run("reject an expired link even though its download session has been started", func(t *testing.T) {
+		server := newProxyServer(t)
+		tctx := request.NewDownloadTokenContext(request.NewUserIdentity(1, 2), "10")
+		tctx.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+		token := sign(tctx)
+
+		server.controller.downloads.sessions[token] = downloadSession{tctx: tctx, url: server.URL + "/storage/10", expiresAt: time.Now().Add(time.Minute)}
+		assert.Equal(t, http.StatusForbidden, proxy(server.controller, http.MethodGet, token, "bytes=3-6").Code)
+		assert.Empty(t, server.log())
+	})
+
+	t.Run("look the file up again once its download url has expired", func(t *testing.T) {
+		server := newProxyServer(t)
+		tctx := request.NewDownloadTokenContext(request.NewUserIdentity(1, 2), "10")
+		token := sign(tctx)
+		server.controller.downloads.put(token, tctx, server.URL+"/expired/10")
+
+		rw := proxy(server.controller, http.MethodGet, token, "")
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "content", rw.Body.String())
+		assert.Equal(t, int32(1), server.lookups.Load())
+		assert.Equal(t, []string{"GET /expired/10", "GET /api/v1/files/10/download", "GET /storage/10"}, server.log())
+
+		session, ok := server.controller.downloads.get(token)
+		assert.True(t, ok)
+		assert.Equal(t, server.URL+"/storage/10", session.url)
+	})
+
+	t.Run("end download sessions at the expiration they were started with", func(t *testing.T) {
+		sessions := newDownloadSessions()
+		tctx := request.NewDownloadTokenContext(request.NewUserIdentity(1, 2), "10")
+		sessions.put("token", tctx, "https://storage.example.com/10")
+		started := sessions.sessions["token"].expiresAt
+
+		session, ok := sessions.get("token")
+		assert.True(t, ok)
+		assert.Equal(t, started, session.expiresAt)
+		sessions.put("token", tctx, "https://storage.example.com/11")
+		assert.Equal(t, started, sessions.sessions["token"].expiresAt)
+
+		sessions.sessions["token"] = downloadSession{tctx: tctx, expiresAt: time.Now().Add(-time.Second)}
+		_, ok = sessions.get("token")
+		assert.False(t, ok)
+		assert.Empty(t, sessions.sessions)
+	})
+}
@@ -0,0 +1,122 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"go-micro.dev/v4/client"
+)
+
+type GenerateController struct {
+	client client.Client
+	config *config.ServerConfig
+	logger log.Logger
+}
+
+func NewGenerateController(
+	client client.Client,
+	config *config.ServerConfig,
+	logger log.Logger,
+) GenerateController {
+	return GenerateController{
+		client: client,
+		config: config,
+		logger: logger,
+	}
+}
+
+// BuildPostGenerate creates a file from a company template filled with the fields of
+// the entity it is attached to, e.g. the deal with its person, organization and products.
+func (c GenerateController) BuildPostGenerate() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+
+		query := r.URL.Query()
+		templateID, filename := strings.TrimSpace(query.Get("template")), strings.TrimSpace(query.Get("name"))
+		entity := getParentEntity(query, "deal_id")
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		if templateID == "" || filename == "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Error("could not extract template id and file name from URL Query")
+			return
+		}
+
+		if err := entity.Validate(); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Errorf("invalid parent entity %s: %s", entity.String(), err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		var resp response.GenerateFileResponse
+		if err := c.client.Call(
+			ctx,
+			c.client.NewRequest(
+				fmt.Sprintf("%s:builder", c.config.Namespace),
+				"GenerateHandler.Generate",
+				request.GenerateFileRequest{
+					UID:        pctx.UID,
+					CID:        pctx.CID,
+					TemplateID: templateID,
+					Filename:   filename,
+					Entity:     entity,
+				},
+			),
+			&resp,
+			client.WithRequestTimeout(30*time.Second),
+		); err != nil {
+			c.logger.Errorf("could not generate file from template %s: %s", templateID, err.Error())
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				rw.WriteHeader(http.StatusRequestTimeout)
+				return
+			}
+
+			microErr := response.MicroError{}
+			if err := json.Unmarshal([]byte(err.Error()), &microErr); err != nil || microErr.Code == 0 {
+				rw.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			rw.WriteHeader(microErr.Code)
+			return
+		}
+
+		rw.WriteHeader(http.StatusCreated)
+		rw.Write(resp.ToJSON())
+	}
+}
@@ -0,0 +1,79 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"go-micro.dev/v4/client"
+)
+
+// getStatus maps go-micro errors to the status code they carry. Timeouts are reported as such.
+func getStatus(err error) int {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusRequestTimeout
+	}
+
+	microErr := response.MicroError{}
+	if err := json.Unmarshal([]byte(err.Error()), &microErr); err != nil || microErr.Code == 0 {
+		return http.StatusInternalServerError
+	}
+
+	return microErr.Code
+}
+
+// isAdmin asks Pipedrive whether the user is a company admin with the user's stored token.
+func isAdmin(
+	ctx context.Context,
+	client client.Client,
+	apiClient pclient.PipedriveApiClient,
+	config *config.ServerConfig,
+	pctx request.PipedriveTokenContext,
+) (bool, error) {
+	var ures response.UserResponse
+	if err := client.Call(ctx, client.NewRequest(
+		fmt.Sprintf("%s:auth", config.Namespace),
+		"UserSelectHandler.GetUser",
+		pctx.Identity(),
+	), &ures); err != nil {
+		return false, err
+	}
+
+	usr, err := apiClient.GetMe(ctx, model.Token{
+		AccessToken:  ures.AccessToken,
+		RefreshToken: ures.RefreshToken,
+		TokenType:    ures.TokenType,
+		Scope:        ures.Scope,
+		ApiDomain:    ures.ApiDomain,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return usr.IsAdmin(), nil
+}
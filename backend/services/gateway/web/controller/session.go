@@ -0,0 +1,119 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"go-micro.dev/v4/client"
+)
+
+type SessionController struct {
+	client    client.Client
+	apiClient pclient.PipedriveApiClient
+	config    *config.ServerConfig
+	logger    log.Logger
+}
+
+func NewSessionController(
+	client client.Client,
+	apiClient pclient.PipedriveApiClient,
+	config *config.ServerConfig,
+	logger log.Logger,
+) SessionController {
+	return SessionController{
+		client:    client,
+		apiClient: apiClient,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+// BuildGetSessions lists the open editing sessions of a file or a parent entity.
+// Without either company admins get every open session of their company.
+func (c SessionController) BuildGetSessions() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		query := r.URL.Query()
+		sreq := request.DocumentSessionSelect{
+			CompanyID: pctx.CID,
+			FileID:    strings.TrimSpace(query.Get("id")),
+		}
+
+		if sreq.FileID == "" {
+			if entity := getParentEntity(query, "deal_id"); entity.ID != "" {
+				if err := entity.Validate(); err != nil {
+					rw.WriteHeader(http.StatusBadRequest)
+					c.logger.Errorf("invalid parent entity %s: %s", entity.String(), err.Error())
+					return
+				}
+
+				sreq.EntityType, sreq.EntityID = entity.Type, entity.ID
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		if sreq.FileID == "" && sreq.EntityID == "" {
+			admin, err := isAdmin(ctx, c.client, c.apiClient, c.config, pctx)
+			if err != nil {
+				c.logger.Errorf("could not check user %s permissions: %s", pctx.Identity().String(), err.Error())
+				rw.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			if !admin {
+				c.logger.Errorf("user %s is not allowed to list company sessions", pctx.Identity().String())
+				rw.WriteHeader(http.StatusForbidden)
+				return
+			}
+		}
+
+		var resp response.DocumentSessionsResponse
+		if err := c.client.Call(ctx, c.client.NewRequest(
+			fmt.Sprintf("%s:documents", c.config.Namespace),
+			"SessionHandler.GetSessions",
+			sreq,
+		), &resp); err != nil {
+			c.logger.Errorf("could not get sessions: %s", err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+		rw.Write(resp.ToJSON())
+	}
+}
@@ -0,0 +1,239 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"go-micro.dev/v4/client"
+)
+
+// templateSignatures are the leading bytes of files in the template formats. Office open xml
+// files are zip archives.
+var templateSignatures = map[string][]byte{
+	"docx": []byte("PK\x03\x04"),
+	"xlsx": []byte("PK\x03\x04"),
+	"pptx": []byte("PK\x03\x04"),
+	"pdf":  []byte("%PDF"),
+}
+
+type TemplateController struct {
+	client    client.Client
+	apiClient pclient.PipedriveApiClient
+	config    *config.ServerConfig
+	logger    log.Logger
+}
+
+func NewTemplateController(
+	client client.Client,
+	apiClient pclient.PipedriveApiClient,
+	config *config.ServerConfig,
+	logger log.Logger,
+) TemplateController {
+	return TemplateController{
+		client:    client,
+		apiClient: apiClient,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+// authorizeAdmin writes the response status when the current user may not manage templates.
+func (c TemplateController) authorizeAdmin(ctx context.Context, rw http.ResponseWriter, pctx request.PipedriveTokenContext) bool {
+	admin, err := isAdmin(ctx, c.client, c.apiClient, c.config, pctx)
+	if err != nil {
+		c.logger.Errorf("could not check user %s permissions: %s", pctx.Identity().String(), err.Error())
+		rw.WriteHeader(http.StatusForbidden)
+		return false
+	}
+
+	if !admin {
+		c.logger.Errorf("user %s is not allowed to manage templates", pctx.Identity().String())
+		rw.WriteHeader(http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+// BuildGetTemplates lists the company templates, optionally of a file type and language.
+func (c TemplateController) BuildGetTemplates() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		query := r.URL.Query()
+		var resp response.DocTemplatesResponse
+		if err := c.client.Call(ctx, c.client.NewRequest(
+			fmt.Sprintf("%s:settings", c.config.Namespace),
+			"TemplateHandler.GetTemplates",
+			request.DocTemplateSelect{
+				CompanyID: fmt.Sprint(pctx.CID),
+				FileType:  strings.TrimSpace(query.Get("type")),
+				Lang:      strings.TrimSpace(query.Get("lang")),
+			},
+		), &resp); err != nil {
+			c.logger.Errorf("could not get company %d templates: %s", pctx.CID, err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+		rw.Write(resp.ToJSON())
+	}
+}
+
+// BuildPostTemplate uploads a company template from a multipart form with the template file
+// and optional name and lang fields. The template format is taken from the file extension.
+func (c TemplateController) BuildPostTemplate() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+		defer cancel()
+
+		if !c.authorizeAdmin(ctx, rw, pctx) {
+			return
+		}
+
+		r.Body = http.MaxBytesReader(rw, r.Body, int64(constants.MaxTemplateSize)+1024*1024)
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Errorf("could not read template file: %s", err.Error())
+			return
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(io.LimitReader(file, int64(constants.MaxTemplateSize)+1))
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Errorf("could not read template file: %s", err.Error())
+			return
+		}
+
+		if len(content) > constants.MaxTemplateSize {
+			rw.WriteHeader(http.StatusRequestEntityTooLarge)
+			c.logger.Errorf("template %s is too large", header.Filename)
+			return
+		}
+
+		fileType := strings.ToLower(strings.TrimPrefix(filepath.Ext(header.Filename), "."))
+		signature, supported := templateSignatures[fileType]
+		if !supported || !bytes.HasPrefix(content, signature) {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Errorf("template %s is not a docx, xlsx, pptx or pdf file", header.Filename)
+			return
+		}
+
+		name := strings.TrimSpace(r.FormValue("name"))
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
+		}
+
+		var resp response.DocTemplateResponse
+		if err := c.client.Call(ctx, c.client.NewRequest(
+			fmt.Sprintf("%s:settings", c.config.Namespace),
+			"TemplateHandler.InsertTemplate",
+			request.DocTemplate{
+				CompanyID: fmt.Sprint(pctx.CID),
+				Name:      name,
+				FileType:  fileType,
+				Lang:      strings.TrimSpace(r.FormValue("lang")),
+				Content:   content,
+				CreatedBy: pctx.Identity().String(),
+			},
+		), &resp); err != nil {
+			c.logger.Errorf("could not create company %d template: %s", pctx.CID, err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		rw.WriteHeader(http.StatusCreated)
+		rw.Write(resp.ToJSON())
+	}
+}
+
+func (c TemplateController) BuildDeleteTemplate() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		id := strings.TrimSpace(r.URL.Query().Get("id"))
+		if id == "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Error("could not extract template id from URL Query")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		if !c.authorizeAdmin(ctx, rw, pctx) {
+			return
+		}
+
+		var res interface{}
+		if err := c.client.Call(ctx, c.client.NewRequest(
+			fmt.Sprintf("%s:settings", c.config.Namespace),
+			"TemplateHandler.DeleteTemplate",
+			request.DocTemplateSelect{
+				CompanyID: fmt.Sprint(pctx.CID),
+				ID:        id,
+			},
+		), &res); err != nil {
+			c.logger.Errorf("could not delete company %d template %s: %s", pctx.CID, id, err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	}
+}
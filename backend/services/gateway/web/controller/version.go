@@ -0,0 +1,401 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"go-micro.dev/v4/client"
+	"golang.org/x/oauth2"
+)
+
+var errNoRecordAccess = errors.New("no access to the file record")
+
+type VersionController struct {
+	client      client.Client
+	apiClient   pclient.PipedriveApiClient
+	jwtManager  crypto.JwtManager
+	config      *config.ServerConfig
+	onlyoffice  *shared.OnlyofficeConfig
+	credentials *oauth2.Config
+	logger      log.Logger
+}
+
+func NewVersionController(
+	client client.Client,
+	apiClient pclient.PipedriveApiClient,
+	jwtManager crypto.JwtManager,
+	config *config.ServerConfig,
+	onlyoffice *shared.OnlyofficeConfig,
+	credentials *oauth2.Config,
+	logger log.Logger,
+) VersionController {
+	return VersionController{
+		client:      client,
+		apiClient:   apiClient,
+		jwtManager:  jwtManager,
+		config:      config,
+		onlyoffice:  onlyoffice,
+		credentials: credentials,
+		logger:      logger,
+	}
+}
+
+func (c VersionController) call(ctx context.Context, endpoint string, req interface{}, res interface{}) error {
+	return c.client.Call(ctx, c.client.NewRequest(fmt.Sprintf("%s:documents", c.config.Namespace), endpoint, req), res)
+}
+
+func (c VersionController) getToken(ctx context.Context, pctx request.PipedriveTokenContext) (model.Token, error) {
+	var ures response.UserResponse
+	if err := c.client.Call(ctx, c.client.NewRequest(
+		fmt.Sprintf("%s:auth", c.config.Namespace),
+		"UserSelectHandler.GetUser",
+		pctx.Identity(),
+	), &ures); err != nil {
+		return model.Token{}, err
+	}
+
+	return model.Token{
+		AccessToken:  ures.AccessToken,
+		RefreshToken: ures.RefreshToken,
+		TokenType:    ures.TokenType,
+		Scope:        ures.Scope,
+		ApiDomain:    ures.ApiDomain,
+	}, nil
+}
+
+// checkAccess looks the record the versions are attached to up on behalf of the user, the same way
+// the editor config does. Pipedrive does not return records the user may not see.
+func (c VersionController) checkAccess(ctx context.Context, token model.Token, version response.DocumentVersionResponse) error {
+	if _, err := c.apiClient.GetRecord(ctx, request.NewParentEntity(version.EntityType, version.EntityID), token); err != nil {
+		var serr *pclient.UnexpectedStatusCodeError
+		if errors.As(err, &serr) && (serr.Code == http.StatusForbidden || serr.Code == http.StatusNotFound) {
+			return errNoRecordAccess
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (c VersionController) getAccessStatus(err error) int {
+	if errors.Is(err, errNoRecordAccess) {
+		return http.StatusForbidden
+	}
+
+	return http.StatusBadGateway
+}
+
+func (c VersionController) BuildGetVersions() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+
+		id := strings.TrimSpace(r.URL.Query().Get("id"))
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		if id == "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Error("could not extract file id from URL Query")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 6*time.Second)
+		defer cancel()
+
+		token, err := c.getToken(ctx, pctx)
+		if err != nil {
+			c.logger.Errorf("could not get user access info: %s", err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		var resp response.DocumentVersionsResponse
+		if err := c.call(ctx, "VersionHandler.GetVersions", request.DocumentVersionSelect{
+			CompanyID: pctx.CID,
+			FileID:    id,
+		}, &resp); err != nil {
+			c.logger.Errorf("could not get file versions: %s", err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		if len(resp.Versions) > 0 {
+			if err := c.checkAccess(ctx, token, resp.Versions[len(resp.Versions)-1]); err != nil {
+				c.logger.Errorf("could not check user %s access to file %s: %s", pctx.Identity().String(), id, err.Error())
+				rw.WriteHeader(c.getAccessStatus(err))
+				return
+			}
+		}
+
+		rw.WriteHeader(http.StatusOK)
+		rw.Write(resp.ToJSON())
+	}
+}
+
+// BuildPostRestoreVersion uploads the contents of a prior version as the newest one.
+func (c VersionController) BuildPostRestoreVersion() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+
+		query := r.URL.Query()
+		id := strings.TrimSpace(query.Get("id"))
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		version, err := strconv.Atoi(strings.TrimSpace(query.Get("version")))
+		if id == "" || err != nil || version <= 0 {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Error("could not extract file id and version from URL Query")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		token, err := c.getToken(ctx, pctx)
+		if err != nil {
+			c.logger.Errorf("could not get user access info: %s", err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		var (
+			target   response.DocumentVersionResponse
+			versions response.DocumentVersionsResponse
+		)
+
+		if err := c.call(ctx, "VersionHandler.GetVersion", request.DocumentVersionSelect{
+			CompanyID: pctx.CID,
+			FileID:    id,
+			Version:   version,
+		}, &target); err != nil {
+			c.logger.Errorf("could not get file %s version %d: %s", id, version, err.Error())
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if err := c.checkAccess(ctx, token, target); err != nil {
+			c.logger.Errorf("could not check user %s access to file %s: %s", pctx.Identity().String(), id, err.Error())
+			rw.WriteHeader(c.getAccessStatus(err))
+			return
+		}
+
+		if target.Deleted {
+			c.logger.Errorf("file %s version %d has been deleted in Pipedrive", id, version)
+			rw.WriteHeader(http.StatusGone)
+			return
+		}
+
+		if err := c.call(ctx, "VersionHandler.GetVersions", request.DocumentVersionSelect{
+			CompanyID: pctx.CID,
+			FileID:    id,
+		}, &versions); err != nil || len(versions.Versions) == 0 {
+			c.logger.Errorf("could not get file %s versions", id)
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		url, err := c.apiClient.GetDownloadURL(ctx, target.FileID, token)
+		if err != nil {
+			c.logger.Errorf("could not get file %s download url: %s", target.FileID, err.Error())
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		file, err := c.apiClient.UploadFile(ctx, url, request.NewParentEntity(target.EntityType, target.EntityID), target.Filename, token)
+		if err != nil {
+			c.logger.Errorf("could not restore file %s version %d: %s", id, version, err.Error())
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var resp response.DocumentVersionResponse
+		if err := c.call(ctx, "VersionHandler.InsertVersion", request.DocumentVersion{
+			CompanyID:  pctx.CID,
+			ParentID:   versions.Versions[len(versions.Versions)-1].FileID,
+			FileID:     fmt.Sprint(file.Data.ID),
+			EntityType: target.EntityType,
+			EntityID:   target.EntityID,
+			Filename:   target.Filename,
+			Author:     pctx.Identity().String(),
+		}, &resp); err != nil {
+			c.logger.Errorf("could not record restored file %d: %s", file.Data.ID, err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		// The restored file supersedes the latest one, which is pruned like after a save
+		if err := shared.PruneVersions(
+			ctx, c.client, c.apiClient, c.config.Namespace, c.logger,
+			token, pctx.CID, resp.FileID, c.onlyoffice.Onlyoffice.Callback.KeepVersions,
+		); err != nil {
+			c.logger.Warnf("could not prune file %s versions: %s", resp.FileID, err.Error())
+		}
+
+		rw.WriteHeader(http.StatusCreated)
+		rw.Write(resp.ToJSON())
+	}
+}
+
+func (c VersionController) BuildGetHistory() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+
+		query := r.URL.Query()
+		id, key := strings.TrimSpace(query.Get("id")), strings.TrimSpace(query.Get("key"))
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		if id == "" || key == "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Error("could not extract file id and doc key from URL Query")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 6*time.Second)
+		defer cancel()
+
+		var resp response.BuildHistoryResponse
+		if err := c.client.Call(ctx, c.client.NewRequest(
+			fmt.Sprintf("%s:builder", c.config.Namespace),
+			"HistoryHandler.BuildHistory",
+			request.BuildHistoryRequest{
+				UID:    pctx.UID,
+				CID:    pctx.CID,
+				FileID: id,
+				DocKey: key,
+			},
+		), &resp); err != nil {
+			c.logger.Errorf("could not build file history: %s", err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+		rw.Write(resp.ToJSON())
+	}
+}
+
+func (c VersionController) BuildGetHistoryData() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+
+		query := r.URL.Query()
+		id, key := strings.TrimSpace(query.Get("id")), strings.TrimSpace(query.Get("key"))
+		pctx, ok := r.Context().Value("X-Pipedrive-App-Context").(request.PipedriveTokenContext)
+		if !ok {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Error("could not extract pipedrive context from the context")
+			return
+		}
+
+		version, err := strconv.Atoi(strings.TrimSpace(query.Get("version")))
+		if id == "" || key == "" || err != nil || version <= 0 {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Error("could not extract file id, doc key and version from URL Query")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		var resp response.BuildHistoryDataResponse
+		if err := c.client.Call(ctx, c.client.NewRequest(
+			fmt.Sprintf("%s:builder", c.config.Namespace),
+			"HistoryHandler.BuildHistoryData",
+			request.BuildHistoryRequest{
+				UID:     pctx.UID,
+				CID:     pctx.CID,
+				FileID:  id,
+				DocKey:  key,
+				Version: version,
+			},
+		), &resp); err != nil {
+			c.logger.Errorf("could not build file history data: %s", err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+		rw.Write(resp.ToJSON())
+	}
+}
+
+// BuildGetChanges serves a stored changes archive to the document server.
+func (c VersionController) BuildGetChanges() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		var tctx request.ChangesTokenContext
+		if err := c.jwtManager.Verify(c.credentials.ClientSecret, r.URL.Query().Get("token"), &tctx); err != nil {
+			c.logger.Errorf("could not verify changes token: %s", err.Error())
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if err := tctx.Validate(); err != nil {
+			c.logger.Errorf("invalid changes token: %s", err.Error())
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		var resp response.DocumentHistoryResponse
+		if err := c.call(ctx, "HistoryHandler.GetArchive", request.DocumentHistorySelect{
+			CompanyID: tctx.CID,
+			FileIDs:   []string{tctx.FileID},
+		}, &resp); err != nil || len(resp.Archive) == 0 {
+			c.logger.Errorf("could not get file %s changes archive", tctx.FileID)
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/zip")
+		rw.WriteHeader(http.StatusOK)
+		rw.Write(resp.Archive)
+	}
+}
@@ -0,0 +1,219 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/rpctest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChanges(t *testing.T) {
+	rpc := rpctest.NewClient()
+	rpc.Handlers["HistoryHandler.GetArchive"] = func(req interface{}, rsp interface{}) error {
+		sel := req.(request.DocumentHistorySelect)
+		if sel.CompanyID == 1 && len(sel.FileIDs) == 1 && sel.FileIDs[0] == "10" {
+			*rsp.(*response.DocumentHistoryResponse) = response.DocumentHistoryResponse{
+				FileID:  "10",
+				Archive: []byte("archive"),
+			}
+		}
+		return nil
+	}
+
+	controller := NewVersionController(rpc, pclient.NewPipedriveApiClient(), testJwtManager, testConfig, newOnlyofficeConfig(""), testCredentials, log.NewEmptyLogger())
+	changes := func(claims jwt.Claims) *httptest.ResponseRecorder {
+		token, err := testJwtManager.Sign(testSecret, claims)
+		assert.NoError(t, err)
+		rw := httptest.NewRecorder()
+		controller.BuildGetChanges()(rw, httptest.NewRequest(http.MethodGet, "/files/changes?token="+url.QueryEscape(token), nil))
+		return rw
+	}
+
+	t.Run("serve a changes archive with a changes token", func(t *testing.T) {
+		rw := changes(request.NewChangesTokenContext(1, "10"))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "application/zip", rw.Header().Get("Content-Type"))
+		assert.Equal(t, "archive", rw.Body.String())
+	})
+
+	t.Run("reject a download token for the same file", func(t *testing.T) {
+		rw := changes(request.NewDownloadTokenContext(request.NewUserIdentity(1, 2), "10"))
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+		assert.Empty(t, rw.Body.String())
+	})
+
+	t.Run("reject a token without a purpose", func(t *testing.T) {
+		rw := changes(jwt.MapClaims{"exp": time.Now().Add(time.Minute).Unix(), "cid": 1, "fid": "10"})
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+	})
+
+	t.Run("report missing archives", func(t *testing.T) {
+		rw := changes(request.NewChangesTokenContext(1, "11"))
+		assert.Equal(t, http.StatusNotFound, rw.Code)
+	})
+}
+
+func TestRestoreVersion(t *testing.T) {
+	files := map[string]string{"1": "first", "2": "second", "3": "third"}
+	pipedrive := newPipedriveServer(files)
+	defer pipedrive.Close()
+
+	lineage := []response.DocumentVersionResponse{
+		{RootID: "1", FileID: "1", EntityType: "deal", EntityID: "5", Version: 1, Filename: "mock.docx"},
+		{RootID: "1", ParentID: "1", FileID: "2", EntityType: "deal", EntityID: "5", Version: 2, Filename: "mock.docx"},
+		{RootID: "1", ParentID: "2", FileID: "3", EntityType: "deal", EntityID: "5", Version: 3, Filename: "mock.docx", Deleted: true},
+		{RootID: "1", ParentID: "3", FileID: "4", EntityType: "deal", EntityID: "5", Version: 4, Filename: "mock.docx"},
+	}
+
+	var inserted []request.DocumentVersion
+	rpc := rpctest.NewClient().WithUser(pipedrive.URL)
+	rpc.Handlers["VersionHandler.GetVersion"] = func(req interface{}, rsp interface{}) error {
+		sel := req.(request.DocumentVersionSelect)
+		for _, version := range lineage {
+			if sel.CompanyID == 1 && version.Version == sel.Version {
+				*rsp.(*response.DocumentVersionResponse) = version
+				return nil
+			}
+		}
+		return errors.New(`{"id":"test","code":404,"detail":"no document version","status":"Not Found"}`)
+	}
+	rpc.Handlers["VersionHandler.GetVersions"] = func(req interface{}, rsp interface{}) error {
+		*rsp.(*response.DocumentVersionsResponse) = response.DocumentVersionsResponse{Versions: lineage}
+		return nil
+	}
+	rpc.Handlers["VersionHandler.InsertVersion"] = func(req interface{}, rsp interface{}) error {
+		version := req.(request.DocumentVersion)
+		inserted = append(inserted, version)
+		*rsp.(*response.DocumentVersionResponse) = response.DocumentVersionResponse{
+			RootID: "1", ParentID: version.ParentID, FileID: version.FileID, Version: 5, Filename: version.Filename,
+		}
+		return nil
+	}
+
+	var pruned []string
+	rpc.Handlers["EventHandler.HandleEvent"] = func(req interface{}, rsp interface{}) error {
+		pruned = append(pruned, req.(request.DocumentEvent).FileID)
+		return nil
+	}
+
+	controller := NewVersionController(rpc, pclient.NewPipedriveApiClient(), testJwtManager, testConfig, newOnlyofficeConfig(""), testCredentials, log.NewEmptyLogger())
+	restore := func(query string) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		controller.BuildPostRestoreVersion()(rw, withPipedriveContext(httptest.NewRequest(http.MethodPost, "/api/versions/restore?"+query, nil), 1, 2))
+		return rw
+	}
+
+	t.Run("restore a prior version as the newest one", func(t *testing.T) {
+		rw := restore("id=4&version=2")
+		assert.Equal(t, http.StatusCreated, rw.Code)
+
+		var resp response.DocumentVersionResponse
+		assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+		assert.Equal(t, 5, resp.Version)
+		assert.Equal(t, "second", files[resp.FileID])
+
+		assert.Len(t, inserted, 1)
+		assert.Equal(t, "4", inserted[0].ParentID)
+		assert.Equal(t, resp.FileID, inserted[0].FileID)
+		assert.Equal(t, "deal", inserted[0].EntityType)
+		assert.Equal(t, "5", inserted[0].EntityID)
+		assert.Equal(t, "1:2", inserted[0].Author)
+	})
+
+	t.Run("remove the attachments the restored file supersedes", func(t *testing.T) {
+		assert.Equal(t, []string{"1", "2", "4"}, pruned)
+		assert.NotContains(t, files, "1")
+		assert.NotContains(t, files, "2")
+	})
+
+	t.Run("deleted versions can not be restored", func(t *testing.T) {
+		rw := restore("id=4&version=3")
+		assert.Equal(t, http.StatusGone, rw.Code)
+	})
+
+	t.Run("unknown versions are not found", func(t *testing.T) {
+		rw := restore("id=4&version=9")
+		assert.Equal(t, http.StatusNotFound, rw.Code)
+	})
+
+	t.Run("reject an invalid version", func(t *testing.T) {
+		rw := restore("id=4&version=zero")
+		assert.Equal(t, http.StatusBadRequest, rw.Code)
+	})
+
+	t.Run("reject users who can not see the record", func(t *testing.T) {
+		lineage[1].EntityID = "private"
+		defer func() { lineage[1].EntityID = "5" }()
+
+		rw := restore("id=4&version=2")
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+	})
+
+	t.Run("nothing else is recorded", func(t *testing.T) {
+		assert.Len(t, inserted, 1)
+	})
+}
+
+func TestGetVersions(t *testing.T) {
+	pipedrive := newPipedriveServer(map[string]string{})
+	defer pipedrive.Close()
+
+	entityID := "5"
+	rpc := rpctest.NewClient().WithUser(pipedrive.URL)
+	rpc.Handlers["VersionHandler.GetVersions"] = func(req interface{}, rsp interface{}) error {
+		*rsp.(*response.DocumentVersionsResponse) = response.DocumentVersionsResponse{Versions: []response.DocumentVersionResponse{
+			{RootID: "1", FileID: "1", EntityType: "deal", EntityID: entityID, Version: 1, Filename: "mock.docx"},
+		}}
+		return nil
+	}
+
+	controller := NewVersionController(rpc, pclient.NewPipedriveApiClient(), testJwtManager, testConfig, newOnlyofficeConfig(""), testCredentials, log.NewEmptyLogger())
+	versions := func() *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		controller.BuildGetVersions()(rw, withPipedriveContext(httptest.NewRequest(http.MethodGet, "/api/versions?id=1", nil), 1, 2))
+		return rw
+	}
+
+	t.Run("list versions of a visible record", func(t *testing.T) {
+		rw := versions()
+		assert.Equal(t, http.StatusOK, rw.Code)
+
+		var resp response.DocumentVersionsResponse
+		assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+		assert.Len(t, resp.Versions, 1)
+	})
+
+	t.Run("reject users who can not see the record", func(t *testing.T) {
+		entityID = "private"
+		assert.Equal(t, http.StatusForbidden, versions().Code)
+	})
+}
@@ -0,0 +1,261 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"go-micro.dev/v4/client"
+)
+
+// maxWebhookSize is well above any Pipedrive notification since files are never inlined.
+const maxWebhookSize = 1 << 20
+
+// webhookEvents are the Pipedrive events subscribed to when a company installs the app.
+// Pipedrive has no file subscriptions, so deleted.file notifications are handled if delivered
+// and attachments removed together with their record are covered by the record events.
+var webhookEvents []model.Webhook = []model.Webhook{
+	{EventAction: request.WebhookActionDeleted, EventObject: request.EntityDeal},
+	{EventAction: request.WebhookActionMerged, EventObject: request.EntityDeal},
+	{EventAction: request.WebhookActionDeleted, EventObject: request.EntityPerson},
+	{EventAction: request.WebhookActionMerged, EventObject: request.EntityPerson},
+	{EventAction: request.WebhookActionDeleted, EventObject: request.EntityOrganization},
+	{EventAction: request.WebhookActionMerged, EventObject: request.EntityOrganization},
+	{EventAction: request.WebhookActionDeleted, EventObject: request.EntityProduct},
+	{EventAction: request.WebhookActionDeleted, EventObject: request.EntityActivity},
+	{EventAction: request.WebhookActionUpdated, EventObject: request.WebhookObjectUser},
+	{EventAction: request.WebhookActionDeleted, EventObject: request.WebhookObjectUser},
+}
+
+type WebhookController struct {
+	client     client.Client
+	apiClient  pclient.PipedriveApiClient
+	config     *config.ServerConfig
+	onlyoffice *shared.OnlyofficeConfig
+	webhook    *shared.WebhookConfig
+	registered *sync.Map
+	logger     log.Logger
+}
+
+func NewWebhookController(
+	client client.Client,
+	apiClient pclient.PipedriveApiClient,
+	config *config.ServerConfig,
+	onlyoffice *shared.OnlyofficeConfig,
+	webhook *shared.WebhookConfig,
+	logger log.Logger,
+) WebhookController {
+	return WebhookController{
+		client:     client,
+		apiClient:  apiClient,
+		config:     config,
+		onlyoffice: onlyoffice,
+		webhook:    webhook,
+		registered: &sync.Map{},
+		logger:     logger,
+	}
+}
+
+// RegisterWebhooks subscribes the company to webhookEvents, skipping existing subscriptions
+// so that reinstalls do not pile up duplicates. Notifications authenticate with the company id
+// and a password derived from it. Subscriptions made with other credentials, e.g. the client
+// secret older versions used, are replaced since Pipedrive never returns their password.
+func (c WebhookController) RegisterWebhooks(ctx context.Context, cid int, token model.Token) error {
+	subscriptionURL := fmt.Sprintf("%s/webhooks/pipedrive", c.onlyoffice.Onlyoffice.Builder.GatewayURL)
+	webhooks, err := c.apiClient.GetWebhooks(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	user := strconv.Itoa(cid)
+	existing := make(map[string]bool, len(webhooks))
+	for _, webhook := range webhooks {
+		if webhook.SubscriptionURL != subscriptionURL {
+			continue
+		}
+
+		if webhook.HttpAuthUser == user {
+			existing[webhook.Event()] = true
+			continue
+		}
+
+		if err := c.apiClient.DeleteWebhook(ctx, webhook.ID, token); err != nil {
+			return err
+		}
+	}
+
+	for _, event := range webhookEvents {
+		if existing[event.Event()] {
+			continue
+		}
+
+		event.SubscriptionURL = subscriptionURL
+		event.HttpAuthUser = user
+		event.HttpAuthPassword = c.webhook.Password(cid)
+		event.Version = "1.0"
+		if err := c.apiClient.CreateWebhook(ctx, event, token); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateWebhooks registers the company webhooks in the background. It runs whenever an admin
+// installs the app or signs in again.
+func (c WebhookController) UpdateWebhooks(cid int, token model.Token) {
+	c.registered.Store(cid, true)
+	c.registerWebhooks(cid, token)
+}
+
+// EnsureWebhooks registers the company webhooks once per gateway instance. It runs whenever an admin
+// opens the settings, so that companies installed before webhooks were introduced get them as well.
+func (c WebhookController) EnsureWebhooks(cid int, token model.Token) {
+	if _, registered := c.registered.LoadOrStore(cid, true); registered {
+		return
+	}
+
+	c.registerWebhooks(cid, token)
+}
+
+// registerWebhooks forgets failed registrations, so that they are retried the next time.
+func (c WebhookController) registerWebhooks(cid int, token model.Token) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := c.RegisterWebhooks(ctx, cid, token); err != nil {
+			c.logger.Warnf("could not register company %d webhooks: %s", cid, err.Error())
+			c.registered.Delete(cid)
+		}
+	}()
+}
+
+func (c WebhookController) publishDocumentEvent(ctx context.Context, event request.DocumentEvent) error {
+	var res interface{}
+	return c.client.Call(ctx, c.client.NewRequest(
+		fmt.Sprintf("%s:documents", c.config.Namespace),
+		"EventHandler.HandleEvent",
+		event,
+	), &res)
+}
+
+// handleWebhook returns false for events the integration does not keep state for.
+func (c WebhookController) handleWebhook(ctx context.Context, webhook request.PipedriveWebhook) (bool, error) {
+	meta := webhook.Meta
+	entity := request.NewParentEntity(meta.Object, string(meta.ID))
+	switch {
+	case meta.Object == request.WebhookObjectUser:
+		if !webhook.Deactivated() {
+			return false, nil
+		}
+
+		uid, err := strconv.Atoi(string(meta.ID))
+		if err != nil {
+			return false, nil
+		}
+
+		var res interface{}
+		return true, c.client.Call(ctx, c.client.NewRequest(
+			fmt.Sprintf("%s:auth", c.config.Namespace),
+			"UserDeleteHandler.DeleteUser",
+			request.NewUserIdentity(meta.CompanyID, uid),
+		), &res)
+	case meta.Object == request.WebhookObjectFile && meta.Action == request.WebhookActionDeleted:
+		return true, c.publishDocumentEvent(ctx, request.DocumentEvent{
+			CompanyID: meta.CompanyID,
+			Action:    request.DocumentEventFileDeleted,
+			FileID:    string(meta.ID),
+		})
+	case entity.Validate() != nil:
+		return false, nil
+	case meta.Action == request.WebhookActionDeleted:
+		return true, c.publishDocumentEvent(ctx, request.DocumentEvent{
+			CompanyID: meta.CompanyID,
+			Action:    request.DocumentEventEntityDeleted,
+			Entity:    entity,
+		})
+	case meta.Action == request.WebhookActionMerged:
+		merged := webhook.MergedID()
+		if merged == "" {
+			return false, nil
+		}
+
+		return true, c.publishDocumentEvent(ctx, request.DocumentEvent{
+			CompanyID:  meta.CompanyID,
+			Action:     request.DocumentEventEntityMerged,
+			Entity:     request.NewParentEntity(meta.Object, merged),
+			MergedInto: entity.ID,
+		})
+	default:
+		return false, nil
+	}
+}
+
+// BuildPostWebhook applies Pipedrive notifications to the stored documents and users.
+// Every handler is idempotent, so failures are reported to let Pipedrive retry the delivery.
+func (c WebhookController) BuildPostWebhook() http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		var webhook request.PipedriveWebhook
+		if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxWebhookSize)).Decode(&webhook); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Errorf("could not unmarshal webhook body: %s", err.Error())
+			return
+		}
+
+		if webhook.Meta.CompanyID <= 0 || webhook.Meta.ID == "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			c.logger.Errorf("webhook %s has no company or object id", webhook.Event)
+			return
+		}
+
+		if cid, ok := r.Context().Value("X-Pipedrive-Webhook-Company").(int); !ok || cid != webhook.Meta.CompanyID {
+			rw.WriteHeader(http.StatusForbidden)
+			c.logger.Errorf("webhook %s of company %d has been authenticated for another company", webhook.Event, webhook.Meta.CompanyID)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		handled, err := c.handleWebhook(ctx, webhook)
+		if err != nil {
+			c.logger.Errorf("could not handle company %d webhook %s: %s", webhook.Meta.CompanyID, webhook.Event, err.Error())
+			rw.WriteHeader(getStatus(err))
+			return
+		}
+
+		if handled {
+			c.logger.Debugf("company %d webhook %s for %s has been handled", webhook.Meta.CompanyID, webhook.Event, webhook.Meta.ID)
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,338 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/gateway/web/middleware"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/rpctest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWebhookConfig() *shared.WebhookConfig {
+	var config shared.WebhookConfig
+	config.Webhook.Secret = "webhook-secret-webhook-secret-webhook"
+	return &config
+}
+
+// webhookServer serves Pipedrive webhook subscriptions.
+type webhookServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	webhooks map[int]model.Webhook
+	nextID   int
+}
+
+func newWebhookServer(webhooks ...model.Webhook) *webhookServer {
+	server := &webhookServer{webhooks: map[int]model.Webhook{}, nextID: 1}
+	for _, webhook := range webhooks {
+		webhook.ID = server.nextID
+		server.webhooks[webhook.ID] = webhook
+		server.nextID++
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/webhooks", func(rw http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		var res struct {
+			Data []model.Webhook `json:"data"`
+		}
+		for _, webhook := range server.webhooks {
+			// Pipedrive never returns the password
+			webhook.HttpAuthPassword = ""
+			res.Data = append(res.Data, webhook)
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(res)
+	})
+	mux.HandleFunc("POST /api/v1/webhooks", func(rw http.ResponseWriter, r *http.Request) {
+		var webhook model.Webhook
+		if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		server.mu.Lock()
+		webhook.ID = server.nextID
+		server.webhooks[webhook.ID] = webhook
+		server.nextID++
+		server.mu.Unlock()
+		rw.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("DELETE /api/v1/webhooks/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(r.PathValue("id"))
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		if _, ok := server.webhooks[id]; !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		delete(server.webhooks, id)
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	server.Server = httptest.NewServer(mux)
+	return server
+}
+
+func (s *webhookServer) list() []model.Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	webhooks := make([]model.Webhook, 0, len(s.webhooks))
+	for _, webhook := range s.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks
+}
+
+func TestRegisterWebhooks(t *testing.T) {
+	const gatewayURL = "https://gateway.example.com"
+	subscriptionURL := gatewayURL + "/webhooks/pipedrive"
+	webhook := newWebhookConfig()
+
+	server := newWebhookServer(
+		model.Webhook{
+			SubscriptionURL: subscriptionURL,
+			EventAction:     request.WebhookActionDeleted,
+			EventObject:     request.EntityDeal,
+			HttpAuthUser:    testCredentials.ClientID,
+		},
+		model.Webhook{
+			SubscriptionURL: "https://another.example.com",
+			EventAction:     request.WebhookActionDeleted,
+			EventObject:     request.EntityDeal,
+			HttpAuthUser:    testCredentials.ClientID,
+		},
+	)
+	defer server.Close()
+
+	controller := NewWebhookController(
+		rpctest.NewClient(), pclient.NewPipedriveApiClient(), testConfig,
+		newOnlyofficeConfig(gatewayURL), webhook, log.NewEmptyLogger(),
+	)
+	token := model.Token{AccessToken: "access", ApiDomain: server.URL}
+
+	t.Run("replace subscriptions authenticated with other credentials", func(t *testing.T) {
+		assert.NoError(t, controller.RegisterWebhooks(context.Background(), 1, token))
+
+		var own []model.Webhook
+		for _, registered := range server.list() {
+			if registered.SubscriptionURL != subscriptionURL {
+				assert.Equal(t, "https://another.example.com", registered.SubscriptionURL)
+				continue
+			}
+
+			assert.Equal(t, "1", registered.HttpAuthUser)
+			assert.Equal(t, webhook.Password(1), registered.HttpAuthPassword)
+			own = append(own, registered)
+		}
+
+		assert.Len(t, own, len(webhookEvents))
+		assert.Len(t, server.list(), len(webhookEvents)+1)
+	})
+
+	t.Run("skip existing subscriptions on reinstalls", func(t *testing.T) {
+		assert.NoError(t, controller.RegisterWebhooks(context.Background(), 1, token))
+		assert.Len(t, server.list(), len(webhookEvents)+1)
+	})
+}
+
+func TestPostWebhook(t *testing.T) {
+	webhook := newWebhookConfig()
+	newController := func(err error) (http.Handler, *[]interface{}) {
+		var calls []interface{}
+		rpc := rpctest.NewClient()
+		rpc.Handlers["UserDeleteHandler.DeleteUser"] = func(req interface{}, rsp interface{}) error {
+			calls = append(calls, req)
+			return err
+		}
+		rpc.Handlers["EventHandler.HandleEvent"] = func(req interface{}, rsp interface{}) error {
+			calls = append(calls, req)
+			return err
+		}
+
+		controller := NewWebhookController(
+			rpc, pclient.PipedriveApiClient{}, testConfig,
+			newOnlyofficeConfig(""), webhook, log.NewEmptyLogger(),
+		)
+
+		return middleware.BuildHandleWebhookMiddleware(webhook, log.NewEmptyLogger()).
+			Protect(controller.BuildPostWebhook()), &calls
+	}
+
+	newRequest := func(cid int, body []byte) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/webhooks/pipedrive", bytes.NewReader(body))
+		r.SetBasicAuth(strconv.Itoa(cid), webhook.Password(cid))
+		return r
+	}
+
+	newWebhook := func(action, object, id string) request.PipedriveWebhook {
+		return request.PipedriveWebhook{
+			Event: action + "." + object,
+			Meta: request.PipedriveWebhookMeta{
+				Action:    action,
+				Object:    object,
+				ID:        request.WebhookID(id),
+				CompanyID: 1,
+				UserID:    2,
+			},
+		}
+	}
+
+	merged := newWebhook(request.WebhookActionMerged, request.EntityPerson, "5")
+	merged.Current = map[string]interface{}{"merge_what_id": float64(6)}
+	deactivated := newWebhook(request.WebhookActionUpdated, request.WebhookObjectUser, "3")
+	deactivated.Current = map[string]interface{}{"active_flag": false}
+	activated := newWebhook(request.WebhookActionUpdated, request.WebhookObjectUser, "3")
+	activated.Current = map[string]interface{}{"active_flag": true}
+
+	tests := []struct {
+		name    string
+		webhook request.PipedriveWebhook
+		call    interface{}
+	}{
+		{
+			name:    "delete deactivated users",
+			webhook: deactivated,
+			call:    request.NewUserIdentity(1, 3),
+		},
+		{
+			name:    "delete removed users",
+			webhook: newWebhook(request.WebhookActionDeleted, request.WebhookObjectUser, "3"),
+			call:    request.NewUserIdentity(1, 3),
+		},
+		{
+			name:    "publish deleted files",
+			webhook: newWebhook(request.WebhookActionDeleted, request.WebhookObjectFile, "7"),
+			call: request.DocumentEvent{
+				CompanyID: 1,
+				Action:    request.DocumentEventFileDeleted,
+				FileID:    "7",
+			},
+		},
+		{
+			name:    "publish deleted entities",
+			webhook: newWebhook(request.WebhookActionDeleted, request.EntityDeal, "4"),
+			call: request.DocumentEvent{
+				CompanyID: 1,
+				Action:    request.DocumentEventEntityDeleted,
+				Entity:    request.NewParentEntity(request.EntityDeal, "4"),
+			},
+		},
+		{
+			name:    "publish merged entities",
+			webhook: merged,
+			call: request.DocumentEvent{
+				CompanyID:  1,
+				Action:     request.DocumentEventEntityMerged,
+				Entity:     request.NewParentEntity(request.EntityPerson, "6"),
+				MergedInto: "5",
+			},
+		},
+		{
+			name:    "acknowledge active users",
+			webhook: activated,
+		},
+		{
+			name:    "acknowledge unknown objects",
+			webhook: newWebhook(request.WebhookActionDeleted, "note", "8"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler, calls := newController(nil)
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, newRequest(1, test.webhook.ToJSON()))
+
+			assert.Equal(t, http.StatusOK, rw.Code)
+			if test.call == nil {
+				assert.Empty(t, *calls)
+			} else {
+				assert.Equal(t, []interface{}{test.call}, *calls)
+			}
+		})
+	}
+
+	t.Run("report failures so that Pipedrive retries", func(t *testing.T) {
+		handler, _ := newController(errors.New(`{"id":"test","code":503,"detail":"unavailable","status":"Service Unavailable"}`))
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, newRequest(1, newWebhook(request.WebhookActionDeleted, request.EntityDeal, "4").ToJSON()))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	})
+
+	t.Run("reject malformed bodies", func(t *testing.T) {
+		handler, calls := newController(nil)
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, newRequest(1, []byte("{")))
+
+		assert.Equal(t, http.StatusBadRequest, rw.Code)
+		assert.Empty(t, *calls)
+	})
+
+	t.Run("reject notifications without credentials", func(t *testing.T) {
+		handler, calls := newController(nil)
+		rw := httptest.NewRecorder()
+		r := newRequest(1, newWebhook(request.WebhookActionDeleted, request.EntityDeal, "4").ToJSON())
+		r.Header.Del("Authorization")
+		handler.ServeHTTP(rw, r)
+
+		assert.Equal(t, http.StatusUnauthorized, rw.Code)
+		assert.Empty(t, *calls)
+	})
+
+	t.Run("reject notifications with the client secret", func(t *testing.T) {
+		handler, calls := newController(nil)
+		rw := httptest.NewRecorder()
+		r := newRequest(1, newWebhook(request.WebhookActionDeleted, request.EntityDeal, "4").ToJSON())
+		r.SetBasicAuth(testCredentials.ClientID, testSecret)
+		handler.ServeHTTP(rw, r)
+
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+		assert.Empty(t, *calls)
+	})
+
+	t.Run("reject notifications of another company", func(t *testing.T) {
+		handler, calls := newController(nil)
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, newRequest(2, newWebhook(request.WebhookActionDeleted, request.EntityDeal, "4").ToJSON()))
+
+		assert.Equal(t, http.StatusForbidden, rw.Code)
+		assert.Empty(t, *calls)
+	})
+}
@@ -49,7 +49,7 @@ func (m AuthMiddleware) Protect(next http.Handler) http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
 		signature := strings.ReplaceAll(r.Header.Get("Authorization"), "Basic ", "")
 		if signature == "" {
-			m.logger.Errorf("an unauthorized access to deauth endpoint")
+			m.logger.Errorf("an unauthorized access to %s endpoint", r.URL.Path)
 			rw.WriteHeader(http.StatusUnauthorized)
 			return
 		}
@@ -57,7 +57,7 @@ func (m AuthMiddleware) Protect(next http.Handler) http.HandlerFunc {
 		validSignature := base64.StdEncoding.
 			EncodeToString([]byte(fmt.Sprintf("%s:%s", m.credentials.ClientID, m.credentials.ClientSecret)))
 		if signature != validSignature {
-			logger.Errorf("invalid uninstall signature")
+			logger.Errorf("invalid %s signature", r.URL.Path)
 			logger.Debugf("valid signature is %s whereas signature is %s", validSignature, signature)
 			rw.WriteHeader(http.StatusForbidden)
 			return
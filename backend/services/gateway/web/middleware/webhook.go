@@ -0,0 +1,66 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"net/http"
+	"strconv"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+)
+
+type WebhookMiddleware struct {
+	webhook *shared.WebhookConfig
+	logger  log.Logger
+}
+
+func BuildHandleWebhookMiddleware(
+	webhook *shared.WebhookConfig,
+	logger log.Logger,
+) WebhookMiddleware {
+	return WebhookMiddleware{
+		webhook: webhook,
+		logger:  logger,
+	}
+}
+
+// Protect accepts Pipedrive notifications whose basic auth user is a company id and whose password
+// has been derived from it. The company is passed on so that notifications are only applied to it.
+func (m WebhookMiddleware) Protect(next http.Handler) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok {
+			m.logger.Errorf("an unauthorized access to %s endpoint", r.URL.Path)
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		cid, err := strconv.Atoi(user)
+		if err != nil || cid <= 0 || !hmac.Equal([]byte(password), []byte(m.webhook.Password(cid))) {
+			m.logger.Errorf("invalid %s credentials", r.URL.Path)
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), "X-Pipedrive-Webhook-Company", cid)))
+	}
+}
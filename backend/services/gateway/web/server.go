@@ -29,12 +29,20 @@ import (
 )
 
 type PipedriveHTTPService struct {
-	apiController     controller.ApiController
-	authController    controller.AuthController
-	fileController    controller.FileController
-	authMiddleware    middleware.AuthMiddleware
-	contextMiddleware middleware.ContextMiddleware
-	mux               *chi.Mux
+	apiController      controller.ApiController
+	authController     controller.AuthController
+	fileController     controller.FileController
+	versionController  controller.VersionController
+	convertController  controller.ConvertController
+	webhookController  controller.WebhookController
+	commandController  controller.CommandController
+	sessionController  controller.SessionController
+	templateController controller.TemplateController
+	generateController controller.GenerateController
+	authMiddleware     middleware.AuthMiddleware
+	contextMiddleware  middleware.ContextMiddleware
+	webhookMiddleware  middleware.WebhookMiddleware
+	mux                *chi.Mux
 }
 
 // NewService initializes http server with options.
@@ -42,16 +50,32 @@ func NewServer(
 	apiController controller.ApiController,
 	authController controller.AuthController,
 	fileController controller.FileController,
+	versionController controller.VersionController,
+	convertController controller.ConvertController,
+	webhookController controller.WebhookController,
+	commandController controller.CommandController,
+	sessionController controller.SessionController,
+	templateController controller.TemplateController,
+	generateController controller.GenerateController,
 	authMiddleware middleware.AuthMiddleware,
 	contextMiddleware middleware.ContextMiddleware,
+	webhookMiddleware middleware.WebhookMiddleware,
 ) shttp.ServerEngine {
 	return PipedriveHTTPService{
-		apiController:     apiController,
-		authController:    authController,
-		fileController:    fileController,
-		authMiddleware:    authMiddleware,
-		contextMiddleware: contextMiddleware,
-		mux:               chi.NewRouter(),
+		apiController:      apiController,
+		authController:     authController,
+		fileController:     fileController,
+		versionController:  versionController,
+		convertController:  convertController,
+		webhookController:  webhookController,
+		commandController:  commandController,
+		sessionController:  sessionController,
+		templateController: templateController,
+		generateController: generateController,
+		authMiddleware:     authMiddleware,
+		contextMiddleware:  contextMiddleware,
+		webhookMiddleware:  webhookMiddleware,
+		mux:                chi.NewRouter(),
 	}
 }
 
@@ -88,6 +112,10 @@ func (s *PipedriveHTTPService) InitializeRoutes() {
 			cr.Delete("/auth", s.authMiddleware.Protect(s.authController.BuildDeleteAuth()))
 		})
 
+		r.Route("/webhooks", func(cr chi.Router) {
+			cr.Post("/pipedrive", s.webhookMiddleware.Protect(s.webhookController.BuildPostWebhook()))
+		})
+
 		r.Route("/api", func(cr chi.Router) {
 			cr.Use(func(h http.Handler) http.Handler {
 				return s.contextMiddleware.Protect(h)
@@ -97,10 +125,34 @@ func (s *PipedriveHTTPService) InitializeRoutes() {
 			cr.Post("/settings", s.apiController.BuildPostSettings())
 			cr.Get("/settings", s.apiController.BuildGetSettings())
 			cr.Get("/settings/check", s.apiController.BuildCheckSettings())
+			cr.Get("/editors", s.apiController.BuildGetEditors())
+			cr.Get("/sessions", s.sessionController.BuildGetSessions())
+			cr.Get("/templates", s.templateController.BuildGetTemplates())
+			cr.Post("/templates", s.templateController.BuildPostTemplate())
+			cr.Delete("/templates", s.templateController.BuildDeleteTemplate())
+			cr.Post("/generate", s.generateController.BuildPostGenerate())
+			cr.Get("/versions", s.versionController.BuildGetVersions())
+			cr.Post("/versions/restore", s.versionController.BuildPostRestoreVersion())
+			cr.Get("/history", s.versionController.BuildGetHistory())
+			cr.Get("/history/data", s.versionController.BuildGetHistoryData())
+			cr.Post("/convert", s.convertController.BuildPostConvert())
+			cr.Route("/commands", func(ccr chi.Router) {
+				ccr.Post("/forcesave", s.commandController.BuildPostForcesave())
+				ccr.Post("/drop", s.commandController.BuildPostDrop())
+				ccr.Post("/info", s.commandController.BuildPostInfo())
+				ccr.Post("/meta", s.commandController.BuildPostMeta())
+				ccr.Get("/version", s.commandController.BuildGetVersion())
+				ccr.Get("/license", s.commandController.BuildGetLicense())
+				ccr.Get("/forgotten", s.commandController.BuildGetForgotten())
+				ccr.Delete("/forgotten", s.commandController.BuildDeleteForgotten())
+			})
 		})
 
 		r.Route("/files", func(fr chi.Router) {
-			fr.Get("/download", s.fileController.BuildGetDownloadUrl())
+			fr.Get("/download", s.contextMiddleware.Protect(s.fileController.BuildGetDownloadUrl()))
+			fr.Get("/proxy", s.fileController.BuildGetProxyFile())
+			fr.Head("/proxy", s.fileController.BuildGetProxyFile())
+			fr.Get("/changes", s.versionController.BuildGetChanges())
 			fr.Get("/create", s.contextMiddleware.Protect(s.fileController.BuildGetFile()))
 		})
 	})
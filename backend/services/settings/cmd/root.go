@@ -27,6 +27,7 @@ import (
 func GetCommands() cli.Commands {
 	return []*cli.Command{
 		Server(),
+		Rotate(),
 	}
 }
 
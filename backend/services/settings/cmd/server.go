@@ -26,6 +26,7 @@ import (
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/service"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/handler"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
 	"github.com/urfave/cli/v2"
 )
 
@@ -49,11 +50,15 @@ func Server() *cli.Command {
 			app := pkg.NewBootstrapper(CONFIG_PATH, pkg.WithModules(
 				rpc.NewService, web.NewDocserverRPCServer,
 				adapter.BuildNewSettingsAdapter,
+				adapter.BuildNewTemplateAdapter,
 				service.NewSettingsService,
+				service.NewTemplateService,
 				handler.NewSettingsSelectHandler,
 				handler.NewSettingsInsertHandler,
 				handler.NewSettingsDeleteHandler,
+				handler.NewTemplateHandler,
 				shared.BuildNewIntegrationCredentialsConfig(CONFIG_PATH),
+				keyring.BuildNewKeyringConfig(CONFIG_PATH), keyring.NewKeyring,
 			)).Bootstrap()
 
 			if err := app.Err(); err != nil {
@@ -31,3 +31,12 @@ func BuildNewSettingsAdapter(config *config.StorageConfig) port.DocSettingsServi
 
 	return adapter
 }
+
+func BuildNewTemplateAdapter(config *config.StorageConfig) port.DocTemplateServiceAdapter {
+	adapter := NewMemoryTemplateAdapter()
+	if config.Storage.URL != "" {
+		adapter = NewMongoTemplateAdapter(config.Storage.URL)
+	}
+
+	return adapter
+}
@@ -23,4 +23,6 @@ import "errors"
 var (
 	ErrNoCompanySettings = errors.New("no company settings")
 	ErrInvalidCompanyID  = errors.New("invalid cid format")
+	ErrSettingsModified  = errors.New("settings have been modified concurrently")
+	ErrNoCompanyTemplate = errors.New("no company template")
 )
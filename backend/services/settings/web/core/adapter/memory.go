@@ -76,10 +76,6 @@ func (m *memoryDocserverAdapter) UpsertSettings(ctx context.Context, settings do
 }
 
 func (m *memoryDocserverAdapter) DeleteSettings(ctx context.Context, cid string) error {
-	if _, ok := m.kvs[cid]; !ok {
-		return ErrNoCompanySettings
-	}
-
 	delete(m.kvs, cid)
 
 	return nil
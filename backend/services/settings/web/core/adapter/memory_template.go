@@ -0,0 +1,106 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/port"
+)
+
+type memoryTemplateAdapter struct {
+	mu        sync.Mutex
+	templates map[string]map[string]domain.DocTemplate
+}
+
+func NewMemoryTemplateAdapter() port.DocTemplateServiceAdapter {
+	return &memoryTemplateAdapter{
+		templates: make(map[string]map[string]domain.DocTemplate),
+	}
+}
+
+func (m *memoryTemplateAdapter) InsertTemplate(ctx context.Context, template domain.DocTemplate) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.templates[template.CompanyID]; !ok {
+		m.templates[template.CompanyID] = make(map[string]domain.DocTemplate)
+	}
+
+	m.templates[template.CompanyID][template.ID] = template
+	return nil
+}
+
+func (m *memoryTemplateAdapter) SelectTemplates(ctx context.Context, cid string) ([]domain.DocTemplate, error) {
+	cid = strings.TrimSpace(cid)
+	if cid == "" {
+		return nil, ErrInvalidCompanyID
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	templates := make([]domain.DocTemplate, 0, len(m.templates[cid]))
+	for _, template := range m.templates[cid] {
+		template.Content = nil
+		templates = append(templates, template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+func (m *memoryTemplateAdapter) SelectTemplate(ctx context.Context, cid, id string) (domain.DocTemplate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	template, ok := m.templates[cid][id]
+	if !ok {
+		return template, ErrNoCompanyTemplate
+	}
+
+	return template, nil
+}
+
+func (m *memoryTemplateAdapter) DeleteTemplate(ctx context.Context, cid, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.templates[cid], id)
+	return nil
+}
+
+func (m *memoryTemplateAdapter) DeleteTemplates(ctx context.Context, cid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.templates, cid)
+	return nil
+}
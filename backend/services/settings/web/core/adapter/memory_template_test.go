@@ -0,0 +1,80 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryTemplateAdapter(t *testing.T) {
+	adapter := NewMemoryTemplateAdapter()
+
+	t.Run("save a template with an invalid file type", func(t *testing.T) {
+		assert.Error(t, adapter.InsertTemplate(context.Background(), domain.DocTemplate{
+			ID: "1", CompanyID: "mock", Name: "mock", FileType: "exe", Content: []byte("mock"),
+		}))
+	})
+
+	t.Run("save templates", func(t *testing.T) {
+		assert.NoError(t, adapter.InsertTemplate(context.Background(), domain.DocTemplate{
+			ID: "1", CompanyID: "mock", Name: "NDA", FileType: "docx", Content: []byte("mock"),
+		}))
+		assert.NoError(t, adapter.InsertTemplate(context.Background(), domain.DocTemplate{
+			ID: "2", CompanyID: "mock", Name: "Contract.docx", FileType: ".DOCX", Lang: "de", Content: []byte("mock"),
+		}))
+	})
+
+	t.Run("list templates without contents", func(t *testing.T) {
+		templates, err := adapter.SelectTemplates(context.Background(), "mock")
+		assert.NoError(t, err)
+		assert.Len(t, templates, 2)
+		assert.Equal(t, "Contract.docx", templates[0].Name)
+		assert.Equal(t, "docx", templates[0].FileType)
+		assert.Equal(t, 4, templates[0].Size)
+		assert.Empty(t, templates[0].Content)
+	})
+
+	t.Run("get a template with its content", func(t *testing.T) {
+		template, err := adapter.SelectTemplate(context.Background(), "mock", "2")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("mock"), template.Content)
+		assert.Equal(t, "Contract.docx", template.Filename())
+	})
+
+	t.Run("get another company template", func(t *testing.T) {
+		_, err := adapter.SelectTemplate(context.Background(), "another", "2")
+		assert.ErrorIs(t, err, ErrNoCompanyTemplate)
+	})
+
+	t.Run("delete templates", func(t *testing.T) {
+		assert.NoError(t, adapter.DeleteTemplate(context.Background(), "mock", "1"))
+		templates, err := adapter.SelectTemplates(context.Background(), "mock")
+		assert.NoError(t, err)
+		assert.Len(t, templates, 1)
+
+		assert.NoError(t, adapter.DeleteTemplates(context.Background(), "mock"))
+		templates, err = adapter.SelectTemplates(context.Background(), "mock")
+		assert.NoError(t, err)
+		assert.Empty(t, templates)
+	})
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/domain"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
 	"github.com/kamva/mgm/v3"
 	"github.com/kamva/mgm/v3/operator"
 	"go.mongodb.org/mongo-driver/bson"
@@ -34,13 +35,18 @@ import (
 )
 
 type docSettingsCollection struct {
-	mgm.DefaultModel `bson:",inline"`
-	CompanyID        string    `json:"company_id" bson:"company_id"`
-	DocAddress       string    `json:"doc_address" bson:"doc_address"`
-	DocSecret        string    `json:"doc_secret" bson:"doc_secret"`
-	DocHeader        string    `json:"doc_header" bson:"doc_header"`
-	DemoEnabled      bool      `json:"demo_enabled" bson:"demo_enabled"`
-	DemoStarted      time.Time `json:"demo_started" bson:"demo_started"`
+	mgm.DefaultModel  `bson:",inline"`
+	CompanyID         string                               `json:"company_id" bson:"company_id"`
+	DocAddress        string                               `json:"doc_address" bson:"doc_address"`
+	DocSecret         string                               `json:"doc_secret" bson:"doc_secret"`
+	DocHeader         string                               `json:"doc_header" bson:"doc_header"`
+	DemoEnabled       bool                                 `json:"demo_enabled" bson:"demo_enabled"`
+	AutoConvert       bool                                 `json:"auto_convert" bson:"auto_convert"`
+	ReplaceBlankForms bool                                 `json:"replace_blank_forms" bson:"replace_blank_forms"`
+	LossyEdit         string                               `json:"lossy_edit" bson:"lossy_edit"`
+	Permissions       request.PermissionsPolicy            `json:"permissions" bson:"permissions"`
+	RolePermissions   map[string]request.PermissionsPolicy `json:"role_permissions,omitempty" bson:"role_permissions"`
+	DemoStarted       time.Time                            `json:"demo_started" bson:"demo_started"`
 }
 
 type mongoUserAdapter struct {
@@ -64,12 +70,17 @@ func (m *mongoUserAdapter) save(ctx context.Context, settings domain.DocSettings
 
 		if err := collection.FirstWithCtx(ctx, bson.M{"company_id": settings.CompanyID}, u); err != nil {
 			if cerr := collection.CreateWithCtx(ctx, &docSettingsCollection{
-				CompanyID:   settings.CompanyID,
-				DocAddress:  settings.DocAddress,
-				DocSecret:   settings.DocSecret,
-				DocHeader:   settings.DocHeader,
-				DemoEnabled: settings.DemoEnabled,
-				DemoStarted: settings.DemoStarted,
+				CompanyID:         settings.CompanyID,
+				DocAddress:        settings.DocAddress,
+				DocSecret:         settings.DocSecret,
+				DocHeader:         settings.DocHeader,
+				DemoEnabled:       settings.DemoEnabled,
+				AutoConvert:       settings.AutoConvert,
+				ReplaceBlankForms: settings.ReplaceBlankForms,
+				LossyEdit:         settings.LossyEdit,
+				Permissions:       settings.Permissions,
+				RolePermissions:   settings.RolePermissions,
+				DemoStarted:       settings.DemoStarted,
 			}); cerr != nil {
 				return cerr
 			}
@@ -82,6 +93,11 @@ func (m *mongoUserAdapter) save(ctx context.Context, settings domain.DocSettings
 		u.DocSecret = settings.DocSecret
 		u.DocHeader = settings.DocHeader
 		u.DemoEnabled = settings.DemoEnabled
+		u.AutoConvert = settings.AutoConvert
+		u.ReplaceBlankForms = settings.ReplaceBlankForms
+		u.LossyEdit = settings.LossyEdit
+		u.Permissions = settings.Permissions
+		u.RolePermissions = settings.RolePermissions
 		if u.DemoStarted.IsZero() {
 			u.DemoStarted = settings.DemoStarted
 		}
@@ -119,12 +135,17 @@ func (m *mongoUserAdapter) SelectSettings(ctx context.Context, cid string) (doma
 	}
 
 	return domain.DocSettings{
-		CompanyID:   settings.CompanyID,
-		DocAddress:  settings.DocAddress,
-		DocSecret:   settings.DocSecret,
-		DocHeader:   settings.DocHeader,
-		DemoEnabled: settings.DemoEnabled,
-		DemoStarted: settings.DemoStarted,
+		CompanyID:         settings.CompanyID,
+		DocAddress:        settings.DocAddress,
+		DocSecret:         settings.DocSecret,
+		DocHeader:         settings.DocHeader,
+		DemoEnabled:       settings.DemoEnabled,
+		AutoConvert:       settings.AutoConvert,
+		ReplaceBlankForms: settings.ReplaceBlankForms,
+		LossyEdit:         settings.LossyEdit,
+		Permissions:       settings.Permissions,
+		RolePermissions:   settings.RolePermissions,
+		DemoStarted:       settings.DemoStarted,
 	}, nil
 }
 
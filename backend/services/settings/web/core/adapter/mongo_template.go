@@ -0,0 +1,164 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/port"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type docTemplateCollection struct {
+	mgm.DefaultModel `bson:",inline"`
+	TemplateID       string `json:"template_id" bson:"template_id"`
+	CompanyID        string `json:"company_id" bson:"company_id"`
+	Name             string `json:"name" bson:"name"`
+	FileType         string `json:"file_type" bson:"file_type"`
+	Lang             string `json:"lang" bson:"lang"`
+	Size             int    `json:"size" bson:"size"`
+	Content          []byte `json:"content,omitempty" bson:"content,omitempty"`
+	CreatedBy        string `json:"created_by" bson:"created_by"`
+}
+
+func (t docTemplateCollection) toTemplate() domain.DocTemplate {
+	return domain.DocTemplate{
+		ID:        t.TemplateID,
+		CompanyID: t.CompanyID,
+		Name:      t.Name,
+		FileType:  t.FileType,
+		Lang:      t.Lang,
+		Size:      t.Size,
+		Content:   t.Content,
+		CreatedBy: t.CreatedBy,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+type mongoTemplateAdapter struct {
+}
+
+func NewMongoTemplateAdapter(url string) port.DocTemplateServiceAdapter {
+	if err := mgm.SetDefaultConfig(
+		&mgm.Config{CtxTimeout: 3 * time.Second}, "pipedrive",
+		options.Client().ApplyURI(url),
+	); err != nil {
+		log.Fatalf("mongo initialization error: %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := mgm.Coll(&docTemplateCollection{}).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "template_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		log.Fatalf("mongo index initialization error: %s", err.Error())
+	}
+
+	return &mongoTemplateAdapter{}
+}
+
+func (m *mongoTemplateAdapter) InsertTemplate(ctx context.Context, template domain.DocTemplate) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
+	return mgm.Coll(&docTemplateCollection{}).CreateWithCtx(ctx, &docTemplateCollection{
+		TemplateID: template.ID,
+		CompanyID:  template.CompanyID,
+		Name:       template.Name,
+		FileType:   template.FileType,
+		Lang:       template.Lang,
+		Size:       template.Size,
+		Content:    template.Content,
+		CreatedBy:  template.CreatedBy,
+	})
+}
+
+// SelectTemplates leaves the contents out, so listing large templates stays cheap.
+func (m *mongoTemplateAdapter) SelectTemplates(ctx context.Context, cid string) ([]domain.DocTemplate, error) {
+	cid = strings.TrimSpace(cid)
+	if cid == "" {
+		return nil, ErrInvalidCompanyID
+	}
+
+	var records []docTemplateCollection
+	if err := mgm.Coll(&docTemplateCollection{}).SimpleFindWithCtx(
+		ctx, &records, bson.M{"company_id": cid},
+		options.Find().
+			SetProjection(bson.M{"content": 0}).
+			SetSort(bson.D{{Key: "name", Value: 1}}),
+	); err != nil {
+		return nil, err
+	}
+
+	templates := make([]domain.DocTemplate, 0, len(records))
+	for _, record := range records {
+		templates = append(templates, record.toTemplate())
+	}
+
+	return templates, nil
+}
+
+func (m *mongoTemplateAdapter) SelectTemplate(ctx context.Context, cid, id string) (domain.DocTemplate, error) {
+	cid, id = strings.TrimSpace(cid), strings.TrimSpace(id)
+	if cid == "" {
+		return domain.DocTemplate{}, ErrInvalidCompanyID
+	}
+
+	template := &docTemplateCollection{}
+	if err := mgm.Coll(template).FirstWithCtx(ctx, bson.M{"company_id": cid, "template_id": id}, template); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.DocTemplate{}, ErrNoCompanyTemplate
+		}
+
+		return domain.DocTemplate{}, err
+	}
+
+	return template.toTemplate(), nil
+}
+
+func (m *mongoTemplateAdapter) DeleteTemplate(ctx context.Context, cid, id string) error {
+	cid = strings.TrimSpace(cid)
+	if cid == "" {
+		return ErrInvalidCompanyID
+	}
+
+	_, err := mgm.Coll(&docTemplateCollection{}).DeleteOne(ctx, bson.M{"company_id": cid, "template_id": id})
+	return err
+}
+
+func (m *mongoTemplateAdapter) DeleteTemplates(ctx context.Context, cid string) error {
+	cid = strings.TrimSpace(cid)
+	if cid == "" {
+		return ErrInvalidCompanyID
+	}
+
+	_, err := mgm.Coll(&docTemplateCollection{}).DeleteMany(ctx, bson.M{"company_id": cid})
+	return err
+}
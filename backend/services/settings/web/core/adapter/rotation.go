@@ -0,0 +1,101 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package adapter
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/port"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoSettingsKeyRotationAdapter struct {
+}
+
+func NewMongoSettingsKeyRotationAdapter(url string) port.DocSettingsKeyRotationAdapter {
+	if err := mgm.SetDefaultConfig(
+		&mgm.Config{CtxTimeout: 3 * time.Second}, "pipedrive",
+		options.Client().ApplyURI(url),
+	); err != nil {
+		log.Fatalf("mongo initialization error: %s", err.Error())
+	}
+
+	return &mongoSettingsKeyRotationAdapter{}
+}
+
+func (m *mongoSettingsKeyRotationAdapter) SelectAllSettings(ctx context.Context) ([]domain.DocSettings, error) {
+	var settings []docSettingsCollection
+	if err := mgm.Coll(&docSettingsCollection{}).SimpleFindWithCtx(ctx, &settings, bson.M{}); err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.DocSettings, 0, len(settings))
+	for _, s := range settings {
+		result = append(result, domain.DocSettings{
+			CompanyID:         s.CompanyID,
+			DocAddress:        s.DocAddress,
+			DocSecret:         s.DocSecret,
+			DocHeader:         s.DocHeader,
+			DemoEnabled:       s.DemoEnabled,
+			AutoConvert:       s.AutoConvert,
+			ReplaceBlankForms: s.ReplaceBlankForms,
+			LossyEdit:         s.LossyEdit,
+			Permissions:       s.Permissions,
+			RolePermissions:   s.RolePermissions,
+			DemoStarted:       s.DemoStarted,
+		})
+	}
+
+	return result, nil
+}
+
+// RotateSettings replaces the encrypted document secret only if it still matches
+// the previously selected one, so that secrets updated in the meantime are never overwritten.
+func (m *mongoSettingsKeyRotationAdapter) RotateSettings(ctx context.Context, previous, rotated domain.DocSettings) error {
+	if previous.CompanyID == "" {
+		return ErrInvalidCompanyID
+	}
+
+	return mgm.Transaction(func(session mongo.Session, sc mongo.SessionContext) error {
+		s := &docSettingsCollection{}
+		collection := mgm.Coll(&docSettingsCollection{})
+		if err := collection.FirstWithCtx(sc, bson.M{"company_id": previous.CompanyID}, s); err != nil {
+			return err
+		}
+
+		if s.DocSecret != previous.DocSecret {
+			return ErrSettingsModified
+		}
+
+		s.DocSecret = rotated.DocSecret
+		s.UpdatedAt = time.Now()
+
+		if err := collection.UpdateWithCtx(sc, s); err != nil {
+			return err
+		}
+
+		return session.CommitTransaction(sc)
+	})
+}
@@ -24,15 +24,23 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
 )
 
 type DocSettings struct {
-	CompanyID   string    `json:"company_id" mapstructure:"company_id"`
-	DocAddress  string    `json:"doc_address" mapstructure:"doc_address"`
-	DocSecret   string    `json:"doc_secret" mapstructure:"doc_secret"`
-	DocHeader   string    `json:"doc_header" mapstructure:"doc_header"`
-	DemoEnabled bool      `json:"demo_enabled" mapstructure:"demo_enabled"`
-	DemoStarted time.Time `json:"demo_started" mapstructure:"demo_started"`
+	CompanyID         string                               `json:"company_id" mapstructure:"company_id"`
+	DocAddress        string                               `json:"doc_address" mapstructure:"doc_address"`
+	DocSecret         string                               `json:"doc_secret" mapstructure:"doc_secret"`
+	DocHeader         string                               `json:"doc_header" mapstructure:"doc_header"`
+	DemoEnabled       bool                                 `json:"demo_enabled" mapstructure:"demo_enabled"`
+	AutoConvert       bool                                 `json:"auto_convert" mapstructure:"auto_convert"`
+	ReplaceBlankForms bool                                 `json:"replace_blank_forms" mapstructure:"replace_blank_forms"`
+	LossyEdit         string                               `json:"lossy_edit" mapstructure:"lossy_edit"`
+	Permissions       request.PermissionsPolicy            `json:"permissions" mapstructure:"permissions"`
+	RolePermissions   map[string]request.PermissionsPolicy `json:"role_permissions,omitempty" mapstructure:"role_permissions"`
+	DemoStarted       time.Time                            `json:"demo_started" mapstructure:"demo_started"`
 }
 
 func (u DocSettings) ToJSON() []byte {
@@ -45,6 +53,7 @@ func (u *DocSettings) Validate() error {
 	u.DocAddress = strings.TrimSpace(u.DocAddress)
 	u.DocSecret = strings.TrimSpace(u.DocSecret)
 	u.DocHeader = strings.TrimSpace(u.DocHeader)
+	u.LossyEdit = strings.ToLower(strings.TrimSpace(u.LossyEdit))
 
 	if u.CompanyID == "" {
 		return &InvalidModelFieldError{
@@ -54,6 +63,39 @@ func (u *DocSettings) Validate() error {
 		}
 	}
 
+	if !constants.IsLossyEditPolicy(u.LossyEdit) {
+		return &InvalidModelFieldError{
+			Model:  "Docserver",
+			Field:  "Lossy Edit",
+			Reason: "Should be one of never, ask or always",
+		}
+	}
+
+	if u.LossyEdit == "" {
+		u.LossyEdit = constants.LossyEditNever
+	}
+
+	if err := u.Permissions.Validate(); err != nil {
+		return &InvalidModelFieldError{
+			Model:  "Docserver",
+			Field:  "Permissions",
+			Reason: "Mode should be one of edit, review, comment or view",
+		}
+	}
+
+	u.Permissions = u.Permissions.Normalize()
+	for set, policy := range u.RolePermissions {
+		if err := policy.Validate(); err != nil {
+			return &InvalidModelFieldError{
+				Model:  "Docserver",
+				Field:  "Role Permissions",
+				Reason: fmt.Sprintf("Permission set %s mode should be one of edit, review, comment or view", set),
+			}
+		}
+
+		u.RolePermissions[set] = policy.Normalize()
+	}
+
 	hasCredentials := u.DocAddress != "" && u.DocSecret != "" && u.DocHeader != ""
 	if hasCredentials {
 		url, err := url.Parse(u.DocAddress)
@@ -0,0 +1,112 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
+)
+
+// DocTemplate is a company file new documents can be created from. Templates without
+// a language are offered for every language.
+type DocTemplate struct {
+	ID        string    `json:"id" mapstructure:"id"`
+	CompanyID string    `json:"company_id" mapstructure:"company_id"`
+	Name      string    `json:"name" mapstructure:"name"`
+	FileType  string    `json:"file_type" mapstructure:"file_type"`
+	Lang      string    `json:"lang" mapstructure:"lang"`
+	Size      int       `json:"size" mapstructure:"size"`
+	Content   []byte    `json:"content,omitempty" mapstructure:"content"`
+	CreatedBy string    `json:"created_by" mapstructure:"created_by"`
+	CreatedAt time.Time `json:"created_at" mapstructure:"created_at"`
+}
+
+func (t DocTemplate) ToJSON() []byte {
+	buf, _ := json.Marshal(t)
+	return buf
+}
+
+// Filename is the name of a file created from the template.
+func (t DocTemplate) Filename() string {
+	return fmt.Sprintf("%s.%s", strings.TrimSuffix(t.Name, filepath.Ext(t.Name)), t.FileType)
+}
+
+func (t *DocTemplate) Validate() error {
+	t.ID = strings.TrimSpace(t.ID)
+	t.CompanyID = strings.TrimSpace(t.CompanyID)
+	t.Name = strings.TrimSpace(t.Name)
+	t.FileType = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(t.FileType), "."))
+	t.Lang = strings.TrimSpace(t.Lang)
+
+	if t.ID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Template",
+			Field:  "ID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if t.CompanyID == "" {
+		return &InvalidModelFieldError{
+			Model:  "Template",
+			Field:  "CompanyID",
+			Reason: "Should not be empty",
+		}
+	}
+
+	if t.Name == "" || len(t.Name) > 190 {
+		return &InvalidModelFieldError{
+			Model:  "Template",
+			Field:  "Name",
+			Reason: "Should not be empty or longer than 190 characters",
+		}
+	}
+
+	if !constants.TemplateFileTypes[t.FileType] {
+		return &InvalidModelFieldError{
+			Model:  "Template",
+			Field:  "File Type",
+			Reason: "Should be one of docx, xlsx, pptx or pdf",
+		}
+	}
+
+	if len(t.Lang) > 10 {
+		return &InvalidModelFieldError{
+			Model:  "Template",
+			Field:  "Lang",
+			Reason: "Should be a language code",
+		}
+	}
+
+	if len(t.Content) == 0 || len(t.Content) > constants.MaxTemplateSize {
+		return &InvalidModelFieldError{
+			Model:  "Template",
+			Field:  "Content",
+			Reason: "Should not be empty or larger than the template size limit",
+		}
+	}
+
+	t.Size = len(t.Content)
+	return nil
+}
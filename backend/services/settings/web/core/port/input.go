@@ -30,3 +30,16 @@ type DocSettingsService interface {
 	UpdateSettings(ctx context.Context, settings domain.DocSettings) (domain.DocSettings, error)
 	RemoveSettings(ctx context.Context, cid string) error
 }
+
+type DocSettingsKeyRotationService interface {
+	RotateSettings(ctx context.Context) (int, error)
+}
+
+type DocTemplateService interface {
+	CreateTemplate(ctx context.Context, template domain.DocTemplate) (domain.DocTemplate, error)
+	// GetTemplates lists the templates of a file type and language. Blank filters match every template.
+	GetTemplates(ctx context.Context, cid, fileType, lang string) ([]domain.DocTemplate, error)
+	GetTemplate(ctx context.Context, cid, id string) (domain.DocTemplate, error)
+	RemoveTemplate(ctx context.Context, cid, id string) error
+	RemoveTemplates(ctx context.Context, cid string) error
+}
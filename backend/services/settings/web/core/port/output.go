@@ -30,3 +30,17 @@ type DocSettingsServiceAdapter interface {
 	UpsertSettings(ctx context.Context, settings domain.DocSettings) (domain.DocSettings, error)
 	DeleteSettings(ctx context.Context, cid string) error
 }
+
+type DocSettingsKeyRotationAdapter interface {
+	SelectAllSettings(ctx context.Context) ([]domain.DocSettings, error)
+	RotateSettings(ctx context.Context, previous, rotated domain.DocSettings) error
+}
+
+// DocTemplateServiceAdapter only returns template contents when a single template is selected.
+type DocTemplateServiceAdapter interface {
+	InsertTemplate(ctx context.Context, template domain.DocTemplate) error
+	SelectTemplates(ctx context.Context, cid string) ([]domain.DocTemplate, error)
+	SelectTemplate(ctx context.Context, cid, id string) (domain.DocTemplate, error)
+	DeleteTemplate(ctx context.Context, cid, id string) error
+	DeleteTemplates(ctx context.Context, cid string) error
+}
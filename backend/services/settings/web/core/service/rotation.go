@@ -0,0 +1,78 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
+)
+
+type settingsKeyRotationService struct {
+	adapter port.DocSettingsKeyRotationAdapter
+	keyring keyring.Keyring
+	logger  plog.Logger
+}
+
+func NewSettingsKeyRotationService(
+	adapter port.DocSettingsKeyRotationAdapter,
+	keyring keyring.Keyring,
+	logger plog.Logger,
+) port.DocSettingsKeyRotationService {
+	return settingsKeyRotationService{
+		adapter: adapter,
+		keyring: keyring,
+		logger:  logger,
+	}
+}
+
+// RotateSettings re-encrypts document secrets that were not encrypted with the active key.
+func (s settingsKeyRotationService) RotateSettings(ctx context.Context) (int, error) {
+	settings, err := s.adapter.SelectAllSettings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return keyring.RecordRotation[domain.DocSettings]{
+		Keyring: s.keyring,
+		Logger:  s.logger,
+		Describe: func(settings domain.DocSettings) string {
+			return fmt.Sprintf("company %s settings", settings.CompanyID)
+		},
+		Ciphertexts: func(settings domain.DocSettings) []string {
+			return []string{settings.DocSecret}
+		},
+		Rotate: s.rotateSettings,
+	}.Run(ctx, settings)
+}
+
+func (s settingsKeyRotationService) rotateSettings(ctx context.Context, settings domain.DocSettings) error {
+	secret, err := keyring.Reencrypt(s.keyring, settings.DocSecret)
+	if err != nil {
+		return err
+	}
+
+	rotated := settings
+	rotated.DocSecret = secret
+	return s.adapter.RotateSettings(ctx, settings, rotated)
+}
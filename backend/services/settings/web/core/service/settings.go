@@ -23,36 +23,32 @@ import (
 	"strings"
 	"time"
 
-	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
 	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/domain"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
 	"github.com/mitchellh/mapstructure"
 	"go-micro.dev/v4/cache"
-	"golang.org/x/oauth2"
 )
 
 type settingsService struct {
-	adapter     port.DocSettingsServiceAdapter
-	encryptor   crypto.Encryptor
-	cache       cache.Cache
-	credentials *oauth2.Config
-	logger      plog.Logger
+	adapter port.DocSettingsServiceAdapter
+	keyring keyring.Keyring
+	cache   cache.Cache
+	logger  plog.Logger
 }
 
 func NewSettingsService(
 	adapter port.DocSettingsServiceAdapter,
-	encryptor crypto.Encryptor,
+	keyring keyring.Keyring,
 	cache cache.Cache,
-	credentials *oauth2.Config,
 	logger plog.Logger,
 ) port.DocSettingsService {
 	return settingsService{
-		adapter:     adapter,
-		encryptor:   encryptor,
-		cache:       cache,
-		credentials: credentials,
-		logger:      logger,
+		adapter: adapter,
+		keyring: keyring,
+		cache:   cache,
+		logger:  logger,
 	}
 }
 
@@ -62,19 +58,24 @@ func (s settingsService) CreateSettings(ctx context.Context, settings domain.Doc
 		return err
 	}
 
-	esecret, err := s.encryptor.Encrypt(settings.DocSecret, []byte(s.credentials.ClientSecret))
+	esecret, err := s.keyring.Encrypt(settings.DocSecret)
 	if err != nil {
 		return err
 	}
 
 	s.logger.Debugf("settings %s are valid. Persisting to database", settings.CompanyID)
 	if err := s.adapter.InsertSettings(ctx, domain.DocSettings{
-		CompanyID:   settings.CompanyID,
-		DocAddress:  settings.DocAddress,
-		DocSecret:   esecret,
-		DocHeader:   settings.DocHeader,
-		DemoEnabled: settings.DemoEnabled,
-		DemoStarted: settings.DemoStarted,
+		CompanyID:         settings.CompanyID,
+		DocAddress:        settings.DocAddress,
+		DocSecret:         esecret,
+		DocHeader:         settings.DocHeader,
+		DemoEnabled:       settings.DemoEnabled,
+		AutoConvert:       settings.AutoConvert,
+		ReplaceBlankForms: settings.ReplaceBlankForms,
+		LossyEdit:         settings.LossyEdit,
+		Permissions:       settings.Permissions,
+		RolePermissions:   settings.RolePermissions,
+		DemoStarted:       settings.DemoStarted,
 	}); err != nil {
 		return err
 	}
@@ -111,18 +112,23 @@ func (s settingsService) GetSettings(ctx context.Context, cid string) (domain.Do
 	}
 
 	s.logger.Debugf("found settings: %v", settings)
-	dsecret, err := s.encryptor.Decrypt(settings.DocSecret, []byte(s.credentials.ClientSecret))
+	dsecret, err := s.keyring.Decrypt(settings.DocSecret)
 	if err != nil {
 		return settings, err
 	}
 
 	return domain.DocSettings{
-		CompanyID:   cid,
-		DocAddress:  settings.DocAddress,
-		DocSecret:   dsecret,
-		DocHeader:   settings.DocHeader,
-		DemoEnabled: settings.DemoEnabled,
-		DemoStarted: settings.DemoStarted,
+		CompanyID:         cid,
+		DocAddress:        settings.DocAddress,
+		DocSecret:         dsecret,
+		DocHeader:         settings.DocHeader,
+		DemoEnabled:       settings.DemoEnabled,
+		AutoConvert:       settings.AutoConvert,
+		ReplaceBlankForms: settings.ReplaceBlankForms,
+		LossyEdit:         settings.LossyEdit,
+		Permissions:       settings.Permissions,
+		RolePermissions:   settings.RolePermissions,
+		DemoStarted:       settings.DemoStarted,
 	}, nil
 }
 
@@ -147,19 +153,24 @@ func (s settingsService) UpdateSettings(ctx context.Context, settings domain.Doc
 		}
 	}
 
-	esecret, err := s.encryptor.Encrypt(settings.DocSecret, []byte(s.credentials.ClientSecret))
+	esecret, err := s.keyring.Encrypt(settings.DocSecret)
 	if err != nil {
 		return settings, err
 	}
 
 	s.logger.Debugf("settings %s are valid to perform an update action", settings.CompanyID)
 	if _, err := s.adapter.UpsertSettings(ctx, domain.DocSettings{
-		CompanyID:   settings.CompanyID,
-		DocAddress:  settings.DocAddress,
-		DocSecret:   esecret,
-		DocHeader:   settings.DocHeader,
-		DemoEnabled: settings.DemoEnabled,
-		DemoStarted: settings.DemoStarted,
+		CompanyID:         settings.CompanyID,
+		DocAddress:        settings.DocAddress,
+		DocSecret:         esecret,
+		DocHeader:         settings.DocHeader,
+		DemoEnabled:       settings.DemoEnabled,
+		AutoConvert:       settings.AutoConvert,
+		ReplaceBlankForms: settings.ReplaceBlankForms,
+		LossyEdit:         settings.LossyEdit,
+		Permissions:       settings.Permissions,
+		RolePermissions:   settings.RolePermissions,
+		DemoStarted:       settings.DemoStarted,
 	}); err != nil {
 		return settings, err
 	}
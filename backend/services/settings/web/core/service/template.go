@@ -0,0 +1,136 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/port"
+	"github.com/google/uuid"
+)
+
+type templateService struct {
+	adapter port.DocTemplateServiceAdapter
+	logger  plog.Logger
+}
+
+func NewTemplateService(
+	adapter port.DocTemplateServiceAdapter,
+	logger plog.Logger,
+) port.DocTemplateService {
+	return templateService{
+		adapter: adapter,
+		logger:  logger,
+	}
+}
+
+func (s templateService) CreateTemplate(ctx context.Context, template domain.DocTemplate) (domain.DocTemplate, error) {
+	template.ID = uuid.NewString()
+	template.CreatedAt = time.Now()
+	s.logger.Debugf("validating company %s template %s to perform a persist action", template.CompanyID, template.Name)
+	if err := template.Validate(); err != nil {
+		return template, err
+	}
+
+	if ctx.Err() != nil {
+		return template, ErrOperationTimeout
+	}
+
+	if err := s.adapter.InsertTemplate(ctx, template); err != nil {
+		return template, err
+	}
+
+	template.Content = nil
+	return template, nil
+}
+
+func (s templateService) GetTemplates(ctx context.Context, cid, fileType, lang string) ([]domain.DocTemplate, error) {
+	cid = strings.TrimSpace(cid)
+	if cid == "" {
+		return nil, &InvalidServiceParameterError{
+			Name:   "CID",
+			Reason: "Should not be blank",
+		}
+	}
+
+	templates, err := s.adapter.SelectTemplates(ctx, cid)
+	if err != nil {
+		return nil, err
+	}
+
+	fileType = strings.ToLower(strings.TrimSpace(fileType))
+	lang = strings.TrimSpace(lang)
+	filtered := make([]domain.DocTemplate, 0, len(templates))
+	for _, template := range templates {
+		if fileType != "" && template.FileType != fileType {
+			continue
+		}
+
+		if lang != "" && template.Lang != "" && template.Lang != lang {
+			continue
+		}
+
+		filtered = append(filtered, template)
+	}
+
+	return filtered, nil
+}
+
+func (s templateService) GetTemplate(ctx context.Context, cid, id string) (domain.DocTemplate, error) {
+	cid, id = strings.TrimSpace(cid), strings.TrimSpace(id)
+	if cid == "" || id == "" {
+		return domain.DocTemplate{}, &InvalidServiceParameterError{
+			Name:   "Template",
+			Reason: "Should not be blank",
+		}
+	}
+
+	s.logger.Debugf("trying to select company %s template %s", cid, id)
+	return s.adapter.SelectTemplate(ctx, cid, id)
+}
+
+func (s templateService) RemoveTemplate(ctx context.Context, cid, id string) error {
+	cid, id = strings.TrimSpace(cid), strings.TrimSpace(id)
+	if cid == "" || id == "" {
+		return &InvalidServiceParameterError{
+			Name:   "Template",
+			Reason: "Should not be blank",
+		}
+	}
+
+	s.logger.Debugf("removing company %s template %s", cid, id)
+	return s.adapter.DeleteTemplate(ctx, cid, id)
+}
+
+func (s templateService) RemoveTemplates(ctx context.Context, cid string) error {
+	cid = strings.TrimSpace(cid)
+	if cid == "" {
+		return &InvalidServiceParameterError{
+			Name:   "CID",
+			Reason: "Should not be blank",
+		}
+	}
+
+	s.logger.Debugf("removing company %s templates", cid)
+	return s.adapter.DeleteTemplates(ctx, cid)
+}
@@ -28,20 +28,23 @@ import (
 )
 
 type SettingsDeleteHandler struct {
-	service port.DocSettingsService
-	client  client.Client
-	logger  log.Logger
+	service   port.DocSettingsService
+	templates port.DocTemplateService
+	client    client.Client
+	logger    log.Logger
 }
 
 func NewSettingsDeleteHandler(
 	service port.DocSettingsService,
+	templates port.DocTemplateService,
 	client client.Client,
 	logger log.Logger,
 ) SettingsDeleteHandler {
 	return SettingsDeleteHandler{
-		service: service,
-		client:  client,
-		logger:  logger,
+		service:   service,
+		templates: templates,
+		client:    client,
+		logger:    logger,
 	}
 }
 
@@ -53,6 +56,11 @@ func (u SettingsDeleteHandler) DeleteSettings(ctx context.Context, cid *string,
 			return nil, err
 		}
 
+		if err := u.templates.RemoveTemplates(ctx, *cid); err != nil {
+			u.logger.Debugf("could not delete company %s templates: %s", *cid, err.Error())
+			return nil, err
+		}
+
 		return nil, nil
 	})
 
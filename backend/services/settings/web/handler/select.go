@@ -59,11 +59,16 @@ func (u SettingsSelectHandler) GetSettings(ctx context.Context, cid *string, res
 
 	if set, ok := settings.(domain.DocSettings); ok {
 		*res = response.DocSettingsResponse{
-			DocAddress:  set.DocAddress,
-			DocSecret:   set.DocSecret,
-			DocHeader:   set.DocHeader,
-			DemoEnabled: set.DemoEnabled,
-			DemoStarted: set.DemoStarted,
+			DocAddress:        set.DocAddress,
+			DocSecret:         set.DocSecret,
+			DocHeader:         set.DocHeader,
+			DemoEnabled:       set.DemoEnabled,
+			AutoConvert:       set.AutoConvert,
+			ReplaceBlankForms: set.ReplaceBlankForms,
+			LossyEdit:         set.LossyEdit,
+			Permissions:       set.Permissions,
+			RolePermissions:   set.RolePermissions,
+			DemoStarted:       set.DemoStarted,
 		}
 		return nil
 	}
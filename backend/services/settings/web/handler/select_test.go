@@ -29,6 +29,9 @@ import (
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/adapter"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/domain"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/service"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/keyring"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/oauth2"
@@ -46,12 +49,13 @@ func (e mockEncryptor) Decrypt(ciphertext string, key []byte) (string, error) {
 
 func TestSelectCaching(t *testing.T) {
 	adapter := adapter.NewMemoryDocserverAdapter()
+	ring, _ := keyring.NewKeyring(&keyring.KeyringConfig{}, mockEncryptor{}, &oauth2.Config{
+		ClientID:     "mock",
+		ClientSecret: "mock",
+	})
 	service := service.NewSettingsService(
-		adapter, mockEncryptor{}, cache.NewCache(&config.CacheConfig{}),
-		&oauth2.Config{
-			ClientID:     "mock",
-			ClientSecret: "mock",
-		}, log.NewEmptyLogger(),
+		adapter, ring, cache.NewCache(&config.CacheConfig{}),
+		log.NewEmptyLogger(),
 	)
 
 	sel := NewSettingsSelectHandler(service, nil, log.NewEmptyLogger())
@@ -71,4 +75,34 @@ func TestSelectCaching(t *testing.T) {
 		assert.NoError(t, sel.GetSettings(context.Background(), &id, &res))
 		assert.NotEmpty(t, res)
 	})
+
+	t.Run("get cached permissions policy", func(t *testing.T) {
+		service.UpdateSettings(context.Background(), domain.DocSettings{
+			CompanyID:  "policy",
+			DocAddress: "https://mock",
+			DocSecret:  "mock",
+			DocHeader:  "mock",
+			Permissions: request.PermissionsPolicy{
+				Mode:            "Review",
+				DisableDownload: true,
+			},
+			RolePermissions: map[string]request.PermissionsPolicy{
+				"managers": {AllowPrint: true},
+			},
+		})
+
+		id := "policy"
+		for i := 0; i < 2; i++ {
+			var res response.DocSettingsResponse
+			assert.NoError(t, sel.GetSettings(context.Background(), &id, &res))
+			assert.Equal(t, request.PermissionsPolicy{
+				Mode:            constants.PermissionModeReview,
+				DisableDownload: true,
+			}, res.Permissions)
+			assert.Equal(t, request.PermissionsPolicy{
+				Mode:       constants.PermissionModeEdit,
+				AllowPrint: true,
+			}, res.RolePermissions["managers"])
+		}
+	})
 }
@@ -0,0 +1,119 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/domain"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/settings/web/core/port"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+)
+
+type TemplateHandler struct {
+	service port.DocTemplateService
+	logger  log.Logger
+}
+
+func NewTemplateHandler(
+	service port.DocTemplateService,
+	logger log.Logger,
+) TemplateHandler {
+	return TemplateHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func toTemplateResponse(template domain.DocTemplate) response.DocTemplateResponse {
+	return response.DocTemplateResponse{
+		ID:        template.ID,
+		Name:      template.Name,
+		Filename:  template.Filename(),
+		FileType:  template.FileType,
+		Lang:      template.Lang,
+		Size:      template.Size,
+		Content:   template.Content,
+		CreatedBy: template.CreatedBy,
+		CreatedAt: template.CreatedAt,
+	}
+}
+
+func (t TemplateHandler) InsertTemplate(ctx context.Context, req request.DocTemplate, res *response.DocTemplateResponse) error {
+	template, err := t.service.CreateTemplate(ctx, domain.DocTemplate{
+		CompanyID: req.CompanyID,
+		Name:      req.Name,
+		FileType:  req.FileType,
+		Lang:      req.Lang,
+		Content:   req.Content,
+		CreatedBy: req.CreatedBy,
+	})
+	if err != nil {
+		t.logger.Errorf("could not create company %s template: %s", req.CompanyID, err.Error())
+		return err
+	}
+
+	*res = toTemplateResponse(template)
+	return nil
+}
+
+func (t TemplateHandler) GetTemplates(ctx context.Context, req request.DocTemplateSelect, res *response.DocTemplatesResponse) error {
+	templates, err, _ := group.Do(fmt.Sprintf("templates-%s:%s:%s", req.CompanyID, req.FileType, req.Lang), func() (interface{}, error) {
+		return t.service.GetTemplates(ctx, req.CompanyID, req.FileType, req.Lang)
+	})
+
+	if err != nil {
+		t.logger.Debugf("could not get company %s templates. Reason: %s", req.CompanyID, err.Error())
+		return err
+	}
+
+	if ts, ok := templates.([]domain.DocTemplate); ok {
+		res.Templates = make([]response.DocTemplateResponse, 0, len(ts))
+		for _, template := range ts {
+			res.Templates = append(res.Templates, toTemplateResponse(template))
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("could not cast company %s templates", req.CompanyID)
+}
+
+func (t TemplateHandler) GetTemplate(ctx context.Context, req request.DocTemplateSelect, res *response.DocTemplateResponse) error {
+	template, err := t.service.GetTemplate(ctx, req.CompanyID, req.ID)
+	if err != nil {
+		t.logger.Debugf("could not get company %s template %s. Reason: %s", req.CompanyID, req.ID, err.Error())
+		return err
+	}
+
+	*res = toTemplateResponse(template)
+	return nil
+}
+
+func (t TemplateHandler) DeleteTemplate(ctx context.Context, req request.DocTemplateSelect, res *interface{}) error {
+	if err := t.service.RemoveTemplate(ctx, req.CompanyID, req.ID); err != nil {
+		t.logger.Errorf("could not delete company %s template %s: %s", req.CompanyID, req.ID, err.Error())
+		return err
+	}
+
+	return nil
+}
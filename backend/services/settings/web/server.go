@@ -24,20 +24,23 @@ import (
 )
 
 type DocserverRPCServer struct {
-	selectHandler handler.SettingsSelectHandler
-	insertHandler handler.SettingsInsertHandler
-	deleteHandler handler.SettingsDeleteHandler
+	selectHandler   handler.SettingsSelectHandler
+	insertHandler   handler.SettingsInsertHandler
+	deleteHandler   handler.SettingsDeleteHandler
+	templateHandler handler.TemplateHandler
 }
 
 func NewDocserverRPCServer(
 	selectHandler handler.SettingsSelectHandler,
 	insertHandler handler.SettingsInsertHandler,
 	deleteHandler handler.SettingsDeleteHandler,
+	templateHandler handler.TemplateHandler,
 ) rpc.RPCEngine {
 	return DocserverRPCServer{
-		selectHandler: selectHandler,
-		insertHandler: insertHandler,
-		deleteHandler: deleteHandler,
+		selectHandler:   selectHandler,
+		insertHandler:   insertHandler,
+		deleteHandler:   deleteHandler,
+		templateHandler: templateHandler,
 	}
 }
 
@@ -46,5 +49,5 @@ func (a DocserverRPCServer) BuildMessageHandlers() []rpc.RPCMessageHandler {
 }
 
 func (a DocserverRPCServer) BuildHandlers() []interface{} {
-	return []interface{}{a.selectHandler, a.insertHandler, a.deleteHandler}
+	return []interface{}{a.selectHandler, a.insertHandler, a.deleteHandler, a.templateHandler}
 }
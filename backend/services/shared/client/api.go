@@ -23,17 +23,26 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
 	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
 	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
 	"github.com/go-resty/resty/v2"
 	"github.com/mitchellh/mapstructure"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
+var redirectClient = &http.Client{
+	Timeout: 15 * time.Second,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
 type PipedriveApiClient struct {
 	client *resty.Client
 }
@@ -98,6 +107,68 @@ func (p *PipedriveApiClient) GetMe(ctx context.Context, token model.Token) (mode
 	return usr, nil
 }
 
+// GetRecord returns the owner and visibility of the record files are attached to.
+func (p *PipedriveApiClient) GetRecord(ctx context.Context, entity request.ParentEntity, token model.Token) (model.Record, error) {
+	data, err := p.GetRecordData(ctx, entity, token)
+	if err != nil {
+		return model.Record{}, err
+	}
+
+	return model.NewRecord(data), nil
+}
+
+// GetRecordData returns all fields of a Pipedrive record as they are sent by the api.
+func (p *PipedriveApiClient) GetRecordData(ctx context.Context, entity request.ParentEntity, token model.Token) (map[string]interface{}, error) {
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+
+	res, err := p.client.R().
+		SetContext(ctx).
+		SetAuthToken(token.AccessToken).
+		SetResult(&resp).
+		Get(fmt.Sprintf("%s/api/v1/%s/%s", token.ApiDomain, entity.Resource(), url.PathEscape(entity.ID)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() != http.StatusOK || resp.Data == nil {
+		return nil, &UnexpectedStatusCodeError{
+			Action: fmt.Sprintf("get %s", entity.Type),
+			Code:   res.StatusCode(),
+		}
+	}
+
+	return resp.Data, nil
+}
+
+// GetDealProducts returns the products attached to a deal.
+func (p *PipedriveApiClient) GetDealProducts(ctx context.Context, id string, token model.Token) ([]map[string]interface{}, error) {
+	var resp struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+
+	res, err := p.client.R().
+		SetContext(ctx).
+		SetAuthToken(token.AccessToken).
+		SetResult(&resp).
+		Get(fmt.Sprintf("%s/api/v1/deals/%s/products", token.ApiDomain, url.PathEscape(id)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() != http.StatusOK {
+		return nil, &UnexpectedStatusCodeError{
+			Action: "get deal products",
+			Code:   res.StatusCode(),
+		}
+	}
+
+	return resp.Data, nil
+}
+
 func (p *PipedriveApiClient) UpdateFile(ctx context.Context, id, name string, token model.Token) error {
 	res, err := p.client.R().
 		SetContext(ctx).
@@ -121,6 +192,26 @@ func (p *PipedriveApiClient) UpdateFile(ctx context.Context, id, name string, to
 	return nil
 }
 
+func (p *PipedriveApiClient) DeleteFile(ctx context.Context, id string, token model.Token) error {
+	res, err := p.client.R().
+		SetContext(ctx).
+		SetAuthToken(token.AccessToken).
+		Delete(fmt.Sprintf("%s/api/v1/files/%s", token.ApiDomain, id))
+
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode() != http.StatusOK {
+		return &UnexpectedStatusCodeError{
+			Action: "delete file",
+			Code:   res.StatusCode(),
+		}
+	}
+
+	return nil
+}
+
 func (c *PipedriveApiClient) ValidateFileSize(ctx context.Context, limit int64, url string) (int64, error) {
 	headResp, err := c.client.R().
 		SetContext(ctx).
@@ -137,7 +228,7 @@ func (c *PipedriveApiClient) ValidateFileSize(ctx context.Context, limit int64,
 	}
 }
 
-func (p PipedriveApiClient) getFile(ctx context.Context, url string) (io.ReadCloser, error) {
+func (p PipedriveApiClient) GetFile(ctx context.Context, url string) (io.ReadCloser, error) {
 	fileResp, err := p.client.R().
 		SetContext(ctx).
 		SetDoNotParseResponse(true).
@@ -154,35 +245,84 @@ func (p PipedriveApiClient) getFile(ctx context.Context, url string) (io.ReadClo
 	return fileResp.RawBody(), nil
 }
 
-func (p *PipedriveApiClient) UploadFile(ctx context.Context, url, deal, fileID, filename string, size int64, token model.Token) error {
-	if err := p.UpdateFile(ctx, fileID, filename, token); err != nil {
-		return err
+// StreamFile requests a file or a range of it, passing through conditional and range headers.
+// HEAD requests only fetch the file headers. Callers must close the response body.
+func (p PipedriveApiClient) StreamFile(ctx context.Context, method, url string, header http.Header) (*http.Response, error) {
+	res, err := p.client.R().
+		SetContext(ctx).
+		SetHeaderMultiValues(header).
+		SetDoNotParseResponse(true).
+		Execute(method, url)
+	if err != nil {
+		return nil, err
 	}
 
-	file, err := p.getFile(ctx, url)
+	switch res.RawResponse.StatusCode {
+	case http.StatusOK, http.StatusPartialContent, http.StatusNotModified, http.StatusRequestedRangeNotSatisfiable:
+		return res.RawResponse, nil
+	default:
+		res.RawBody().Close()
+		return nil, &UnexpectedStatusCodeError{
+			Action: "stream file",
+			Code:   res.RawResponse.StatusCode,
+		}
+	}
+}
+
+// UploadFile stores a document server file as a new attachment of the parent entity. Pipedrive files are immutable,
+// so every upload yields a new file id which is then tracked as a version by the documents service and the
+// superseded attachments are removed by shared.PruneVersions.
+func (p *PipedriveApiClient) UploadFile(ctx context.Context, url string, entity request.ParentEntity, filename string, token model.Token) (response.AddFileResponse, error) {
+	file, err := p.GetFile(ctx, url)
 	if err != nil {
-		return err
+		return response.AddFileResponse{}, err
 	}
 	defer file.Close()
 
-	_, err = p.client.R().
-		SetContext(ctx).
-		SetAuthToken(token.AccessToken).
-		SetFileReader("file", filename, file).
-		SetFormData(map[string]string{
-			"deal_id": deal,
-		}).
-		Post(fmt.Sprintf("%s/api/v1/files", token.ApiDomain))
+	res, err := p.CreateFile(ctx, entity, filename, file, token)
+	if err != nil {
+		return res, err
+	}
+
+	if !res.Success || res.Data.ID == 0 {
+		return res, &UnexpectedStatusCodeError{
+			Action: "upload file",
+			Code:   http.StatusBadGateway,
+		}
+	}
 
+	return res, nil
+}
+
+// GetDownloadURL resolves a short-lived direct link to the file contents.
+func (p *PipedriveApiClient) GetDownloadURL(ctx context.Context, fileID string, token model.Token) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v1/files/%s/download", token.ApiDomain, fileID), nil)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := redirectClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return "", &UnexpectedStatusCodeError{
+			Action: "get download url",
+			Code:   resp.StatusCode,
+		}
+	}
+
+	return resp.Header.Get("Location"), nil
 }
 
-func (p *PipedriveApiClient) CreateFile(ctx context.Context, deal, filename string, file io.ReadCloser, token model.Token) (response.AddFileResponse, error) {
+func (p *PipedriveApiClient) CreateFile(ctx context.Context, entity request.ParentEntity, filename string, file io.ReadCloser, token model.Token) (response.AddFileResponse, error) {
 	var body response.AddFileResponse
+	if err := entity.Validate(); err != nil {
+		return body, err
+	}
 
 	_, err := p.client.R().
 		SetResult(&body).
@@ -190,7 +330,7 @@ func (p *PipedriveApiClient) CreateFile(ctx context.Context, deal, filename stri
 		SetAuthToken(token.AccessToken).
 		SetFileReader("file", filename, file).
 		SetFormData(map[string]string{
-			"deal_id": deal,
+			entity.FormField(): entity.ID,
 		}).
 		Post(fmt.Sprintf("%s/api/v1/files", token.ApiDomain))
 
@@ -200,3 +340,70 @@ func (p *PipedriveApiClient) CreateFile(ctx context.Context, deal, filename stri
 
 	return body, nil
 }
+
+func (p *PipedriveApiClient) GetWebhooks(ctx context.Context, token model.Token) ([]model.Webhook, error) {
+	var resp struct {
+		Data []model.Webhook `json:"data"`
+	}
+
+	res, err := p.client.R().
+		SetContext(ctx).
+		SetAuthToken(token.AccessToken).
+		SetResult(&resp).
+		Get(fmt.Sprintf("%s/api/v1/webhooks", token.ApiDomain))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode() != http.StatusOK {
+		return nil, &UnexpectedStatusCodeError{
+			Action: "get webhooks",
+			Code:   res.StatusCode(),
+		}
+	}
+
+	return resp.Data, nil
+}
+
+// CreateWebhook subscribes to company events. Pipedrive only allows admins to manage webhooks.
+func (p *PipedriveApiClient) CreateWebhook(ctx context.Context, webhook model.Webhook, token model.Token) error {
+	res, err := p.client.R().
+		SetContext(ctx).
+		SetAuthToken(token.AccessToken).
+		SetBody(webhook).
+		Post(fmt.Sprintf("%s/api/v1/webhooks", token.ApiDomain))
+
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode() != http.StatusCreated && res.StatusCode() != http.StatusOK {
+		return &UnexpectedStatusCodeError{
+			Action: "create webhook",
+			Code:   res.StatusCode(),
+		}
+	}
+
+	return nil
+}
+
+func (p *PipedriveApiClient) DeleteWebhook(ctx context.Context, id int, token model.Token) error {
+	res, err := p.client.R().
+		SetContext(ctx).
+		SetAuthToken(token.AccessToken).
+		Delete(fmt.Sprintf("%s/api/v1/webhooks/%d", token.ApiDomain, id))
+
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode() != http.StatusOK {
+		return &UnexpectedStatusCodeError{
+			Action: "delete webhook",
+			Code:   res.StatusCode(),
+		}
+	}
+
+	return nil
+}
@@ -115,6 +115,11 @@ func (c PipedriveAuthClient) RefreshAccessToken(ctx context.Context, refreshToke
 		return resp, err
 	}
 
+	// Pipedrive responds with invalid_grant once a refresh token is revoked or already used
+	if res.StatusCode() == http.StatusBadRequest || res.StatusCode() == http.StatusUnauthorized {
+		return resp, ErrRefreshTokenRevoked
+	}
+
 	if res.StatusCode() != http.StatusOK {
 		return resp, &UnexpectedStatusCodeError{
 			Action: "refresh access token",
@@ -124,3 +129,29 @@ func (c PipedriveAuthClient) RefreshAccessToken(ctx context.Context, refreshToke
 
 	return resp, resp.Validate()
 }
+
+// RevokeToken invalidates a refresh token together with the access tokens issued for it.
+func (c PipedriveAuthClient) RevokeToken(ctx context.Context, refreshToken string) error {
+	res, err := c.client.R().
+		SetHeader("Content-Type", "application/x-www-form-urlencoded").
+		SetContext(ctx).
+		SetBody(strings.NewReader(url.Values{
+			"token":           []string{refreshToken},
+			"token_type_hint": []string{"refresh_token"},
+		}.Encode())).
+		SetBasicAuth(c.clientID, c.clientSecret).
+		Post("/oauth/revoke")
+
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode() != http.StatusOK {
+		return &UnexpectedStatusCodeError{
+			Action: "revoke token",
+			Code:   res.StatusCode(),
+		}
+	}
+
+	return nil
+}
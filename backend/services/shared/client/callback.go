@@ -0,0 +1,89 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// CallbackClient calls the internal endpoints of the callback service.
+type CallbackClient struct {
+	client     *resty.Client
+	jwtManager crypto.JwtManager
+}
+
+func NewCallbackClient(jwtManager crypto.JwtManager) CallbackClient {
+	otelClient := &http.Client{
+		Transport: otelhttp.NewTransport(&http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   30 * time.Second,
+			ResponseHeaderTimeout: 6 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		}),
+	}
+
+	return CallbackClient{
+		client: resty.NewWithClient(otelClient).
+			SetRetryCount(2).
+			SetRetryWaitTime(120 * time.Millisecond).
+			SetRetryMaxWaitTime(900 * time.Millisecond).
+			SetLogger(log.NewEmptyLogger()).
+			AddRetryCondition(func(r *resty.Response, err error) bool {
+				return r.StatusCode() == http.StatusTooManyRequests
+			}),
+		jwtManager: jwtManager,
+	}
+}
+
+// PurgeCompany drops the queued callback jobs of a company. The request is signed with the client secret.
+func (c CallbackClient) PurgeCompany(ctx context.Context, url, secret string, cid int) error {
+	token, err := c.jwtManager.Sign(secret, request.NewPurgeTokenContext(cid))
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.R().
+		SetContext(ctx).
+		SetHeader("Authorization", fmt.Sprintf("Bearer %s", token)).
+		Post(fmt.Sprintf("%s/purge", strings.TrimSuffix(url, "/")))
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode() >= 300 {
+		return &UnexpectedStatusCodeError{
+			Action: "callback purge",
+			Code:   res.StatusCode(),
+		}
+	}
+
+	return nil
+}
@@ -20,9 +20,10 @@ package client
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
+	neturl "net/url"
+	"strings"
 	"time"
 
 	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
@@ -34,7 +35,34 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
-var ErrCommandServiceError = errors.New("got a command service error 1 status")
+var commandErrors = map[int]string{
+	1: "document key is missing or no document with such key could be found",
+	2: "callback url is not correct",
+	3: "internal server error",
+	4: "no changes were applied to the document before the forcesave command",
+	5: "command is not correct",
+	6: "invalid token",
+}
+
+// Command service error codes callers may want to handle.
+const (
+	CommandErrorNoDocument int = 1
+	CommandErrorNoChanges  int = 4
+)
+
+type CommandError struct {
+	Command string
+	Code    int
+}
+
+func (e *CommandError) Error() string {
+	reason, ok := commandErrors[e.Code]
+	if !ok {
+		reason = "unknown error"
+	}
+
+	return fmt.Sprintf("could not perform %s command. Reason: %s (%d)", e.Command, reason, e.Code)
+}
 
 type CommandClient struct {
 	client     *resty.Client
@@ -66,32 +94,126 @@ func NewCommandClient(jwtManager crypto.JwtManager) CommandClient {
 	}
 }
 
-func (p *CommandClient) License(ctx context.Context, url, secret string) error {
-	var resp response.BaseCommandResponse
-
-	token, err := p.jwtManager.Sign(secret, request.BaseCommandRequest{
-		C: "version",
-	})
+type commandResult interface {
+	ErrorCode() int
+}
 
+// call signs a command with the document server secret. The token is sent in the body
+// and, when the document server expects it there, in the authorization header as well.
+func (p *CommandClient) call(ctx context.Context, url, secret, header string, req request.CommandRequest, result commandResult) error {
+	token, err := p.jwtManager.Sign(secret, req)
 	if err != nil {
 		return err
 	}
 
-	res, err := p.client.R().
+	shardkey := req.Key
+	if shardkey == "" {
+		shardkey = uuid.New().String()
+	}
+
+	r := p.client.R().
 		SetContext(ctx).
 		SetBody(request.TokenCommandRequest{
 			Token: token,
 		}).
-		SetResult(&resp).
-		Post(fmt.Sprintf("%scommand?shardkey=%s", url, uuid.New().String()))
+		SetResult(result)
+	if header != "" {
+		r.SetHeader(header, fmt.Sprintf("Bearer %s", token))
+	}
 
+	res, err := r.Post(fmt.Sprintf("%s/command?shardkey=%s", strings.TrimSuffix(url, "/"), neturl.QueryEscape(shardkey)))
 	if err != nil {
 		return err
 	}
 
-	if res.StatusCode() >= 300 || resp.Error != 0 {
-		return ErrCommandServiceError
+	if res.StatusCode() >= 300 {
+		return &UnexpectedStatusCodeError{
+			Action: fmt.Sprintf("%s command", req.C),
+			Code:   res.StatusCode(),
+		}
+	}
+
+	if result.ErrorCode() != 0 {
+		return &CommandError{Command: req.C, Code: result.ErrorCode()}
 	}
 
 	return nil
 }
+
+// Forcesave saves the document without closing it. The userdata is passed back in the forcesave callback.
+func (p *CommandClient) Forcesave(ctx context.Context, url, secret, header, key, userdata string) (response.CommandKeyResponse, error) {
+	var resp response.CommandKeyResponse
+	return resp, p.call(ctx, url, secret, header, request.CommandRequest{
+		C:        request.CommandForcesave,
+		Key:      key,
+		UserData: userdata,
+	}, &resp)
+}
+
+// Drop disconnects the users from the document editing session.
+func (p *CommandClient) Drop(ctx context.Context, url, secret, header, key string, users []string) (response.CommandKeyResponse, error) {
+	var resp response.CommandKeyResponse
+	return resp, p.call(ctx, url, secret, header, request.CommandRequest{
+		C:     request.CommandDrop,
+		Key:   key,
+		Users: users,
+	}, &resp)
+}
+
+// Info requests the document status. The editors list is delivered to the callback handler.
+func (p *CommandClient) Info(ctx context.Context, url, secret, header, key, userdata string) (response.CommandKeyResponse, error) {
+	var resp response.CommandKeyResponse
+	return resp, p.call(ctx, url, secret, header, request.CommandRequest{
+		C:        request.CommandInfo,
+		Key:      key,
+		UserData: userdata,
+	}, &resp)
+}
+
+// Meta renames the document for everyone editing it.
+func (p *CommandClient) Meta(ctx context.Context, url, secret, header, key, title string) (response.CommandKeyResponse, error) {
+	var resp response.CommandKeyResponse
+	return resp, p.call(ctx, url, secret, header, request.CommandRequest{
+		C:    request.CommandMeta,
+		Key:  key,
+		Meta: &request.CommandDocumentMeta{Title: title},
+	}, &resp)
+}
+
+func (p *CommandClient) Version(ctx context.Context, url, secret, header string) (response.VersionCommandResponse, error) {
+	var resp response.VersionCommandResponse
+	return resp, p.call(ctx, url, secret, header, request.CommandRequest{
+		C: request.CommandVersion,
+	}, &resp)
+}
+
+func (p *CommandClient) License(ctx context.Context, url, secret, header string) (response.LicenseCommandResponse, error) {
+	var resp response.LicenseCommandResponse
+	return resp, p.call(ctx, url, secret, header, request.CommandRequest{
+		C: request.CommandLicense,
+	}, &resp)
+}
+
+// GetForgotten returns a link to a document which was not saved after its editors had left.
+func (p *CommandClient) GetForgotten(ctx context.Context, url, secret, header, key string) (response.ForgottenCommandResponse, error) {
+	var resp response.ForgottenCommandResponse
+	return resp, p.call(ctx, url, secret, header, request.CommandRequest{
+		C:   request.CommandGetForgotten,
+		Key: key,
+	}, &resp)
+}
+
+func (p *CommandClient) GetForgottenList(ctx context.Context, url, secret, header string) (response.ForgottenListCommandResponse, error) {
+	var resp response.ForgottenListCommandResponse
+	return resp, p.call(ctx, url, secret, header, request.CommandRequest{
+		C: request.CommandGetForgottenList,
+	}, &resp)
+}
+
+func (p *CommandClient) DeleteForgotten(ctx context.Context, url, secret, header, key string) (response.CommandKeyResponse, error) {
+	var resp response.CommandKeyResponse
+	return resp, p.call(ctx, url, secret, header, request.CommandRequest{
+		C:   request.CommandDeleteForgotten,
+		Key: key,
+	}, &resp)
+}
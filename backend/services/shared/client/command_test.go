@@ -0,0 +1,128 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/stretchr/testify/assert"
+)
+
+const commandSecret = "secret"
+
+// newCommandServer emulates the document server command service, answering every
+// verified command with the error code given.
+func newCommandServer(t *testing.T, jwtManager crypto.JwtManager, header string, code int) (*httptest.Server, *[]request.CommandRequest) {
+	var commands []request.CommandRequest
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var body request.TokenCommandRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var cmd request.CommandRequest
+		if err := jwtManager.Verify(commandSecret, body.Token, &cmd); err != nil {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if header != "" {
+			assert.Equal(t, fmt.Sprintf("Bearer %s", body.Token), r.Header.Get(header))
+		}
+
+		assert.Equal(t, "/command", r.URL.Path)
+		assert.NotEmpty(t, r.URL.Query().Get("shardkey"))
+		commands = append(commands, cmd)
+		rw.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(rw, `{"error":%d,"key":%q,"version":"9.0.0"}`, code, cmd.Key)
+	}))
+
+	return server, &commands
+}
+
+func TestCommandClient(t *testing.T) {
+	jwtManager := crypto.NewJwtManager(&config.CryptoConfig{})
+	client := NewCommandClient(jwtManager)
+
+	t.Run("sign commands in the body and the header", func(t *testing.T) {
+		server, commands := newCommandServer(t, jwtManager, "Authorization", 0)
+		defer server.Close()
+
+		resp, err := client.Forcesave(context.Background(), server.URL+"/", commandSecret, "Authorization", "key", "data")
+		assert.NoError(t, err)
+		assert.Equal(t, "key", resp.Key)
+		assert.Len(t, *commands, 1)
+		assert.Equal(t, request.CommandForcesave, (*commands)[0].C)
+		assert.Equal(t, "data", (*commands)[0].UserData)
+	})
+
+	t.Run("return command results", func(t *testing.T) {
+		server, _ := newCommandServer(t, jwtManager, "", 0)
+		defer server.Close()
+
+		resp, err := client.Version(context.Background(), server.URL, commandSecret, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "9.0.0", resp.Version)
+	})
+
+	t.Run("report missing documents", func(t *testing.T) {
+		server, _ := newCommandServer(t, jwtManager, "", CommandErrorNoDocument)
+		defer server.Close()
+
+		_, err := client.Drop(context.Background(), server.URL, commandSecret, "", "key", []string{"1:2"})
+		var cerr *CommandError
+		assert.True(t, errors.As(err, &cerr))
+		assert.Equal(t, request.CommandDrop, cerr.Command)
+		assert.Equal(t, CommandErrorNoDocument, cerr.Code)
+		assert.Contains(t, cerr.Error(), "no document with such key")
+	})
+
+	t.Run("report forcesaves without changes", func(t *testing.T) {
+		server, _ := newCommandServer(t, jwtManager, "", CommandErrorNoChanges)
+		defer server.Close()
+
+		_, err := client.Forcesave(context.Background(), server.URL, commandSecret, "", "key", "")
+		var cerr *CommandError
+		assert.True(t, errors.As(err, &cerr))
+		assert.Equal(t, request.CommandForcesave, cerr.Command)
+		assert.Equal(t, CommandErrorNoChanges, cerr.Code)
+		assert.Contains(t, cerr.Error(), "no changes were applied")
+	})
+
+	t.Run("reject commands signed with another secret", func(t *testing.T) {
+		server, commands := newCommandServer(t, jwtManager, "", 0)
+		defer server.Close()
+
+		_, err := client.Info(context.Background(), server.URL, "another", "", "key", "")
+		var serr *UnexpectedStatusCodeError
+		assert.True(t, errors.As(err, &serr))
+		assert.Equal(t, http.StatusForbidden, serr.Code)
+		assert.Empty(t, *commands)
+	})
+}
@@ -0,0 +1,130 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+var conversionErrors = map[int]string{
+	-1:  "unknown error",
+	-2:  "conversion timeout",
+	-3:  "conversion error",
+	-4:  "could not download the source file",
+	-5:  "incorrect password",
+	-6:  "conversion database error",
+	-7:  "invalid input",
+	-8:  "invalid token",
+	-9:  "could not detect the output format",
+	-10: "source file size limit exceeded",
+}
+
+type ConversionError struct {
+	Code int
+}
+
+func (e *ConversionError) Error() string {
+	reason, ok := conversionErrors[e.Code]
+	if !ok {
+		reason = conversionErrors[-1]
+	}
+
+	return fmt.Sprintf("could not convert a file. Reason: %s (%d)", reason, e.Code)
+}
+
+type ConvertClient struct {
+	client     *resty.Client
+	jwtManager crypto.JwtManager
+}
+
+func NewConvertClient(jwtManager crypto.JwtManager) ConvertClient {
+	otelClient := &http.Client{
+		Transport: otelhttp.NewTransport(&http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   30 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		}),
+	}
+
+	return ConvertClient{
+		client: resty.NewWithClient(otelClient).
+			SetRetryCount(0).
+			SetRetryWaitTime(120 * time.Millisecond).
+			SetRetryMaxWaitTime(900 * time.Millisecond).
+			SetLogger(log.NewEmptyLogger()).
+			AddRetryCondition(func(r *resty.Response, err error) bool {
+				return r.StatusCode() == http.StatusTooManyRequests
+			}),
+		jwtManager: jwtManager,
+	}
+}
+
+// Convert sends a conversion request to the document server. Async requests return as soon as
+// the conversion has started and should be repeated with the same key until EndConvert is set.
+func (p *ConvertClient) Convert(ctx context.Context, url, secret, header string, req request.ConvertRequest) (response.ConvertResponse, error) {
+	var resp response.ConvertResponse
+
+	req.Token = ""
+	token, err := p.jwtManager.Sign(secret, req)
+	if err != nil {
+		return resp, err
+	}
+
+	req.Token = token
+	r := p.client.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		SetBody(req).
+		SetResult(&resp)
+	if header != "" {
+		r.SetHeader(header, fmt.Sprintf("Bearer %s", token))
+	}
+
+	res, err := r.Post(fmt.Sprintf("%s/converter", strings.TrimSuffix(url, "/")))
+	if err != nil {
+		return resp, err
+	}
+
+	if res.StatusCode() >= 300 {
+		return resp, &UnexpectedStatusCodeError{
+			Action: "convert file",
+			Code:   res.StatusCode(),
+		}
+	}
+
+	if resp.Error != 0 {
+		return resp, &ConversionError{Code: resp.Error}
+	}
+
+	return resp, nil
+}
@@ -26,6 +26,7 @@ import (
 var (
 	ErrInvalidUrlFormat     = errors.New("url is not valid")
 	ErrInvalidContentLength = errors.New("could not perform api actions due to exceeding content-length")
+	ErrRefreshTokenRevoked  = errors.New("pipedrive refresh token has been revoked")
 )
 
 type UnexpectedStatusCodeError struct {
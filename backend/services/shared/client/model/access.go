@@ -23,3 +23,9 @@ type Access struct {
 	Admin        bool   `json:"admin" mapstructure:"admin"`
 	PermissionID string `json:"permission_set_id" mapstructure:"permission_set_id"`
 }
+
+// Pipedrive apps a user may be given access to.
+const (
+	AppGlobal string = "global"
+	AppSales  string = "sales"
+)
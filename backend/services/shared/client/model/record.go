@@ -0,0 +1,70 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package model
+
+import "strconv"
+
+// VisibleToOwner marks a record only its owner and followers can see.
+const VisibleToOwner string = "1"
+
+// Record is the part of a Pipedrive deal, person, organization, lead, product or activity
+// which decides what users other than its owner may do with its files.
+type Record struct {
+	OwnerID   int
+	VisibleTo string
+}
+
+// NewRecord picks the owner and visibility out of a Pipedrive record. Depending on the record type
+// owners are stored in either owner_id or user_id, as an id or as a user object.
+func NewRecord(data map[string]interface{}) Record {
+	var record Record
+	for _, key := range []string{"owner_id", "user_id"} {
+		if id := parseUserID(data[key]); id > 0 {
+			record.OwnerID = id
+			break
+		}
+	}
+
+	switch visibility := data["visible_to"].(type) {
+	case string:
+		record.VisibleTo = visibility
+	case float64:
+		record.VisibleTo = strconv.Itoa(int(visibility))
+	}
+
+	return record
+}
+
+func parseUserID(value interface{}) int {
+	switch id := value.(type) {
+	case float64:
+		return int(id)
+	case string:
+		val, _ := strconv.Atoi(id)
+		return val
+	case map[string]interface{}:
+		if val, ok := id["id"]; ok {
+			return parseUserID(val)
+		}
+
+		return parseUserID(id["value"])
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,81 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRecord(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   map[string]interface{}
+		record Record
+	}{
+		{
+			name:   "deal owned by a user id",
+			data:   map[string]interface{}{"user_id": float64(2), "visible_to": "3"},
+			record: Record{OwnerID: 2, VisibleTo: "3"},
+		},
+		{
+			name: "deal owned by a user object",
+			data: map[string]interface{}{
+				"user_id":    map[string]interface{}{"id": float64(2), "name": "John"},
+				"visible_to": "1",
+			},
+			record: Record{OwnerID: 2, VisibleTo: VisibleToOwner},
+		},
+		{
+			name: "person owned by a user object value",
+			data: map[string]interface{}{
+				"owner_id":   map[string]interface{}{"value": float64(2), "name": "John"},
+				"visible_to": float64(3),
+			},
+			record: Record{OwnerID: 2, VisibleTo: "3"},
+		},
+		{
+			name:   "lead owned by a user id string",
+			data:   map[string]interface{}{"owner_id": "2", "visible_to": "7"},
+			record: Record{OwnerID: 2, VisibleTo: "7"},
+		},
+		{
+			name:   "owner id preferred over user id",
+			data:   map[string]interface{}{"owner_id": float64(2), "user_id": float64(3)},
+			record: Record{OwnerID: 2},
+		},
+		{
+			name:   "user id used when the owner id is empty",
+			data:   map[string]interface{}{"owner_id": nil, "user_id": float64(3)},
+			record: Record{OwnerID: 3},
+		},
+		{
+			name:   "record without an owner",
+			data:   map[string]interface{}{"owner_id": "unknown", "visible_to": nil},
+			record: Record{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.record, NewRecord(test.data))
+		})
+	}
+}
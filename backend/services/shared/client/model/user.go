@@ -43,3 +43,25 @@ func (u *User) Validate() error {
 
 	return nil
 }
+
+// IsAdmin reports whether the user administers the company or its sales app.
+func (u User) IsAdmin() bool {
+	for _, access := range u.Access {
+		if access.Admin && (access.App == AppGlobal || access.App == AppSales) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasApp reports whether the user has been given access to a Pipedrive app.
+func (u User) HasApp(app string) bool {
+	for _, access := range u.Access {
+		if access.App == app {
+			return true
+		}
+	}
+
+	return false
+}
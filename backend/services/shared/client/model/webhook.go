@@ -0,0 +1,36 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package model
+
+// Webhook is a Pipedrive webhook subscription. Pipedrive never returns
+// the basic auth password of existing subscriptions.
+type Webhook struct {
+	ID               int    `json:"id,omitempty" mapstructure:"id"`
+	SubscriptionURL  string `json:"subscription_url" mapstructure:"subscription_url"`
+	EventAction      string `json:"event_action" mapstructure:"event_action"`
+	EventObject      string `json:"event_object" mapstructure:"event_object"`
+	HttpAuthUser     string `json:"http_auth_user,omitempty" mapstructure:"http_auth_user"`
+	HttpAuthPassword string `json:"http_auth_password,omitempty" mapstructure:"http_auth_password"`
+	Version          string `json:"version,omitempty" mapstructure:"version"`
+}
+
+// Event returns the event name Pipedrive puts into webhook notifications, e.g. deleted.deal.
+func (w Webhook) Event() string {
+	return w.EventAction + "." + w.EventObject
+}
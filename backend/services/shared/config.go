@@ -20,7 +20,11 @@ package shared
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/sethvargo/go-envconfig"
@@ -36,6 +40,17 @@ type IntegrationCredentials struct {
 		Scopes       []string `yaml:"scopes" env:"CREDENTIALS_SCOPES"`
 	} `yaml:"credentials"`
 }
+type TokenRefresherConfig struct {
+	Refresher struct {
+		Enabled   bool `yaml:"enabled" env:"REFRESHER_ENABLED,overwrite"`
+		Interval  int  `yaml:"interval" env:"REFRESHER_INTERVAL,overwrite"`
+		Window    int  `yaml:"window" env:"REFRESHER_WINDOW,overwrite"`
+		BatchSize int  `yaml:"batch_size" env:"REFRESHER_BATCH_SIZE,overwrite"`
+		LockTTL   int  `yaml:"lock_ttl" env:"REFRESHER_LOCK_TTL,overwrite"`
+		LockWait  int  `yaml:"lock_wait" env:"REFRESHER_LOCK_WAIT,overwrite"`
+	} `yaml:"refresher"`
+}
+
 type OnlyofficeConfig struct {
 	Onlyoffice struct {
 		Builder  OnlyofficeBuilderConfig  `yaml:"builder"`
@@ -94,6 +109,78 @@ func BuildNewIntegrationCredentialsConfig(path string) func() (*oauth2.Config, e
 	}
 }
 
+func (rc *TokenRefresherConfig) Validate() error {
+	if rc.Refresher.Interval <= 0 {
+		return &InvalidConfigurationParameterError{
+			Parameter: "Refresher Interval",
+			Reason:    "Should be positive",
+		}
+	}
+
+	if rc.Refresher.Window <= 0 {
+		return &InvalidConfigurationParameterError{
+			Parameter: "Refresher Window",
+			Reason:    "Should be positive",
+		}
+	}
+
+	if rc.Refresher.BatchSize <= 0 {
+		return &InvalidConfigurationParameterError{
+			Parameter: "Refresher BatchSize",
+			Reason:    "Should be positive",
+		}
+	}
+
+	if rc.Refresher.LockTTL <= 0 {
+		return &InvalidConfigurationParameterError{
+			Parameter: "Refresher LockTTL",
+			Reason:    "Should be positive",
+		}
+	}
+
+	if rc.Refresher.LockWait < 0 {
+		return &InvalidConfigurationParameterError{
+			Parameter: "Refresher LockWait",
+			Reason:    "Should not be negative",
+		}
+	}
+
+	return nil
+}
+
+func BuildNewTokenRefresherConfig(path string) func() (*TokenRefresherConfig, error) {
+	return func() (*TokenRefresherConfig, error) {
+		var config TokenRefresherConfig
+		config.Refresher.Enabled = true
+		config.Refresher.Interval = 60
+		config.Refresher.Window = 600
+		config.Refresher.BatchSize = 50
+		config.Refresher.LockTTL = 30
+		config.Refresher.LockWait = 10
+		if path != "" {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+
+			decoder := yaml.NewDecoder(file)
+
+			if err := decoder.Decode(&config); err != nil {
+				return nil, err
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		defer cancel()
+		if err := envconfig.Process(ctx, &config); err != nil {
+			return nil, err
+		}
+
+		return &config, config.Validate()
+	}
+}
+
 func (oc *OnlyofficeConfig) Validate() error {
 	if err := oc.Onlyoffice.Builder.Validate(); err != nil {
 		return err
@@ -111,6 +198,11 @@ func BuildNewOnlyofficeConfig(path string) func() (*OnlyofficeConfig, error) {
 		var config OnlyofficeConfig
 		config.Onlyoffice.Callback.MaxSize = 20000000
 		config.Onlyoffice.Callback.UploadTimeout = 120
+		config.Onlyoffice.Callback.Workers = 4
+		config.Onlyoffice.Callback.MaxAttempts = 5
+		config.Onlyoffice.Callback.RetryDelay = 10
+		config.Onlyoffice.Callback.PollInterval = 1
+		config.Onlyoffice.Callback.Retention = 7
 		if path != "" {
 			file, err := os.Open(path)
 			if err != nil {
@@ -148,9 +240,89 @@ func (oc *OnlyofficeBuilderConfig) Validate() error {
 type OnlyofficeCallbackConfig struct {
 	MaxSize       int64 `yaml:"max_size" env:"ONLYOFFICE_CALLBACK_MAX_SIZE,overwrite"`
 	UploadTimeout int   `yaml:"upload_timeout" env:"ONLYOFFICE_CALLBACK_UPLOAD_TIMEOUT,overwrite"`
+	Workers       int   `yaml:"workers" env:"ONLYOFFICE_CALLBACK_WORKERS,overwrite"`
+	MaxAttempts   int   `yaml:"max_attempts" env:"ONLYOFFICE_CALLBACK_MAX_ATTEMPTS,overwrite"`
+	RetryDelay    int   `yaml:"retry_delay" env:"ONLYOFFICE_CALLBACK_RETRY_DELAY,overwrite"`
+	PollInterval  int   `yaml:"poll_interval" env:"ONLYOFFICE_CALLBACK_POLL_INTERVAL,overwrite"`
+	// Retention is the number of days finished jobs are kept to deduplicate late document server retries.
+	Retention int `yaml:"retention" env:"ONLYOFFICE_CALLBACK_RETENTION,overwrite"`
+	// LegacyURLsUntil is the RFC 3339 time until which unsigned callback urls of editors opened before
+	// callback urls were signed are accepted. They are rejected unless it is set.
+	LegacyURLsUntil string `yaml:"legacy_urls_until" env:"ONLYOFFICE_CALLBACK_LEGACY_URLS_UNTIL,overwrite"`
+	// KeepVersions is the number of superseded versions whose Pipedrive attachments are kept to be restored.
+	// Older attachments are removed so that saves do not pile up files under the same name.
+	KeepVersions int `yaml:"keep_versions" env:"ONLYOFFICE_CALLBACK_KEEP_VERSIONS,overwrite"`
+}
+
+// maxLegacyURLsWindow is how long editors opened with unsigned callback urls may keep calling back.
+const maxLegacyURLsWindow = 7 * 24 * time.Hour
+
+// AcceptsLegacyURLs reports whether unsigned callback urls are still accepted.
+func (c *OnlyofficeCallbackConfig) AcceptsLegacyURLs(now time.Time) bool {
+	until, err := time.Parse(time.RFC3339, c.LegacyURLsUntil)
+	return err == nil && now.Before(until)
 }
 
 func (c *OnlyofficeCallbackConfig) Validate() error {
+	if c.Workers < 0 {
+		return &InvalidConfigurationParameterError{
+			Parameter: "Callback Workers",
+			Reason:    "Should not be negative",
+		}
+	}
+
+	if c.MaxAttempts <= 0 {
+		return &InvalidConfigurationParameterError{
+			Parameter: "Callback MaxAttempts",
+			Reason:    "Should be positive",
+		}
+	}
+
+	if c.RetryDelay < 0 {
+		return &InvalidConfigurationParameterError{
+			Parameter: "Callback RetryDelay",
+			Reason:    "Should not be negative",
+		}
+	}
+
+	if c.PollInterval <= 0 {
+		return &InvalidConfigurationParameterError{
+			Parameter: "Callback PollInterval",
+			Reason:    "Should be positive",
+		}
+	}
+
+	if c.KeepVersions < 0 {
+		return &InvalidConfigurationParameterError{
+			Parameter: "Callback KeepVersions",
+			Reason:    "Should not be negative",
+		}
+	}
+
+	if c.LegacyURLsUntil != "" {
+		until, err := time.Parse(time.RFC3339, c.LegacyURLsUntil)
+		if err != nil {
+			return &InvalidConfigurationParameterError{
+				Parameter: "Callback LegacyURLsUntil",
+				Reason:    "Should be an RFC 3339 time",
+			}
+		}
+
+		if until.After(time.Now().Add(maxLegacyURLsWindow)) {
+			return &InvalidConfigurationParameterError{
+				Parameter: "Callback LegacyURLsUntil",
+				Reason:    "Should not be more than 7 days ahead",
+			}
+		}
+	}
+
+	if c.Retention <= 0 {
+		return &InvalidConfigurationParameterError{
+			Parameter: "Callback Retention",
+			Reason:    "Should be positive",
+		}
+	}
+
 	return nil
 }
 
@@ -185,3 +357,56 @@ func (c *OnlyofficeDemoConfig) Validate() error {
 	}
 	return nil
 }
+
+// WebhookConfig holds the dedicated key Pipedrive webhook credentials are derived from,
+// so that webhook subscriptions never carry the client secret.
+type WebhookConfig struct {
+	Webhook struct {
+		Secret string `yaml:"secret" env:"WEBHOOK_SECRET,overwrite"`
+	} `yaml:"webhook"`
+}
+
+func (wc *WebhookConfig) Validate() error {
+	if len(wc.Webhook.Secret) < 32 {
+		return &InvalidConfigurationParameterError{
+			Parameter: "Webhook Secret",
+			Reason:    "Should be at least 32 characters long",
+		}
+	}
+
+	return nil
+}
+
+// Password returns the basic auth password of the company webhooks, an HMAC of the company id.
+func (wc *WebhookConfig) Password(cid int) string {
+	mac := hmac.New(sha256.New, []byte(wc.Webhook.Secret))
+	mac.Write([]byte(strconv.Itoa(cid)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func BuildNewWebhookConfig(path string) func() (*WebhookConfig, error) {
+	return func() (*WebhookConfig, error) {
+		var config WebhookConfig
+		if path != "" {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+
+			decoder := yaml.NewDecoder(file)
+
+			if err := decoder.Decode(&config); err != nil {
+				return nil, err
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		defer cancel()
+		if err := envconfig.Process(ctx, &config); err != nil {
+			return nil, err
+		}
+
+		return &config, config.Validate()
+	}
+}
@@ -0,0 +1,45 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package constants
+
+// Document server callback statuses.
+// See https://api.onlyoffice.com/docs/docs-api/usage-api/callback-handler/
+const (
+	CallbackStatusEditing        int = 1
+	CallbackStatusMustSave       int = 2
+	CallbackStatusSaveError      int = 3
+	CallbackStatusClosed         int = 4
+	CallbackStatusMustForcesave  int = 6
+	CallbackStatusForcesaveError int = 7
+)
+
+// Document server callback action types.
+const (
+	CallbackActionDisconnect int = 0
+	CallbackActionConnect    int = 1
+	CallbackActionForcesave  int = 2
+)
+
+// Document server forcesave types.
+const (
+	CallbackForcesaveTypeCommand    int = 0
+	CallbackForcesaveTypeButton     int = 1
+	CallbackForcesaveTypeTimer      int = 2
+	CallbackForcesaveTypeSubmitForm int = 3
+)
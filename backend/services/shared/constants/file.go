@@ -106,3 +106,15 @@ func GetFileType(fileExt string) (string, error) {
 	}
 	return fileType, nil
 }
+
+// TemplateFileTypes lists the formats company templates can be uploaded in. New files
+// are created from templates the same way as from blank files, so the formats match.
+var TemplateFileTypes map[string]bool = map[string]bool{
+	"docx": true,
+	"xlsx": true,
+	"pptx": true,
+	"pdf":  true,
+}
+
+// MaxTemplateSize keeps a stored template well below the mongo document size limit.
+const MaxTemplateSize int = 8 * 1024 * 1024
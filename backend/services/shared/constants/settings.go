@@ -0,0 +1,54 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package constants
+
+// Company policies for editing formats which cannot be saved without losing some formatting.
+const (
+	LossyEditNever  string = "never"
+	LossyEditAsk    string = "ask"
+	LossyEditAlways string = "always"
+)
+
+// IsLossyEditPolicy reports whether the policy is known. An empty policy falls back to never.
+func IsLossyEditPolicy(policy string) bool {
+	switch policy {
+	case "", LossyEditNever, LossyEditAsk, LossyEditAlways:
+		return true
+	default:
+		return false
+	}
+}
+
+// Company policies for what editors may do with a document. Edit is further limited by the file format.
+const (
+	PermissionModeEdit    string = "edit"
+	PermissionModeReview  string = "review"
+	PermissionModeComment string = "comment"
+	PermissionModeView    string = "view"
+)
+
+// IsPermissionMode reports whether the mode is known. An empty mode falls back to edit.
+func IsPermissionMode(mode string) bool {
+	switch mode {
+	case "", PermissionModeEdit, PermissionModeReview, PermissionModeComment, PermissionModeView:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,125 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keyring
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sethvargo/go-envconfig"
+	"gopkg.in/yaml.v2"
+)
+
+// A KeyConfig describes a single versioned key. Exactly one source
+// (value, file or wrapped) is expected to be set.
+type KeyConfig struct {
+	ID      string `yaml:"id"`
+	Value   string `yaml:"value"`
+	File    string `yaml:"file"`
+	Wrapped string `yaml:"wrapped"`
+}
+
+func (k KeyConfig) isEmpty() bool {
+	return k.Value == "" && k.File == "" && k.Wrapped == ""
+}
+
+type KeyringConfig struct {
+	Keyring struct {
+		Active string      `yaml:"active" env:"KEYRING_ACTIVE,overwrite"`
+		Keys   []KeyConfig `yaml:"keys"`
+		Legacy KeyConfig   `yaml:"legacy"`
+		KMS    struct {
+			MasterKeyFile string `yaml:"master_key_file" env:"KEYRING_KMS_MASTER_KEY_FILE,overwrite"`
+		} `yaml:"kms"`
+	} `yaml:"keyring"`
+}
+
+func (c *KeyringConfig) Validate() error {
+	ids := make(map[string]bool, len(c.Keyring.Keys))
+	for _, key := range c.Keyring.Keys {
+		id := strings.TrimSpace(key.ID)
+		if id == "" || strings.Contains(id, keySeparator) {
+			return &InvalidKeyError{
+				ID:     key.ID,
+				Reason: "Should not be blank or contain " + keySeparator,
+			}
+		}
+
+		if ids[id] {
+			return &InvalidKeyError{
+				ID:     id,
+				Reason: "Should be unique",
+			}
+		}
+
+		if key.isEmpty() {
+			return &InvalidKeyError{
+				ID:     id,
+				Reason: "Should have a value, file or wrapped source",
+			}
+		}
+
+		// Keys read from files or unwrapped are checked once they are resolved
+		if key.Value != "" {
+			if err := validateKeySize(id, []byte(key.Value)); err != nil {
+				return err
+			}
+		}
+
+		ids[id] = true
+	}
+
+	if c.Keyring.Active != "" && !ids[c.Keyring.Active] {
+		return &InvalidKeyError{
+			ID:     c.Keyring.Active,
+			Reason: "Active key is not configured",
+		}
+	}
+
+	return nil
+}
+
+func BuildNewKeyringConfig(path string) func() (*KeyringConfig, error) {
+	return func() (*KeyringConfig, error) {
+		var config KeyringConfig
+		if path != "" {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+
+			decoder := yaml.NewDecoder(file)
+
+			if err := decoder.Decode(&config); err != nil {
+				return nil, err
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		defer cancel()
+		if err := envconfig.Process(ctx, &config); err != nil {
+			return nil, err
+		}
+
+		return &config, config.Validate()
+	}
+}
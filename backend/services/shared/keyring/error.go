@@ -0,0 +1,50 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keyring
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnknownKey        = errors.New("ciphertext is encrypted with an unknown key")
+	ErrInvalidCiphertext = errors.New("ciphertext could not be authenticated with its key")
+	ErrMissingMasterKey  = errors.New("wrapped keys require a kms master key")
+	ErrInvalidWrapped    = errors.New("could not unwrap key with the kms master key")
+)
+
+type InvalidKeyError struct {
+	ID     string
+	Reason string
+}
+
+func (e *InvalidKeyError) Error() string {
+	return fmt.Sprintf("invalid keyring key [%s]. Reason: %s", e.ID, e.Reason)
+}
+
+// RotationError reports the records a rotation has left encrypted with previous keys.
+type RotationError struct {
+	Failed int
+	Total  int
+}
+
+func (e *RotationError) Error() string {
+	return fmt.Sprintf("could not rotate %d of %d records encrypted with previous keys", e.Failed, e.Total)
+}
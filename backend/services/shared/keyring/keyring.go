@@ -0,0 +1,208 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package keyring provides versioned encryption keys for data persisted by
+// the integration services.
+//
+// Ciphertexts produced with a configured key are prefixed with the key id
+// ("<id>:<ciphertext>"). Ciphertexts without a prefix were produced before
+// the keyring was introduced and are decrypted with the legacy key, which
+// defaults to the integration client secret.
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"golang.org/x/oauth2"
+)
+
+const keySeparator = ":"
+
+// KeySize is the length of keyring keys, which are used as AES-256 keys.
+const KeySize = 32
+
+// A Keyring encrypts new values with the active key and decrypts values
+// produced with any key it knows about.
+type Keyring interface {
+	Encrypt(text string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+	// NeedsRotation reports whether ciphertext was not produced with the active key.
+	NeedsRotation(ciphertext string) bool
+	ActiveKeyID() string
+}
+
+type keyring struct {
+	encryptor crypto.Encryptor
+	active    string
+	keys      map[string][]byte
+	legacy    []byte
+}
+
+func NewKeyring(
+	config *KeyringConfig,
+	encryptor crypto.Encryptor,
+	credentials *oauth2.Config,
+) (Keyring, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	ring := keyring{
+		encryptor: encryptor,
+		active:    config.Keyring.Active,
+		keys:      make(map[string][]byte, len(config.Keyring.Keys)),
+		legacy:    []byte(credentials.ClientSecret),
+	}
+
+	for _, key := range config.Keyring.Keys {
+		material, err := resolveKey(key, encryptor, config.Keyring.KMS.MasterKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := validateKeySize(key.ID, material); err != nil {
+			return nil, err
+		}
+
+		ring.keys[strings.TrimSpace(key.ID)] = material
+	}
+
+	if !config.Keyring.Legacy.isEmpty() {
+		material, err := resolveKey(config.Keyring.Legacy, encryptor, config.Keyring.KMS.MasterKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		ring.legacy = material
+	}
+
+	return ring, nil
+}
+
+func (k keyring) Encrypt(text string) (string, error) {
+	if k.active == "" {
+		return k.encryptor.Encrypt(text, k.legacy)
+	}
+
+	ciphertext, err := k.encryptor.Encrypt(text, k.keys[k.active])
+	if err != nil {
+		return "", err
+	}
+
+	return k.active + keySeparator + ciphertext, nil
+}
+
+func (k keyring) Decrypt(ciphertext string) (string, error) {
+	id, text := splitCiphertext(ciphertext)
+	if id == "" {
+		return k.decrypt(text, k.legacy)
+	}
+
+	key, ok := k.keys[id]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	return k.decrypt(text, key)
+}
+
+// decrypt rejects ciphertexts which do not authenticate with the key. The encryptor returns
+// an empty text for them instead of an error, which would otherwise be stored on rotation.
+func (k keyring) decrypt(text string, key []byte) (string, error) {
+	plaintext, err := k.encryptor.Decrypt(text, key)
+	if err != nil || plaintext != "" {
+		return plaintext, err
+	}
+
+	if err := authenticate(text, key); err != nil {
+		return "", err
+	}
+
+	return plaintext, nil
+}
+
+func (k keyring) NeedsRotation(ciphertext string) bool {
+	id, _ := splitCiphertext(ciphertext)
+	return id != k.active
+}
+
+func (k keyring) ActiveKeyID() string {
+	return k.active
+}
+
+func resolveKey(key KeyConfig, encryptor crypto.Encryptor, masterKeyFile string) ([]byte, error) {
+	provider, err := newKeyProvider(key, encryptor, masterKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Resolve(key)
+}
+
+// validateKeySize rejects keys which would not be used as they are. Legacy keys predate
+// the keyring and keep whatever length the client secret has.
+func validateKeySize(id string, key []byte) error {
+	if len(key) != KeySize {
+		return &InvalidKeyError{
+			ID:     id,
+			Reason: fmt.Sprintf("Should be %d bytes long", KeySize),
+		}
+	}
+
+	return nil
+}
+
+// authenticate opens an AES-GCM ciphertext of the encryptor, whose keys are padded or cut to 32 bytes.
+func authenticate(text string, key []byte) error {
+	validKey := make([]byte, KeySize)
+	copy(validKey, key)
+
+	block, err := aes.NewCipher(validKey)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	buf, err := base64.StdEncoding.DecodeString(text)
+	if err != nil || len(buf) < gcm.NonceSize() {
+		return ErrInvalidCiphertext
+	}
+
+	if _, err := gcm.Open(nil, buf[:gcm.NonceSize()], buf[gcm.NonceSize():], nil); err != nil {
+		return ErrInvalidCiphertext
+	}
+
+	return nil
+}
+
+func splitCiphertext(ciphertext string) (string, string) {
+	if id, text, ok := strings.Cut(ciphertext, keySeparator); ok {
+		return id, text
+	}
+
+	return "", ciphertext
+}
@@ -0,0 +1,185 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keyring
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+var encryptor = crypto.NewEncryptor(&config.CryptoConfig{})
+
+const (
+	firstKey  = "first-key-0123456789abcdefghijkl"
+	secondKey = "second-key-0123456789abcdefghijk"
+	masterKey = "master-key-0123456789abcdefghijk"
+)
+
+var credentials = &oauth2.Config{
+	ClientSecret: "legacy-secret",
+}
+
+func newConfig(active string, keys ...KeyConfig) *KeyringConfig {
+	var config KeyringConfig
+	config.Keyring.Active = active
+	config.Keyring.Keys = keys
+	return &config
+}
+
+func TestKeyring(t *testing.T) {
+	legacy, err := encryptor.Encrypt("token", []byte(credentials.ClientSecret))
+	assert.NoError(t, err)
+
+	t.Run("encrypt with legacy key when no active key is set", func(t *testing.T) {
+		ring, err := NewKeyring(newConfig(""), encryptor, credentials)
+		assert.NoError(t, err)
+
+		ciphertext, err := ring.Encrypt("token")
+		assert.NoError(t, err)
+		assert.False(t, strings.Contains(ciphertext, keySeparator))
+		assert.False(t, ring.NeedsRotation(ciphertext))
+
+		text, err := ring.Decrypt(legacy)
+		assert.NoError(t, err)
+		assert.Equal(t, "token", text)
+	})
+
+	t.Run("encrypt with the active key", func(t *testing.T) {
+		ring, err := NewKeyring(newConfig("v2",
+			KeyConfig{ID: "v1", Value: firstKey},
+			KeyConfig{ID: "v2", Value: secondKey},
+		), encryptor, credentials)
+		assert.NoError(t, err)
+
+		ciphertext, err := ring.Encrypt("token")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(ciphertext, "v2:"))
+		assert.False(t, ring.NeedsRotation(ciphertext))
+		assert.True(t, ring.NeedsRotation(legacy))
+
+		text, err := ring.Decrypt(ciphertext)
+		assert.NoError(t, err)
+		assert.Equal(t, "token", text)
+
+		text, err = ring.Decrypt(legacy)
+		assert.NoError(t, err)
+		assert.Equal(t, "token", text)
+	})
+
+	t.Run("decrypt with an unknown key", func(t *testing.T) {
+		ring, err := NewKeyring(newConfig("v1", KeyConfig{ID: "v1", Value: firstKey}), encryptor, credentials)
+		assert.NoError(t, err)
+
+		_, err = ring.Decrypt("v3:ciphertext")
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("decrypt with a wrong key", func(t *testing.T) {
+		ring, err := NewKeyring(newConfig("v1", KeyConfig{ID: "v1", Value: firstKey}), encryptor, &oauth2.Config{
+			ClientSecret: "another-secret",
+		})
+		assert.NoError(t, err)
+
+		_, err = ring.Decrypt(legacy)
+		assert.ErrorIs(t, err, ErrInvalidCiphertext)
+
+		ciphertext, err := encryptor.Encrypt("token", []byte(secondKey))
+		assert.NoError(t, err)
+		_, err = ring.Decrypt("v1:" + ciphertext)
+		assert.ErrorIs(t, err, ErrInvalidCiphertext)
+	})
+
+	t.Run("decrypt an empty value", func(t *testing.T) {
+		ring, err := NewKeyring(newConfig("v1", KeyConfig{ID: "v1", Value: firstKey}), encryptor, credentials)
+		assert.NoError(t, err)
+
+		ciphertext, err := ring.Encrypt("")
+		assert.NoError(t, err)
+
+		text, err := ring.Decrypt(ciphertext)
+		assert.NoError(t, err)
+		assert.Empty(t, text)
+	})
+
+	t.Run("reject an active key that is not configured", func(t *testing.T) {
+		_, err := NewKeyring(newConfig("v2", KeyConfig{ID: "v1", Value: firstKey}), encryptor, credentials)
+		assert.Error(t, err)
+	})
+
+	t.Run("load keys from files and the local kms", func(t *testing.T) {
+		dir := t.TempDir()
+		keyFile := filepath.Join(dir, "v1.key")
+		masterFile := filepath.Join(dir, "master.key")
+		assert.NoError(t, os.WriteFile(keyFile, []byte(firstKey+"\n"), 0600))
+		assert.NoError(t, os.WriteFile(masterFile, []byte(masterKey+"\n"), 0600))
+
+		kms, err := NewLocalKMSProvider(encryptor, masterFile)
+		assert.NoError(t, err)
+
+		wrapped, err := kms.Wrap([]byte(secondKey))
+		assert.NoError(t, err)
+
+		config := newConfig("v2",
+			KeyConfig{ID: "v1", File: keyFile},
+			KeyConfig{ID: "v2", Wrapped: wrapped},
+		)
+		config.Keyring.KMS.MasterKeyFile = masterFile
+
+		ring, err := NewKeyring(config, encryptor, credentials)
+		assert.NoError(t, err)
+
+		ciphertext, err := encryptor.Encrypt("token", []byte(secondKey))
+		assert.NoError(t, err)
+
+		text, err := ring.Decrypt("v2:" + ciphertext)
+		assert.NoError(t, err)
+		assert.Equal(t, "token", text)
+
+		ciphertext, err = encryptor.Encrypt("token", []byte(firstKey))
+		assert.NoError(t, err)
+
+		text, err = ring.Decrypt("v1:" + ciphertext)
+		assert.NoError(t, err)
+		assert.Equal(t, "token", text)
+	})
+
+	t.Run("reject keys that are not 32 bytes long", func(t *testing.T) {
+		_, err := NewKeyring(newConfig("v1", KeyConfig{ID: "v1", Value: "short-key"}), encryptor, credentials)
+		var kerr *InvalidKeyError
+		assert.ErrorAs(t, err, &kerr)
+
+		keyFile := filepath.Join(t.TempDir(), "v1.key")
+		assert.NoError(t, os.WriteFile(keyFile, []byte("short-key\n"), 0600))
+
+		_, err = NewKeyring(newConfig("v1", KeyConfig{ID: "v1", File: keyFile}), encryptor, credentials)
+		assert.ErrorAs(t, err, &kerr)
+	})
+
+	t.Run("reject wrapped keys without a master key", func(t *testing.T) {
+		_, err := NewKeyring(newConfig("v1", KeyConfig{ID: "v1", Wrapped: "wrapped"}), encryptor, credentials)
+		assert.ErrorIs(t, err, ErrMissingMasterKey)
+	})
+}
@@ -0,0 +1,117 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keyring
+
+import (
+	"os"
+	"strings"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+)
+
+// A KeyProvider resolves raw key material for a configured key.
+type KeyProvider interface {
+	Resolve(key KeyConfig) ([]byte, error)
+}
+
+type staticKeyProvider struct{}
+
+func (p staticKeyProvider) Resolve(key KeyConfig) ([]byte, error) {
+	return []byte(key.Value), nil
+}
+
+type fileKeyProvider struct{}
+
+func (p fileKeyProvider) Resolve(key KeyConfig) ([]byte, error) {
+	return readKeyFile(key.File)
+}
+
+// A LocalKMSProvider mimics a KMS envelope scheme locally: data keys are stored
+// wrapped (encrypted) with a master key that never leaves the key file.
+type LocalKMSProvider struct {
+	encryptor crypto.Encryptor
+	master    []byte
+}
+
+func NewLocalKMSProvider(encryptor crypto.Encryptor, masterKeyFile string) (LocalKMSProvider, error) {
+	if masterKeyFile == "" {
+		return LocalKMSProvider{}, ErrMissingMasterKey
+	}
+
+	master, err := readKeyFile(masterKeyFile)
+	if err != nil {
+		return LocalKMSProvider{}, err
+	}
+
+	if err := validateKeySize(masterKeyFile, master); err != nil {
+		return LocalKMSProvider{}, err
+	}
+
+	return LocalKMSProvider{
+		encryptor: encryptor,
+		master:    master,
+	}, nil
+}
+
+func (p LocalKMSProvider) Resolve(key KeyConfig) ([]byte, error) {
+	dkey, err := p.encryptor.Decrypt(key.Wrapped, p.master)
+	if err != nil {
+		return nil, err
+	}
+
+	if dkey == "" {
+		return nil, ErrInvalidWrapped
+	}
+
+	return []byte(dkey), nil
+}
+
+// Wrap encrypts a data key with the master key so that it can be stored
+// in the keyring configuration as a wrapped key.
+func (p LocalKMSProvider) Wrap(key []byte) (string, error) {
+	return p.encryptor.Encrypt(string(key), p.master)
+}
+
+func newKeyProvider(key KeyConfig, encryptor crypto.Encryptor, masterKeyFile string) (KeyProvider, error) {
+	switch {
+	case key.Wrapped != "":
+		return NewLocalKMSProvider(encryptor, masterKeyFile)
+	case key.File != "":
+		return fileKeyProvider{}, nil
+	default:
+		return staticKeyProvider{}, nil
+	}
+}
+
+func readKeyFile(path string) ([]byte, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := strings.TrimSpace(string(buf))
+	if key == "" {
+		return nil, &InvalidKeyError{
+			ID:     path,
+			Reason: "Key file should not be empty",
+		}
+	}
+
+	return []byte(key), nil
+}
@@ -0,0 +1,84 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keyring
+
+import (
+	"context"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+)
+
+// RecordRotation re-encrypts the records of one kind a service stores.
+type RecordRotation[T any] struct {
+	Keyring Keyring
+	Logger  log.Logger
+	// Describe names a record in logs, e.g. "user 1:2 tokens".
+	Describe func(record T) string
+	// Ciphertexts lists the values of a record encrypted with the keyring.
+	Ciphertexts func(record T) []string
+	// Rotate re-encrypts and stores a record holding values encrypted with previous keys.
+	Rotate func(ctx context.Context, record T) error
+}
+
+// Run rotates the records which were not encrypted with the active key. Records that fail to rotate
+// are left untouched so that the rotation may be retried, and are reported as a rotation error once
+// the other records have been rotated.
+func (r RecordRotation[T]) Run(ctx context.Context, records []T) (int, error) {
+	rotated, failed := 0, 0
+	for _, record := range records {
+		if !r.needsRotation(record) {
+			continue
+		}
+
+		r.Logger.Debugf("rotating %s to key %s", r.Describe(record), r.Keyring.ActiveKeyID())
+		if err := r.Rotate(ctx, record); err != nil {
+			r.Logger.Warnf("could not rotate %s: %s", r.Describe(record), err.Error())
+			failed++
+			continue
+		}
+
+		rotated++
+	}
+
+	if failed > 0 {
+		return rotated, &RotationError{Failed: failed, Total: rotated + failed}
+	}
+
+	return rotated, nil
+}
+
+func (r RecordRotation[T]) needsRotation(record T) bool {
+	for _, ciphertext := range r.Ciphertexts(record) {
+		if r.Keyring.NeedsRotation(ciphertext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reencrypt encrypts a value encrypted with any known key with the active key.
+func Reencrypt(ring Keyring, ciphertext string) (string, error) {
+	text, err := ring.Decrypt(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	return ring.Encrypt(text)
+}
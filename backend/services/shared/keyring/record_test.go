@@ -0,0 +1,70 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keyring
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordRotation(t *testing.T) {
+	ring, err := NewKeyring(newConfig("v1", KeyConfig{ID: "v1", Value: firstKey}), encryptor, credentials)
+	assert.NoError(t, err)
+
+	legacy, err := encryptor.Encrypt("token", []byte(credentials.ClientSecret))
+	assert.NoError(t, err)
+	wrong, err := encryptor.Encrypt("token", []byte("another-secret"))
+	assert.NoError(t, err)
+	current, err := ring.Encrypt("token")
+	assert.NoError(t, err)
+
+	stored := map[string]string{}
+	rotation := RecordRotation[string]{
+		Keyring:     ring,
+		Logger:      log.NewEmptyLogger(),
+		Describe:    func(record string) string { return "record" },
+		Ciphertexts: func(record string) []string { return []string{record} },
+		Rotate: func(ctx context.Context, record string) error {
+			ciphertext, err := Reencrypt(ring, record)
+			if err != nil {
+				return err
+			}
+
+			stored[record] = ciphertext
+			return nil
+		},
+	}
+
+	rotated, err := rotation.Run(context.Background(), []string{legacy, wrong, current})
+	assert.Equal(t, 1, rotated)
+
+	var rerr *RotationError
+	assert.ErrorAs(t, err, &rerr)
+	assert.Equal(t, 1, rerr.Failed)
+	assert.Equal(t, 2, rerr.Total)
+
+	assert.Len(t, stored, 1)
+	text, err := ring.Decrypt(stored[legacy])
+	assert.NoError(t, err)
+	assert.Equal(t, "token", text)
+	assert.NotContains(t, stored, wrong)
+}
@@ -0,0 +1,112 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keyring
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/config"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/crypto"
+	"github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared"
+	"github.com/urfave/cli/v2"
+)
+
+// A Rotation re-encrypts the records a service stores with the active key and returns how many were rotated.
+type Rotation func(ctx context.Context) (int, error)
+
+// NewRotateCommand builds the maintenance command which re-encrypts the records of a service
+// kept in the persistent storage. Records is what the records are called in the command output.
+func NewRotateCommand(
+	usage, records string,
+	newRotation func(storageURL string, ring Keyring, logger log.Logger) Rotation,
+) *cli.Command {
+	return &cli.Command{
+		Name:     "rotate",
+		Usage:    usage,
+		Category: "maintenance",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config_path",
+				Usage:   "sets custom configuration path",
+				Aliases: []string{"config", "conf", "c"},
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "sets rotation timeout",
+				Value: 10 * time.Minute,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var (
+				CONFIG_PATH = c.String("config_path")
+			)
+
+			storage, err := config.BuildNewStorageConfig(CONFIG_PATH)()
+			if err != nil {
+				return err
+			}
+
+			if storage.Storage.URL == "" {
+				return errors.New("rotation requires a persistent storage url")
+			}
+
+			cryptoConfig, err := config.BuildNewCryptoConfig(CONFIG_PATH)()
+			if err != nil {
+				return err
+			}
+
+			loggerConfig, err := config.BuildNewLoggerConfig(CONFIG_PATH)()
+			if err != nil {
+				return err
+			}
+
+			credentials, err := shared.BuildNewIntegrationCredentialsConfig(CONFIG_PATH)()
+			if err != nil {
+				return err
+			}
+
+			keyringConfig, err := BuildNewKeyringConfig(CONFIG_PATH)()
+			if err != nil {
+				return err
+			}
+
+			if keyringConfig.Keyring.Active == "" {
+				return errors.New("rotation requires an active keyring key")
+			}
+
+			ring, err := NewKeyring(keyringConfig, crypto.NewEncryptor(cryptoConfig), credentials)
+			if err != nil {
+				return err
+			}
+
+			logger := log.NewDefaultLogger(loggerConfig)
+			ctx, cancel := context.WithTimeout(c.Context, c.Duration("timeout"))
+			defer cancel()
+
+			// Records which could not be rotated fail the command once the others are done,
+			// so the previous key is not removed while it is still in use
+			rotated, err := newRotation(storage.Storage.URL, ring, logger)(ctx)
+			logger.Infof("rotated %d %s to key %s", rotated, records, ring.ActiveKeyID())
+			return err
+		},
+	}
+}
@@ -0,0 +1,122 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package merge
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxFieldDepth limits how deep nested record objects are flattened, e.g. deal.person_id.email.
+const maxFieldDepth int = 3
+
+// Fields maps placeholder names such as deal.title or person.email to their values.
+type Fields map[string]string
+
+// AddRecord flattens a Pipedrive record into fields prefixed with the record name.
+// Nested objects are joined with dots and lists of labeled values (emails, phones)
+// are reduced to their primary value.
+func (f Fields) AddRecord(prefix string, data map[string]interface{}) {
+	f.add(prefix, data, 1)
+}
+
+// AddList adds records as prefix.1.field, prefix.2.field and so on, along with prefix.count.
+func (f Fields) AddList(prefix string, data []map[string]interface{}) {
+	for i, record := range data {
+		f.add(prefix+"."+strconv.Itoa(i+1), record, 1)
+	}
+
+	f[prefix+".count"] = strconv.Itoa(len(data))
+}
+
+// Names returns sorted field names.
+func (f Fields) Names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func (f Fields) add(prefix string, data map[string]interface{}, depth int) {
+	for key, value := range data {
+		name := prefix + "." + key
+		switch val := value.(type) {
+		case map[string]interface{}:
+			if depth < maxFieldDepth {
+				f.add(name, val, depth+1)
+			}
+		case []interface{}:
+			if primary, ok := primaryValue(val); ok {
+				f[name] = primary
+			}
+		default:
+			if text, ok := formatValue(val); ok {
+				f[name] = text
+			}
+		}
+	}
+}
+
+// primaryValue picks the primary entry of a Pipedrive labeled value list or its first entry.
+func primaryValue(values []interface{}) (string, bool) {
+	var first string
+	found := false
+	for _, value := range values {
+		entry, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		text, ok := formatValue(entry["value"])
+		if !ok {
+			continue
+		}
+
+		if primary, _ := entry["primary"].(bool); primary {
+			return text, true
+		}
+
+		if !found {
+			first, found = text, true
+		}
+	}
+
+	return first, found
+}
+
+func formatValue(value interface{}) (string, bool) {
+	switch val := value.(type) {
+	case nil:
+		return "", true
+	case string:
+		return strings.TrimSpace(val), true
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(val), true
+	case bool:
+		return strconv.FormatBool(val), true
+	default:
+		return "", false
+	}
+}
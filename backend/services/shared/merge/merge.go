@@ -0,0 +1,188 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package merge fills {{placeholder}} fields of office open xml documents.
+//
+// Placeholders are matched against the text of a whole document part, so a
+// placeholder split by the editor into several runs is still replaced. The
+// value is written into the run the placeholder starts in, keeping its
+// formatting, and the rest of the placeholder is removed from the other runs.
+// Placeholders without a value are left as they are.
+package merge
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+	textPattern        = regexp.MustCompile(`(<(w:t|a:t|t)(?:\s[^>]*)?>)([^<]*)</(?:w:t|a:t|t)>`)
+	partPattern        = regexp.MustCompile(
+		`^(word/(document|header\d*|footer\d*|footnotes|endnotes)|ppt/(slides/slide|notesSlides/notesSlide)\d+|xl/sharedStrings)\.xml$`,
+	)
+)
+
+// Fill replaces placeholders in a docx, xlsx or pptx document.
+func Fill(content []byte, fields Fields) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, ErrUnsupportedDocument
+	}
+
+	supported := false
+	for _, file := range reader.File {
+		if file.Name == "[Content_Types].xml" {
+			supported = true
+			break
+		}
+	}
+
+	if !supported {
+		return nil, ErrUnsupportedDocument
+	}
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, file := range reader.File {
+		if !partPattern.MatchString(file.Name) {
+			if err := writer.Copy(file); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		part, err := readPart(file)
+		if err != nil {
+			return nil, err
+		}
+
+		header := file.FileHeader
+		header.Method = zip.Deflate
+		w, err := writer.CreateHeader(&header)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := w.Write(fillPart(part, fields)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func readPart(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// fillPart replaces placeholders in the text nodes of a single document part.
+func fillPart(part []byte, fields Fields) []byte {
+	nodes := textPattern.FindAllSubmatchIndex(part, -1)
+	if len(nodes) == 0 {
+		return part
+	}
+
+	var joined strings.Builder
+	starts := make([]int, len(nodes))
+	ends := make([]int, len(nodes))
+	for i, node := range nodes {
+		starts[i] = joined.Len()
+		joined.Write(part[node[6]:node[7]])
+		ends[i] = joined.Len()
+	}
+
+	text := joined.String()
+	matches := placeholderPattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return part
+	}
+
+	texts := make([]strings.Builder, len(nodes))
+	changed := make([]bool, len(nodes))
+	nodeAt := func(pos int) int {
+		return sort.Search(len(nodes), func(i int) bool { return ends[i] > pos })
+	}
+
+	copyText := func(from, to int) {
+		for pos := from; pos < to; {
+			i := nodeAt(pos)
+			end := min(ends[i], to)
+			texts[i].WriteString(text[pos:end])
+			pos = end
+		}
+	}
+
+	pos := 0
+	for _, match := range matches {
+		copyText(pos, match[0])
+		i := nodeAt(match[0])
+		if value, ok := fields[text[match[2]:match[3]]]; ok {
+			xml.EscapeText(&texts[i], []byte(value))
+			for j := i; j < len(nodes) && starts[j] < match[1]; j++ {
+				changed[j] = true
+			}
+		} else {
+			copyText(match[0], match[1])
+		}
+
+		pos = match[1]
+	}
+	copyText(pos, len(text))
+
+	var out bytes.Buffer
+	last := 0
+	for i, node := range nodes {
+		if !changed[i] {
+			continue
+		}
+
+		out.Write(part[last:node[0]])
+		out.Write(openingTag(part[node[2]:node[3]], string(part[node[4]:node[5]])))
+		out.WriteString(texts[i].String())
+		last = node[7]
+	}
+	out.Write(part[last:])
+
+	return out.Bytes()
+}
+
+// openingTag keeps spaces around replaced values in word and spreadsheet text nodes.
+func openingTag(tag []byte, name string) []byte {
+	if name == "a:t" || bytes.Contains(tag, []byte("xml:space")) {
+		return tag
+	}
+
+	return append([]byte(`<`+name+` xml:space="preserve"`), tag[len(name)+1:]...)
+}
@@ -0,0 +1,144 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package merge
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildDocument(t *testing.T, parts map[string]string) []byte {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := writer.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func readDocumentPart(t *testing.T, content []byte, name string) string {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+	for _, file := range reader.File {
+		if file.Name != name {
+			continue
+		}
+
+		rc, err := file.Open()
+		assert.NoError(t, err)
+		defer rc.Close()
+		part, err := io.ReadAll(rc)
+		assert.NoError(t, err)
+		return string(part)
+	}
+
+	t.Fatalf("part %s not found", name)
+	return ""
+}
+
+func TestFields(t *testing.T) {
+	fields := Fields{}
+	fields.AddRecord("deal", map[string]interface{}{
+		"title": " Big deal ",
+		"value": float64(1500),
+		"won":   false,
+		"person_id": map[string]interface{}{
+			"name": "John Smith",
+			"email": []interface{}{
+				map[string]interface{}{"value": "john@work.com", "primary": false},
+				map[string]interface{}{"value": "john@home.com", "primary": true},
+			},
+		},
+		"close_time": nil,
+	})
+	fields.AddList("products", []map[string]interface{}{
+		{"name": "Licence", "item_price": 9.5},
+	})
+
+	assert.Equal(t, "Big deal", fields["deal.title"])
+	assert.Equal(t, "1500", fields["deal.value"])
+	assert.Equal(t, "false", fields["deal.won"])
+	assert.Equal(t, "John Smith", fields["deal.person_id.name"])
+	assert.Equal(t, "john@home.com", fields["deal.person_id.email"])
+	assert.Equal(t, "", fields["deal.close_time"])
+	assert.Equal(t, "Licence", fields["products.1.name"])
+	assert.Equal(t, "9.5", fields["products.1.item_price"])
+	assert.Equal(t, "1", fields["products.count"])
+	assert.Contains(t, fields.Names(), "deal.person_id.email")
+}
+
+func TestFill(t *testing.T) {
+	fields := Fields{
+		"deal.title":  "Supply & support",
+		"person.name": "John Smith",
+	}
+
+	t.Run("fill a word document", func(t *testing.T) {
+		doc := buildDocument(t, map[string]string{
+			"[Content_Types].xml": `<Types/>`,
+			"word/document.xml": `<w:document><w:body><w:p><w:r><w:t>Deal: {{deal.title}}</w:t></w:r></w:p>` +
+				`<w:p><w:r><w:t>Dear {{</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>person.name</w:t></w:r>` +
+				`<w:r><w:t xml:space="preserve">}}, </w:t></w:r><w:r><w:t>{{ unknown }}</w:t></w:r></w:p></w:body></w:document>`,
+			"word/footer1.xml": `<w:ftr><w:p><w:r><w:t>{{person.name}}</w:t></w:r></w:p></w:ftr>`,
+			"word/styles.xml":  `<w:styles>{{deal.title}}</w:styles>`,
+		})
+
+		filled, err := Fill(doc, fields)
+		assert.NoError(t, err)
+		assert.Equal(t, `<w:document><w:body><w:p><w:r><w:t xml:space="preserve">Deal: Supply &amp; support</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t xml:space="preserve">Dear John Smith</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve"></w:t></w:r>`+
+			`<w:r><w:t xml:space="preserve">, </w:t></w:r><w:r><w:t>{{ unknown }}</w:t></w:r></w:p></w:body></w:document>`,
+			readDocumentPart(t, filled, "word/document.xml"))
+		assert.Equal(t, `<w:ftr><w:p><w:r><w:t xml:space="preserve">John Smith</w:t></w:r></w:p></w:ftr>`,
+			readDocumentPart(t, filled, "word/footer1.xml"))
+		assert.Equal(t, `<w:styles>{{deal.title}}</w:styles>`, readDocumentPart(t, filled, "word/styles.xml"))
+	})
+
+	t.Run("fill spreadsheet and presentation text", func(t *testing.T) {
+		doc := buildDocument(t, map[string]string{
+			"[Content_Types].xml":   `<Types/>`,
+			"xl/sharedStrings.xml":  `<sst><si><t>{{deal.title}}</t></si><si><t>Total</t></si></sst>`,
+			"ppt/slides/slide1.xml": `<p:sld><a:p><a:r><a:t>{{person.name}}</a:t></a:r></a:p></p:sld>`,
+		})
+
+		filled, err := Fill(doc, fields)
+		assert.NoError(t, err)
+		assert.Equal(t, `<sst><si><t xml:space="preserve">Supply &amp; support</t></si><si><t>Total</t></si></sst>`,
+			readDocumentPart(t, filled, "xl/sharedStrings.xml"))
+		assert.Equal(t, `<p:sld><a:p><a:r><a:t>John Smith</a:t></a:r></a:p></p:sld>`,
+			readDocumentPart(t, filled, "ppt/slides/slide1.xml"))
+	})
+
+	t.Run("reject documents which are not office open xml", func(t *testing.T) {
+		_, err := Fill([]byte("%PDF-1.7"), fields)
+		assert.ErrorIs(t, err, ErrUnsupportedDocument)
+
+		_, err = Fill(buildDocument(t, map[string]string{"mimetype": "text"}), fields)
+		assert.ErrorIs(t, err, ErrUnsupportedDocument)
+	})
+}
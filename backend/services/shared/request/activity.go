@@ -0,0 +1,47 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import "encoding/json"
+
+type DocumentActivity struct {
+	CompanyID  int      `json:"company_id" mapstructure:"company_id"`
+	FileID     string   `json:"file_id" mapstructure:"file_id"`
+	EntityType string   `json:"entity_type" mapstructure:"entity_type"`
+	EntityID   string   `json:"entity_id" mapstructure:"entity_id"`
+	DocKey     string   `json:"doc_key" mapstructure:"doc_key"`
+	Status     int      `json:"status" mapstructure:"status"`
+	Users      []string `json:"users" mapstructure:"users"`
+	Error      string   `json:"error,omitempty" mapstructure:"error"`
+}
+
+func (a DocumentActivity) ToJSON() []byte {
+	buf, _ := json.Marshal(a)
+	return buf
+}
+
+type DocumentActivitySelect struct {
+	CompanyID int    `json:"company_id" mapstructure:"company_id"`
+	FileID    string `json:"file_id" mapstructure:"file_id"`
+}
+
+func (a DocumentActivitySelect) ToJSON() []byte {
+	buf, _ := json.Marshal(a)
+	return buf
+}
@@ -22,6 +22,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
 )
 
 type MissingRequestFieldsError struct {
@@ -39,11 +42,79 @@ type CallbackRequest struct {
 		Type   int    `json:"type"`
 		UserID string `json:"userid"`
 	} `json:"actions"`
-	Key    string   `json:"key"`
-	Status int      `json:"status"`
-	Users  []string `json:"users"`
-	URL    string   `json:"url"`
-	Token  string   `json:"token"`
+	Key           string          `json:"key"`
+	Status        int             `json:"status"`
+	Users         []string        `json:"users"`
+	URL           string          `json:"url"`
+	FileType      string          `json:"filetype" mapstructure:"filetype"`
+	ForceSaveType int             `json:"forcesavetype" mapstructure:"forcesavetype"`
+	ChangesURL    string          `json:"changesurl" mapstructure:"changesurl"`
+	History       CallbackHistory `json:"history"`
+	Token         string          `json:"token"`
+}
+
+// callbackTokenTTL bounds how long a callback url stays valid. The document server
+// keeps the url of the last user who joined the editing session.
+const callbackTokenTTL = 7 * 24 * time.Hour
+
+// CallbackTokenContext carries the file a callback saves to. It is signed into the callback url, so
+// document server callbacks cannot be redirected to another file or entity, and bound to a document key.
+type CallbackTokenContext struct {
+	jwt.RegisteredClaims
+	Purpose    string `json:"typ" mapstructure:"typ"`
+	CID        int    `json:"cid" mapstructure:"cid"`
+	EntityType string `json:"etype" mapstructure:"etype"`
+	EntityID   string `json:"eid" mapstructure:"eid"`
+	FileID     string `json:"fid" mapstructure:"fid"`
+	Filename   string `json:"filename" mapstructure:"filename"`
+	DocKey     string `json:"key" mapstructure:"key"`
+}
+
+func NewCallbackTokenContext(cid int, entity ParentEntity, fileID, filename, key string) CallbackTokenContext {
+	return CallbackTokenContext{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(callbackTokenTTL)),
+		},
+		Purpose:    TokenPurposeCallback,
+		CID:        cid,
+		EntityType: entity.Type,
+		EntityID:   entity.ID,
+		FileID:     fileID,
+		Filename:   filename,
+		DocKey:     key,
+	}
+}
+
+// Entity returns the Pipedrive record the saved file is attached to.
+func (c CallbackTokenContext) Entity() ParentEntity {
+	return NewParentEntity(c.EntityType, c.EntityID)
+}
+
+// Validate rejects tokens issued for anything but a document server callback.
+func (c CallbackTokenContext) Validate() error {
+	if c.Purpose != TokenPurposeCallback {
+		return ErrInvalidTokenPurpose
+	}
+
+	if c.CID <= 0 || strings.TrimSpace(c.FileID) == "" || c.DocKey == "" {
+		return ErrInvalidTokenClaims
+	}
+
+	return c.Entity().Validate()
+}
+
+// CallbackHistory describes the changes made to a document since its previous version.
+type CallbackHistory struct {
+	ServerVersion string           `json:"serverVersion" mapstructure:"serverVersion"`
+	Changes       []CallbackChange `json:"changes" mapstructure:"changes"`
+}
+
+type CallbackChange struct {
+	Created string `json:"created" mapstructure:"created"`
+	User    struct {
+		ID   string `json:"id" mapstructure:"id"`
+		Name string `json:"name" mapstructure:"name"`
+	} `json:"user" mapstructure:"user"`
 }
 
 func (cr CallbackRequest) ToJSON() []byte {
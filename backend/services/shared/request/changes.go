@@ -0,0 +1,64 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ChangesTokenContext grants the document server short-lived access to a stored changes archive.
+type ChangesTokenContext struct {
+	jwt.RegisteredClaims
+	Purpose string `json:"typ" mapstructure:"typ"`
+	CID     int    `json:"cid" mapstructure:"cid"`
+	FileID  string `json:"fid" mapstructure:"fid"`
+}
+
+// NewChangesTokenContext grants access for as long as the editor needs to request a version diff.
+func NewChangesTokenContext(cid int, fileID string) ChangesTokenContext {
+	return ChangesTokenContext{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
+		},
+		Purpose: TokenPurposeChanges,
+		CID:     cid,
+		FileID:  fileID,
+	}
+}
+
+// Validate rejects tokens issued for anything but a changes archive.
+func (c ChangesTokenContext) Validate() error {
+	if c.Purpose != TokenPurposeChanges {
+		return ErrInvalidTokenPurpose
+	}
+
+	if c.CID <= 0 || c.FileID == "" {
+		return ErrInvalidTokenClaims
+	}
+
+	return nil
+}
+
+func (c ChangesTokenContext) ToJSON() []byte {
+	buf, _ := json.Marshal(c)
+	return buf
+}
@@ -20,16 +20,39 @@ package request
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-type BaseCommandRequest struct {
+// Document server command service commands.
+// See https://api.onlyoffice.com/docs/docs-api/additional-api/command-service/
+const (
+	CommandForcesave        string = "forcesave"
+	CommandDrop             string = "drop"
+	CommandInfo             string = "info"
+	CommandMeta             string = "meta"
+	CommandVersion          string = "version"
+	CommandLicense          string = "license"
+	CommandGetForgotten     string = "getForgotten"
+	CommandGetForgottenList string = "getForgottenList"
+	CommandDeleteForgotten  string = "deleteForgotten"
+)
+
+type CommandDocumentMeta struct {
+	Title string `json:"title"`
+}
+
+type CommandRequest struct {
 	jwt.RegisteredClaims
-	C string `json:"c"`
+	C        string               `json:"c"`
+	Key      string               `json:"key,omitempty"`
+	Users    []string             `json:"users,omitempty"`
+	UserData string               `json:"userdata,omitempty"`
+	Meta     *CommandDocumentMeta `json:"meta,omitempty"`
 }
 
-func (c BaseCommandRequest) ToJSON() []byte {
+func (c CommandRequest) ToJSON() []byte {
 	buf, _ := json.Marshal(c)
 	return buf
 }
@@ -42,3 +65,50 @@ func (c TokenCommandRequest) ToJSON() []byte {
 	buf, _ := json.Marshal(c)
 	return buf
 }
+
+// DocumentCommandRequest asks the builder to run a command against the company document server.
+// Commands addressing a document take either its key or the id of a file with a known editing session.
+type DocumentCommandRequest struct {
+	CID      int      `json:"cid" mapstructure:"cid"`
+	UID      int      `json:"uid" mapstructure:"uid"`
+	Command  string   `json:"command" mapstructure:"command"`
+	Key      string   `json:"key,omitempty" mapstructure:"key"`
+	FileID   string   `json:"file_id,omitempty" mapstructure:"file_id"`
+	Users    []string `json:"users,omitempty" mapstructure:"users"`
+	UserData string   `json:"userdata,omitempty" mapstructure:"userdata"`
+	Title    string   `json:"title,omitempty" mapstructure:"title"`
+}
+
+func (c DocumentCommandRequest) ToJSON() []byte {
+	buf, _ := json.Marshal(c)
+	return buf
+}
+
+// Validate checks the command arguments. File ids are resolved to keys by the builder.
+func (c *DocumentCommandRequest) Validate() error {
+	c.Key = strings.TrimSpace(c.Key)
+	c.FileID = strings.TrimSpace(c.FileID)
+	c.Title = strings.TrimSpace(c.Title)
+
+	switch c.Command {
+	case CommandVersion, CommandLicense, CommandGetForgottenList:
+		return nil
+	case CommandForcesave, CommandInfo, CommandGetForgotten, CommandDeleteForgotten:
+	case CommandDrop:
+		if len(c.Users) == 0 {
+			return ErrInvalidCommandUsers
+		}
+	case CommandMeta:
+		if c.Title == "" {
+			return ErrInvalidCommandTitle
+		}
+	default:
+		return ErrInvalidCommand
+	}
+
+	if c.Key == "" && c.FileID == "" {
+		return ErrInvalidCommandKey
+	}
+
+	return nil
+}
@@ -21,14 +21,16 @@ package request
 import "encoding/json"
 
 type BuildConfigRequest struct {
-	UID       int    `json:"uid"`
-	CID       int    `json:"cid"`
-	Deal      string `json:"deal_id"`
-	UserAgent string `json:"user_agent"`
-	FileID    string `json:"file_id"`
-	Filename  string `json:"file_name"`
-	DocKey    string `json:"doc_key"`
-	Dark      bool   `json:"dark"`
+	UID       int          `json:"uid"`
+	CID       int          `json:"cid"`
+	Entity    ParentEntity `json:"entity"`
+	UserAgent string       `json:"user_agent"`
+	FileID    string       `json:"file_id"`
+	Filename  string       `json:"file_name"`
+	DocKey    string       `json:"doc_key"`
+	Dark      bool         `json:"dark"`
+	Convert   bool         `json:"convert"`
+	LossyEdit bool         `json:"lossy_edit"`
 }
 
 func (c BuildConfigRequest) ToJSON() []byte {
@@ -0,0 +1,47 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import "encoding/json"
+
+type DocumentConversion struct {
+	CompanyID  int    `json:"company_id" mapstructure:"company_id"`
+	Key        string `json:"key" mapstructure:"key"`
+	FileID     string `json:"file_id" mapstructure:"file_id"`
+	ResultID   string `json:"result_id" mapstructure:"result_id"`
+	Filename   string `json:"filename" mapstructure:"filename"`
+	EntityType string `json:"entity_type" mapstructure:"entity_type"`
+	EntityID   string `json:"entity_id" mapstructure:"entity_id"`
+	UpdateTime string `json:"update_time,omitempty" mapstructure:"update_time"`
+}
+
+func (c DocumentConversion) ToJSON() []byte {
+	buf, _ := json.Marshal(c)
+	return buf
+}
+
+type DocumentConversionSelect struct {
+	CompanyID int    `json:"company_id" mapstructure:"company_id"`
+	Key       string `json:"key" mapstructure:"key"`
+}
+
+func (c DocumentConversionSelect) ToJSON() []byte {
+	buf, _ := json.Marshal(c)
+	return buf
+}
@@ -0,0 +1,57 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import (
+	"encoding/json"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ConvertRequest is a document server conversion api request. Repeating an async
+// request with the same key polls the conversion started by the first one.
+type ConvertRequest struct {
+	jwt.RegisteredClaims
+	Async      bool   `json:"async"`
+	Filetype   string `json:"filetype"`
+	Key        string `json:"key"`
+	Outputtype string `json:"outputtype"`
+	Title      string `json:"title,omitempty"`
+	URL        string `json:"url"`
+	Token      string `json:"token,omitempty"`
+}
+
+func (c ConvertRequest) ToJSON() []byte {
+	buf, _ := json.Marshal(c)
+	return buf
+}
+
+type ConvertFileRequest struct {
+	UID        int          `json:"uid" mapstructure:"uid"`
+	CID        int          `json:"cid" mapstructure:"cid"`
+	FileID     string       `json:"file_id" mapstructure:"file_id"`
+	Filename   string       `json:"filename" mapstructure:"filename"`
+	Entity     ParentEntity `json:"entity" mapstructure:"entity"`
+	OutputType string       `json:"output_type" mapstructure:"output_type"`
+}
+
+func (c ConvertFileRequest) ToJSON() []byte {
+	buf, _ := json.Marshal(c)
+	return buf
+}
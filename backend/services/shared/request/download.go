@@ -0,0 +1,75 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// downloadTokenTTL bounds a download link. The document server fetches a file right after the editor
+// opens, either at once or in a series of range requests, which all have to be made within it.
+const downloadTokenTTL = 15 * time.Minute
+
+// DownloadTokenContext grants short-lived access to a Pipedrive file through the gateway
+// on behalf of the user who requested it.
+type DownloadTokenContext struct {
+	jwt.RegisteredClaims
+	Purpose string `json:"typ" mapstructure:"typ"`
+	CID     int    `json:"cid" mapstructure:"cid"`
+	UID     int    `json:"uid" mapstructure:"uid"`
+	FileID  string `json:"fid" mapstructure:"fid"`
+}
+
+// NewDownloadTokenContext grants access for as long as the document server needs to download the file.
+func NewDownloadTokenContext(id UserIdentity, fileID string) DownloadTokenContext {
+	return DownloadTokenContext{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(downloadTokenTTL)),
+		},
+		Purpose: TokenPurposeDownload,
+		CID:     id.CompanyID,
+		UID:     id.UserID,
+		FileID:  fileID,
+	}
+}
+
+// Validate rejects tokens issued for anything but a file download.
+func (c DownloadTokenContext) Validate() error {
+	if c.Purpose != TokenPurposeDownload {
+		return ErrInvalidTokenPurpose
+	}
+
+	if c.CID <= 0 || c.UID <= 0 || c.FileID == "" {
+		return ErrInvalidTokenClaims
+	}
+
+	return nil
+}
+
+func (c DownloadTokenContext) Identity() UserIdentity {
+	return NewUserIdentity(c.CID, c.UID)
+}
+
+func (c DownloadTokenContext) ToJSON() []byte {
+	buf, _ := json.Marshal(c)
+	return buf
+}
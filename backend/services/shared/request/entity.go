@@ -0,0 +1,106 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	EntityDeal         string = "deal"
+	EntityPerson       string = "person"
+	EntityOrganization string = "organization"
+	EntityLead         string = "lead"
+	EntityProduct      string = "product"
+	EntityActivity     string = "activity"
+)
+
+// entityFields maps an entity type to the form field Pipedrive expects when attaching a file to it.
+var entityFields map[string]string = map[string]string{
+	EntityDeal:         "deal_id",
+	EntityPerson:       "person_id",
+	EntityOrganization: "org_id",
+	EntityLead:         "lead_id",
+	EntityProduct:      "product_id",
+	EntityActivity:     "activity_id",
+}
+
+// entityResources maps an entity type to its Pipedrive api resource path.
+var entityResources map[string]string = map[string]string{
+	EntityDeal:         "deals",
+	EntityPerson:       "persons",
+	EntityOrganization: "organizations",
+	EntityLead:         "leads",
+	EntityProduct:      "products",
+	EntityActivity:     "activities",
+}
+
+// ParentEntity identifies a Pipedrive record a file is attached to.
+// Lead ids are uuids, so ids are kept as strings for every entity type.
+type ParentEntity struct {
+	Type string `json:"type" mapstructure:"type"`
+	ID   string `json:"id" mapstructure:"id"`
+}
+
+// NewParentEntity builds a parent entity. An empty type defaults to a deal to keep
+// links generated before other entity types were supported working.
+func NewParentEntity(entityType, id string) ParentEntity {
+	entityType = strings.ToLower(strings.TrimSpace(entityType))
+	if entityType == "" {
+		entityType = EntityDeal
+	}
+
+	return ParentEntity{
+		Type: entityType,
+		ID:   strings.TrimSpace(id),
+	}
+}
+
+// FormField returns the Pipedrive files api field used to link a file to the entity.
+func (e ParentEntity) FormField() string {
+	return entityFields[e.Type]
+}
+
+// Resource returns the Pipedrive api resource path of the entity type.
+func (e ParentEntity) Resource() string {
+	return entityResources[e.Type]
+}
+
+func (e ParentEntity) String() string {
+	return fmt.Sprintf("%s:%s", e.Type, e.ID)
+}
+
+func (e ParentEntity) ToJSON() []byte {
+	buf, _ := json.Marshal(e)
+	return buf
+}
+
+func (e ParentEntity) Validate() error {
+	if _, ok := entityFields[e.Type]; !ok {
+		return ErrInvalidEntityType
+	}
+
+	if e.ID == "" {
+		return ErrInvalidEntityID
+	}
+
+	return nil
+}
@@ -21,10 +21,21 @@ package request
 import "errors"
 
 var (
-	ErrInvalidCompanyID  = errors.New("invalid company id")
-	ErrInvalidDocAddress = errors.New("invalid doc server address")
-	ErrInvalidDocSecret  = errors.New("invalid doc server secret")
-	ErrInvalidDocHeader  = errors.New("invalid doc server header")
-	ErrInvalidDemoPeriod = errors.New("demo period has expired")
-	ErrHttpNotAllowed    = errors.New("document server must use https protocol for pipedrive integration")
+	ErrInvalidCompanyID    = errors.New("invalid company id")
+	ErrInvalidDocAddress   = errors.New("invalid doc server address")
+	ErrInvalidDocSecret    = errors.New("invalid doc server secret")
+	ErrInvalidDocHeader    = errors.New("invalid doc server header")
+	ErrInvalidDemoPeriod   = errors.New("demo period has expired")
+	ErrHttpNotAllowed      = errors.New("document server must use https protocol for pipedrive integration")
+	ErrInvalidUserIdentity = errors.New("invalid user identity")
+	ErrInvalidEntityType   = errors.New("invalid parent entity type")
+	ErrInvalidEntityID     = errors.New("invalid parent entity id")
+	ErrInvalidLossyEdit    = errors.New("invalid lossy edit policy")
+	ErrInvalidPermissions  = errors.New("invalid editor permissions policy")
+	ErrInvalidCommand      = errors.New("invalid document server command")
+	ErrInvalidCommandKey   = errors.New("command requires a document key or a file id")
+	ErrInvalidCommandUsers = errors.New("drop command requires users")
+	ErrInvalidCommandTitle = errors.New("meta command requires a title")
+	ErrInvalidTokenPurpose = errors.New("token was issued for another purpose")
+	ErrInvalidTokenClaims  = errors.New("token misses required claims")
 )
@@ -0,0 +1,42 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import "encoding/json"
+
+// Document events mirror Pipedrive changes to the files the integration keeps state for.
+const (
+	DocumentEventFileDeleted   string = "file.deleted"
+	DocumentEventEntityDeleted string = "entity.deleted"
+	DocumentEventEntityMerged  string = "entity.merged"
+)
+
+type DocumentEvent struct {
+	CompanyID int          `json:"company_id" mapstructure:"company_id"`
+	Action    string       `json:"action" mapstructure:"action"`
+	FileID    string       `json:"file_id,omitempty" mapstructure:"file_id"`
+	Entity    ParentEntity `json:"entity,omitempty" mapstructure:"entity"`
+	// MergedInto is the id of the entity a merged entity has been merged into.
+	MergedInto string `json:"merged_into,omitempty" mapstructure:"merged_into"`
+}
+
+func (e DocumentEvent) ToJSON() []byte {
+	buf, _ := json.Marshal(e)
+	return buf
+}
@@ -0,0 +1,36 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import "encoding/json"
+
+// GenerateFileRequest asks for a new file built from a company template with its
+// placeholders filled from the fields of the entity the file is attached to.
+type GenerateFileRequest struct {
+	UID        int          `json:"uid" mapstructure:"uid"`
+	CID        int          `json:"cid" mapstructure:"cid"`
+	TemplateID string       `json:"template_id" mapstructure:"template_id"`
+	Filename   string       `json:"filename" mapstructure:"filename"`
+	Entity     ParentEntity `json:"entity" mapstructure:"entity"`
+}
+
+func (c GenerateFileRequest) ToJSON() []byte {
+	buf, _ := json.Marshal(c)
+	return buf
+}
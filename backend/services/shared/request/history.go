@@ -0,0 +1,57 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import "encoding/json"
+
+type DocumentHistory struct {
+	CompanyID     int              `json:"company_id" mapstructure:"company_id"`
+	FileID        string           `json:"file_id" mapstructure:"file_id"`
+	ServerVersion string           `json:"server_version" mapstructure:"server_version"`
+	Changes       []CallbackChange `json:"changes" mapstructure:"changes"`
+	Archive       []byte           `json:"archive,omitempty" mapstructure:"archive"`
+}
+
+func (h DocumentHistory) ToJSON() []byte {
+	buf, _ := json.Marshal(h)
+	return buf
+}
+
+type DocumentHistorySelect struct {
+	CompanyID int      `json:"company_id" mapstructure:"company_id"`
+	FileIDs   []string `json:"file_ids" mapstructure:"file_ids"`
+}
+
+func (h DocumentHistorySelect) ToJSON() []byte {
+	buf, _ := json.Marshal(h)
+	return buf
+}
+
+type BuildHistoryRequest struct {
+	UID     int    `json:"uid"`
+	CID     int    `json:"cid"`
+	FileID  string `json:"file_id"`
+	DocKey  string `json:"doc_key"`
+	Version int    `json:"version,omitempty"`
+}
+
+func (h BuildHistoryRequest) ToJSON() []byte {
+	buf, _ := json.Marshal(h)
+	return buf
+}
@@ -0,0 +1,79 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// UserIdentity identifies a Pipedrive user within a specific company.
+// Pipedrive user ids are only unique per company, so both parts are required.
+type UserIdentity struct {
+	CompanyID int `json:"company_id" mapstructure:"company_id"`
+	UserID    int `json:"user_id" mapstructure:"user_id"`
+}
+
+func NewUserIdentity(cid, uid int) UserIdentity {
+	return UserIdentity{
+		CompanyID: cid,
+		UserID:    uid,
+	}
+}
+
+// ParseUserIdentity parses an identity previously formatted with String.
+func ParseUserIdentity(id string) (UserIdentity, error) {
+	var identity UserIdentity
+	parts := strings.Split(strings.TrimSpace(id), ":")
+	if len(parts) != 2 {
+		return identity, ErrInvalidUserIdentity
+	}
+
+	cid, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return identity, ErrInvalidUserIdentity
+	}
+
+	uid, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return identity, ErrInvalidUserIdentity
+	}
+
+	identity = NewUserIdentity(cid, uid)
+	return identity, identity.Validate()
+}
+
+func (i UserIdentity) String() string {
+	return fmt.Sprintf("%d:%d", i.CompanyID, i.UserID)
+}
+
+func (i UserIdentity) ToJSON() []byte {
+	buf, _ := json.Marshal(i)
+	return buf
+}
+
+func (i UserIdentity) Validate() error {
+	if i.CompanyID <= 0 || i.UserID <= 0 {
+		return ErrInvalidUserIdentity
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import (
+	"strings"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
+)
+
+// PermissionsPolicy restricts what editors may do with a company's documents.
+// The zero value keeps the defaults: editing, downloading and copying without printing.
+type PermissionsPolicy struct {
+	Mode            string `json:"mode,omitempty" mapstructure:"mode"`
+	DisableDownload bool   `json:"disable_download,omitempty" mapstructure:"disable_download"`
+	DisableCopy     bool   `json:"disable_copy,omitempty" mapstructure:"disable_copy"`
+	AllowPrint      bool   `json:"allow_print,omitempty" mapstructure:"allow_print"`
+}
+
+// Normalize lowercases the mode and falls back to edit when it is not set.
+func (p PermissionsPolicy) Normalize() PermissionsPolicy {
+	p.Mode = strings.ToLower(strings.TrimSpace(p.Mode))
+	if p.Mode == "" {
+		p.Mode = constants.PermissionModeEdit
+	}
+
+	return p
+}
+
+func (p PermissionsPolicy) Validate() error {
+	if !constants.IsPermissionMode(strings.ToLower(strings.TrimSpace(p.Mode))) {
+		return ErrInvalidPermissions
+	}
+
+	return nil
+}
+
+// permissionModeRanks orders modes from the most to the least restrictive one.
+var permissionModeRanks = map[string]int{
+	constants.PermissionModeView:    0,
+	constants.PermissionModeComment: 1,
+	constants.PermissionModeReview:  2,
+	constants.PermissionModeEdit:    3,
+}
+
+// Restrict lowers the policy mode to the given one unless the policy is already more restrictive.
+func (p PermissionsPolicy) Restrict(mode string) PermissionsPolicy {
+	p = p.Normalize()
+	if rank, ok := permissionModeRanks[mode]; ok && rank < permissionModeRanks[p.Mode] {
+		p.Mode = mode
+	}
+
+	return p
+}
+
+// ResolvePermissions picks the policy of the first Pipedrive permission set with an override
+// and falls back to the company policy.
+func ResolvePermissions(
+	policy PermissionsPolicy,
+	overrides map[string]PermissionsPolicy,
+	permissionSets ...string,
+) PermissionsPolicy {
+	for _, set := range permissionSets {
+		if override, ok := overrides[set]; ok {
+			return override.Normalize()
+		}
+	}
+
+	return policy.Normalize()
+}
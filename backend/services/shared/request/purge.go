@@ -0,0 +1,55 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PurgeTokenContext authorizes the callback service to drop the queued jobs of an uninstalled company.
+type PurgeTokenContext struct {
+	jwt.RegisteredClaims
+	Purpose string `json:"typ" mapstructure:"typ"`
+	CID     int    `json:"cid" mapstructure:"cid"`
+}
+
+func NewPurgeTokenContext(cid int) PurgeTokenContext {
+	return PurgeTokenContext{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		Purpose: TokenPurposePurge,
+		CID:     cid,
+	}
+}
+
+// Validate rejects tokens issued for anything but a company purge.
+func (c PurgeTokenContext) Validate() error {
+	if c.Purpose != TokenPurposePurge {
+		return ErrInvalidTokenPurpose
+	}
+
+	if c.CID <= 0 {
+		return ErrInvalidTokenClaims
+	}
+
+	return nil
+}
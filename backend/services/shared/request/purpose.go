@@ -0,0 +1,28 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+// Tokens the integration signs with the client secret carry the purpose they were issued for,
+// so a token handed to the document server for one endpoint is never accepted by another.
+const (
+	TokenPurposeDownload string = "download"
+	TokenPurposeChanges  string = "changes"
+	TokenPurposeCallback string = "callback"
+	TokenPurposePurge    string = "purge"
+)
@@ -22,14 +22,21 @@ import (
 	"encoding/json"
 	"net/url"
 	"strings"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/constants"
 )
 
 type DocSettings struct {
-	CompanyID   int    `json:"company_id" mapstructure:"company_id"`
-	DocAddress  string `json:"doc_address" mapstructure:"doc_address"`
-	DocSecret   string `json:"doc_secret" mapstructure:"doc_secret"`
-	DocHeader   string `json:"doc_header" mapstructure:"doc_header"`
-	DemoEnabled bool   `json:"demo_enabled" mapstructure:"demo_enabled"`
+	CompanyID         int                          `json:"company_id" mapstructure:"company_id"`
+	DocAddress        string                       `json:"doc_address" mapstructure:"doc_address"`
+	DocSecret         string                       `json:"doc_secret" mapstructure:"doc_secret"`
+	DocHeader         string                       `json:"doc_header" mapstructure:"doc_header"`
+	DemoEnabled       bool                         `json:"demo_enabled" mapstructure:"demo_enabled"`
+	AutoConvert       bool                         `json:"auto_convert" mapstructure:"auto_convert"`
+	ReplaceBlankForms bool                         `json:"replace_blank_forms" mapstructure:"replace_blank_forms"`
+	LossyEdit         string                       `json:"lossy_edit" mapstructure:"lossy_edit"`
+	Permissions       PermissionsPolicy            `json:"permissions" mapstructure:"permissions"`
+	RolePermissions   map[string]PermissionsPolicy `json:"role_permissions,omitempty" mapstructure:"role_permissions"`
 }
 
 func (c DocSettings) ToJSON() []byte {
@@ -46,6 +53,20 @@ func (c DocSettings) Validate() error {
 		return ErrInvalidCompanyID
 	}
 
+	if !constants.IsLossyEditPolicy(strings.ToLower(strings.TrimSpace(c.LossyEdit))) {
+		return ErrInvalidLossyEdit
+	}
+
+	if err := c.Permissions.Validate(); err != nil {
+		return err
+	}
+
+	for _, policy := range c.RolePermissions {
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+	}
+
 	hasCredentials := c.DocAddress != "" || c.DocSecret != "" || c.DocHeader != ""
 	if hasCredentials {
 		if c.DocAddress == "" {
@@ -0,0 +1,48 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import "encoding/json"
+
+type DocTemplate struct {
+	CompanyID string `json:"company_id" mapstructure:"company_id"`
+	Name      string `json:"name" mapstructure:"name"`
+	FileType  string `json:"file_type" mapstructure:"file_type"`
+	Lang      string `json:"lang,omitempty" mapstructure:"lang"`
+	Content   []byte `json:"content" mapstructure:"content"`
+	CreatedBy string `json:"created_by,omitempty" mapstructure:"created_by"`
+}
+
+func (t DocTemplate) ToJSON() []byte {
+	buf, _ := json.Marshal(t)
+	return buf
+}
+
+// DocTemplateSelect selects a single template by id or lists templates by file type and language.
+type DocTemplateSelect struct {
+	CompanyID string `json:"company_id" mapstructure:"company_id"`
+	ID        string `json:"id,omitempty" mapstructure:"id"`
+	FileType  string `json:"file_type,omitempty" mapstructure:"file_type"`
+	Lang      string `json:"lang,omitempty" mapstructure:"lang"`
+}
+
+func (t DocTemplateSelect) ToJSON() []byte {
+	buf, _ := json.Marshal(t)
+	return buf
+}
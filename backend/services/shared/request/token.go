@@ -36,3 +36,7 @@ func (c PipedriveTokenContext) ToJSON() []byte {
 	buf, _ := json.Marshal(c)
 	return buf
 }
+
+func (c PipedriveTokenContext) Identity() UserIdentity {
+	return NewUserIdentity(c.CID, c.UID)
+}
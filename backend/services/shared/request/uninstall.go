@@ -31,3 +31,7 @@ func (r UninstallRequest) ToJSON() []byte {
 	buf, _ := json.Marshal(r)
 	return buf
 }
+
+func (r UninstallRequest) Identity() UserIdentity {
+	return NewUserIdentity(r.CompanyID, r.UserID)
+}
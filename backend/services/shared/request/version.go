@@ -0,0 +1,47 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import "encoding/json"
+
+type DocumentVersion struct {
+	CompanyID  int    `json:"company_id" mapstructure:"company_id"`
+	ParentID   string `json:"parent_id" mapstructure:"parent_id"`
+	FileID     string `json:"file_id" mapstructure:"file_id"`
+	EntityType string `json:"entity_type" mapstructure:"entity_type"`
+	EntityID   string `json:"entity_id" mapstructure:"entity_id"`
+	Filename   string `json:"filename" mapstructure:"filename"`
+	Author     string `json:"author" mapstructure:"author"`
+}
+
+func (v DocumentVersion) ToJSON() []byte {
+	buf, _ := json.Marshal(v)
+	return buf
+}
+
+type DocumentVersionSelect struct {
+	CompanyID int    `json:"company_id" mapstructure:"company_id"`
+	FileID    string `json:"file_id" mapstructure:"file_id"`
+	Version   int    `json:"version,omitempty" mapstructure:"version"`
+}
+
+func (v DocumentVersionSelect) ToJSON() []byte {
+	buf, _ := json.Marshal(v)
+	return buf
+}
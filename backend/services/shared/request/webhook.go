@@ -0,0 +1,108 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package request
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Pipedrive webhook event actions.
+const (
+	WebhookActionUpdated string = "updated"
+	WebhookActionMerged  string = "merged"
+	WebhookActionDeleted string = "deleted"
+)
+
+// Pipedrive webhook event objects which are not file parent entities.
+const (
+	WebhookObjectFile string = "file"
+	WebhookObjectUser string = "user"
+)
+
+// WebhookID accepts both numeric ids and lead uuids.
+type WebhookID string
+
+func (id *WebhookID) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*id = WebhookID(stringifyWebhookValue(value))
+	return nil
+}
+
+func stringifyWebhookValue(value interface{}) string {
+	switch val := value.(type) {
+	case string:
+		return strings.TrimSpace(val)
+	case float64:
+		return strconv.FormatInt(int64(val), 10)
+	default:
+		return ""
+	}
+}
+
+type PipedriveWebhookMeta struct {
+	Action    string    `json:"action"`
+	Object    string    `json:"object"`
+	ID        WebhookID `json:"id"`
+	CompanyID int       `json:"company_id"`
+	UserID    int       `json:"user_id"`
+	Host      string    `json:"host"`
+}
+
+// PipedriveWebhook is a version 1.0 Pipedrive webhook notification.
+type PipedriveWebhook struct {
+	Event    string                 `json:"event"`
+	Meta     PipedriveWebhookMeta   `json:"meta"`
+	Current  map[string]interface{} `json:"current"`
+	Previous map[string]interface{} `json:"previous"`
+	Retry    int                    `json:"retry"`
+}
+
+func (w PipedriveWebhook) ToJSON() []byte {
+	buf, _ := json.Marshal(w)
+	return buf
+}
+
+// MergedID returns the id of the record merged into the current one.
+func (w PipedriveWebhook) MergedID() string {
+	if id := stringifyWebhookValue(w.Current["merge_what_id"]); id != "" {
+		return id
+	}
+
+	if id := stringifyWebhookValue(w.Previous["id"]); id != string(w.Meta.ID) {
+		return id
+	}
+
+	return ""
+}
+
+// Deactivated reports whether a user event disables the user in Pipedrive.
+func (w PipedriveWebhook) Deactivated() bool {
+	if w.Meta.Action == WebhookActionDeleted {
+		return true
+	}
+
+	active, ok := w.Current["active_flag"].(bool)
+	return ok && !active
+}
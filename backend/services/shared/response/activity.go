@@ -0,0 +1,42 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type DocumentActivityResponse struct {
+	FileID      string    `json:"file_id" mapstructure:"file_id"`
+	EntityType  string    `json:"entity_type" mapstructure:"entity_type"`
+	EntityID    string    `json:"entity_id" mapstructure:"entity_id"`
+	DocKey      string    `json:"doc_key,omitempty" mapstructure:"doc_key"`
+	Status      int       `json:"status" mapstructure:"status"`
+	Editors     []string  `json:"editors" mapstructure:"editors"`
+	LastError   string    `json:"last_error,omitempty" mapstructure:"last_error"`
+	LastErrorAt time.Time `json:"last_error_at,omitempty" mapstructure:"last_error_at"`
+	Deleted     bool      `json:"deleted,omitempty" mapstructure:"deleted"`
+	UpdatedAt   time.Time `json:"updated_at" mapstructure:"updated_at"`
+}
+
+func (r DocumentActivityResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
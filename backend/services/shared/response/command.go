@@ -18,6 +18,80 @@
 
 package response
 
+import "encoding/json"
+
 type BaseCommandResponse struct {
 	Error int `json:"error"`
 }
+
+func (r BaseCommandResponse) ErrorCode() int {
+	return r.Error
+}
+
+// CommandKeyResponse is returned by commands which only echo the document key:
+// forcesave, drop, info, meta and deleteForgotten.
+type CommandKeyResponse struct {
+	BaseCommandResponse
+	Key string `json:"key"`
+}
+
+type VersionCommandResponse struct {
+	BaseCommandResponse
+	Version string `json:"version"`
+}
+
+type CommandLicense struct {
+	EndDate         string `json:"end_date,omitempty"`
+	Trial           bool   `json:"trial"`
+	Customization   bool   `json:"customization"`
+	Connections     int    `json:"connections"`
+	ConnectionsView int    `json:"connections_view"`
+	UsersCount      int    `json:"users_count"`
+	UsersViewCount  int    `json:"users_view_count"`
+	UsersExpire     int    `json:"users_expire"`
+}
+
+type CommandServer struct {
+	ResultType   int    `json:"resultType"`
+	PackageType  int    `json:"packageType"`
+	BuildDate    string `json:"buildDate"`
+	BuildVersion string `json:"buildVersion"`
+	BuildNumber  int    `json:"buildNumber"`
+}
+
+type LicenseCommandResponse struct {
+	BaseCommandResponse
+	License CommandLicense         `json:"license"`
+	Server  CommandServer          `json:"server"`
+	Quota   map[string]interface{} `json:"quota,omitempty"`
+}
+
+type ForgottenCommandResponse struct {
+	BaseCommandResponse
+	Key string `json:"key"`
+	URL string `json:"url"`
+}
+
+type ForgottenListCommandResponse struct {
+	BaseCommandResponse
+	Keys []string `json:"keys"`
+}
+
+// DocumentCommandResponse carries the result of any command. Command service errors are
+// reported in Error rather than as rpc errors so that callers can tell them apart.
+type DocumentCommandResponse struct {
+	Command string                 `json:"command"`
+	Error   int                    `json:"error"`
+	Key     string                 `json:"key,omitempty"`
+	Version string                 `json:"version,omitempty"`
+	URL     string                 `json:"url,omitempty"`
+	Keys    []string               `json:"keys,omitempty"`
+	License *CommandLicense        `json:"license,omitempty"`
+	Server  *CommandServer         `json:"server,omitempty"`
+	Quota   map[string]interface{} `json:"quota,omitempty"`
+}
+
+func (r DocumentCommandResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
@@ -26,14 +26,18 @@ import (
 
 type BuildConfigResponse struct {
 	jwt.RegisteredClaims
-	Document     Document     `json:"document"`
-	DocumentType string       `json:"documentType"`
-	EditorConfig EditorConfig `json:"editorConfig"`
-	Type         string       `json:"type"`
-	Token        string       `json:"token,omitempty"`
-	Session      bool         `json:"is_session,omitempty"`
-	ServerURL    string       `json:"server_url"`
-	DemoEnabled  bool         `json:"demo_enabled"`
+	Document      Document     `json:"document"`
+	DocumentType  string       `json:"documentType"`
+	EditorConfig  EditorConfig `json:"editorConfig"`
+	Type          string       `json:"type"`
+	Token         string       `json:"token,omitempty"`
+	Session       bool         `json:"is_session,omitempty"`
+	ServerURL     string       `json:"server_url"`
+	DemoEnabled   bool         `json:"demo_enabled"`
+	FileID        string       `json:"file_id,omitempty"`
+	Convertible   bool         `json:"convertible,omitempty"`
+	Converted     bool         `json:"converted,omitempty"`
+	LossyEditable bool         `json:"lossy_editable,omitempty"`
 }
 
 func (r BuildConfigResponse) ToJSON() []byte {
@@ -77,6 +81,7 @@ type User struct {
 
 type Customization struct {
 	Goback        Goback `json:"goback"`
+	Forcesave     bool   `json:"forcesave"`
 	Plugins       bool   `json:"plugins"`
 	HideRightMenu bool   `json:"hideRightMenu"`
 	UiTheme       string `json:"uiTheme"`
@@ -0,0 +1,102 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type DocumentHistoryResponse struct {
+	FileID        string          `json:"file_id"`
+	ServerVersion string          `json:"server_version"`
+	Changes       []HistoryChange `json:"changes"`
+	Archive       []byte          `json:"archive,omitempty"`
+	HasArchive    bool            `json:"has_archive"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func (r DocumentHistoryResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
+
+type DocumentHistoriesResponse struct {
+	Histories []DocumentHistoryResponse `json:"histories"`
+}
+
+func (r DocumentHistoriesResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
+
+type HistoryChange struct {
+	Created string      `json:"created"`
+	User    HistoryUser `json:"user"`
+}
+
+type HistoryUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type HistoryItem struct {
+	Changes       []HistoryChange `json:"changes,omitempty"`
+	Created       string          `json:"created"`
+	Key           string          `json:"key"`
+	ServerVersion string          `json:"serverVersion,omitempty"`
+	User          HistoryUser     `json:"user"`
+	Version       int             `json:"version"`
+}
+
+// BuildHistoryResponse is passed as is to the editor's refreshHistory method.
+type BuildHistoryResponse struct {
+	CurrentVersion int           `json:"currentVersion"`
+	History        []HistoryItem `json:"history"`
+}
+
+func (r BuildHistoryResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
+
+type HistoryPrevious struct {
+	FileType string `json:"fileType"`
+	Key      string `json:"key"`
+	URL      string `json:"url"`
+}
+
+// BuildHistoryDataResponse is passed as is to the editor's setHistoryData method.
+type BuildHistoryDataResponse struct {
+	jwt.RegisteredClaims
+	ChangesURL string           `json:"changesUrl,omitempty"`
+	FileType   string           `json:"fileType"`
+	Key        string           `json:"key"`
+	Previous   *HistoryPrevious `json:"previous,omitempty"`
+	URL        string           `json:"url"`
+	Version    int              `json:"version"`
+	Token      string           `json:"token,omitempty"`
+}
+
+func (r BuildHistoryDataResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
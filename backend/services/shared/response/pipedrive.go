@@ -26,6 +26,11 @@ type AddFileResponse struct {
 		ID         int    `json:"id"`
 		Filename   string `json:"file_name"`
 		DealID     int    `json:"deal_id"`
+		PersonID   int    `json:"person_id"`
+		OrgID      int    `json:"org_id"`
+		ProductID  int    `json:"product_id"`
+		ActivityID int    `json:"activity_id"`
+		LeadID     string `json:"lead_id"`
 		UpdateTime string `json:"update_time"`
 	} `json:"data"`
 }
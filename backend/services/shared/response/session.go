@@ -0,0 +1,50 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type DocumentSessionResponse struct {
+	FileID     string    `json:"file_id"`
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	UserID     string    `json:"user_id"`
+	Name       string    `json:"name,omitempty"`
+	Mode       string    `json:"mode"`
+	State      string    `json:"state"`
+	OpenedAt   time.Time `json:"opened_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (r DocumentSessionResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
+
+type DocumentSessionsResponse struct {
+	Sessions []DocumentSessionResponse `json:"sessions"`
+}
+
+func (r DocumentSessionsResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
@@ -21,14 +21,21 @@ package response
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
 )
 
 type DocSettingsResponse struct {
-	DocAddress  string    `json:"doc_address"`
-	DocSecret   string    `json:"doc_secret"`
-	DocHeader   string    `json:"doc_header"`
-	DemoEnabled bool      `json:"demo_enabled"`
-	DemoStarted time.Time `json:"demo_started"`
+	DocAddress        string                               `json:"doc_address"`
+	DocSecret         string                               `json:"doc_secret"`
+	DocHeader         string                               `json:"doc_header"`
+	DemoEnabled       bool                                 `json:"demo_enabled"`
+	AutoConvert       bool                                 `json:"auto_convert"`
+	ReplaceBlankForms bool                                 `json:"replace_blank_forms"`
+	LossyEdit         string                               `json:"lossy_edit"`
+	Permissions       request.PermissionsPolicy            `json:"permissions"`
+	RolePermissions   map[string]request.PermissionsPolicy `json:"role_permissions,omitempty"`
+	DemoStarted       time.Time                            `json:"demo_started"`
 }
 
 func (r DocSettingsResponse) ToJSON() []byte {
@@ -36,6 +43,20 @@ func (r DocSettingsResponse) ToJSON() []byte {
 	return buf
 }
 
+// IsDemoModeValid reports whether the company still works with the demo document server,
+// which is available for 30 days since the demo has been started.
+func (r DocSettingsResponse) IsDemoModeValid() bool {
+	if !r.DemoEnabled {
+		return false
+	}
+
+	if r.DemoStarted.IsZero() {
+		return true
+	}
+
+	return r.DemoStarted.After(time.Now().AddDate(0, 0, -30))
+}
+
 type SettingsConfiguredResponse struct {
 	Configured bool `json:"configured"`
 }
@@ -0,0 +1,50 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type DocTemplateResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Filename  string    `json:"filename"`
+	FileType  string    `json:"file_type"`
+	Lang      string    `json:"lang,omitempty"`
+	Size      int       `json:"size"`
+	Content   []byte    `json:"content,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r DocTemplateResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
+
+type DocTemplatesResponse struct {
+	Templates []DocTemplateResponse `json:"templates"`
+}
+
+func (r DocTemplatesResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
@@ -0,0 +1,37 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package response
+
+import "encoding/json"
+
+// The company scope is reported once the last user of the company has uninstalled the app.
+const (
+	UninstallScopeUser    string = "user"
+	UninstallScopeCompany string = "company"
+)
+
+type UninstallResponse struct {
+	Scope        string `json:"scope" mapstructure:"scope"`
+	RemovedUsers int    `json:"removed_users" mapstructure:"removed_users"`
+}
+
+func (r UninstallResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
@@ -18,16 +18,20 @@
 
 package response
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+)
 
 type UserResponse struct {
-	ID           string `json:"id" mapstructure:"id"`
-	AccessToken  string `json:"access_token" mapstructure:"access_token"`
-	RefreshToken string `json:"refresh_token" mapstructure:"refresh_token"`
-	TokenType    string `json:"token_type" mapstructure:"token_type"`
-	Scope        string `json:"scope" mapstructure:"scope"`
-	ApiDomain    string `json:"api_domain" mapstructure:"api_domain"`
-	ExpiresAt    int64  `json:"expires_at" mapstructure:"expires_at"`
+	ID           request.UserIdentity `json:"id" mapstructure:"id"`
+	AccessToken  string               `json:"access_token" mapstructure:"access_token"`
+	RefreshToken string               `json:"refresh_token" mapstructure:"refresh_token"`
+	TokenType    string               `json:"token_type" mapstructure:"token_type"`
+	Scope        string               `json:"scope" mapstructure:"scope"`
+	ApiDomain    string               `json:"api_domain" mapstructure:"api_domain"`
+	ExpiresAt    int64                `json:"expires_at" mapstructure:"expires_at"`
 }
 
 func (ur UserResponse) ToJSON() []byte {
@@ -0,0 +1,51 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type DocumentVersionResponse struct {
+	RootID     string    `json:"root_id"`
+	ParentID   string    `json:"parent_id,omitempty"`
+	FileID     string    `json:"file_id"`
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	Version    int       `json:"version"`
+	Filename   string    `json:"filename"`
+	Author     string    `json:"author,omitempty"`
+	Deleted    bool      `json:"deleted,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (r DocumentVersionResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
+
+type DocumentVersionsResponse struct {
+	Versions []DocumentVersionResponse `json:"versions"`
+}
+
+func (r DocumentVersionsResponse) ToJSON() []byte {
+	buf, _ := json.Marshal(r)
+	return buf
+}
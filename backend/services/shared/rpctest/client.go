@@ -0,0 +1,81 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package rpctest provides a go-micro client for tests that answers calls by endpoint.
+package rpctest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"go-micro.dev/v4/client"
+)
+
+// Handler answers a call with its request body and the response to fill in.
+type Handler func(req interface{}, rsp interface{}) error
+
+// Client answers go-micro calls by endpoint, e.g. UserSelectHandler.GetUser.
+// Calls to endpoints without a handler fail with a micro error.
+type Client struct {
+	client.Client
+	Handlers map[string]Handler
+}
+
+func NewClient() *Client {
+	return &Client{Handlers: map[string]Handler{}}
+}
+
+func (c *Client) NewRequest(service, endpoint string, req interface{}, opts ...client.RequestOption) client.Request {
+	return client.NewRequest(service, endpoint, req, opts...)
+}
+
+func (c *Client) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	handler, ok := c.Handlers[req.Endpoint()]
+	if !ok {
+		return fmt.Errorf(`{"id":"test","code":500,"detail":"unexpected call %s","status":"Internal Server Error"}`, req.Endpoint())
+	}
+
+	return handler(req.Body(), rsp)
+}
+
+// WithUser makes the auth service return a user whose Pipedrive api is served by domain.
+func (c *Client) WithUser(domain string) *Client {
+	c.Handlers["UserSelectHandler.GetUser"] = func(req interface{}, rsp interface{}) error {
+		*rsp.(*response.UserResponse) = response.UserResponse{
+			ID:          req.(request.UserIdentity),
+			AccessToken: "access",
+			TokenType:   "Bearer",
+			ApiDomain:   domain,
+		}
+		return nil
+	}
+
+	return c
+}
+
+// WithSettings makes the settings service return the company document server settings.
+func (c *Client) WithSettings(settings response.DocSettingsResponse) *Client {
+	c.Handlers["SettingsSelectHandler.GetSettings"] = func(req interface{}, rsp interface{}) error {
+		*rsp.(*response.DocSettingsResponse) = settings
+		return nil
+	}
+
+	return c
+}
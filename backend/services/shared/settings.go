@@ -0,0 +1,78 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"go-micro.dev/v4/client"
+)
+
+var ErrNoSettingsFound = errors.New("could not find document server settings")
+
+// GetDocumentServerSettings resolves the document server a company works with, falling back to the demo server.
+func GetDocumentServerSettings(
+	ctx context.Context,
+	client client.Client,
+	namespace string,
+	onlyoffice *OnlyofficeConfig,
+	logger plog.Logger,
+	cid string,
+) (response.DocSettingsResponse, error) {
+	var docs response.DocSettingsResponse
+	if err := client.Call(
+		ctx,
+		client.NewRequest(
+			fmt.Sprintf("%s:settings", namespace),
+			"SettingsSelectHandler.GetSettings",
+			cid,
+		),
+		&docs,
+	); err != nil {
+		logger.Debugf("could not get document server settings: %s", err.Error())
+		return docs, err
+	}
+
+	if docs.IsDemoModeValid() {
+		if onlyoffice.Onlyoffice.Demo.DocumentServerURL == "" ||
+			onlyoffice.Onlyoffice.Demo.DocumentServerSecret == "" ||
+			onlyoffice.Onlyoffice.Demo.DocumentServerHeader == "" {
+			logger.Errorf("demo mode is enabled but demo credentials are not configured")
+			return docs, ErrNoSettingsFound
+		}
+
+		logger.Debugf("using demo mode for company %s", cid)
+		docs.DocAddress = onlyoffice.Onlyoffice.Demo.DocumentServerURL
+		docs.DocSecret = onlyoffice.Onlyoffice.Demo.DocumentServerSecret
+		docs.DocHeader = onlyoffice.Onlyoffice.Demo.DocumentServerHeader
+		return docs, nil
+	}
+
+	if docs.DocAddress == "" || docs.DocSecret == "" || docs.DocHeader == "" {
+		logger.Debugf("no settings found and demo mode not valid")
+		return docs, ErrNoSettingsFound
+	}
+
+	logger.Debugf("using regular document server settings for company %s", cid)
+	return docs, nil
+}
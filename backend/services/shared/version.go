@@ -0,0 +1,93 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	plog "github.com/ONLYOFFICE/onlyoffice-integration-adapters/log"
+	pclient "github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/client/model"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/request"
+	"github.com/ONLYOFFICE/onlyoffice-pipedrive/services/shared/response"
+	"go-micro.dev/v4/client"
+)
+
+// PruneVersions removes the Pipedrive attachments of the versions superseded by the latest file of a lineage,
+// except for the keep most recent ones. Removed versions stay in the lineage as deleted.
+func PruneVersions(
+	ctx context.Context,
+	client client.Client,
+	apiClient pclient.PipedriveApiClient,
+	namespace string,
+	logger plog.Logger,
+	token model.Token,
+	cid int,
+	fileID string,
+	keep int,
+) error {
+	var versions response.DocumentVersionsResponse
+	if err := client.Call(ctx, client.NewRequest(
+		fmt.Sprintf("%s:documents", namespace),
+		"VersionHandler.GetVersions",
+		request.DocumentVersionSelect{CompanyID: cid, FileID: fileID},
+	), &versions); err != nil {
+		return err
+	}
+
+	superseded := make([]response.DocumentVersionResponse, 0, len(versions.Versions))
+	for _, version := range versions.Versions {
+		if !version.Deleted && version.FileID != fileID {
+			superseded = append(superseded, version)
+		}
+	}
+
+	if len(superseded) <= keep {
+		return nil
+	}
+
+	for _, version := range superseded[:len(superseded)-keep] {
+		if err := apiClient.DeleteFile(ctx, version.FileID, token); err != nil {
+			var serr *pclient.UnexpectedStatusCodeError
+			if !errors.As(err, &serr) || (serr.Code != http.StatusNotFound && serr.Code != http.StatusGone) {
+				return err
+			}
+		}
+
+		var res interface{}
+		if err := client.Call(ctx, client.NewRequest(
+			fmt.Sprintf("%s:documents", namespace),
+			"EventHandler.HandleEvent",
+			request.DocumentEvent{
+				CompanyID: cid,
+				Action:    request.DocumentEventFileDeleted,
+				FileID:    version.FileID,
+			},
+		), &res); err != nil {
+			return err
+		}
+
+		logger.Debugf("removed company %d file %s superseded by %s", cid, version.FileID, fileID)
+	}
+
+	return nil
+}
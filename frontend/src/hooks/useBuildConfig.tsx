@@ -20,18 +20,21 @@ import { useQuery } from "@tanstack/react-query";
 
 import { fetchConfig } from "@services/config";
 
+import { ParentEntity } from "@utils/entity";
+
 export function useBuildConfig(
   token: string,
   id: string,
   name: string,
-  key: string,
-  dealID: string,
+  entity: ParentEntity,
   dark = false,
+  convert = false,
+  lossy = false,
 ) {
   const { isLoading, error, data } = useQuery({
-    queryKey: ["config", id, key, dark],
+    queryKey: ["config", id, dark, convert, lossy],
     queryFn: ({ signal }) =>
-      fetchConfig(token, id, name, key, dealID, dark, signal),
+      fetchConfig(token, id, name, entity, dark, convert, lossy, signal),
     staleTime: 0,
     gcTime: 0,
     refetchOnWindowFocus: false,
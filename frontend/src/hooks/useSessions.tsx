@@ -0,0 +1,39 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+import { useQuery } from "@tanstack/react-query";
+import AppExtensionsSDK from "@pipedrive/app-extensions-sdk";
+
+import { fetchSessions } from "@services/session";
+
+import { ParentEntity } from "@utils/entity";
+
+export function useSessions(
+  sdk: AppExtensionsSDK | null | undefined,
+  entity: ParentEntity,
+) {
+  const { data } = useQuery({
+    queryKey: ["sessions", entity.type, entity.id],
+    queryFn: ({ signal }) => fetchSessions(sdk!, entity, signal),
+    enabled: !!sdk && !!entity.id,
+    staleTime: 5000,
+    refetchInterval: 5000,
+    refetchOnWindowFocus: false,
+  });
+
+  return { sessions: data?.sessions || [] };
+}
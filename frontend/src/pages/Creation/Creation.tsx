@@ -18,7 +18,6 @@
 
 import React, { useEffect, useRef, useState } from "react";
 import axios from "axios";
-import md5 from "md5";
 import AppExtensionsSDK, { Command } from "@pipedrive/app-extensions-sdk";
 import { useTranslation } from "react-i18next";
 import i18next from "i18next";
@@ -30,6 +29,11 @@ import { OnlyofficeTitle } from "@components/title";
 
 import { getFileIcon } from "@utils/file";
 import { getCurrentURL } from "@utils/url";
+import { getEntityQuery, getParentEntity } from "@utils/entity";
+
+import { fetchTemplates, generateFile } from "@services/template";
+
+import { Template } from "src/types/template";
 
 import Redirect from "@assets/redirect.svg";
 
@@ -40,8 +44,13 @@ export const Creation: React.FC = () => {
   const [file, setFile] = useState(
     t("document.new", "New Document") || "New Document",
   );
-  const [fileType, setFileType] = useState<"docx" | "pptx" | "xlsx">("docx");
-  const handleChangeFile = (newType: "docx" | "pptx" | "xlsx") => {
+  const [fileType, setFileType] = useState<"docx" | "pptx" | "xlsx" | "pdf">(
+    "docx",
+  );
+  const [templates, setTemplates] = useState<Template[]>([]);
+  const [template, setTemplate] = useState("");
+  const [fill, setFill] = useState(true);
+  const handleChangeFile = (newType: "docx" | "pptx" | "xlsx" | "pdf") => {
     if (!creating) setFileType(newType);
   };
 
@@ -55,6 +64,16 @@ export const Creation: React.FC = () => {
       .catch(() => setSDK(null));
   }, []);
 
+  useEffect(() => {
+    if (!sdk) return undefined;
+    const controller = new AbortController();
+    setTemplate("");
+    fetchTemplates(sdk, fileType, i18next.language, controller.signal)
+      .then((res) => setTemplates(res.templates || []))
+      .catch(() => setTemplates([]));
+    return () => controller.abort();
+  }, [sdk, fileType]);
+
   useEffect(() => {
     if (fileTypeRef.current !== fileType) {
       const defaultDocx = t("document.new", "New Document") || "New Document";
@@ -63,9 +82,13 @@ export const Creation: React.FC = () => {
         "New Presentation";
       const defaultXlsx =
         t("document.new.spreadsheet", "New Spreadsheet") || "New Spreadsheet";
+      const defaultPdf = t("document.new.form", "New Form") || "New Form";
 
       const isDefault =
-        file === defaultDocx || file === defaultPptx || file === defaultXlsx;
+        file === defaultDocx ||
+        file === defaultPptx ||
+        file === defaultXlsx ||
+        file === defaultPdf;
       if (isDefault) {
         switch (fileType) {
           case "docx":
@@ -77,6 +100,9 @@ export const Creation: React.FC = () => {
           case "xlsx":
             setFile(defaultXlsx);
             break;
+          case "pdf":
+            setFile(defaultPdf);
+            break;
           default:
             break;
         }
@@ -134,7 +160,7 @@ export const Creation: React.FC = () => {
                 selected={fileType === "xlsx"}
               />
             </div>
-            <div className="grow">
+            <div className="grow pr-5">
               <OnlyofficeTile
                 Icon={getFileIcon("sample.pptx")}
                 text={t("creation.tiles.presentation", "Presentation")}
@@ -143,7 +169,63 @@ export const Creation: React.FC = () => {
                 selected={fileType === "pptx"}
               />
             </div>
+            <div className="grow">
+              <OnlyofficeTile
+                Icon={getFileIcon("sample.pdf")}
+                text={t("creation.tiles.form", "PDF form")}
+                onClick={() => handleChangeFile("pdf")}
+                onKeyDown={() => handleChangeFile("pdf")}
+                selected={fileType === "pdf"}
+              />
+            </div>
           </div>
+          {templates.length > 0 && (
+            <div className="w-full pt-5">
+              <label
+                htmlFor="creation-template"
+                className="font-semibold text-sm text-gray-700 dark:text-dark-text py-2"
+              >
+                {t("creation.inputs.template", "Template")}
+              </label>
+              <select
+                id="creation-template"
+                className="font-normal text-sm text-gray-700 dark:text-dark-text block w-full border rounded-sm h-10 px-4 bg-white dark:bg-dark-bg border-gray-light dark:border-dark-border"
+                value={template}
+                onChange={(e) => setTemplate(e.target.value)}
+                disabled={creating}
+              >
+                <option value="">
+                  {t("creation.inputs.template.blank", "Blank")}
+                </option>
+                {templates.map((tmpl) => (
+                  <option key={tmpl.id} value={tmpl.id}>
+                    {tmpl.name}
+                  </option>
+                ))}
+              </select>
+              {template && fileType !== "pdf" && (
+                <div className="flex items-center pt-2">
+                  <input
+                    type="checkbox"
+                    id="creation-fill"
+                    checked={fill}
+                    onChange={(e) => setFill(e.target.checked)}
+                    disabled={creating}
+                    className="w-4 h-4 text-blue-600 bg-gray-100 dark:bg-dark-bg border-gray-300 dark:border-dark-border rounded focus:ring-blue-500 focus:ring-2 disabled:opacity-50 disabled:cursor-not-allowed"
+                  />
+                  <label
+                    htmlFor="creation-fill"
+                    className="ml-2 text-sm font-medium text-gray-900 dark:text-dark-text"
+                  >
+                    {t(
+                      "creation.inputs.template.fill",
+                      "Fill in the record fields",
+                    )}
+                  </label>
+                </div>
+              )}
+            </div>
+          )}
         </div>
       </div>
       <div className="h-[48px] flex items-center w-full bg-white dark:bg-dark-bg border-t dark:border-dark-border">
@@ -168,34 +250,46 @@ export const Creation: React.FC = () => {
                 const token = await sdk?.execute(Command.GET_SIGNED_TOKEN);
                 if (!token) return;
                 const { parameters } = getCurrentURL();
+                const entity = getParentEntity(parameters);
 
                 try {
-                  const fres = await axios({
-                    method: "GET",
-                    url: `${process.env.BACKEND_GATEWAY}/files/create`,
-                    headers: {
-                      "X-Pipedrive-App-Context": token.token,
-                    },
-                    params: {
-                      lang: i18next.language,
-                      type: fileType,
-                      deal: parameters.get("selectedIds") || "",
-                      filename: `${file
-                        .replaceAll("/", ":")
-                        .replaceAll("\\", ":")
-                        .substring(0, 190)}.${fileType}`,
-                    },
-                  });
+                  const name = `${file
+                    .replaceAll("/", ":")
+                    .replaceAll("\\", ":")
+                    .substring(0, 190)}.${fileType}`;
+                  let id: string;
+                  if (template && fill && fileType !== "pdf" && sdk) {
+                    const gres = await generateFile(
+                      sdk,
+                      template,
+                      name,
+                      entity,
+                    );
+                    id = gres.file_id;
+                  } else {
+                    const fres = await axios({
+                      method: "GET",
+                      url: `${process.env.BACKEND_GATEWAY}/files/create`,
+                      headers: {
+                        "X-Pipedrive-App-Context": token.token,
+                      },
+                      params: {
+                        lang: i18next.language,
+                        type: fileType,
+                        entity_type: entity.type,
+                        entity_id: entity.id,
+                        template: template || undefined,
+                        filename: name,
+                      },
+                    });
+                    id = fres.data.data.id;
+                  }
                   window.open(
-                    `/editor?token=${token.token}&id=${
-                      fres.data.data.id
-                    }&deal_id=${
-                      fres.data.data.deal_id
-                    }&name=${`${encodeURIComponent(
+                    `/editor?token=${token.token}&id=${id}&${getEntityQuery(
+                      entity,
+                    )}&name=${`${encodeURIComponent(
                       file.substring(0, 190),
-                    )}.${fileType}`}&key=${md5(
-                      fres.data.data.id + fres.data.data.update_time,
-                    )}&lng=${i18next.language}`,
+                    )}.${fileType}`}&lng=${i18next.language}`,
                   );
                   await sdk?.execute(Command.CLOSE_MODAL);
                 } catch {
@@ -27,12 +27,13 @@ import { OnlyofficeDragDrop } from "@components/drop";
 import { uploadFile } from "@services/file";
 
 import { getCurrentURL } from "@utils/url";
+import { getParentEntity } from "@utils/entity";
 
 const onDrop = <T extends File>(acceptedFiles: T[]): Promise<void> => {
   const { url, parameters } = getCurrentURL();
   return uploadFile(
     `${url}api/v1/files`,
-    parameters.get("selectedIds") || "",
+    getParentEntity(parameters),
     acceptedFiles[0],
   );
 };
@@ -16,7 +16,7 @@
  *
  */
 
-import React from "react";
+import React, { useEffect } from "react";
 import { useSearchParams } from "react-router-dom";
 import { useTranslation } from "react-i18next";
 import { DocumentEditor } from "@onlyoffice/document-editor-react";
@@ -28,6 +28,10 @@ import { OnlyofficeSpinner } from "@components/spinner";
 
 import { useBuildConfig } from "@hooks/useBuildConfig";
 
+import { fetchHistory, fetchHistoryData } from "@services/history";
+import { fetchVersions, restoreVersion } from "@services/version";
+
+import { EntityType } from "@utils/entity";
 import { getFileFavicon } from "@utils/file";
 
 import Icon from "@assets/nofile.svg";
@@ -55,33 +59,159 @@ export const OnlyofficeEditorPage: React.FC = () => {
     params.get("token") || "",
     params.get("id") || "",
     params.get("name") || "new.docx",
-    params.get("key") || new Date().toTimeString(),
-    params.get("deal_id") || "1",
+    {
+      type: (params.get("entity_type") || "deal") as EntityType,
+      id: params.get("entity_id") || params.get("deal_id") || "1",
+    },
     isDark,
+    params.get("convert") === "true",
+    params.get("lossy") === "true",
   );
 
+  const fileID = data?.file_id || params.get("id") || "";
+
+  useEffect(() => {
+    if (!data?.converted) return;
+    const search = new URLSearchParams(window.location.search);
+    search.set("id", fileID);
+    search.set("name", data.document.title);
+    search.delete("key");
+    search.delete("convert");
+    window.history.replaceState(null, "", `?${search.toString()}`);
+  }, [data, fileID]);
+
+  const onRequestEditRights = () => {
+    const search = new URLSearchParams(window.location.search);
+    if (
+      data?.lossy_editable &&
+      // eslint-disable-next-line no-alert
+      window.confirm(
+        t(
+          "editor.lossy.confirm",
+          "Some formatting may be lost when this file is saved. Do you want to edit it anyway?",
+        ),
+      )
+    ) {
+      search.set("lossy", "true");
+    } else if (data?.convertible) {
+      search.set("convert", "true");
+    } else {
+      return;
+    }
+
+    window.location.search = search.toString();
+  };
+
   const validConfig = !error && !isLoading && data;
   const backgroundClass = isDark ? "bg-dark-bg" : "bg-white";
 
-  const onDocumentReady = () => {
-    if (data?.demo_enabled) {
-      const docEditor = (
-        window as {
-          DocEditor?: {
-            instances?: {
-              docxEditor?: { showMessage?: (message: string) => void };
+  const getEditor = () =>
+    (
+      window as {
+        DocEditor?: {
+          instances?: {
+            docxEditor?: {
+              refreshHistory?: (history: object) => void;
+              setHistoryData?: (data: object) => void;
             };
           };
-        }
-      ).DocEditor?.instances?.docxEditor;
-      if (docEditor && docEditor.showMessage) {
+        };
+      }
+    ).DocEditor?.instances?.docxEditor;
+
+  const onRequestHistory = async () => {
+    const editor = getEditor();
+    try {
+      const history = await fetchHistory(
+        params.get("token") || "",
+        fileID,
+        data?.document.key || "",
+      );
+      editor?.refreshHistory?.(history);
+    } catch {
+      editor?.refreshHistory?.({
+        error: t("editor.history.error", "Could not load version history"),
+      });
+    }
+  };
+
+  const onRequestHistoryData = async (event: { data: number }) => {
+    const editor = getEditor();
+    try {
+      const historyData = await fetchHistoryData(
+        params.get("token") || "",
+        fileID,
+        data?.document.key || "",
+        event.data,
+      );
+      editor?.setHistoryData?.(historyData);
+    } catch {
+      editor?.setHistoryData?.({
+        error: t("editor.history.error", "Could not load version history"),
+        version: event.data,
+      });
+    }
+  };
+
+  const onRequestRestore = async (event: { data: { version: number } }) => {
+    const editor = getEditor();
+    try {
+      const restored = await restoreVersion(
+        params.get("token") || "",
+        fileID,
+        event.data.version,
+      );
+      const search = new URLSearchParams(window.location.search);
+      search.set("id", restored.file_id);
+      search.set("name", restored.filename);
+      search.delete("key");
+      window.location.search = search.toString();
+    } catch {
+      editor?.refreshHistory?.({
+        error: t(
+          "editor.history.restore.error",
+          "Could not restore the version",
+        ),
+      });
+    }
+  };
+
+  const onDocumentReady = async () => {
+    const docEditor = (
+      window as {
+        DocEditor?: {
+          instances?: {
+            docxEditor?: { showMessage?: (message: string) => void };
+          };
+        };
+      }
+    ).DocEditor?.instances?.docxEditor;
+
+    if (data?.demo_enabled && docEditor?.showMessage) {
+      docEditor.showMessage(
+        t(
+          "editor.demo.message",
+          "You are using public demo ONLYOFFICE Document Server. Please do not store private sensitive data.",
+        ),
+      );
+    }
+
+    try {
+      const { versions } = await fetchVersions(
+        params.get("token") || "",
+        fileID,
+      );
+      const latest = versions.filter((v) => !v.deleted).pop();
+      if (latest && latest.file_id !== fileID && docEditor?.showMessage) {
         docEditor.showMessage(
           t(
-            "editor.demo.message",
-            "You are using public demo ONLYOFFICE Document Server. Please do not store private sensitive data.",
+            "editor.history.outdated",
+            "A newer version of this file exists. Changes made here will be saved as the newest version.",
           ),
         );
       }
+    } catch {
+      // the version lineage is optional, files without one are edited as usual
     }
   };
 
@@ -92,7 +222,9 @@ export const OnlyofficeEditorPage: React.FC = () => {
           {
             rel: "shortcut icon",
             type: "image/x-icon",
-            href: `${getFileFavicon(params.get("name") || "new.docx")}`,
+            href: `${getFileFavicon(
+              data?.document.title || params.get("name") || "new.docx",
+            )}`,
           },
         ]}
       />
@@ -173,6 +305,17 @@ export const OnlyofficeEditorPage: React.FC = () => {
                 },
                 onWarning: onEditor,
                 onDocumentReady,
+                onRequestHistory,
+                onRequestHistoryData,
+                onRequestRestore:
+                  data.document.permissions.edit ? onRequestRestore : undefined,
+                onRequestHistoryClose: () => {
+                  window.location.reload();
+                },
+                onRequestEditRights:
+                  data.convertible || data.lossy_editable
+                    ? onRequestEditRights
+                    : undefined,
               },
             }}
           />
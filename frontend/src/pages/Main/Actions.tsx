@@ -18,7 +18,6 @@
 
 import React, { useEffect, useState } from "react";
 import i18next from "i18next";
-import md5 from "md5";
 import AppExtensionsSDK, { Command } from "@pipedrive/app-extensions-sdk";
 import { useTranslation } from "react-i18next";
 
@@ -29,6 +28,7 @@ import { downloadFile } from "@services/file";
 
 import { getFileParts, isFileSupported } from "@utils/file";
 import { getCurrentURL } from "@utils/url";
+import { getEntityQuery, getParentEntity } from "@utils/entity";
 
 import { File } from "src/types/file";
 
@@ -91,13 +91,11 @@ export const OnlyofficeFileActions: React.FC<FileActionsProps> = ({ file }) => {
       if (token) {
         const [name, ext] = getFileParts(file.name);
         if (win && win.location)
-          win.location.href = `/editor?token=${token.token}&deal_id=${
-            parameters.get("selectedIds") || "1"
-          }&id=${file.id}&name=${`${encodeURIComponent(
+          win.location.href = `/editor?token=${token.token}&${getEntityQuery(
+            getParentEntity(parameters),
+          )}&id=${file.id}&name=${`${encodeURIComponent(
             name.substring(0, 190),
-          )}.${ext}`}&key=${md5(file.id + file.update_time)}&lng=${
-            i18next.language
-          }&dark=${isDark}`;
+          )}.${ext}`}&lng=${i18next.language}&dark=${isDark}`;
       }
     }
     // temporary solution
@@ -107,7 +105,9 @@ export const OnlyofficeFileActions: React.FC<FileActionsProps> = ({ file }) => {
   const handleDownload = async () => {
     setDisable(true);
     try {
-      const durl = await downloadFile(url, file.id);
+      const token = await sdk?.execute(Command.GET_SIGNED_TOKEN);
+      if (!token) throw new Error("could not get a signed token");
+      const durl = await downloadFile(token.token, file.id);
       window.open(durl);
     } catch {
       await sdk?.execute(Command.SHOW_SNACKBAR, {
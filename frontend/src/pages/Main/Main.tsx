@@ -32,11 +32,13 @@ import { OnlyofficeSpinner } from "@components/spinner";
 import { OnlyofficeBackgroundError } from "@layouts/ErrorBackground";
 
 import { useFileSearch } from "@hooks/useFileSearch";
+import { useSessions } from "@hooks/useSessions";
 
 import { checkSettings } from "@services/settings";
 
 import { formatBytes, getFileIcon, isFileSupported } from "@utils/file";
 import { getCurrentURL } from "@utils/url";
+import { getEntityResource, getParentEntity } from "@utils/entity";
 
 import SettingsError from "@assets/settings-error.svg";
 import { OnlyofficeFileActions } from "./Actions";
@@ -44,16 +46,28 @@ import { OnlyofficeFileActions } from "./Actions";
 export const Main: React.FC = () => {
   const { t } = useTranslation();
   const { url, parameters } = getCurrentURL();
+  const entity = getParentEntity(parameters);
   const [sdk, setSDK] = useState<AppExtensionsSDK | null>();
   const [settingsConfigured, setSettingsConfigured] = useState<boolean | null>(
     null,
   );
   const { isLoading, fetchNextPage, isFetchingNextPage, files, hasNextPage } =
     useFileSearch(
-      `${url}api/v1/deals/${parameters.get("selectedIds")}/files`,
+      `${url}api/v1/${getEntityResource(entity)}/${entity.id}/files`,
       20,
     );
 
+  const { sessions } = useSessions(sdk, entity);
+  const getEditingInfo = (id: string): Record<string, string> => {
+    const names = sessions
+      .filter((session) => session.file_id === String(id))
+      .map((session) => session.name || session.user_id);
+    if (names.length === 0) return {};
+    return {
+      [t("files.info.editing", "Being edited by")]: names.join(", "),
+    };
+  };
+
   const observer = useRef<IntersectionObserver | null>(null);
   const lastItem = useCallback(
     (node: Element | null) => {
@@ -147,6 +161,7 @@ export const Main: React.FC = () => {
                         [t("files.info.size", "Size")]: formatBytes(
                           file.file_size,
                         ),
+                        ...getEditingInfo(file.id),
                       }}
                     />
                   </OnlyofficeFile>
@@ -174,6 +189,7 @@ export const Main: React.FC = () => {
                       [t("files.info.size", "Size")]: formatBytes(
                         file.file_size,
                       ),
+                      ...getEditingInfo(file.id),
                     }}
                   />
                 </OnlyofficeFile>
@@ -37,6 +37,12 @@ import OnlyofficeLogo from "@assets/onlyoffice-logo.svg";
 import SettingsError from "@assets/settings-error.svg";
 import { getCurrentURL } from "@utils/url";
 
+import {
+  LossyEditPolicy,
+  PermissionMode,
+  PermissionsPolicy,
+} from "src/types/settings";
+
 function SettingsErrorIcon() {
   return (
     <div className="flex flex-col items-center justify-center">
@@ -57,6 +63,13 @@ export const SettingsPage: React.FC = () => {
   const [header, setHeader] = useState<string | undefined>(undefined);
   const [demoEnabled, setDemoEnabled] = useState(false);
   const [demoStarted, setDemoStarted] = useState<string | undefined>(undefined);
+  const [autoConvert, setAutoConvert] = useState(false);
+  const [lossyEdit, setLossyEdit] = useState<LossyEditPolicy>("never");
+  const [replaceBlankForms, setReplaceBlankForms] = useState(false);
+  const [permissions, setPermissions] = useState<PermissionsPolicy>({});
+  const [rolePermissions, setRolePermissions] = useState<
+    Record<string, PermissionsPolicy>
+  >({});
   const [saving, setSaving] = useState(false);
 
   const isDemoValid = (): boolean => {
@@ -154,6 +167,11 @@ export const SettingsPage: React.FC = () => {
               setHeader(res.doc_header);
               setDemoEnabled(res.demo_enabled);
               setDemoStarted(res.demo_started);
+              setAutoConvert(res.auto_convert);
+              setLossyEdit(res.lossy_edit || "never");
+              setReplaceBlankForms(res.replace_blank_forms);
+              setPermissions(res.permissions || {});
+              setRolePermissions(res.role_permissions || {});
               setAdmin(true);
             }
           } catch {
@@ -205,6 +223,11 @@ export const SettingsPage: React.FC = () => {
           secret || "",
           header || "",
           demoEnabled,
+          autoConvert,
+          lossyEdit,
+          replaceBlankForms,
+          permissions,
+          rolePermissions,
         );
         setDemoStarted(demoStarted || new Date().toISOString());
         await sdk.execute(Command.SHOW_SNACKBAR, {
@@ -399,6 +422,198 @@ export const SettingsPage: React.FC = () => {
                     )}
               </p>
             </div>
+            <div className="pl-5 pr-5 mt-4">
+              <div className="flex items-center">
+                <input
+                  type="checkbox"
+                  id="auto-convert"
+                  checked={autoConvert}
+                  onChange={(e) => setAutoConvert(e.target.checked)}
+                  disabled={saving}
+                  className="w-4 h-4 text-blue-600 bg-gray-100 dark:bg-dark-bg border-gray-300 dark:border-dark-border rounded focus:ring-blue-500 focus:ring-2 disabled:opacity-50 disabled:cursor-not-allowed"
+                />
+                <label
+                  htmlFor="auto-convert"
+                  className="ml-2 text-sm font-medium text-gray-900 dark:text-dark-text"
+                >
+                  {t(
+                    "settings.inputs.convert",
+                    "Convert legacy formats on open",
+                  )}
+                </label>
+              </div>
+              <p className="text-xs text-gray-500 dark:text-dark-muted mt-1 ml-6">
+                {t(
+                  "settings.inputs.convert.description",
+                  "Files like .doc, .xls and .ppt are converted to an editable copy automatically instead of on request",
+                )}
+              </p>
+            </div>
+            <div className="pl-5 pr-5 mt-4">
+              <label
+                htmlFor="lossy-edit"
+                className="text-sm font-medium text-gray-900 dark:text-dark-text"
+              >
+                {t("settings.inputs.lossy", "Edit ODF and CSV files")}
+              </label>
+              <select
+                id="lossy-edit"
+                value={lossyEdit}
+                onChange={(e) =>
+                  setLossyEdit(e.target.value as LossyEditPolicy)
+                }
+                disabled={saving}
+                className="block mt-1 w-full text-sm border border-gray-300 dark:border-dark-border rounded bg-white dark:bg-dark-bg text-gray-900 dark:text-dark-text disabled:opacity-50 disabled:cursor-not-allowed"
+              >
+                <option value="never">
+                  {t("settings.inputs.lossy.never", "Never")}
+                </option>
+                <option value="ask">
+                  {t("settings.inputs.lossy.ask", "Ask before editing")}
+                </option>
+                <option value="always">
+                  {t("settings.inputs.lossy.always", "Always")}
+                </option>
+              </select>
+              <p className="text-xs text-gray-500 dark:text-dark-muted mt-1">
+                {t(
+                  "settings.inputs.lossy.description",
+                  "Editing these formats may lose some formatting when the file is saved",
+                )}
+              </p>
+            </div>
+            <div className="pl-5 pr-5 mt-4">
+              <div className="flex items-center">
+                <input
+                  type="checkbox"
+                  id="replace-blank-forms"
+                  checked={replaceBlankForms}
+                  onChange={(e) => setReplaceBlankForms(e.target.checked)}
+                  disabled={saving}
+                  className="w-4 h-4 text-blue-600 bg-gray-100 dark:bg-dark-bg border-gray-300 dark:border-dark-border rounded focus:ring-blue-500 focus:ring-2 disabled:opacity-50 disabled:cursor-not-allowed"
+                />
+                <label
+                  htmlFor="replace-blank-forms"
+                  className="ml-2 text-sm font-medium text-gray-900 dark:text-dark-text"
+                >
+                  {t(
+                    "settings.inputs.forms",
+                    "Replace blank forms once filled",
+                  )}
+                </label>
+              </div>
+              <p className="text-xs text-gray-500 dark:text-dark-muted mt-1 ml-6">
+                {t(
+                  "settings.inputs.forms.description",
+                  "Submitted PDF forms are attached as new files. Enable to remove the blank form after it is submitted",
+                )}
+              </p>
+            </div>
+            <div className="pl-5 pr-5 mt-4">
+              <label
+                htmlFor="permissions-mode"
+                className="text-sm font-medium text-gray-900 dark:text-dark-text"
+              >
+                {t("settings.inputs.permissions", "Editor permissions")}
+              </label>
+              <select
+                id="permissions-mode"
+                value={permissions.mode || "edit"}
+                onChange={(e) =>
+                  setPermissions({
+                    ...permissions,
+                    mode: e.target.value as PermissionMode,
+                  })
+                }
+                disabled={saving}
+                className="block mt-1 w-full text-sm border border-gray-300 dark:border-dark-border rounded bg-white dark:bg-dark-bg text-gray-900 dark:text-dark-text disabled:opacity-50 disabled:cursor-not-allowed"
+              >
+                <option value="edit">
+                  {t("settings.inputs.permissions.edit", "Edit")}
+                </option>
+                <option value="review">
+                  {t("settings.inputs.permissions.review", "Review only")}
+                </option>
+                <option value="comment">
+                  {t("settings.inputs.permissions.comment", "Comment only")}
+                </option>
+                <option value="view">
+                  {t("settings.inputs.permissions.view", "View only")}
+                </option>
+              </select>
+              <div className="flex items-center mt-2">
+                <input
+                  type="checkbox"
+                  id="permissions-download"
+                  checked={!permissions.disable_download}
+                  onChange={(e) =>
+                    setPermissions({
+                      ...permissions,
+                      disable_download: !e.target.checked,
+                    })
+                  }
+                  disabled={saving}
+                  className="w-4 h-4 text-blue-600 bg-gray-100 dark:bg-dark-bg border-gray-300 dark:border-dark-border rounded focus:ring-blue-500 focus:ring-2 disabled:opacity-50 disabled:cursor-not-allowed"
+                />
+                <label
+                  htmlFor="permissions-download"
+                  className="ml-2 text-sm font-medium text-gray-900 dark:text-dark-text"
+                >
+                  {t(
+                    "settings.inputs.permissions.download",
+                    "Allow downloading",
+                  )}
+                </label>
+              </div>
+              <div className="flex items-center mt-2">
+                <input
+                  type="checkbox"
+                  id="permissions-copy"
+                  checked={!permissions.disable_copy}
+                  onChange={(e) =>
+                    setPermissions({
+                      ...permissions,
+                      disable_copy: !e.target.checked,
+                    })
+                  }
+                  disabled={saving}
+                  className="w-4 h-4 text-blue-600 bg-gray-100 dark:bg-dark-bg border-gray-300 dark:border-dark-border rounded focus:ring-blue-500 focus:ring-2 disabled:opacity-50 disabled:cursor-not-allowed"
+                />
+                <label
+                  htmlFor="permissions-copy"
+                  className="ml-2 text-sm font-medium text-gray-900 dark:text-dark-text"
+                >
+                  {t("settings.inputs.permissions.copy", "Allow copying")}
+                </label>
+              </div>
+              <div className="flex items-center mt-2">
+                <input
+                  type="checkbox"
+                  id="permissions-print"
+                  checked={!!permissions.allow_print}
+                  onChange={(e) =>
+                    setPermissions({
+                      ...permissions,
+                      allow_print: e.target.checked,
+                    })
+                  }
+                  disabled={saving}
+                  className="w-4 h-4 text-blue-600 bg-gray-100 dark:bg-dark-bg border-gray-300 dark:border-dark-border rounded focus:ring-blue-500 focus:ring-2 disabled:opacity-50 disabled:cursor-not-allowed"
+                />
+                <label
+                  htmlFor="permissions-print"
+                  className="ml-2 text-sm font-medium text-gray-900 dark:text-dark-text"
+                >
+                  {t("settings.inputs.permissions.print", "Allow printing")}
+                </label>
+              </div>
+              <p className="text-xs text-gray-500 dark:text-dark-muted mt-1">
+                {t(
+                  "settings.inputs.permissions.description",
+                  "Applies to every user unless their Pipedrive permission set has its own policy",
+                )}
+              </p>
+            </div>
             <div className="flex justify-start items-center mt-4 ml-5">
               <OnlyofficeButton
                 text={t("button.save", "Save")}
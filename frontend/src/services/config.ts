@@ -19,6 +19,8 @@
 import axios, { AxiosInstance } from "axios";
 import axiosRetry from "axios-retry";
 
+import { ParentEntity } from "@utils/entity";
+
 import { ConfigResponse } from "src/types/config";
 
 const setupRetry = (
@@ -38,9 +40,10 @@ export const fetchConfig = async (
   token: string,
   id: string,
   name: string,
-  key: string,
-  dealID: string,
+  entity: ParentEntity,
   dark?: boolean,
+  convert?: boolean,
+  lossy?: boolean,
   signal?: AbortSignal,
 ) => {
   const client = axios.create();
@@ -52,9 +55,11 @@ export const fetchConfig = async (
     params: {
       id,
       name,
-      key,
-      deal_id: dealID,
+      entity_type: entity.type,
+      entity_id: entity.id,
       dark: dark?.toString() || "false",
+      convert: convert?.toString() || "false",
+      lossy: lossy?.toString() || "false",
     },
     headers: {
       "Content-Type": "application/json",
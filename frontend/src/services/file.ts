@@ -21,6 +21,8 @@ import axiosRetry from "axios-retry";
 
 import { AuthToken } from "@context/TokenContext";
 
+import { getEntityField, ParentEntity } from "@utils/entity";
+
 import { FileResponse } from "src/types/file";
 
 const setupRetry = (
@@ -80,14 +82,15 @@ export const fetchFiles = async (
   }
 };
 
-export const downloadFile = async (domain: string, id: string) => {
+export const downloadFile = async (token: string, id: string) => {
   const resp = await axios.get(
-    `${process.env.BACKEND_GATEWAY}/files/download?domain=${encodeURIComponent(
-      domain,
-    )}&file_id=${id}`,
+    `${process.env.BACKEND_GATEWAY}/files/download`,
     {
+      params: {
+        file_id: id,
+      },
       headers: {
-        Authorization: `Bearer ${AuthToken.access_token}`,
+        "X-Pipedrive-App-Context": token,
       },
     },
   );
@@ -113,10 +116,14 @@ export const deleteFile = async (
   return res.status === 200;
 };
 
-export const uploadFile = async (url: string, deal: string, file: File) => {
+export const uploadFile = async (
+  url: string,
+  entity: ParentEntity,
+  file: File,
+) => {
   const form = new FormData();
   form.append("file", file);
-  form.append("deal_id", deal);
+  form.append(getEntityField(entity), entity.id);
 
   const res = await axios({
     method: "POST",
@@ -0,0 +1,59 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+import axios from "axios";
+
+import { HistoryDataResponse, HistoryResponse } from "src/types/history";
+
+export const fetchHistory = async (token: string, id: string, key: string) => {
+  const res = await axios<HistoryResponse>({
+    method: "GET",
+    url: `${process.env.BACKEND_GATEWAY}/api/history`,
+    params: {
+      id,
+      key,
+    },
+    headers: {
+      "Content-Type": "application/json",
+      "X-Pipedrive-App-Context": token,
+    },
+  });
+  return res.data;
+};
+
+export const fetchHistoryData = async (
+  token: string,
+  id: string,
+  key: string,
+  version: number,
+) => {
+  const res = await axios<HistoryDataResponse>({
+    method: "GET",
+    url: `${process.env.BACKEND_GATEWAY}/api/history/data`,
+    params: {
+      id,
+      key,
+      version,
+    },
+    headers: {
+      "Content-Type": "application/json",
+      "X-Pipedrive-App-Context": token,
+    },
+  });
+  return res.data;
+};
@@ -20,7 +20,11 @@ import axios, { AxiosInstance } from "axios";
 import axiosRetry from "axios-retry";
 import AppExtensionsSDK, { Command } from "@pipedrive/app-extensions-sdk";
 
-import { SettingsResponse } from "src/types/settings";
+import {
+  LossyEditPolicy,
+  PermissionsPolicy,
+  SettingsResponse,
+} from "src/types/settings";
 
 const setupRetry = (
   client: AxiosInstance,
@@ -45,6 +49,11 @@ export const postSettings = async (
   secret: string,
   header: string,
   demoEnabled = false,
+  autoConvert = false,
+  lossyEdit: LossyEditPolicy = "never",
+  replaceBlankForms = false,
+  permissions: PermissionsPolicy = {},
+  rolePermissions: Record<string, PermissionsPolicy> = {},
 ) => {
   const pctx = await sdk.execute(Command.GET_SIGNED_TOKEN);
   const client = axios.create({ baseURL: process.env.BACKEND_GATEWAY });
@@ -62,6 +71,11 @@ export const postSettings = async (
       doc_secret: secret,
       doc_header: header,
       demo_enabled: demoEnabled,
+      auto_convert: autoConvert,
+      lossy_edit: lossyEdit,
+      replace_blank_forms: replaceBlankForms,
+      permissions,
+      role_permissions: rolePermissions,
     },
     timeout: 4000,
   });
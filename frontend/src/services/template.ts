@@ -0,0 +1,70 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+import axios from "axios";
+import AppExtensionsSDK, { Command } from "@pipedrive/app-extensions-sdk";
+
+import { ParentEntity } from "@utils/entity";
+
+import { GenerateResponse, TemplatesResponse } from "src/types/template";
+
+export const fetchTemplates = async (
+  sdk: AppExtensionsSDK,
+  type: string,
+  lang: string,
+  signal?: AbortSignal,
+) => {
+  const pctx = await sdk.execute(Command.GET_SIGNED_TOKEN);
+  const res = await axios<TemplatesResponse>({
+    method: "GET",
+    url: `${process.env.BACKEND_GATEWAY}/api/templates`,
+    params: {
+      type,
+      lang,
+    },
+    headers: {
+      "Content-Type": "application/json",
+      "X-Pipedrive-App-Context": pctx.token,
+    },
+    signal,
+  });
+  return res.data;
+};
+
+export const generateFile = async (
+  sdk: AppExtensionsSDK,
+  template: string,
+  name: string,
+  entity: ParentEntity,
+) => {
+  const pctx = await sdk.execute(Command.GET_SIGNED_TOKEN);
+  const res = await axios<GenerateResponse>({
+    method: "POST",
+    url: `${process.env.BACKEND_GATEWAY}/api/generate`,
+    params: {
+      template,
+      name,
+      entity_type: entity.type,
+      entity_id: entity.id,
+    },
+    headers: {
+      "Content-Type": "application/json",
+      "X-Pipedrive-App-Context": pctx.token,
+    },
+  });
+  return res.data;
+};
@@ -0,0 +1,55 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+import axios from "axios";
+
+import { Version, VersionsResponse } from "src/types/version";
+
+export const fetchVersions = async (token: string, id: string) => {
+  const res = await axios<VersionsResponse>({
+    method: "GET",
+    url: `${process.env.BACKEND_GATEWAY}/api/versions`,
+    params: {
+      id,
+    },
+    headers: {
+      "Content-Type": "application/json",
+      "X-Pipedrive-App-Context": token,
+    },
+  });
+  return res.data;
+};
+
+export const restoreVersion = async (
+  token: string,
+  id: string,
+  version: number,
+) => {
+  const res = await axios<Version>({
+    method: "POST",
+    url: `${process.env.BACKEND_GATEWAY}/api/versions/restore`,
+    params: {
+      id,
+      version,
+    },
+    headers: {
+      "Content-Type": "application/json",
+      "X-Pipedrive-App-Context": token,
+    },
+  });
+  return res.data;
+};
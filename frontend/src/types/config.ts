@@ -68,4 +68,8 @@ export type ConfigResponse = {
   token: string;
   server_url: string;
   demo_enabled: boolean;
+  file_id?: string;
+  convertible?: boolean;
+  converted?: boolean;
+  lossy_editable?: boolean;
 };
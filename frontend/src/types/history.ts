@@ -0,0 +1,55 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+type HistoryUser = {
+  id: string;
+  name?: string;
+};
+
+type HistoryChange = {
+  created: string;
+  user: HistoryUser;
+};
+
+type HistoryItem = {
+  changes?: HistoryChange[];
+  created: string;
+  key: string;
+  serverVersion?: string;
+  user: HistoryUser;
+  version: number;
+};
+
+export type HistoryResponse = {
+  currentVersion: number;
+  history: HistoryItem[];
+};
+
+export type HistoryDataResponse = {
+  changesUrl?: string;
+  fileType: string;
+  key: string;
+  previous?: {
+    fileType: string;
+    key: string;
+    url: string;
+  };
+  url: string;
+  version: number;
+  token?: string;
+};
@@ -0,0 +1,36 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+export type SessionMode = "edit" | "view";
+
+export type SessionState = "opening" | "connected";
+
+export type Session = {
+  file_id: string;
+  entity_type: string;
+  entity_id: string;
+  user_id: string;
+  name?: string;
+  mode: SessionMode;
+  state: SessionState;
+  opened_at: string;
+  updated_at: string;
+};
+
+export type SessionsResponse = {
+  sessions: Session[];
+};
@@ -16,10 +16,26 @@
  *
  */
 
+export type LossyEditPolicy = "never" | "ask" | "always";
+
+export type PermissionMode = "edit" | "review" | "comment" | "view";
+
+export type PermissionsPolicy = {
+  mode?: PermissionMode;
+  disable_download?: boolean;
+  disable_copy?: boolean;
+  allow_print?: boolean;
+};
+
 export type SettingsResponse = {
   doc_address: string;
   doc_secret: string;
   doc_header: string;
   demo_enabled: boolean;
   demo_started: string;
+  auto_convert: boolean;
+  replace_blank_forms: boolean;
+  lossy_edit: LossyEditPolicy;
+  permissions?: PermissionsPolicy;
+  role_permissions?: Record<string, PermissionsPolicy>;
 };
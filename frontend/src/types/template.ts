@@ -0,0 +1,37 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+export type Template = {
+  id: string;
+  name: string;
+  filename: string;
+  file_type: "docx" | "xlsx" | "pptx" | "pdf";
+  lang?: string;
+  size: number;
+  created_by?: string;
+  created_at: string;
+};
+
+export type TemplatesResponse = {
+  templates: Template[];
+};
+
+export type GenerateResponse = {
+  file_id: string;
+  filename: string;
+  update_time?: string;
+};
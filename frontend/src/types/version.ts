@@ -0,0 +1,33 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+export type Version = {
+  root_id: string;
+  parent_id?: string;
+  file_id: string;
+  entity_type: string;
+  entity_id: string;
+  version: number;
+  filename: string;
+  author?: string;
+  deleted?: boolean;
+  created_at: string;
+};
+
+export type VersionsResponse = {
+  versions: Version[];
+};
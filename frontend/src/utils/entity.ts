@@ -0,0 +1,68 @@
+/**
+ *
+ * (c) Copyright Ascensio System SIA 2026
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+export type EntityType =
+  | "deal"
+  | "person"
+  | "organization"
+  | "lead"
+  | "product"
+  | "activity";
+
+export type ParentEntity = {
+  type: EntityType;
+  id: string;
+};
+
+const entityFields: Record<EntityType, string> = {
+  deal: "deal_id",
+  person: "person_id",
+  organization: "org_id",
+  lead: "lead_id",
+  product: "product_id",
+  activity: "activity_id",
+};
+
+const entityResources: Record<EntityType, string> = {
+  deal: "deals",
+  person: "persons",
+  organization: "organizations",
+  lead: "leads",
+  product: "products",
+  activity: "activities",
+};
+
+const isEntityType = (value: string): value is EntityType =>
+  Object.keys(entityFields).includes(value);
+
+export const getParentEntity = (parameters: URLSearchParams): ParentEntity => {
+  const resource = (parameters.get("resource") || "").toLowerCase();
+  return {
+    type: isEntityType(resource) ? resource : "deal",
+    id: parameters.get("selectedIds") || "",
+  };
+};
+
+export const getEntityField = (entity: ParentEntity) =>
+  entityFields[entity.type];
+
+export const getEntityResource = (entity: ParentEntity) =>
+  entityResources[entity.type];
+
+export const getEntityQuery = (entity: ParentEntity) =>
+  `entity_type=${entity.type}&entity_id=${encodeURIComponent(entity.id)}`;